@@ -0,0 +1,341 @@
+package attributes
+
+import (
+	"auth/service"
+	"auth/utils"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// AttributeHandler gère les requêtes liées aux attributs personnalisés des utilisateurs
+type AttributeHandler struct {
+	attributeService *service.UserAttributeService
+}
+
+// NewAttributeHandler crée une nouvelle instance de AttributeHandler
+func NewAttributeHandler(attributeService *service.UserAttributeService) *AttributeHandler {
+	return &AttributeHandler{
+		attributeService: attributeService,
+	}
+}
+
+// tenantService limite le service à l'organisation active du token
+func (h *AttributeHandler) tenantService(ctx echo.Context) *service.UserAttributeService {
+	return h.attributeService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// CreateDefinitionHandler gère la requête pour créer un attribut personnalisé
+// @Summary Crée un attribut personnalisé
+// @Description Définit un nouvel attribut de profil (type, validation, exposition dans les tokens) pour l'organisation active.
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param attribute body AttributeDefinitionIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[AttributeDefinitionOut]
+// @Router /attributes [post]
+func (h *AttributeHandler) CreateDefinitionHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload AttributeDefinitionIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	definition, err := h.tenantService(ctx).CreateDefinition(service.AttributeDefinitionInput(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	utils.SetAuditTarget(ctx, definition.Definition.Name)
+	jsonResponse := utils.HttpResponse[AttributeDefinitionOut]{
+		Message:   "L'attribut a bien été créé",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      toAttributeDefinitionOut(definition),
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// GetDefinitionsHandler gère la requête pour lister les attributs personnalisés
+// @Summary Liste les attributs personnalisés
+// @Description Liste les attributs de profil définis pour l'organisation active.
+// @Tags Attributes
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]AttributeDefinitionOut]
+// @Router /attributes [get]
+func (h *AttributeHandler) GetDefinitionsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	definitions, err := h.tenantService(ctx).GetDefinitions()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucun attribut trouvé",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	definitionList := []AttributeDefinitionOut{}
+	for i := 0; i < len(definitions); i++ {
+		definitionList = append(definitionList, toAttributeDefinitionOut(definitions[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]AttributeDefinitionOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      definitionList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetDefinitionHandler gère la requête pour récupérer un attribut personnalisé
+// @Summary Récupère un attribut personnalisé
+// @Description Récupère un attribut de profil en fonction de son nom.
+// @Tags Attributes
+// @Produce json
+// @Param name path string true "Nom de l'attribut"
+// @Success 200 {object} utils.HttpResponse[AttributeDefinitionOut]
+// @Router /attributes/{name} [get]
+func (h *AttributeHandler) GetDefinitionHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	definition, err := h.tenantService(ctx).GetDefinition(ctx.Param("name"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[AttributeDefinitionOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toAttributeDefinitionOut(definition),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// UpdateDefinitionHandler gère la requête pour modifier un attribut personnalisé
+// @Summary Modifie un attribut personnalisé
+// @Description Modifie le libellé, la validation et l'exposition d'un attribut. Son nom et son type ne changent pas.
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param name path string true "Nom de l'attribut"
+// @Param attribute body AttributeDefinitionUpdateIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[AttributeDefinitionOut]
+// @Router /attributes/{name} [put]
+func (h *AttributeHandler) UpdateDefinitionHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload AttributeDefinitionUpdateIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	definition, err := h.tenantService(ctx).UpdateDefinition(ctx.Param("name"), service.AttributeDefinitionInput{
+		Label:        payload.Label,
+		Type:         payload.Type,
+		Required:     payload.Required,
+		UserEditable: payload.UserEditable,
+		Options:      payload.Options,
+		Pattern:      payload.Pattern,
+		MaxLength:    payload.MaxLength,
+		ClaimName:    payload.ClaimName,
+	})
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[AttributeDefinitionOut]{
+		Message:   "L'attribut a bien été mis à jour",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toAttributeDefinitionOut(definition),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// DeleteDefinitionHandler gère la requête pour supprimer un attribut personnalisé
+// @Summary Supprime un attribut personnalisé
+// @Description Supprime un attribut de profil et ses valeurs pour tous les utilisateurs de l'organisation.
+// @Tags Attributes
+// @Param name path string true "Nom de l'attribut"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /attributes/{name} [delete]
+func (h *AttributeHandler) DeleteDefinitionHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).DeleteDefinition(ctx.Param("name"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "L'attribut a bien été supprimé",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func toAttributeDefinitionOut(definition service.AttributeDefinitionDetail) AttributeDefinitionOut {
+	return AttributeDefinitionOut{
+		Id:           definition.Definition.Id,
+		Name:         definition.Definition.Name,
+		Label:        definition.Definition.Label,
+		Type:         definition.Definition.Type,
+		Required:     definition.Definition.Required,
+		UserEditable: definition.Definition.UserEditable,
+		Options:      definition.Options,
+		Pattern:      definition.Definition.Pattern,
+		MaxLength:    definition.Definition.MaxLength,
+		ClaimName:    definition.Definition.ClaimName,
+		CreatedAt:    definition.Definition.CreatedAt,
+		UpdatedAt:    definition.Definition.UpdatedAt,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "oneof":
+		return fieldName + " must be one of " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
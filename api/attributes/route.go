@@ -0,0 +1,16 @@
+package attributes
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAttributeRoutes(apiGroup *echo.Group, handler *AttributeHandler, guard *middlewares.Guard) {
+	//Admin method
+	apiGroup.POST("", handler.CreateDefinitionHandler, guard.AuditMiddle("attribute.create", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("", handler.GetDefinitionsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:name", handler.GetDefinitionHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.PUT("/:name", handler.UpdateDefinitionHandler, guard.AuditMiddle("attribute.update", "name"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:name", handler.DeleteDefinitionHandler, guard.AuditMiddle("attribute.delete", "name"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
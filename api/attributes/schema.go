@@ -0,0 +1,41 @@
+package attributes
+
+import "time"
+
+type AttributeDefinitionIn struct {
+	Name         string   `json:"name" validate:"required,max=50"`
+	Label        string   `json:"label" validate:"required,max=120"`
+	Type         string   `json:"type" validate:"required,oneof=string number boolean date enum"`
+	Required     bool     `json:"required"`
+	UserEditable bool     `json:"user_editable"`
+	Options      []string `json:"options" validate:"max=100,dive,required,max=120"`
+	Pattern      string   `json:"pattern" validate:"max=255"`
+	MaxLength    int      `json:"max_length" validate:"min=0"`
+	ClaimName    string   `json:"claim_name" validate:"max=50"`
+}
+
+type AttributeDefinitionUpdateIn struct {
+	Label        string   `json:"label" validate:"required,max=120"`
+	Type         string   `json:"type" validate:"required,oneof=string number boolean date enum"`
+	Required     bool     `json:"required"`
+	UserEditable bool     `json:"user_editable"`
+	Options      []string `json:"options" validate:"max=100,dive,required,max=120"`
+	Pattern      string   `json:"pattern" validate:"max=255"`
+	MaxLength    int      `json:"max_length" validate:"min=0"`
+	ClaimName    string   `json:"claim_name" validate:"max=50"`
+}
+
+type AttributeDefinitionOut struct {
+	Id           string    `json:"id"`
+	Name         string    `json:"name"`
+	Label        string    `json:"label"`
+	Type         string    `json:"type"`
+	Required     bool      `json:"required"`
+	UserEditable bool      `json:"user_editable"`
+	Options      []string  `json:"options"`
+	Pattern      string    `json:"pattern"`
+	MaxLength    int       `json:"max_length"`
+	ClaimName    string    `json:"claim_name"`
+	CreatedAt    time.Time `json:"create_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
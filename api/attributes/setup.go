@@ -0,0 +1,21 @@
+package attributes
+
+import (
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupAttribute config Attribute
+func SetupAttribute(apiGroup *echo.Group, db *gorm.DB, guard *middlewares.Guard) {
+	attributeRepo := repository.NewAttributeRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	attributeService := service.NewUserAttributeService(attributeRepo, userRepo)
+	attributeHandler := NewAttributeHandler(attributeService)
+
+	attributeGroup := apiGroup.Group("/attributes")
+	RegisterAttributeRoutes(attributeGroup, attributeHandler, guard)
+}
@@ -0,0 +1,247 @@
+package audit
+
+import (
+	"auth/service"
+	"auth/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// AuditHandler gère les requêtes liées au journal d'audit
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler crée une nouvelle instance de AuditHandler
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetEventsHandler gère la requête pour consulter le journal d'audit
+// @Summary Consulte le journal d'audit
+// @Description Liste les évènements de sécurité (connexions, codes otp, mots de passe, rôles, suppressions, permissions, impersonations), les plus récents en premier.
+// @Tags Audit
+// @Produce json
+// @Param limit query int false "Nombre d'évènements par page (200 au maximum)"
+// @Param offset query int false "Nombre d'évènements à ignorer"
+// @Param action query string false "Action, par exemple auth.login"
+// @Param outcome query string false "Résultat : success ou failure"
+// @Param actor_id query string false "Auteur de l'action, ou administrateur en impersonation"
+// @Param target_id query string false "Cible de l'action"
+// @Param organization_id query string false "Organisation active de l'auteur"
+// @Param correlation_id query string false "Identifiant de la requête (X-Request-Id)"
+// @Param ip_address query string false "Adresse IP du client"
+// @Param from query string false "Date minimale (RFC3339 ou AAAA-MM-JJ)"
+// @Param to query string false "Date maximale, exclue (RFC3339 ou AAAA-MM-JJ)"
+// @Success 200 {object} utils.HttpResponse[AuditListOut]
+// @Router /audit/events [get]
+func (h *AuditHandler) GetEventsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_audit_log")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload AuditListIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	listQuery, err := toAuditListQuery(payload)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	page, err := h.auditService.WithContext(ctx.Request().Context()).FindEvents(listQuery)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	eventList := []AuditEventOut{}
+	for i := 0; i < len(page.Events); i++ {
+		event := page.Events[i]
+		eventOut := AuditEventOut{
+			Id:             event.Id,
+			Sequence:       event.Sequence,
+			OccurredAt:     event.OccurredAt,
+			Action:         event.Action,
+			Outcome:        event.Outcome,
+			ActorId:        event.ActorId,
+			ActorEmail:     event.ActorEmail,
+			ImpersonatorId: event.ImpersonatorId,
+			OrganizationId: event.OrganizationId,
+			TargetType:     event.TargetType,
+			TargetId:       event.TargetId,
+			IpAddress:      event.IpAddress,
+			UserAgent:      event.UserAgent,
+			CorrelationId:  event.CorrelationId,
+			PreviousHash:   event.PreviousHash,
+			Hash:           event.Hash,
+		}
+		if json.Valid([]byte(event.Details)) {
+			eventOut.Details = json.RawMessage(event.Details)
+		}
+		eventList = append(eventList, eventOut)
+	}
+
+	jsonResponse := utils.HttpResponse[AuditListOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: AuditListOut{
+			Items:  eventList,
+			Total:  page.Total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// VerifyChainHandler gère la requête pour contrôler l'intégrité du journal d'audit
+// @Summary Contrôle l'intégrité du journal d'audit
+// @Description Recalcule la chaîne d'empreintes et signale la première entrée modifiée, supprimée ou insérée.
+// @Tags Audit
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[AuditVerificationOut]
+// @Router /audit/verify [get]
+func (h *AuditHandler) VerifyChainHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_audit_log")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	verification, err := h.auditService.WithContext(ctx.Request().Context()).VerifyChain()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusInternalServerError,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusInternalServerError, jsonResponse)
+	}
+
+	message := "Le journal d'audit est intègre"
+	if !verification.Valid {
+		message = "Le journal d'audit a été altéré"
+	}
+
+	jsonResponse := utils.HttpResponse[AuditVerificationOut]{
+		Message:   message,
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: AuditVerificationOut{
+			Valid:    verification.Valid,
+			Checked:  verification.Checked,
+			BrokenAt: verification.BrokenAt,
+			Reason:   verification.Reason,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+func toAuditListQuery(payload AuditListIn) (service.AuditListQuery, error) {
+	listQuery := service.AuditListQuery{
+		Action:         payload.Action,
+		Outcome:        payload.Outcome,
+		ActorId:        payload.ActorId,
+		TargetId:       payload.TargetId,
+		OrganizationId: payload.OrganizationId,
+		CorrelationId:  payload.CorrelationId,
+		IpAddress:      payload.IpAddress,
+		Limit:          payload.Limit,
+		Offset:         payload.Offset,
+	}
+
+	var err error
+	if listQuery.From, err = parseDateParam(payload.From); err != nil {
+		return service.AuditListQuery{}, fmt.Errorf("from doit être une date RFC3339 ou AAAA-MM-JJ")
+	}
+	if listQuery.To, err = parseDateParam(payload.To); err != nil {
+		return service.AuditListQuery{}, fmt.Errorf("to doit être une date RFC3339 ou AAAA-MM-JJ")
+	}
+	return listQuery, nil
+}
+
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "oneof":
+		return fieldName + " must be one of " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
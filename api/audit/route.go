@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAuditRoutes(apiGroup *echo.Group, handler *AuditHandler, guard *middlewares.Guard) {
+	//Admin method
+	apiGroup.GET("/events", handler.GetEventsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/verify", handler.VerifyChainHandler, guard.IsAdminMiddle, guard.GetPermission)
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditListIn struct {
+	Limit          int    `query:"limit" validate:"min=0,max=200"`
+	Offset         int    `query:"offset" validate:"min=0"`
+	Action         string `query:"action" validate:"max=80"`
+	Outcome        string `query:"outcome" validate:"omitempty,oneof=success failure"`
+	ActorId        string `query:"actor_id" validate:"max=120"`
+	TargetId       string `query:"target_id" validate:"max=120"`
+	OrganizationId string `query:"organization_id" validate:"max=120"`
+	CorrelationId  string `query:"correlation_id" validate:"max=120"`
+	IpAddress      string `query:"ip_address" validate:"omitempty,ip"`
+	From           string `query:"from"`
+	To             string `query:"to"`
+}
+
+type AuditEventOut struct {
+	Id             string          `json:"id"`
+	Sequence       int64           `json:"sequence"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	Action         string          `json:"action"`
+	Outcome        string          `json:"outcome"`
+	ActorId        string          `json:"actor_id,omitempty"`
+	ActorEmail     string          `json:"actor_email,omitempty"`
+	ImpersonatorId string          `json:"impersonator_id,omitempty"`
+	OrganizationId string          `json:"organization_id,omitempty"`
+	TargetType     string          `json:"target_type"`
+	TargetId       string          `json:"target_id,omitempty"`
+	IpAddress      string          `json:"ip_address"`
+	UserAgent      string          `json:"user_agent"`
+	CorrelationId  string          `json:"correlation_id"`
+	Details        json.RawMessage `json:"details,omitempty"`
+	PreviousHash   string          `json:"previous_hash"`
+	Hash           string          `json:"hash"`
+}
+
+type AuditListOut struct {
+	Items  []AuditEventOut `json:"items"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+type AuditVerificationOut struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"auth/middlewares"
+	"auth/model"
+	"auth/repository"
+	"auth/service"
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupAudit config Audit
+func SetupAudit(apiGroup *echo.Group, db *gorm.DB, guard *middlewares.Guard) {
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := NewAuditHandler(auditService)
+
+	// Les routes sensibles tracent leurs évènements dans le journal d'audit
+	guard.RegisterAuditRecorder(func(ctx context.Context, event model.AuditEvent) {
+		auditService.WithContext(ctx).Record(event)
+	})
+
+	auditGroup := apiGroup.Group("/audit")
+	RegisterAuditRoutes(auditGroup, auditHandler, guard)
+}
@@ -4,6 +4,7 @@ import (
 	"auth/api/users"
 	"auth/service"
 	"auth/utils"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -26,9 +27,10 @@ func NewAuthenticationHandler(authService *service.AuthenticationService) *Authe
 // LoginHandler authentifier un utlisateur
 // @Summary Authentifier un utlisateur
 // @Description Authentifier un utlisateur
+// @Description Avec la double authentification, le code est envoyé par email et la réponse porte la session à vérifier sur /auth/two_factor_verification.
 // @Tags Authentications
 // @Param user body AuthIn true "Détails de l'utilisateur"
-// @Success 201 {object} utils.HttpResponse[AuthResponse]
+// @Success 201 {object} utils.HttpResponse[AuthResponse[AuthOut]]
 // @Produce json
 // @Router /auth/login [post]
 func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
@@ -67,7 +69,10 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	modeAuth, err := h.authService.GetAuthMode(payload.Username)
+	// La cible reste le nom d'utilisateur saisi tant que l'authentification n'a pas abouti
+	utils.SetAuditTarget(ctx, payload.Username)
+
+	modeAuth, err := h.authService.WithContext(ctx.Request().Context()).GetAuthMode(payload.Username)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[map[string]interface{}]{
 			Message:   "Nom d'utilisateur ou mot de passe incorrect",
@@ -82,17 +87,17 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 	}
 
 	var jsonResponse any
-	var errObj any
+	var errObj error
 
 	switch modeAuth {
 	case "two_factor_auth":
-		authResponse, errAuth := h.authService.AuthByOtp(payload.Username, payload.Password)
+		authResponse, errAuth := h.authService.WithContext(ctx.Request().Context()).AuthByOtp(payload.Username, payload.Password)
 		if errAuth != nil {
 			errObj = errAuth
 		}
 
 		jsonResponse = utils.HttpResponse[AuthResponse[service.OtpResponse]]{
-			Message:   "Un code OTP à été envoyé ce sur numéro",
+			Message:   "Un code de vérification est requis pour terminer la connexion",
 			Success:   true,
 			CodeError: http.StatusOK,
 			Data: AuthResponse[service.OtpResponse]{
@@ -101,7 +106,7 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 				UseOTP:          true,
 				Content: service.OtpResponse{
 					SessionId: authResponse.SessionId,
-					Code:      authResponse.Code,
+					Method:    authResponse.Method,
 					IsUsed:    authResponse.IsUsed,
 					ExpireHas: authResponse.ExpireHas,
 				},
@@ -109,9 +114,11 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 		}
 		break
 	case "basic_auth":
-		authResponse, errAuth := h.authService.Login(payload.Username, payload.Password)
+		authResponse, errAuth := h.authService.WithContext(ctx.Request().Context()).Login(payload.Username, payload.Password, clientInfo(ctx))
 		if errAuth != nil {
 			errObj = errAuth
+		} else {
+			utils.SetAuditTarget(ctx, authResponse.UserId)
 		}
 
 		jsonResponse = utils.HttpResponse[AuthResponse[AuthOut]]{
@@ -127,6 +134,7 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 					Name:  authResponse.Name,
 					Email: authResponse.Email,
 					Role:  authResponse.Role,
+					OrgId: authResponse.OrgId,
 					Token: Token{
 						AccessToken:  authResponse.Token.AccessToken,
 						RefreshToken: authResponse.Token.RefreshToken,
@@ -139,13 +147,7 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 	}
 
 	if errObj != nil {
-		jsonResponse = utils.HttpResponse[any]{
-			Message:   err.Error(),
-			Success:   false,
-			CodeError: http.StatusBadRequest,
-			Data:      nil,
-		}
-		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		return authErrorResponse(ctx, errObj)
 	}
 
 	return ctx.JSON(http.StatusOK, jsonResponse)
@@ -155,10 +157,10 @@ func (h *AuthenticationHandler) LoginHandler(ctx echo.Context) error {
 // @Summary Authentifier un utlisateur
 // @Description Authentifier un utlisateur
 // @Tags Authentications
-// @Param user body AuthIn true "Détails de l'utilisateur"
+// @Param user body TwoFactorIn true "Session et code reçu par email"
 // @Success 201 {object} utils.HttpResponse[AuthOut]
 // @Produce json
-// @Router /auth/login [post]
+// @Router /auth/two_factor_verification [post]
 func (h *AuthenticationHandler) VerifyTwoFactorCredentialHandler(ctx echo.Context) error {
 
 	var payload TwoFactorIn
@@ -195,19 +197,13 @@ func (h *AuthenticationHandler) VerifyTwoFactorCredentialHandler(ctx echo.Contex
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	response, err := h.authService.VerifyOtpCode(payload.SessionId, payload.Otp)
+	response, err := h.authService.WithContext(ctx.Request().Context()).VerifyOtpCode(payload.SessionId, payload.Otp, clientInfo(ctx))
 	if err != nil {
-		jsonResponse := utils.HttpResponse[map[string]interface{}]{
-			Message:   err.Error(),
-			Success:   false,
-			CodeError: http.StatusBadRequest,
-			Data:      nil,
-		}
-
-		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		return authErrorResponse(ctx, err)
 	}
+	utils.SetAuditTarget(ctx, response.UserId)
 
-	userInfo, _ := h.authService.UserProfil(response.UserId)
+	userInfo, _ := h.authService.WithContext(ctx.Request().Context()).UserProfil(response.UserId)
 
 	jsonResponse := utils.HttpResponse[AuthResponse[AuthOut]]{
 		Message:   "Connexion succès",
@@ -222,6 +218,7 @@ func (h *AuthenticationHandler) VerifyTwoFactorCredentialHandler(ctx echo.Contex
 				Name:  response.Name,
 				Email: response.Email,
 				Role:  response.Role,
+				OrgId: response.OrgId,
 				Token: Token{
 					AccessToken:  response.Token.AccessToken,
 					RefreshToken: response.Token.RefreshToken,
@@ -256,7 +253,7 @@ func (h *AuthenticationHandler) UserProfilHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	userProfil, err := h.authService.UserProfil(fmt.Sprintf("%v", userId))
+	userProfil, err := h.authService.WithContext(ctx.Request().Context()).UserProfil(fmt.Sprintf("%v", userId))
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   err.Error(),
@@ -299,15 +296,10 @@ func (h *AuthenticationHandler) RefreshTokenHandler(ctx echo.Context) error {
 
 	userId := ctx.Get("userId")
 
-	authResponse, err := h.authService.RefreshToken(fmt.Sprintf("%s", userId))
+	authResponse, err := h.authService.WithContext(ctx.Request().Context()).RefreshToken(fmt.Sprintf("%s", userId), utils.GetOrganizationId(ctx),
+		utils.GetSessionId(ctx), utils.ExtractToken(ctx.Request().Header.Get("Authorization")))
 	if err != nil {
-		jsonResponse := utils.HttpResponse[any]{
-			Message:   err.Error(),
-			Success:   false,
-			CodeError: http.StatusBadRequest,
-			Data:      nil,
-		}
-		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		return authErrorResponse(ctx, err)
 	}
 
 	jsonResponse := utils.HttpResponse[RefreshTokenOut]{
@@ -315,7 +307,8 @@ func (h *AuthenticationHandler) RefreshTokenHandler(ctx echo.Context) error {
 		Success:   true,
 		CodeError: http.StatusAccepted,
 		Data: RefreshTokenOut{
-			Id: authResponse.UserId,
+			Id:    authResponse.UserId,
+			OrgId: authResponse.OrgId,
 			Token: Token{
 				AccessToken:  authResponse.Token.AccessToken,
 				RefreshToken: authResponse.Token.RefreshToken,
@@ -334,7 +327,7 @@ func (h *AuthenticationHandler) RefreshTokenHandler(ctx echo.Context) error {
 // @Router /auth/forget_password [put]
 func (h *AuthenticationHandler) ForgetPasswordHandler(ctx echo.Context) error {
 
-	h.authService.ForgetPassword("")
+	h.authService.WithContext(ctx.Request().Context()).ForgetPassword("")
 	return nil
 }
 
@@ -397,7 +390,8 @@ func (h *AuthenticationHandler) ResetPasswordHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	restPasswordResponse, err := h.authService.ChangePassword(fmt.Sprintf("%s", userId), payload.NewPassword)
+	restPasswordResponse, err := h.authService.WithContext(ctx.Request().Context()).ChangePassword(
+		fmt.Sprintf("%s", userId), utils.GetOrganizationId(ctx), utils.GetSessionId(ctx), payload.NewPassword)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   err.Error(),
@@ -417,6 +411,7 @@ func (h *AuthenticationHandler) ResetPasswordHandler(ctx echo.Context) error {
 			Name:  restPasswordResponse.Name,
 			Email: restPasswordResponse.Email,
 			Role:  restPasswordResponse.Role,
+			OrgId: restPasswordResponse.OrgId,
 			Token: Token{
 				AccessToken:  restPasswordResponse.Token.AccessToken,
 				RefreshToken: restPasswordResponse.Token.RefreshToken,
@@ -427,33 +422,90 @@ func (h *AuthenticationHandler) ResetPasswordHandler(ctx echo.Context) error {
 	return ctx.JSON(http.StatusAccepted, jsonResponse)
 }
 
-// InitPasswordHandler Initialisation du mot de passe utilisateur
-// @Summary Initialisation du mot de passe utilisateur
-// @Description Initialisation du mot de passe utilisateur
+// RenewPasswordHandler Renouveler un mot de passe expiré
+// @Summary Renouveler un mot de passe expiré
+// @Description Sans session : l'ancien mot de passe prouve l'identité, puis le compte redevient actif.
+// @Description L'utilisateur se connecte ensuite avec son nouveau mot de passe.
 // @Tags Authentications
-// @securityDefinitions.apikey BearerAuth
-// @in header
-// @name Authorization
-// @Param init_password body InitUserPasswordIn true "Body data"
-// @Success 202 {object} utils.HttpResponse[any]
+// @Accept json
 // @Produce json
-// @Router /auth/init_password [put]
-func (h *AuthenticationHandler) InitPasswordHandler(ctx echo.Context) error {
+// @Param renew_password body RenewPasswordIn true "Identifiants et nouveau mot de passe"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /auth/renew_password [put]
+func (h *AuthenticationHandler) RenewPasswordHandler(ctx echo.Context) error {
 
-	var payload InitUserPasswordIn
+	var payload RenewPasswordIn
 
-	err := utils.VerifyPermission(ctx, "init_user_password")
-	if err != nil {
+	if err := ctx.Bind(&payload); err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Message:   "Données JSON invalides",
 			Success:   false,
-			CodeError: http.StatusUnauthorized,
+			CodeError: http.StatusBadRequest,
 			Data:      nil,
 		}
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	if err = ctx.Bind(&payload); err != nil {
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Validation failed",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	if payload.OldPassword == payload.NewPassword {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous devez saisir un mot de passe different de l'actuel",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err := h.authService.WithContext(ctx.Request().Context()).RenewExpiredPassword(payload.Username, payload.OldPassword, payload.NewPassword)
+	if err != nil {
+		return authErrorResponse(ctx, err)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Le mot de passe a été renouvelé, vous pouvez vous connecter",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// SwitchOrganizationHandler Changer d'organisation active
+// @Summary Changer d'organisation active
+// @Description Génère de nouveaux tokens pour une autre organisation de l'utilisateur
+// @Tags Authentications
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Param switch_organization body SwitchOrganizationIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[AuthOut]
+// @Produce json
+// @Router /auth/switch_organization [put]
+func (h *AuthenticationHandler) SwitchOrganizationHandler(ctx echo.Context) error {
+
+	userId := ctx.Get("userId")
+
+	var payload SwitchOrganizationIn
+
+	if err := ctx.Bind(&payload); err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   "Données JSON invalides",
 			Success:   false,
@@ -485,25 +537,137 @@ func (h *AuthenticationHandler) InitPasswordHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	err = h.authService.InitPassword(payload.UserId, payload.Password)
+	authResponse, err := h.authService.WithContext(ctx.Request().Context()).SwitchOrganization(fmt.Sprintf("%s", userId), payload.OrganizationId, utils.GetSessionId(ctx))
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Nous avons rencontré un problème durant l'initialisation du mot de passe",
+			Message:   err.Error(),
 			Success:   false,
-			CodeError: http.StatusBadRequest,
+			CodeError: http.StatusForbidden,
 			Data:      nil,
 		}
+		return ctx.JSON(http.StatusForbidden, jsonResponse)
+	}
 
+	jsonResponse := utils.HttpResponse[AuthOut]{
+		Message:   "Organisation active mise à jour",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data: AuthOut{
+			Id:    authResponse.UserId,
+			Name:  authResponse.Name,
+			Email: authResponse.Email,
+			Role:  authResponse.Role,
+			OrgId: authResponse.OrgId,
+			Token: Token{
+				AccessToken:  authResponse.Token.AccessToken,
+				RefreshToken: authResponse.Token.RefreshToken,
+				ExpiresAt:    authResponse.Token.ExpiresAt,
+			},
+		},
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// UserOrganizationsHandler Lister les organisations de l'utilisateur
+// @Summary Lister les organisations de l'utilisateur
+// @Description Lister les organisations dont l'utilisateur connecté est membre
+// @Tags Authentications
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Success 200 {object} utils.HttpResponse[[]OrganizationOut]
+// @Produce json
+// @Router /auth/organizations [get]
+func (h *AuthenticationHandler) UserOrganizationsHandler(ctx echo.Context) error {
+
+	userId := ctx.Get("userId")
+
+	organizations, err := h.authService.WithContext(ctx.Request().Context()).UserOrganizations(fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	jsonResponse := utils.HttpResponse[any]{
-		Message:   "Le nouveau mot de passe de l'utilisateur à bien été initialisé",
+	activeOrgId := utils.GetOrganizationId(ctx)
+	organizationList := []OrganizationOut{}
+
+	for i := 0; i < len(organizations); i++ {
+		organization := organizations[i]
+		organizationList = append(organizationList, OrganizationOut{
+			Id:       organization.Id,
+			Name:     organization.Name,
+			Slug:     organization.Slug,
+			Describe: organization.Describe,
+			IsActive: organization.Id == activeOrgId,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[[]OrganizationOut]{
+		Message:   "Données récupérée avec succès",
 		Success:   true,
-		CodeError: http.StatusAccepted,
+		CodeError: http.StatusOK,
+		Data:      organizationList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// clientInfo décrit l'appareil à l'origine de la requête, enregistré avec la session
+func clientInfo(ctx echo.Context) service.ClientInfo {
+	return service.ClientInfo{
+		IpAddress: ctx.RealIP(),
+		UserAgent: ctx.Request().UserAgent(),
+	}
+}
+
+// authErrorResponse répond 403 avec un code distinct lorsque l'état du compte bloque
+// l'authentification ou qu'un second facteur doit être enrôlé, et 400 pour les autres erreurs
+func authErrorResponse(ctx echo.Context, err error) error {
+	var stateErr *service.AccountStateError
+	if errors.As(err, &stateErr) {
+		data := map[string]interface{}{
+			"code":  stateErr.ErrorCode(),
+			"state": stateErr.State,
+		}
+		if stateErr.State == service.AccountStateLocked {
+			data["locked_until"] = stateErr.LockedUntil
+		}
+
+		jsonResponse := utils.HttpResponse[map[string]interface{}]{
+			Message:   stateErr.Error(),
+			Success:   false,
+			CodeError: http.StatusForbidden,
+			Data:      data,
+		}
+		return ctx.JSON(http.StatusForbidden, jsonResponse)
+	}
+
+	var enrollmentErr *service.TwoFactorEnrollmentError
+	if errors.As(err, &enrollmentErr) {
+		jsonResponse := utils.HttpResponse[map[string]interface{}]{
+			Message:   enrollmentErr.Error(),
+			Success:   false,
+			CodeError: http.StatusForbidden,
+			Data: map[string]interface{}{
+				"code":             enrollmentErr.ErrorCode(),
+				"enrollment_token": enrollmentErr.Token,
+				"expires_at":       enrollmentErr.ExpiresAt,
+			},
+		}
+		return ctx.JSON(http.StatusForbidden, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   err.Error(),
+		Success:   false,
+		CodeError: http.StatusBadRequest,
 		Data:      nil,
 	}
-	return ctx.JSON(http.StatusAccepted, jsonResponse)
+	return ctx.JSON(http.StatusBadRequest, jsonResponse)
 }
 
 // Fonction pour formater les erreurs de validation
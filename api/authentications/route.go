@@ -6,12 +6,14 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterAuthRoutes(apiGroup *echo.Group, handler *AuthenticationHandler) {
-	apiGroup.POST("/login", handler.LoginHandler)                                                                     //OK
-	apiGroup.PUT("/forget_password", handler.ForgetPasswordHandler)                                                   //NOK
-	apiGroup.GET("/me", handler.UserProfilHandler, middlewares.IsAuthorizedMiddle)                                    //OK
-	apiGroup.POST("/two_factor_verification", handler.VerifyTwoFactorCredentialHandler)                               //OK
-	apiGroup.PUT("/reset_password", handler.ResetPasswordHandler, middlewares.IsAuthorizedMiddle)                     //OK
-	apiGroup.PUT("/refresh_token", handler.RefreshTokenHandler, middlewares.IsRefreshTokenMiddle)                     //OK
-	apiGroup.PUT("/init_password", handler.InitPasswordHandler, middlewares.IsAdminMiddle, middlewares.GetPermission) //OK
+func RegisterAuthRoutes(apiGroup *echo.Group, handler *AuthenticationHandler, guard *middlewares.Guard) {
+	apiGroup.POST("/login", handler.LoginHandler, guard.AuditMiddle("auth.login", ""))                                                                                          //OK
+	apiGroup.PUT("/forget_password", handler.ForgetPasswordHandler)                                                                                                             //NOK
+	apiGroup.GET("/me", handler.UserProfilHandler, guard.IsAuthorizedMiddle)                                                                                                    //OK
+	apiGroup.POST("/two_factor_verification", handler.VerifyTwoFactorCredentialHandler, guard.AuditMiddle("auth.otp_verification", ""))                                         //OK
+	apiGroup.PUT("/reset_password", handler.ResetPasswordHandler, guard.AuditMiddle("auth.password_change", ""), guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle) //OK
+	apiGroup.PUT("/refresh_token", handler.RefreshTokenHandler, guard.IsRefreshTokenMiddle)                                                                                     //OK
+	apiGroup.GET("/organizations", handler.UserOrganizationsHandler, guard.IsAuthorizedMiddle)
+	apiGroup.PUT("/switch_organization", handler.SwitchOrganizationHandler, guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle)
+	apiGroup.PUT("/renew_password", handler.RenewPasswordHandler, guard.AuditMiddle("auth.password_renew", ""))
 }
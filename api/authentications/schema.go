@@ -7,6 +7,7 @@ type AuthOut struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
 	Role  string `json:"role"`
+	OrgId string `json:"org_id"`
 	Token Token  `json:"token"`
 }
 
@@ -29,17 +30,31 @@ type TwoFactorIn struct {
 
 type RefreshTokenOut struct {
 	Id    string `json:"id"`
+	OrgId string `json:"org_id"`
 	Token Token  `json:"token"`
 }
 
+type SwitchOrganizationIn struct {
+	OrganizationId string `json:"organization_id" validate:"required,uuid"`
+}
+
+type OrganizationOut struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Describe string `json:"describe"`
+	IsActive bool   `json:"is_active"`
+}
+
 type ResetPasswordIn struct {
 	OldPassword string `json:"old_password" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required"`
 }
 
-type InitUserPasswordIn struct {
-	UserId   string `json:"user_id" validate:"required,uuid"`
-	Password string `json:"password" validate:"required"`
+type RenewPasswordIn struct {
+	Username    string `json:"username" validate:"required"`
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
 }
 
 type Token struct {
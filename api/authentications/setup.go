@@ -1,20 +1,40 @@
 package authentications
 
 import (
+	"auth/config"
+	"auth/middlewares"
+	"auth/model"
 	"auth/repository"
 	"auth/service"
+	"context"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 // SetupAuthentication config User
-func SetupAuthentication(apiGroup *echo.Group, db *gorm.DB) {
+func SetupAuthentication(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
 	userRepo := repository.NewUserRepository(db)
 	roleRepo := repository.NewRoleRepository(db)
 	otpRepo := repository.NewOtpRepository(db)
-	userService := service.NewAuthenticationService(userRepo, roleRepo, otpRepo)
+	orgRepo := repository.NewOrganizationRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	attributeRepo := repository.NewAttributeRepository(db)
+	methodRepo := repository.NewTwoFactorMethodRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	loginRepo := repository.NewLoginEventRepository(db)
+	twoFactorService := service.NewTwoFactorService(userRepo, roleRepo, methodRepo, otpRepo, service.NewNotifier(appConfig.Smtp),
+		appConfig.Auth.OtpLength, appConfig.Auth.OtpTTL)
+	loginHistoryService := service.NewLoginHistoryService(loginRepo, userRepo, service.NewNotifier(appConfig.Smtp),
+		service.OpenIpLocator(appConfig.LoginAlert.GeoIpDatabase), appConfig.LoginAlert.ReportUrl, appConfig.LoginAlert.ReportTTL,
+		appConfig.Auth.Security())
+	userService := service.NewAuthenticationService(userRepo, roleRepo, orgRepo, groupRepo, attributeRepo, sessionRepo, twoFactorService, loginHistoryService,
+		appConfig.Auth.Security(), appConfig.Auth.AccessTokenTTL, appConfig.Auth.RefreshTokenTTL)
 	userHandler := NewAuthenticationHandler(userService)
 
+	guard.RegisterTokenChecker(func(ctx context.Context, claims *model.Claims) error {
+		return userService.WithContext(ctx).CheckToken(claims)
+	})
+
 	authGroup := apiGroup.Group("/auth")
-	RegisterAuthRoutes(authGroup, userHandler)
+	RegisterAuthRoutes(authGroup, userHandler, guard)
 }
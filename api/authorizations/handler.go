@@ -0,0 +1,629 @@
+package authorizations
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// AuthorizationHandler gère les requêtes liées aux décisions d'autorisation
+type AuthorizationHandler struct {
+	authorizationService *service.AuthorizationService
+}
+
+// NewAuthorizationHandler crée une nouvelle instance de AuthorizationHandler
+func NewAuthorizationHandler(authorizationService *service.AuthorizationService) *AuthorizationHandler {
+	return &AuthorizationHandler{
+		authorizationService: authorizationService,
+	}
+}
+
+// tenantService limite le service aux politiques de l'organisation active du token
+func (h *AuthorizationHandler) tenantService(ctx echo.Context) *service.AuthorizationService {
+	return h.authorizationService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// CheckHandler évalue une demande d'autorisation
+// @Summary Décision d'autorisation
+// @Description Évalue les politiques actives de l'organisation active pour un sujet, une ressource, une action et un environnement.
+// @Description Sans sujet, les attributs du token appelant sont utilisés.
+// @Description Le rôle, les rôles et les permissions du sujet sont chargés depuis ses rôles enregistrés (subject.id et subject.org_id) ; ceux fournis dans la requête sont ignorés.
+// @Description À défaut de politique applicable, l'action est accordée si ces rôles donnent la permission <action>_<type>.
+// @Tags Authorizations
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Accept json
+// @Produce json
+// @Param check body CheckIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[service.AuthorizationDecision]
+// @Router /authz/check [post]
+func (h *AuthorizationHandler) CheckHandler(ctx echo.Context) error {
+
+	var payload CheckIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	subject := payload.Subject
+	if len(subject) == 0 {
+		subject = subjectFromClaims(ctx)
+	}
+
+	environment := payload.Environment
+	if environment == nil {
+		environment = map[string]interface{}{}
+	}
+	if _, ok := environment["ip"]; !ok {
+		environment["ip"] = ctx.RealIP()
+	}
+
+	decision, err := h.tenantService(ctx).Check(service.AuthorizationRequest{
+		Subject:     subject,
+		Resource:    payload.Resource,
+		Action:      payload.Action,
+		Environment: environment,
+	})
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusInternalServerError,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusInternalServerError, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[service.AuthorizationDecision]{
+		Message:   "Décision calculée",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      decision,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// BatchCheckHandler évalue plusieurs autorisations en une seule requête
+// @Summary Décisions d'autorisation groupées
+// @Description Évalue une liste de couples (action, ressource) pour l'utilisateur connecté.
+// @Description Une action est accordée par une politique active ou, à défaut, par la permission <action>_<type> des rôles enregistrés de l'utilisateur dans l'organisation active.
+// @Tags Authorizations
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Accept json
+// @Produce json
+// @Param checks body BatchCheckIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[[]BatchDecisionOut]
+// @Router /authz/check/batch [post]
+func (h *AuthorizationHandler) BatchCheckHandler(ctx echo.Context) error {
+
+	var payload BatchCheckIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	environment := payload.Environment
+	if environment == nil {
+		environment = map[string]interface{}{}
+	}
+	if _, ok := environment["ip"]; !ok {
+		environment["ip"] = ctx.RealIP()
+	}
+
+	var requests []service.AuthorizationRequest
+	for i := 0; i < len(payload.Checks); i++ {
+		requests = append(requests, service.AuthorizationRequest{
+			Resource:    payload.Checks[i].Resource,
+			Action:      payload.Checks[i].Action,
+			Environment: environment,
+		})
+	}
+
+	decisions, err := h.tenantService(ctx).CheckBatch(subjectFromClaims(ctx), requests)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusInternalServerError,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusInternalServerError, jsonResponse)
+	}
+
+	decisionList := []BatchDecisionOut{}
+	for i := 0; i < len(decisions); i++ {
+		decisionList = append(decisionList, BatchDecisionOut{
+			Action:          payload.Checks[i].Action,
+			Resource:        payload.Checks[i].Resource,
+			Allowed:         decisions[i].Allowed,
+			Decision:        decisions[i].Decision,
+			Reasons:         decisions[i].Reasons,
+			MatchedPolicies: decisions[i].MatchedPolicies,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[[]BatchDecisionOut]{
+		Message:   "Décisions calculées",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      decisionList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetEffectivePermissionsHandler retourne les permissions effectives de l'utilisateur connecté
+// @Summary Permissions effectives
+// @Description Retourne les rôles portés par le token dans l'organisation active et les permissions qui en découlent.
+// @Tags Authorizations
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[EffectivePermissionsOut]
+// @Router /authz/permissions [get]
+func (h *AuthorizationHandler) GetEffectivePermissionsHandler(ctx echo.Context) error {
+
+	claims, ok := ctx.Get("claims").(*model.Claims)
+	if !ok {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'etes pas autorisé à executer cette route",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnauthorized, jsonResponse)
+	}
+
+	roles := claims.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	jsonResponse := utils.HttpResponse[EffectivePermissionsOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: EffectivePermissionsOut{
+			UserId:      claims.Id,
+			OrgId:       claims.OrgId,
+			Role:        claims.Role,
+			Roles:       roles,
+			Permissions: permissionsFromContext(ctx),
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetPoliciesHandler liste les politiques actives
+// @Summary Liste les politiques actives
+// @Description Liste la version active de chaque politique d'accès de l'organisation active.
+// @Tags Authorizations
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]PolicyOut]
+// @Router /authz/policies [get]
+func (h *AuthorizationHandler) GetPoliciesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_policies")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	policies, err := h.tenantService(ctx).GetPolicies()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucune politique trouvée",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	policyList := []PolicyOut{}
+	for i := 0; i < len(policies); i++ {
+		policyList = append(policyList, toPolicyOut(policies[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]PolicyOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      policyList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetPolicyHandler récupère la version active d'une politique
+// @Summary Récupère une politique
+// @Description Récupère la version active d'une politique par son nom.
+// @Tags Authorizations
+// @Produce json
+// @Param name path string true "Nom de la politique"
+// @Success 200 {object} utils.HttpResponse[PolicyOut]
+// @Router /authz/policies/{name} [get]
+func (h *AuthorizationHandler) GetPolicyHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_policies")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	policy, err := h.tenantService(ctx).GetPolicy(ctx.Param("name"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[PolicyOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toPolicyOut(policy),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetPolicyVersionsHandler liste l'historique des versions d'une politique
+// @Summary Historique d'une politique
+// @Description Liste toutes les versions d'une politique, la plus récente en premier.
+// @Tags Authorizations
+// @Produce json
+// @Param name path string true "Nom de la politique"
+// @Success 200 {object} utils.HttpResponse[[]PolicyOut]
+// @Router /authz/policies/{name}/versions [get]
+func (h *AuthorizationHandler) GetPolicyVersionsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_policies")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	policies, err := h.tenantService(ctx).GetPolicyVersions(ctx.Param("name"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	policyList := []PolicyOut{}
+	for i := 0; i < len(policies); i++ {
+		policyList = append(policyList, toPolicyOut(policies[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]PolicyOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      policyList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// CreatePolicyHandler crée une politique d'accès
+// @Summary Crée une politique
+// @Description Crée la première version d'une politique d'accès.
+// @Tags Authorizations
+// @Accept json
+// @Produce json
+// @Param policy body PolicyIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[PolicyOut]
+// @Router /authz/policies [post]
+func (h *AuthorizationHandler) CreatePolicyHandler(ctx echo.Context) error {
+
+	userId := ctx.Get("userId")
+
+	err := utils.VerifyPermission(ctx, "manage_policies")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload PolicyIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	utils.SetAuditTarget(ctx, payload.Name)
+	policy, err := h.tenantService(ctx).CreatePolicy(service.Policy(payload), fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[PolicyOut]{
+		Message:   "Policy has been created",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      toPolicyOut(policy),
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// UpdatePolicyHandler publie une nouvelle version d'une politique
+// @Summary Nouvelle version d'une politique
+// @Description Enregistre une nouvelle version active ; les versions précédentes restent consultables.
+// @Tags Authorizations
+// @Accept json
+// @Produce json
+// @Param name path string true "Nom de la politique"
+// @Param policy body UpdatePolicyIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[PolicyOut]
+// @Router /authz/policies/{name} [put]
+func (h *AuthorizationHandler) UpdatePolicyHandler(ctx echo.Context) error {
+
+	userId := ctx.Get("userId")
+
+	err := utils.VerifyPermission(ctx, "manage_policies")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload UpdatePolicyIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	policy, err := h.tenantService(ctx).UpdatePolicy(service.Policy{
+		Name:     ctx.Param("name"),
+		Describe: payload.Describe,
+		Document: payload.Document,
+	}, fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[PolicyOut]{
+		Message:   "Nouvelle version de la politique publiée",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toPolicyOut(policy),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// DeactivatePolicyHandler désactive une politique
+// @Summary Désactive une politique
+// @Description Retire une politique de l'évaluation en conservant son historique.
+// @Tags Authorizations
+// @Param name path string true "Nom de la politique"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /authz/policies/{name} [delete]
+func (h *AuthorizationHandler) DeactivatePolicyHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_policies")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).DeactivatePolicy(ctx.Param("name"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Policy has been deactivated",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// subjectFromClaims construit les attributs du sujet à partir du token de la requête
+func subjectFromClaims(ctx echo.Context) map[string]interface{} {
+	claims, ok := ctx.Get("claims").(*model.Claims)
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	// Le rôle, les rôles et les permissions sont chargés par le service à partir de id et org_id
+	return map[string]interface{}{
+		"id":     claims.Id,
+		"email":  claims.Subject,
+		"org_id": claims.OrgId,
+	}
+}
+
+// permissionsFromContext retourne les permissions résolues par le middleware GetPermission
+func permissionsFromContext(ctx echo.Context) []string {
+	roles, ok := ctx.Get("roles").(utils.JsonRoleItem)
+	if !ok || roles.Permissions == nil {
+		return []string{}
+	}
+	return roles.Permissions
+}
+
+func toPolicyOut(policy service.PolicyDetail) PolicyOut {
+	return PolicyOut{
+		Id:             policy.Policy.Id,
+		OrganizationId: policy.Policy.OrganizationId,
+		Name:           policy.Policy.Name,
+		Version:        policy.Policy.Version,
+		Describe:       policy.Policy.Describe,
+		IsActive:       policy.Policy.IsActive,
+		Document:       policy.Document,
+		CreatedBy:      policy.Policy.CreatedBy,
+		CreatedAt:      policy.Policy.CreatedAt,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "oneof":
+		return fieldName + " must be one of " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
@@ -0,0 +1,21 @@
+package authorizations
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAuthorizationRoutes(apiGroup *echo.Group, handler *AuthorizationHandler, guard *middlewares.Guard) {
+	apiGroup.POST("/check", handler.CheckHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.POST("/check/batch", handler.BatchCheckHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.GET("/permissions", handler.GetEffectivePermissionsHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+
+	//Admin method
+	apiGroup.GET("/policies", handler.GetPoliciesHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.POST("/policies", handler.CreatePolicyHandler, guard.AuditMiddle("policy.create", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("/policies/:name", handler.GetPolicyHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.PUT("/policies/:name", handler.UpdatePolicyHandler, guard.AuditMiddle("policy.update", "name"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/policies/:name", handler.DeactivatePolicyHandler, guard.AuditMiddle("policy.deactivate", "name"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("/policies/:name/versions", handler.GetPolicyVersionsHandler, guard.IsAdminMiddle, guard.GetPermission)
+}
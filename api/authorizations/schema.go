@@ -0,0 +1,63 @@
+package authorizations
+
+import (
+	"auth/service"
+	"time"
+)
+
+type CheckIn struct {
+	Subject     map[string]interface{} `json:"subject"`
+	Resource    map[string]interface{} `json:"resource" validate:"required"`
+	Action      string                 `json:"action" validate:"required"`
+	Environment map[string]interface{} `json:"environment"`
+}
+
+type PolicyIn struct {
+	Name     string                 `json:"name" validate:"required,max=120"`
+	Describe string                 `json:"describe" validate:"max=500"`
+	Document service.PolicyDocument `json:"document" validate:"required"`
+}
+
+type UpdatePolicyIn struct {
+	Describe string                 `json:"describe" validate:"max=500"`
+	Document service.PolicyDocument `json:"document" validate:"required"`
+}
+
+type PolicyOut struct {
+	Id             string                 `json:"id"`
+	OrganizationId string                 `json:"organization_id"`
+	Name           string                 `json:"name"`
+	Version        int                    `json:"version"`
+	Describe       string                 `json:"describe"`
+	IsActive       bool                   `json:"is_active"`
+	Document       service.PolicyDocument `json:"document"`
+	CreatedBy      string                 `json:"created_by"`
+	CreatedAt      time.Time              `json:"create_at,omitempty"`
+}
+
+type BatchCheckItemIn struct {
+	Action   string                 `json:"action" validate:"required"`
+	Resource map[string]interface{} `json:"resource" validate:"required"`
+}
+
+type BatchCheckIn struct {
+	Checks      []BatchCheckItemIn     `json:"checks" validate:"required,min=1,max=100,dive"`
+	Environment map[string]interface{} `json:"environment"`
+}
+
+type BatchDecisionOut struct {
+	Action          string                 `json:"action"`
+	Resource        map[string]interface{} `json:"resource"`
+	Allowed         bool                   `json:"allowed"`
+	Decision        string                 `json:"decision"`
+	Reasons         []string               `json:"reasons"`
+	MatchedPolicies []string               `json:"matched_policies"`
+}
+
+type EffectivePermissionsOut struct {
+	UserId      string   `json:"user_id"`
+	OrgId       string   `json:"org_id"`
+	Role        string   `json:"role"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
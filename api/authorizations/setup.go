@@ -0,0 +1,24 @@
+package authorizations
+
+import (
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupAuthorization config Authorization
+func SetupAuthorization(apiGroup *echo.Group, db *gorm.DB, guard *middlewares.Guard) {
+	policyRepo := repository.NewPolicyRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	authorizationService := service.NewAuthorizationService(policyRepo, userRepo, roleRepo, orgRepo, groupRepo)
+	authorizationHandler := NewAuthorizationHandler(authorizationService)
+
+	authorizationGroup := apiGroup.Group("/authz")
+	RegisterAuthorizationRoutes(authorizationGroup, authorizationHandler, guard)
+}
@@ -1,15 +1,50 @@
 package api
 
 import (
+	"auth/api/attributes"
+	"auth/api/audit"
 	"auth/api/authentications"
+	"auth/api/authorizations"
+	"auth/api/groups"
+	"auth/api/health"
+	"auth/api/impersonations"
+	"auth/api/invitations"
+	"auth/api/logins"
+	"auth/api/organizations"
+	"auth/api/sessions"
+	"auth/api/twofactor"
 	"auth/api/users"
+	"auth/api/webhooks"
+	"auth/config"
+	"auth/middlewares"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func GlobalSetup(ech *echo.Echo, db *gorm.DB) {
+// GlobalSetup installe les routes des modules ; guard porte les middlewares d'authentification
+// partagés, auxquels les modules ajoutent leurs vérifications de token et enregistreurs
+func GlobalSetup(ech *echo.Echo, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
+	health.SetupHealth(ech, db, appConfig)
+
 	apiGroup := ech.Group("/api/v1")
-	users.SetupUser(apiGroup, db)
-	authentications.SetupAuthentication(apiGroup, db)
+	users.SetupUser(apiGroup, db, appConfig, guard)
+	authentications.SetupAuthentication(apiGroup, db, appConfig, guard)
+	organizations.SetupOrganization(apiGroup, db, guard)
+	groups.SetupGroup(apiGroup, db, guard)
+	authorizations.SetupAuthorization(apiGroup, db, guard)
+	impersonations.SetupImpersonation(apiGroup, db, appConfig, guard)
+	invitations.SetupInvitation(apiGroup, db, appConfig, guard)
+	attributes.SetupAttribute(apiGroup, db, guard)
+	twofactor.SetupTwoFactor(apiGroup, db, appConfig, guard)
+	sessions.SetupSession(apiGroup, db, guard)
+	logins.SetupLogin(apiGroup, db, appConfig, guard)
+	audit.SetupAudit(apiGroup, db, guard)
+	webhooks.SetupWebhook(apiGroup, db, appConfig, guard)
+}
+
+// StartBackgroundJobs lance les traitements périodiques
+func StartBackgroundJobs(db *gorm.DB, appConfig config.AppConfig) {
+	users.StartUserPurge(db, appConfig)
+	webhooks.StartWebhookDispatcher(db, appConfig.Webhook)
 }
@@ -0,0 +1,607 @@
+package groups
+
+import (
+	"auth/service"
+	"auth/utils"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// GroupHandler gère les requêtes liées aux groupes d'utilisateurs
+type GroupHandler struct {
+	groupService *service.GroupService
+}
+
+// NewGroupHandler crée une nouvelle instance de GroupHandler
+func NewGroupHandler(groupService *service.GroupService) *GroupHandler {
+	return &GroupHandler{
+		groupService: groupService,
+	}
+}
+
+// tenantService limite le service à l'organisation active du token
+func (h *GroupHandler) tenantService(ctx echo.Context) *service.GroupService {
+	return h.groupService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// CreateGroupHandler gère la requête pour créer un groupe
+// @Summary Crée un nouveau groupe
+// @Description Crée un nouveau groupe dans l'organisation active.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group body GroupIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[GroupOut]
+// @Router /groups [post]
+func (h *GroupHandler) CreateGroupHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload GroupIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	group, err := h.tenantService(ctx).CreateGroup(service.Group(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	utils.SetAuditTarget(ctx, group.Group.Id)
+	jsonResponse := utils.HttpResponse[GroupOut]{
+		Message:   "Group has been created",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      toGroupOut(group),
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// GetAllGroupsHandler gère la requête pour récupérer tous les groupes
+// @Summary Récupère tous les groupes
+// @Description Récupère la liste des groupes de l'organisation active.
+// @Tags Groups
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]GroupOut]
+// @Router /groups [get]
+func (h *GroupHandler) GetAllGroupsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	groups, err := h.tenantService(ctx).GetAllGroups()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucun groupe trouvé",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	groupList := []GroupOut{}
+	for i := 0; i < len(groups); i++ {
+		groupList = append(groupList, toGroupOut(groups[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]GroupOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      groupList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetGroupByIdHandler gère la requête pour récupérer un groupe par son ID
+// @Summary Récupère un groupe par ID
+// @Description Récupère un groupe en fonction de son ID.
+// @Tags Groups
+// @Produce json
+// @Param id path string true "ID du groupe"
+// @Success 200 {object} utils.HttpResponse[GroupOut]
+// @Router /groups/{id} [get]
+func (h *GroupHandler) GetGroupByIdHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	group, err := h.tenantService(ctx).GetGroupById(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[GroupOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toGroupOut(group),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// UpdateGroupHandler gère la requête pour mettre à jour un groupe
+// @Summary Met à jour un groupe
+// @Description Met à jour le nom et la description d'un groupe.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "ID du groupe"
+// @Param group body GroupIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[GroupOut]
+// @Router /groups/{id} [put]
+func (h *GroupHandler) UpdateGroupHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload GroupIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	group, err := h.tenantService(ctx).UpdateGroup(ctx.Param("id"), service.Group(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[GroupOut]{
+		Message:   "Groupe mis à jour avec succès",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toGroupOut(group),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// DeleteGroupHandler gère la requête pour supprimer un groupe
+// @Summary Supprime un groupe
+// @Description Supprime un groupe avec ses adhésions et ses rôles.
+// @Tags Groups
+// @Param id path string true "ID du groupe"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /groups/{id} [delete]
+func (h *GroupHandler) DeleteGroupHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).DeleteGroup(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Group has been deleted",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// GetMembersHandler gère la requête pour lister les membres d'un groupe
+// @Summary Liste les membres d'un groupe
+// @Description Liste les utilisateurs membres d'un groupe.
+// @Tags Groups
+// @Produce json
+// @Param id path string true "ID du groupe"
+// @Success 200 {object} utils.HttpResponse[[]GroupMemberOut]
+// @Router /groups/{id}/members [get]
+func (h *GroupHandler) GetMembersHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	members, err := h.tenantService(ctx).GetMembers(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	memberList := []GroupMemberOut{}
+	for i := 0; i < len(members); i++ {
+		memberList = append(memberList, GroupMemberOut{
+			Id:       members[i].Id,
+			Name:     members[i].Name,
+			Sername:  members[i].Sername,
+			Email:    members[i].Email,
+			Username: members[i].Username,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[[]GroupMemberOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      memberList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// AddMemberHandler gère la requête pour ajouter un membre à un groupe
+// @Summary Ajoute un membre à un groupe
+// @Description Ajoute un utilisateur de l'organisation active à un groupe.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "ID du groupe"
+// @Param member body GroupMemberIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[any]
+// @Router /groups/{id}/members [post]
+func (h *GroupHandler) AddMemberHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload GroupMemberIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).AddMember(ctx.Param("id"), payload.UserId)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Le membre a bien été ajouté au groupe",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// RemoveMemberHandler gère la requête pour retirer un membre d'un groupe
+// @Summary Retire un membre d'un groupe
+// @Description Retire un utilisateur d'un groupe.
+// @Tags Groups
+// @Param id path string true "ID du groupe"
+// @Param userId path string true "ID de l'utilisateur"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /groups/{id}/members/{userId} [delete]
+func (h *GroupHandler) RemoveMemberHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).RemoveMember(ctx.Param("id"), ctx.Param("userId"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Le membre a bien été retiré du groupe",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// AddRoleHandler gère la requête pour attacher un rôle à un groupe
+// @Summary Attache un rôle à un groupe
+// @Description Les membres du groupe héritent des permissions du rôle.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "ID du groupe"
+// @Param role body GroupRoleIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[GroupOut]
+// @Router /groups/{id}/roles [post]
+func (h *GroupHandler) AddRoleHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload GroupRoleIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	group, err := h.tenantService(ctx).AddRole(ctx.Param("id"), payload.Role)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[GroupOut]{
+		Message:   "Le rôle a bien été attribué au groupe",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toGroupOut(group),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// RemoveRoleHandler gère la requête pour détacher un rôle d'un groupe
+// @Summary Détache un rôle d'un groupe
+// @Description Détache un rôle d'un groupe.
+// @Tags Groups
+// @Param id path string true "ID du groupe"
+// @Param role path string true "Rôle à retirer"
+// @Success 202 {object} utils.HttpResponse[GroupOut]
+// @Router /groups/{id}/roles/{role} [delete]
+func (h *GroupHandler) RemoveRoleHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	group, err := h.tenantService(ctx).RemoveRole(ctx.Param("id"), ctx.Param("role"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[GroupOut]{
+		Message:   "Le rôle a bien été retiré du groupe",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toGroupOut(group),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func toGroupOut(group service.GroupDetail) GroupOut {
+	return GroupOut{
+		Id:           group.Group.Id,
+		Name:         group.Group.Name,
+		Describe:     group.Group.Describe,
+		Roles:        group.Roles,
+		MembersCount: len(group.Group.GroupMembers),
+		CreatedAt:    group.Group.CreatedAt,
+		UpdatedAt:    group.Group.UpdatedAt,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "email":
+		return fieldName + " must be a valid email address"
+	case "uuid", "uuid4":
+		return fieldName + " must be a valid UUID"
+	default:
+		return fieldName + " is invalid"
+	}
+}
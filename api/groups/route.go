@@ -0,0 +1,23 @@
+package groups
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterGroupRoutes(apiGroup *echo.Group, handler *GroupHandler, guard *middlewares.Guard) {
+	//Admin method
+	apiGroup.POST("", handler.CreateGroupHandler, guard.AuditMiddle("group.create", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("", handler.GetAllGroupsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:id", handler.GetGroupByIdHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id", handler.UpdateGroupHandler, guard.AuditMiddle("group.update", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id", handler.DeleteGroupHandler, guard.AuditMiddle("group.delete", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+
+	apiGroup.GET("/:id/members", handler.GetMembersHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.POST("/:id/members", handler.AddMemberHandler, guard.AuditMiddle("group.member_add", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id/members/:userId", handler.RemoveMemberHandler, guard.AuditMiddle("group.member_remove", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+
+	apiGroup.POST("/:id/roles", handler.AddRoleHandler, guard.AuditMiddle("group.role_add", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id/roles/:role", handler.RemoveRoleHandler, guard.AuditMiddle("group.role_remove", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
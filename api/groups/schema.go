@@ -0,0 +1,34 @@
+package groups
+
+import "time"
+
+type GroupIn struct {
+	Name     string `json:"name" validate:"required,max=80"`
+	Describe string `json:"describe" validate:"max=500"`
+}
+
+type GroupMemberIn struct {
+	UserId string `json:"user_id" validate:"required,uuid"`
+}
+
+type GroupRoleIn struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type GroupOut struct {
+	Id           string    `json:"id"`
+	Name         string    `json:"name"`
+	Describe     string    `json:"describe"`
+	Roles        []string  `json:"roles"`
+	MembersCount int       `json:"members_count"`
+	CreatedAt    time.Time `json:"create_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
+
+type GroupMemberOut struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Sername  string `json:"sername"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,22 @@
+package groups
+
+import (
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupGroup config Group
+func SetupGroup(apiGroup *echo.Group, db *gorm.DB, guard *middlewares.Guard) {
+	groupRepo := repository.NewGroupRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	groupService := service.NewGroupService(groupRepo, userRepo, roleRepo)
+	groupHandler := NewGroupHandler(groupService)
+
+	groupGroup := apiGroup.Group("/groups")
+	RegisterGroupRoutes(groupGroup, groupHandler, guard)
+}
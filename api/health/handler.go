@@ -0,0 +1,108 @@
+package health
+
+import (
+	"auth/service"
+	"auth/utils"
+	"auth/version"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// readinessTimeout borne la durée des vérifications de disponibilité
+const readinessTimeout = 3 * time.Second
+
+// HealthHandler gère les sondes de l'orchestrateur
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler crée une nouvelle instance de HealthHandler
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// LivenessHandler indique que le processus répond
+// @Summary Sonde de vie
+// @Description Répond 200 tant que le serveur traite des requêtes ; aucune dépendance n'est vérifiée.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[HealthOut]
+// @Router /healthz [get]
+func (h *HealthHandler) LivenessHandler(ctx echo.Context) error {
+	jsonResponse := utils.HttpResponse[HealthOut]{
+		Message:   "Service en vie",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      HealthOut{Status: service.HealthStatusOk},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// ReadinessHandler indique si le service peut recevoir du trafic
+// @Summary Sonde de disponibilité
+// @Description Vérifie la connexion à la base de données, l'absence de migration en attente et la clé de signature des tokens.
+// @Description Répond 503 si une vérification échoue, avec le détail de chacune.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[ReadinessOut]
+// @Failure 503 {object} utils.HttpResponse[ReadinessOut]
+// @Router /readyz [get]
+func (h *HealthHandler) ReadinessHandler(ctx echo.Context) error {
+	checkCtx, cancel := context.WithTimeout(ctx.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	report := h.healthService.WithContext(checkCtx).Readiness()
+
+	readiness := ReadinessOut{Status: "ready", Checks: []HealthCheckOut{}}
+	for i := 0; i < len(report.Checks); i++ {
+		check := report.Checks[i]
+		readiness.Checks = append(readiness.Checks, HealthCheckOut{
+			Name:       check.Name,
+			Status:     check.Status,
+			Error:      check.Error,
+			DurationMs: float64(check.Duration.Microseconds()) / 1000,
+		})
+	}
+
+	if !report.Ready {
+		readiness.Status = "not_ready"
+		jsonResponse := utils.HttpResponse[ReadinessOut]{
+			Message:   "Service indisponible",
+			Success:   false,
+			CodeError: http.StatusServiceUnavailable,
+			Data:      readiness,
+		}
+		return ctx.JSON(http.StatusServiceUnavailable, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[ReadinessOut]{
+		Message:   "Service prêt",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      readiness,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// VersionHandler retourne les informations de build
+// @Summary Informations de build
+// @Description Version, commit et date de build du service, ainsi que la version de Go utilisée.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[VersionOut]
+// @Router /version [get]
+func (h *HealthHandler) VersionHandler(ctx echo.Context) error {
+	info := version.Get()
+	jsonResponse := utils.HttpResponse[VersionOut]{
+		Message:   "Informations de build",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      VersionOut(info),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
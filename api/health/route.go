@@ -0,0 +1,12 @@
+package health
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterHealthRoutes expose les sondes à la racine du serveur, hors de /api/v1 et sans authentification
+func RegisterHealthRoutes(ech *echo.Echo, handler *HealthHandler) {
+	ech.GET("/healthz", handler.LivenessHandler)
+	ech.GET("/readyz", handler.ReadinessHandler)
+	ech.GET("/version", handler.VersionHandler)
+}
@@ -0,0 +1,25 @@
+package health
+
+type HealthOut struct {
+	Status string `json:"status"`
+}
+
+type HealthCheckOut struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+type ReadinessOut struct {
+	Status string           `json:"status"`
+	Checks []HealthCheckOut `json:"checks"`
+}
+
+type VersionOut struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	Modified  bool   `json:"modified"`
+	GoVersion string `json:"go_version"`
+}
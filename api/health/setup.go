@@ -0,0 +1,20 @@
+package health
+
+import (
+	"auth/config"
+	"auth/migrations"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupHealth config Health
+func SetupHealth(ech *echo.Echo, db *gorm.DB, appConfig config.AppConfig) {
+	healthRepo := repository.NewHealthRepository(db)
+	healthService := service.NewHealthService(healthRepo, migrations.NewMigrator(db, appConfig.Db.DbProvider), appConfig.Auth.Security())
+	healthHandler := NewHealthHandler(healthService)
+
+	RegisterHealthRoutes(ech, healthHandler)
+}
@@ -0,0 +1,324 @@
+package impersonations
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// ImpersonationHandler gère les requêtes liées aux sessions d'impersonation
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+}
+
+// NewImpersonationHandler crée une nouvelle instance de ImpersonationHandler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// StartImpersonationHandler démarre une session "se connecter en tant que"
+// @Summary Démarre une impersonation
+// @Description Génère un access token de courte durée pour un utilisateur de l'organisation active.
+// @Description Le token porte le claim "act" de l'administrateur, ne peut pas être rafraîchi
+// @Description et ne permet ni changement de mot de passe ni modification du 2FA.
+// @Tags Impersonations
+// @Accept json
+// @Produce json
+// @Param impersonation body ImpersonationIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[StartImpersonationOut]
+// @Router /impersonations [post]
+func (h *ImpersonationHandler) StartImpersonationHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "impersonate_users")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload ImpersonationIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	claims := ctx.Get("claims").(*model.Claims)
+	utils.SetAuditTarget(ctx, payload.UserId)
+	authResponse, impersonation, err := h.impersonationService.WithContext(ctx.Request().Context()).StartImpersonation(service.ImpersonationActor{
+		UserId: claims.Id,
+		Email:  claims.Subject,
+		OrgId:  claims.OrgId,
+	}, payload.UserId, payload.Reason)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[StartImpersonationOut]{
+		Message:   "Impersonation has been started",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data: StartImpersonationOut{
+			Impersonation: toImpersonationOut(impersonation),
+			Token: Token{
+				AccessToken: authResponse.Token.AccessToken,
+				ExpiresAt:   authResponse.Token.ExpiresAt,
+			},
+		},
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// EndCurrentImpersonationHandler termine la session portée par le token de la requête
+// @Summary Termine l'impersonation en cours
+// @Description Termine la session d'impersonation du token utilisé ; il est refusé dès la requête suivante.
+// @Tags Impersonations
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /impersonations/end [post]
+func (h *ImpersonationHandler) EndCurrentImpersonationHandler(ctx echo.Context) error {
+
+	claims := ctx.Get("claims").(*model.Claims)
+	if claims.Act == nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Ce token n'est pas issu d'une impersonation",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err := h.impersonationService.WithContext(ctx.Request().Context()).EndImpersonation(claims.Act.SessionId, claims.OrgId, claims.Act.Subject)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Impersonation has been ended",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// GetImpersonationsHandler liste les sessions d'impersonation de l'organisation
+// @Summary Liste les impersonations
+// @Description Liste les sessions d'impersonation de l'organisation active, les plus récentes en premier.
+// @Tags Impersonations
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]ImpersonationOut]
+// @Router /impersonations [get]
+func (h *ImpersonationHandler) GetImpersonationsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_impersonations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	impersonations, err := h.impersonationService.WithContext(ctx.Request().Context()).GetImpersonations(utils.GetOrganizationId(ctx))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucune session trouvée",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	impersonationList := []ImpersonationOut{}
+	for i := 0; i < len(impersonations); i++ {
+		impersonationList = append(impersonationList, toImpersonationOut(impersonations[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]ImpersonationOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      impersonationList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetImpersonationHandler récupère une session et les actions effectuées
+// @Summary Détail d'une impersonation
+// @Description Récupère une session d'impersonation et les requêtes effectuées sous son token.
+// @Tags Impersonations
+// @Produce json
+// @Param id path string true "Identifiant de la session"
+// @Success 200 {object} utils.HttpResponse[ImpersonationOut]
+// @Router /impersonations/{id} [get]
+func (h *ImpersonationHandler) GetImpersonationHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_impersonations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	impersonation, err := h.impersonationService.WithContext(ctx.Request().Context()).GetImpersonation(ctx.Param("id"), utils.GetOrganizationId(ctx))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	impersonationOut := toImpersonationOut(impersonation)
+	impersonationOut.Actions = []ImpersonationAction{}
+	for i := 0; i < len(impersonation.Actions); i++ {
+		action := impersonation.Actions[i]
+		impersonationOut.Actions = append(impersonationOut.Actions, ImpersonationAction{
+			Method:    action.Method,
+			Path:      action.Path,
+			Status:    action.Status,
+			Ip:        action.Ip,
+			CreatedAt: action.CreatedAt,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[ImpersonationOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      impersonationOut,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// EndImpersonationHandler termine une session d'impersonation
+// @Summary Termine une impersonation
+// @Description Termine une session de l'organisation active ; son token est refusé dès la requête suivante.
+// @Tags Impersonations
+// @Param id path string true "Identifiant de la session"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /impersonations/{id} [delete]
+func (h *ImpersonationHandler) EndImpersonationHandler(ctx echo.Context) error {
+
+	userId := ctx.Get("userId")
+
+	err := utils.VerifyPermission(ctx, "impersonate_users")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.impersonationService.WithContext(ctx.Request().Context()).EndImpersonation(ctx.Param("id"), utils.GetOrganizationId(ctx), fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Impersonation has been ended",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func toImpersonationOut(impersonation model.Impersonation) ImpersonationOut {
+	return ImpersonationOut{
+		Id:             impersonation.Id,
+		ActorId:        impersonation.ActorId,
+		TargetUserId:   impersonation.TargetUserId,
+		OrganizationId: impersonation.OrganizationId,
+		Reason:         impersonation.Reason,
+		IsActive:       impersonation.IsActive(time.Now()),
+		ExpiresAt:      impersonation.ExpiresAt,
+		EndedAt:        impersonation.EndedAt,
+		EndedBy:        impersonation.EndedBy,
+		CreatedAt:      impersonation.CreatedAt,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "uuid":
+		return fieldName + " must be a valid uuid"
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
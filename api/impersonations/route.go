@@ -0,0 +1,17 @@
+package impersonations
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterImpersonationRoutes(apiGroup *echo.Group, handler *ImpersonationHandler, guard *middlewares.Guard) {
+	apiGroup.POST("/end", handler.EndCurrentImpersonationHandler, guard.AuditMiddle("impersonation.end", ""), guard.IsAuthorizedMiddle)
+
+	//Admin method
+	apiGroup.POST("", handler.StartImpersonationHandler, guard.AuditMiddle("impersonation.start", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("", handler.GetImpersonationsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:id", handler.GetImpersonationHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id", handler.EndImpersonationHandler, guard.AuditMiddle("impersonation.end", "id"), guard.IsAdminMiddle, guard.GetPermission)
+}
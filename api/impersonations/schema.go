@@ -0,0 +1,40 @@
+package impersonations
+
+import "time"
+
+type ImpersonationIn struct {
+	UserId string `json:"user_id" validate:"required,uuid"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ImpersonationOut struct {
+	Id             string                `json:"id"`
+	ActorId        string                `json:"actor_id"`
+	TargetUserId   string                `json:"target_user_id"`
+	OrganizationId string                `json:"organization_id"`
+	Reason         string                `json:"reason"`
+	IsActive       bool                  `json:"is_active"`
+	ExpiresAt      time.Time             `json:"expires_at"`
+	EndedAt        time.Time             `json:"ended_at,omitempty"`
+	EndedBy        string                `json:"ended_by,omitempty"`
+	CreatedAt      time.Time             `json:"create_at,omitempty"`
+	Actions        []ImpersonationAction `json:"actions,omitempty"`
+}
+
+type ImpersonationAction struct {
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	Ip        string    `json:"ip"`
+	CreatedAt time.Time `json:"create_at"`
+}
+
+type StartImpersonationOut struct {
+	Impersonation ImpersonationOut `json:"impersonation"`
+	Token         Token            `json:"token"`
+}
+
+type Token struct {
+	ExpiresAt   time.Time `json:"expires_at"`
+	AccessToken string    `json:"access_token"`
+}
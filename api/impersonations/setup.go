@@ -0,0 +1,47 @@
+package impersonations
+
+import (
+	"auth/config"
+	"auth/middlewares"
+	"auth/model"
+	"auth/repository"
+	"auth/service"
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupImpersonation config Impersonation
+func SetupImpersonation(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	otpRepo := repository.NewOtpRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	attributeRepo := repository.NewAttributeRepository(db)
+	methodRepo := repository.NewTwoFactorMethodRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	loginRepo := repository.NewLoginEventRepository(db)
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	twoFactorService := service.NewTwoFactorService(userRepo, roleRepo, methodRepo, otpRepo, service.NewNotifier(appConfig.Smtp),
+		appConfig.Auth.OtpLength, appConfig.Auth.OtpTTL)
+	loginHistoryService := service.NewLoginHistoryService(loginRepo, userRepo, service.NewNotifier(appConfig.Smtp),
+		service.OpenIpLocator(appConfig.LoginAlert.GeoIpDatabase), appConfig.LoginAlert.ReportUrl, appConfig.LoginAlert.ReportTTL,
+		appConfig.Auth.Security())
+	authService := service.NewAuthenticationService(userRepo, roleRepo, orgRepo, groupRepo, attributeRepo, sessionRepo, twoFactorService, loginHistoryService,
+		appConfig.Auth.Security(), appConfig.Auth.AccessTokenTTL, appConfig.Auth.RefreshTokenTTL)
+	impersonationService := service.NewImpersonationService(authService, impersonationRepo, userRepo, roleRepo)
+	impersonationHandler := NewImpersonationHandler(impersonationService)
+
+	// Les tokens d'une session terminée sont refusés et chaque requête impersonnée est tracée
+	guard.RegisterTokenChecker(func(ctx context.Context, claims *model.Claims) error {
+		return impersonationService.WithContext(ctx).CheckToken(claims)
+	})
+	guard.RegisterActionRecorder(func(ctx context.Context, claims *model.Claims, method string, path string, status int, ip string) {
+		impersonationService.WithContext(ctx).RecordAction(claims, method, path, status, ip)
+	})
+
+	impersonationGroup := apiGroup.Group("/impersonations")
+	RegisterImpersonationRoutes(impersonationGroup, impersonationHandler, guard)
+}
@@ -0,0 +1,405 @@
+package invitations
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// InvitationHandler gère les requêtes liées aux invitations
+type InvitationHandler struct {
+	invitationService *service.InvitationService
+}
+
+// NewInvitationHandler crée une nouvelle instance de InvitationHandler
+func NewInvitationHandler(invitationService *service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// tenantService limite le service à l'organisation active du token
+func (h *InvitationHandler) tenantService(ctx echo.Context) *service.InvitationService {
+	return h.invitationService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// CreateInvitationHandler invite un utilisateur à définir son mot de passe
+// @Summary Invite un utilisateur
+// @Description Sans user_id, crée un compte en attente d'activation dans l'organisation active et envoie le lien d'invitation.
+// @Description Avec user_id, envoie à un membre existant un lien pour définir un nouveau mot de passe.
+// @Description Le lien est à usage unique et expire ; une nouvelle invitation révoque les précédentes du même utilisateur.
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Param invitation body InvitationIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[InvitationOut]
+// @Failure 502 {object} utils.HttpResponse[InvitationOut] "Invitation enregistrée mais email non envoyé"
+// @Router /invitations [post]
+func (h *InvitationHandler) CreateInvitationHandler(ctx echo.Context) error {
+
+	var payload InvitationIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Réinitialiser le mot de passe d'un membre existant relève de init_user_password
+	permission := "invite_users"
+	if payload.UserId != "" {
+		permission = "init_user_password"
+	}
+	if err := utils.VerifyPermission(ctx, permission); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	invitedBy := fmt.Sprintf("%v", ctx.Get("userId"))
+
+	var invitation model.Invitation
+	var err error
+	if payload.UserId != "" {
+		invitation, err = h.tenantService(ctx).InviteExistingUser(payload.UserId, invitedBy)
+	} else {
+		invitation, err = h.tenantService(ctx).InviteNewUser(service.NewUserInvitation{
+			Name:     payload.Name,
+			Sername:  payload.Sername,
+			Email:    payload.Email,
+			Username: payload.Username,
+			Role:     payload.Role,
+		}, invitedBy)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrInvitationNotSent) {
+			utils.SetAuditTarget(ctx, invitation.Id)
+			return invitationNotSent(ctx, invitation)
+		}
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	utils.SetAuditTarget(ctx, invitation.Id)
+	jsonResponse := utils.HttpResponse[InvitationOut]{
+		Message:   "L'invitation a été envoyée",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      toInvitationOut(invitation),
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// invitationNotSent signale à l'administrateur une invitation enregistrée dont l'email n'est pas parti
+func invitationNotSent(ctx echo.Context, invitation model.Invitation) error {
+	jsonResponse := utils.HttpResponse[InvitationOut]{
+		Message:   service.ErrInvitationNotSent.Error(),
+		Success:   false,
+		CodeError: http.StatusBadGateway,
+		Data:      toInvitationOut(invitation),
+	}
+	return ctx.JSON(http.StatusBadGateway, jsonResponse)
+}
+
+// GetPendingInvitationsHandler liste les invitations en attente
+// @Summary Liste les invitations en attente
+// @Description Invitations ni acceptées ni révoquées de l'organisation active ; les invitations expirées peuvent être renvoyées.
+// @Tags Invitations
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]InvitationOut]
+// @Router /invitations [get]
+func (h *InvitationHandler) GetPendingInvitationsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_invitations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	invitations, err := h.tenantService(ctx).GetPendingInvitations()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	invitationList := []InvitationOut{}
+	for i := 0; i < len(invitations); i++ {
+		invitationList = append(invitationList, toInvitationOut(invitations[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]InvitationOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      invitationList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// ResendInvitationHandler renvoie une invitation avec un nouveau lien
+// @Summary Renvoie une invitation
+// @Description Génère un nouveau lien avec une nouvelle date d'expiration ; le lien précédent devient invalide.
+// @Tags Invitations
+// @Produce json
+// @Param id path string true "ID de l'invitation"
+// @Success 200 {object} utils.HttpResponse[InvitationOut]
+// @Failure 502 {object} utils.HttpResponse[InvitationOut] "Invitation enregistrée mais email non envoyé"
+// @Router /invitations/{id}/resend [post]
+func (h *InvitationHandler) ResendInvitationHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "invite_users")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	invitation, err := h.tenantService(ctx).ResendInvitation(ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvitationNotSent) {
+			return invitationNotSent(ctx, invitation)
+		}
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[InvitationOut]{
+		Message:   "L'invitation a été renvoyée",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toInvitationOut(invitation),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// RevokeInvitationHandler révoque une invitation en attente
+// @Summary Révoque une invitation
+// @Description Le lien de l'invitation devient immédiatement invalide.
+// @Description Un compte jamais activé, sans mot de passe, est supprimé avec l'invitation.
+// @Tags Invitations
+// @Produce json
+// @Param id path string true "ID de l'invitation"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /invitations/{id} [delete]
+func (h *InvitationHandler) RevokeInvitationHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "invite_users")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	if err := h.tenantService(ctx).RevokeInvitation(ctx.Param("id")); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "L'invitation a été révoquée",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// GetInvitationByTokenHandler vérifie un lien d'invitation avant la saisie du mot de passe
+// @Summary Vérifie un lien d'invitation
+// @Description Retourne l'adresse email invitée tant que le lien est valide.
+// @Tags Invitations
+// @Produce json
+// @Param token query string true "Token du lien d'invitation"
+// @Success 200 {object} utils.HttpResponse[InvitationPreviewOut]
+// @Router /invitations/accept [get]
+func (h *InvitationHandler) GetInvitationByTokenHandler(ctx echo.Context) error {
+
+	invitation, err := h.invitationService.WithContext(ctx.Request().Context()).GetInvitationByToken(ctx.QueryParam("token"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[InvitationPreviewOut]{
+		Message:   "Invitation valide",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: InvitationPreviewOut{
+			Email:     invitation.Email,
+			ExpiresAt: invitation.ExpiresAt,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// AcceptInvitationHandler définit le mot de passe de l'utilisateur invité
+// @Summary Accepte une invitation
+// @Description Enregistre le mot de passe choisi et active le compte. Le lien ne peut être utilisé qu'une fois.
+// @Description Un compte désactivé ou verrouillé ne peut pas être activé par ce lien.
+// @Tags Invitations
+// @Accept json
+// @Produce json
+// @Param accept body AcceptInvitationIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /invitations/accept [post]
+func (h *InvitationHandler) AcceptInvitationHandler(ctx echo.Context) error {
+
+	var payload AcceptInvitationIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	invitation, err := h.invitationService.WithContext(ctx.Request().Context()).AcceptInvitation(payload.Token, payload.Password)
+	utils.SetAuditTarget(ctx, invitation.UserId)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		var stateErr *service.AccountStateError
+		if errors.As(err, &stateErr) {
+			statusCode = http.StatusForbidden
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: statusCode,
+			Data:      nil,
+		}
+		return ctx.JSON(statusCode, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Votre mot de passe a été enregistré, vous pouvez vous connecter",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func toInvitationOut(invitation model.Invitation) InvitationOut {
+	return InvitationOut{
+		Id:             invitation.Id,
+		Email:          invitation.Email,
+		UserId:         invitation.UserId,
+		OrganizationId: invitation.OrganizationId,
+		InvitedBy:      invitation.InvitedBy,
+		IsExpired:      !time.Now().Before(invitation.ExpiresAt),
+		ExpiresAt:      invitation.ExpiresAt,
+		SentCount:      invitation.SentCount,
+		LastSentAt:     invitation.LastSentAt,
+		CreatedAt:      invitation.CreatedAt,
+	}
+}
+
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required", "required_without":
+		return fieldName + " is required"
+	case "uuid":
+		return fieldName + " must be a valid uuid"
+	case "email":
+		return fieldName + " must be a valid email address"
+	case "min":
+		return fieldName + " must be at least " + err.Param() + " characters"
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
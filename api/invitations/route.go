@@ -0,0 +1,18 @@
+package invitations
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterInvitationRoutes(apiGroup *echo.Group, handler *InvitationHandler, guard *middlewares.Guard) {
+	apiGroup.GET("/accept", handler.GetInvitationByTokenHandler)
+	apiGroup.POST("/accept", handler.AcceptInvitationHandler, guard.AuditMiddle("invitation.accept", ""))
+
+	//Admin method
+	apiGroup.POST("", handler.CreateInvitationHandler, guard.AuditMiddle("invitation.create", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("", handler.GetPendingInvitationsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.POST("/:id/resend", handler.ResendInvitationHandler, guard.AuditMiddle("invitation.resend", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id", handler.RevokeInvitationHandler, guard.AuditMiddle("invitation.revoke", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
@@ -0,0 +1,35 @@
+package invitations
+
+import "time"
+
+type InvitationIn struct {
+	UserId   string `json:"user_id" validate:"omitempty,uuid"`
+	Name     string `json:"name" validate:"required_without=UserId,max=80"`
+	Sername  string `json:"sername" validate:"required_without=UserId"`
+	Email    string `json:"email" validate:"required_without=UserId,omitempty,email"`
+	Username string `json:"username" validate:"required_without=UserId"`
+	Role     string `json:"role"`
+}
+
+type AcceptInvitationIn struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type InvitationOut struct {
+	Id             string    `json:"id"`
+	Email          string    `json:"email"`
+	UserId         string    `json:"user_id"`
+	OrganizationId string    `json:"organization_id"`
+	InvitedBy      string    `json:"invited_by"`
+	IsExpired      bool      `json:"is_expired"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	SentCount      int       `json:"sent_count"`
+	LastSentAt     time.Time `json:"last_sent_at"`
+	CreatedAt      time.Time `json:"create_at"`
+}
+
+type InvitationPreviewOut struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
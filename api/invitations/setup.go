@@ -0,0 +1,25 @@
+package invitations
+
+import (
+	"auth/config"
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupInvitation config Invitation
+func SetupInvitation(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
+	invitationRepo := repository.NewInvitationRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, roleRepo, orgRepo,
+		service.NewNotifier(appConfig.Smtp), appConfig.Invitation.InvitationUrl, appConfig.Invitation.InvitationTTL, appConfig.Auth.Security())
+	invitationHandler := NewInvitationHandler(invitationService)
+
+	invitationGroup := apiGroup.Group("/invitations")
+	RegisterInvitationRoutes(invitationGroup, invitationHandler, guard)
+}
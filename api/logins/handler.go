@@ -0,0 +1,270 @@
+package logins
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// LoginHandler gère les requêtes liées à l'historique des connexions
+type LoginHandler struct {
+	loginHistoryService *service.LoginHistoryService
+}
+
+// NewLoginHandler crée une nouvelle instance de LoginHandler
+func NewLoginHandler(loginHistoryService *service.LoginHistoryService) *LoginHandler {
+	return &LoginHandler{
+		loginHistoryService: loginHistoryService,
+	}
+}
+
+// tenantService limite le service à l'organisation active du token
+func (h *LoginHandler) tenantService(ctx echo.Context) *service.LoginHistoryService {
+	return h.loginHistoryService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// GetMyLoginsHandler gère la requête pour consulter ses dernières connexions
+// @Summary Liste ses dernières connexions
+// @Description Liste les connexions réussies de l'utilisateur connecté, les plus récentes en premier, avec l'appareil et la localisation approximative.
+// @Tags Logins
+// @Produce json
+// @Param limit query int false "Nombre de connexions par page (100 au maximum)"
+// @Param offset query int false "Nombre de connexions à ignorer"
+// @Success 200 {object} utils.HttpResponse[LoginHistoryOut]
+// @Router /users/me/logins [get]
+func (h *LoginHandler) GetMyLoginsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.getHistory(ctx, h.loginHistoryService.WithContext(ctx.Request().Context()), fmt.Sprintf("%v", ctx.Get("userId")))
+}
+
+// GetUserLoginsHandler gère la requête pour consulter les dernières connexions d'un utilisateur
+// @Summary Liste les dernières connexions d'un utilisateur
+// @Description Liste les connexions réussies d'un utilisateur de l'organisation active, les plus récentes en premier.
+// @Tags Logins
+// @Produce json
+// @Param id path string true "Identifiant de l'utilisateur"
+// @Param limit query int false "Nombre de connexions par page (100 au maximum)"
+// @Param offset query int false "Nombre de connexions à ignorer"
+// @Success 200 {object} utils.HttpResponse[LoginHistoryOut]
+// @Router /users/{id}/logins [get]
+func (h *LoginHandler) GetUserLoginsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_sessions")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.getHistory(ctx, h.tenantService(ctx), ctx.Param("id"))
+}
+
+// GetReportableLoginHandler vérifie un lien "ce n'était pas moi" avant la saisie du nouveau mot de passe
+// @Summary Vérifie un lien de signalement de connexion
+// @Description Retourne la connexion signalée tant que le lien est valide.
+// @Tags Logins
+// @Produce json
+// @Param token query string true "Token du lien reçu dans l'alerte"
+// @Success 200 {object} utils.HttpResponse[LoginEventOut]
+// @Router /auth/logins/report [get]
+func (h *LoginHandler) GetReportableLoginHandler(ctx echo.Context) error {
+
+	event, err := h.loginHistoryService.WithContext(ctx.Request().Context()).GetReportableLogin(ctx.QueryParam("token"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[LoginEventOut]{
+		Message:   "Lien valide",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toLoginEventOut(event),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// ReportLoginHandler signale une connexion frauduleuse
+// @Summary Signale une connexion frauduleuse
+// @Description Déconnecte tous les appareils de l'utilisateur et remplace son mot de passe. Le lien ne peut être utilisé qu'une fois.
+// @Tags Logins
+// @Accept json
+// @Produce json
+// @Param report body ReportLoginIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /auth/logins/report [post]
+func (h *LoginHandler) ReportLoginHandler(ctx echo.Context) error {
+
+	var payload ReportLoginIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	event, err := h.loginHistoryService.WithContext(ctx.Request().Context()).ReportLogin(payload.Token, payload.Password)
+	utils.SetAuditTarget(ctx, event.UserId)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		var stateErr *service.AccountStateError
+		if errors.As(err, &stateErr) {
+			statusCode = http.StatusForbidden
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: statusCode,
+			Data:      nil,
+		}
+		return ctx.JSON(statusCode, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Tous vos appareils ont été déconnectés et votre nouveau mot de passe a été enregistré",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func (h *LoginHandler) getHistory(ctx echo.Context, loginHistoryService *service.LoginHistoryService, userId string) error {
+	var payload LoginHistoryIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	page, err := loginHistoryService.GetHistory(userId, payload.Limit, payload.Offset)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	eventList := []LoginEventOut{}
+	for i := 0; i < len(page.Events); i++ {
+		eventList = append(eventList, toLoginEventOut(page.Events[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[LoginHistoryOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: LoginHistoryOut{
+			Items:  eventList,
+			Total:  page.Total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+func toLoginEventOut(event model.LoginEvent) LoginEventOut {
+	return LoginEventOut{
+		Id:          event.Id,
+		Method:      event.Method,
+		Device:      event.Device,
+		UserAgent:   event.UserAgent,
+		IpAddress:   event.IpAddress,
+		Country:     event.Country,
+		City:        event.City,
+		NewDevice:   event.NewDevice,
+		NewLocation: event.NewLocation,
+		Reported:    !event.ReportedAt.IsZero(),
+		CreatedAt:   event.CreatedAt,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
@@ -0,0 +1,19 @@
+package logins
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterLoginRoutes(apiGroup *echo.Group, handler *LoginHandler, guard *middlewares.Guard) {
+	apiGroup.GET("/me/logins", handler.GetMyLoginsHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+
+	//Admin method
+	apiGroup.GET("/:id/logins", handler.GetUserLoginsHandler, guard.IsAdminMiddle, guard.GetPermission)
+}
+
+func RegisterLoginReportRoutes(apiGroup *echo.Group, handler *LoginHandler, guard *middlewares.Guard) {
+	apiGroup.GET("/report", handler.GetReportableLoginHandler)
+	apiGroup.POST("/report", handler.ReportLoginHandler, guard.AuditMiddle("auth.login_report", ""))
+}
@@ -0,0 +1,34 @@
+package logins
+
+import "time"
+
+type LoginHistoryIn struct {
+	Limit  int `query:"limit" validate:"min=0,max=100"`
+	Offset int `query:"offset" validate:"min=0"`
+}
+
+type ReportLoginIn struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type LoginEventOut struct {
+	Id          string    `json:"id"`
+	Method      string    `json:"method"`
+	Device      string    `json:"device"`
+	UserAgent   string    `json:"user_agent"`
+	IpAddress   string    `json:"ip_address"`
+	Country     string    `json:"country"`
+	City        string    `json:"city"`
+	NewDevice   bool      `json:"new_device"`
+	NewLocation bool      `json:"new_location"`
+	Reported    bool      `json:"reported"`
+	CreatedAt   time.Time `json:"create_at"`
+}
+
+type LoginHistoryOut struct {
+	Items  []LoginEventOut `json:"items"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
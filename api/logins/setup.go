@@ -0,0 +1,27 @@
+package logins
+
+import (
+	"auth/config"
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupLogin config Login
+func SetupLogin(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
+	loginRepo := repository.NewLoginEventRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	loginHistoryService := service.NewLoginHistoryService(loginRepo, userRepo, service.NewNotifier(appConfig.Smtp),
+		service.OpenIpLocator(appConfig.LoginAlert.GeoIpDatabase), appConfig.LoginAlert.ReportUrl, appConfig.LoginAlert.ReportTTL,
+		appConfig.Auth.Security())
+	loginHandler := NewLoginHandler(loginHistoryService)
+
+	userGroup := apiGroup.Group("/users")
+	RegisterLoginRoutes(userGroup, loginHandler, guard)
+
+	reportGroup := apiGroup.Group("/auth/logins")
+	RegisterLoginReportRoutes(reportGroup, loginHandler, guard)
+}
@@ -0,0 +1,467 @@
+package organizations
+
+import (
+	"auth/service"
+	"auth/utils"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// OrganizationHandler gère les requêtes liées aux organisations
+type OrganizationHandler struct {
+	organizationService *service.OrganizationService
+}
+
+// NewOrganizationHandler crée une nouvelle instance de OrganizationHandler
+func NewOrganizationHandler(organizationService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+	}
+}
+
+// CreateOrganizationHandler gère la requête pour créer une organisation
+// @Summary Crée une nouvelle organisation
+// @Description Crée une nouvelle organisation avec les détails fournis.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param organization body OrganizationIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[OrganizationOut]
+// @Router /organizations [post]
+func (h *OrganizationHandler) CreateOrganizationHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_organizations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload OrganizationIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	organization, err := h.organizationService.WithContext(ctx.Request().Context()).CreateOrganization(service.Organization(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	utils.SetAuditTarget(ctx, organization.Id)
+	jsonResponse := utils.HttpResponse[OrganizationOut]{
+		Message:   "Organization has been created",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data: OrganizationOut{
+			Id:        organization.Id,
+			Name:      organization.Name,
+			Slug:      organization.Slug,
+			Describe:  organization.Describe,
+			CreatedAt: organization.CreatedAt,
+		},
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// GetAllOrganizationsHandler gère la requête pour récupérer toutes les organisations
+// @Summary Récupère toutes les organisations
+// @Description Récupère la liste de toutes les organisations.
+// @Tags Organizations
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]OrganizationOut]
+// @Router /organizations [get]
+func (h *OrganizationHandler) GetAllOrganizationsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_organizations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	organizations, err := h.organizationService.WithContext(ctx.Request().Context()).GetAllOrganizations()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucune organisation trouvée",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	organizationList := []OrganizationOut{}
+	for i := 0; i < len(organizations); i++ {
+		organization := organizations[i]
+		organizationList = append(organizationList, OrganizationOut{
+			Id:        organization.Id,
+			Name:      organization.Name,
+			Slug:      organization.Slug,
+			Describe:  organization.Describe,
+			CreatedAt: organization.CreatedAt,
+			UpdatedAt: organization.UpdatedAt,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[[]OrganizationOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      organizationList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetOrganizationByIdHandler gère la requête pour récupérer une organisation par son ID
+// @Summary Récupère une organisation par ID
+// @Description Récupère une organisation en fonction de son ID.
+// @Tags Organizations
+// @Produce json
+// @Param id path string true "ID de l'organisation"
+// @Success 200 {object} utils.HttpResponse[OrganizationOut]
+// @Router /organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganizationByIdHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_organizations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	organization, err := h.organizationService.WithContext(ctx.Request().Context()).GetOrganizationById(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Cette organisation n'existe pas dans le système",
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[OrganizationOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: OrganizationOut{
+			Id:        organization.Id,
+			Name:      organization.Name,
+			Slug:      organization.Slug,
+			Describe:  organization.Describe,
+			CreatedAt: organization.CreatedAt,
+			UpdatedAt: organization.UpdatedAt,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetMembersHandler gère la requête pour lister les membres d'une organisation
+// @Summary Liste les membres d'une organisation
+// @Description Liste les membres d'une organisation avec leurs rôles.
+// @Tags Organizations
+// @Produce json
+// @Param id path string true "ID de l'organisation"
+// @Success 200 {object} utils.HttpResponse[[]MemberOut]
+// @Router /organizations/{id}/members [get]
+func (h *OrganizationHandler) GetMembersHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_organizations")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	members, err := h.organizationService.WithContext(ctx.Request().Context()).GetMembers(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	memberList := []MemberOut{}
+	for i := 0; i < len(members); i++ {
+		memberList = append(memberList, toMemberOut(members[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]MemberOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      memberList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// AddMemberHandler gère la requête pour ajouter un membre à une organisation
+// @Summary Ajoute un membre à une organisation
+// @Description Ajoute un utilisateur existant à une organisation avec les rôles fournis.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'organisation"
+// @Param member body MemberIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[MemberOut]
+// @Router /organizations/{id}/members [post]
+func (h *OrganizationHandler) AddMemberHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_organization_members")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload MemberIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	member, err := h.organizationService.WithContext(ctx.Request().Context()).AddMember(ctx.Param("id"), payload.UserId, payload.Roles)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[MemberOut]{
+		Message:   "Le membre a bien été ajouté à l'organisation",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      toMemberOut(member),
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// UpdateMemberRolesHandler gère la requête pour modifier les rôles d'un membre
+// @Summary Modifie les rôles d'un membre
+// @Description Remplace les rôles d'un membre dans une organisation.
+// @Tags Organizations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'organisation"
+// @Param userId path string true "ID de l'utilisateur"
+// @Param roles body MemberRolesIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[MemberOut]
+// @Router /organizations/{id}/members/{userId} [put]
+func (h *OrganizationHandler) UpdateMemberRolesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_organization_members")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload MemberRolesIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	member, err := h.organizationService.WithContext(ctx.Request().Context()).UpdateMemberRoles(ctx.Param("id"), ctx.Param("userId"), payload.Roles)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[MemberOut]{
+		Message:   "Les rôles du membre ont bien été mis à jour",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toMemberOut(member),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// RemoveMemberHandler gère la requête pour retirer un membre d'une organisation
+// @Summary Retire un membre d'une organisation
+// @Description Retire un utilisateur d'une organisation.
+// @Tags Organizations
+// @Param id path string true "ID de l'organisation"
+// @Param userId path string true "ID de l'utilisateur"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /organizations/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMemberHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_organization_members")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.organizationService.WithContext(ctx.Request().Context()).RemoveMember(ctx.Param("id"), ctx.Param("userId"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Le membre a bien été retiré de l'organisation",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func toMemberOut(member service.OrganizationMember) MemberOut {
+	return MemberOut{
+		MembershipId: member.MembershipId,
+		UserId:       member.User.Id,
+		Name:         member.User.Name,
+		Sername:      member.User.Sername,
+		Email:        member.User.Email,
+		Username:     member.User.Username,
+		Roles:        member.Roles,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "email":
+		return fieldName + " must be a valid email address"
+	case "uuid", "uuid4":
+		return fieldName + " must be a valid UUID"
+	case "unique":
+		return fieldName + " must not contain duplicates"
+	default:
+		return fieldName + " is invalid"
+	}
+}
@@ -0,0 +1,19 @@
+package organizations
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterOrganizationRoutes(apiGroup *echo.Group, handler *OrganizationHandler, guard *middlewares.Guard) {
+	//Admin method
+	apiGroup.POST("", handler.CreateOrganizationHandler, guard.AuditMiddle("organization.create", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("", handler.GetAllOrganizationsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:id", handler.GetOrganizationByIdHandler, guard.IsAdminMiddle, guard.GetPermission)
+
+	apiGroup.GET("/:id/members", handler.GetMembersHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.POST("/:id/members", handler.AddMemberHandler, guard.AuditMiddle("organization.member_add", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id/members/:userId", handler.UpdateMemberRolesHandler, guard.AuditMiddle("organization.member_roles", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id/members/:userId", handler.RemoveMemberHandler, guard.AuditMiddle("organization.member_remove", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
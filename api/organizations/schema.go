@@ -0,0 +1,37 @@
+package organizations
+
+import "time"
+
+type OrganizationIn struct {
+	Name     string `json:"name" validate:"required"`
+	Slug     string `json:"slug" validate:"required,max=120"`
+	Describe string `json:"describe" validate:"max=500"`
+}
+
+type OrganizationOut struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Describe  string    `json:"describe"`
+	CreatedAt time.Time `json:"create_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+type MemberIn struct {
+	UserId string   `json:"user_id" validate:"required,uuid"`
+	Roles  []string `json:"roles" validate:"required,unique,dive,required"`
+}
+
+type MemberRolesIn struct {
+	Roles []string `json:"roles" validate:"required,unique,dive,required"`
+}
+
+type MemberOut struct {
+	MembershipId string   `json:"membership_id"`
+	UserId       string   `json:"user_id"`
+	Name         string   `json:"name"`
+	Sername      string   `json:"sername"`
+	Email        string   `json:"email"`
+	Username     string   `json:"username"`
+	Roles        []string `json:"roles"`
+}
@@ -0,0 +1,22 @@
+package organizations
+
+import (
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupOrganization config Organization
+func SetupOrganization(apiGroup *echo.Group, db *gorm.DB, guard *middlewares.Guard) {
+	orgRepo := repository.NewOrganizationRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	organizationService := service.NewOrganizationService(orgRepo, userRepo, roleRepo)
+	organizationHandler := NewOrganizationHandler(organizationService)
+
+	organizationGroup := apiGroup.Group("/organizations")
+	RegisterOrganizationRoutes(organizationGroup, organizationHandler, guard)
+}
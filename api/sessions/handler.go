@@ -0,0 +1,209 @@
+package sessions
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SessionHandler gère les requêtes liées aux sessions des utilisateurs
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+// NewSessionHandler crée une nouvelle instance de SessionHandler
+func NewSessionHandler(sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// tenantService limite le service à l'organisation active du token
+func (h *SessionHandler) tenantService(ctx echo.Context) *service.SessionService {
+	return h.sessionService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// GetMySessionsHandler gère la requête pour lister ses sessions actives
+// @Summary Liste ses sessions actives
+// @Description Liste les appareils sur lesquels l'utilisateur connecté est authentifié ; la session de la requête est marquée "current".
+// @Tags Sessions
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]SessionOut]
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) GetMySessionsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	sessions, err := h.sessionService.WithContext(ctx.Request().Context()).GetSessions(fmt.Sprintf("%v", ctx.Get("userId")))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[[]SessionOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toSessionList(sessions, utils.GetSessionId(ctx)),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// RevokeMySessionHandler gère la requête pour révoquer une de ses sessions
+// @Summary Révoque une de ses sessions
+// @Description Déconnecte un appareil : le refresh token et les access tokens de la session sont refusés.
+// @Tags Sessions
+// @Produce json
+// @Param id path string true "Identifiant de la session"
+// @Success 200 {object} utils.HttpResponse[any]
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeMySessionHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.sessionService.WithContext(ctx.Request().Context()).RevokeSession(fmt.Sprintf("%v", ctx.Get("userId")), ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "La session a bien été révoquée",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetUserSessionsHandler gère la requête pour lister les sessions actives d'un utilisateur
+// @Summary Liste les sessions actives d'un utilisateur
+// @Description Liste les appareils sur lesquels un utilisateur de l'organisation active est authentifié.
+// @Tags Sessions
+// @Produce json
+// @Param id path string true "Identifiant de l'utilisateur"
+// @Success 200 {object} utils.HttpResponse[[]SessionOut]
+// @Router /users/{id}/sessions [get]
+func (h *SessionHandler) GetUserSessionsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_sessions")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	sessions, err := h.tenantService(ctx).GetSessions(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[[]SessionOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toSessionList(sessions, utils.GetSessionId(ctx)),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// RevokeUserSessionHandler gère la requête pour révoquer une session d'un utilisateur
+// @Summary Révoque une session d'un utilisateur
+// @Description Déconnecte un appareil d'un utilisateur de l'organisation active.
+// @Tags Sessions
+// @Produce json
+// @Param id path string true "Identifiant de l'utilisateur"
+// @Param sessionId path string true "Identifiant de la session"
+// @Success 200 {object} utils.HttpResponse[any]
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func (h *SessionHandler) RevokeUserSessionHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "revoke_user_sessions")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).RevokeSession(ctx.Param("id"), ctx.Param("sessionId"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "La session a bien été révoquée",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+func toSessionList(sessions []model.Session, currentSessionId string) []SessionOut {
+	sessionList := []SessionOut{}
+	for i := 0; i < len(sessions); i++ {
+		sessionList = append(sessionList, SessionOut{
+			Id:         sessions[i].Id,
+			Device:     sessions[i].Device,
+			UserAgent:  sessions[i].UserAgent,
+			IpAddress:  sessions[i].IpAddress,
+			Current:    sessions[i].Id == currentSessionId,
+			CreatedAt:  sessions[i].CreatedAt,
+			LastSeenAt: sessions[i].LastSeenAt,
+			ExpiresAt:  sessions[i].ExpiresAt,
+		})
+	}
+	return sessionList
+}
@@ -0,0 +1,16 @@
+package sessions
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterSessionRoutes(apiGroup *echo.Group, handler *SessionHandler, guard *middlewares.Guard) {
+	apiGroup.GET("/me/sessions", handler.GetMySessionsHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.DELETE("/me/sessions/:id", handler.RevokeMySessionHandler, guard.AuditMiddle("session.revoke", "id"), guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+
+	//Admin method
+	apiGroup.GET("/:id/sessions", handler.GetUserSessionsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id/sessions/:sessionId", handler.RevokeUserSessionHandler, guard.AuditMiddle("session.revoke", "sessionId"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
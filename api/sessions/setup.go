@@ -0,0 +1,21 @@
+package sessions
+
+import (
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupSession config Session
+func SetupSession(apiGroup *echo.Group, db *gorm.DB, guard *middlewares.Guard) {
+	sessionRepo := repository.NewSessionRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionService := service.NewSessionService(sessionRepo, userRepo)
+	sessionHandler := NewSessionHandler(sessionService)
+
+	userGroup := apiGroup.Group("/users")
+	RegisterSessionRoutes(userGroup, sessionHandler, guard)
+}
@@ -0,0 +1,614 @@
+package twofactor
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// TwoFactorHandler gère les requêtes liées à la double authentification
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+// NewTwoFactorHandler crée une nouvelle instance de TwoFactorHandler
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// GetStatusHandler gère la requête pour consulter la double authentification de l'utilisateur connecté
+// @Summary Consulte ses seconds facteurs
+// @Description Indique si la double authentification est active ou imposée par un rôle et liste les seconds facteurs enrôlés.
+// @Tags TwoFactor
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[TwoFactorStatusOut]
+// @Router /users/me/two-factor [get]
+func (h *TwoFactorHandler) GetStatusHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	status, err := h.twoFactorService.WithContext(ctx.Request().Context()).GetStatus(fmt.Sprintf("%v", ctx.Get("userId")), claimRoleNames(ctx))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	methodList := []TwoFactorMethodOut{}
+	for i := 0; i < len(status.Methods); i++ {
+		methodList = append(methodList, toTwoFactorMethodOut(status.Methods[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[TwoFactorStatusOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: TwoFactorStatusOut{
+			Enabled:  status.Enabled,
+			Required: status.Required,
+			Methods:  methodList,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// StartEnrollmentHandler gère la requête pour enrôler un second facteur
+// @Summary Enrôle un second facteur
+// @Description Ajoute un second facteur totp (clé et adresse otpauth retournées une seule fois) ou email (code envoyé). Le mot de passe actuel ou un code récent est requis.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param method body TwoFactorMethodIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[TwoFactorEnrollmentOut]
+// @Router /users/me/two-factor/methods [post]
+func (h *TwoFactorHandler) StartEnrollmentHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload TwoFactorMethodIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	enrollment, err := h.twoFactorService.WithContext(ctx.Request().Context()).StartEnrollment(fmt.Sprintf("%v", ctx.Get("userId")), payload.Type, payload.Label,
+		service.TwoFactorReauth{CurrentPassword: payload.CurrentPassword, SessionId: payload.SessionId, Code: payload.Code})
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	enrollmentOut := TwoFactorEnrollmentOut{
+		Method: toTwoFactorMethodOut(enrollment.Method),
+		Secret: enrollment.Secret,
+		Url:    enrollment.Url,
+	}
+	if enrollment.Challenge.SessionId != "" {
+		challenge := toTwoFactorChallengeOut(enrollment.Challenge)
+		enrollmentOut.Challenge = &challenge
+	}
+
+	jsonResponse := utils.HttpResponse[TwoFactorEnrollmentOut]{
+		Message:   "Le second facteur doit maintenant être confirmé avec un code",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      enrollmentOut,
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// ConfirmEnrollmentHandler gère la requête pour confirmer un second facteur
+// @Summary Confirme un second facteur
+// @Description Active un second facteur enrôlé avec un premier code : celui de l'application pour totp, celui reçu (et sa session) pour email.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param id path string true "Identifiant du second facteur"
+// @Param code body TwoFactorConfirmIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[TwoFactorMethodOut]
+// @Router /users/me/two-factor/methods/{id}/confirm [post]
+func (h *TwoFactorHandler) ConfirmEnrollmentHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload TwoFactorConfirmIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	method, err := h.twoFactorService.WithContext(ctx.Request().Context()).ConfirmEnrollment(fmt.Sprintf("%v", ctx.Get("userId")), ctx.Param("id"), payload.SessionId, payload.Code)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[TwoFactorMethodOut]{
+		Message:   "La double authentification est activée",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toTwoFactorMethodOut(method),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// SetDefaultHandler gère la requête pour choisir le second facteur par défaut
+// @Summary Choisit le second facteur par défaut
+// @Description Le second facteur par défaut est demandé à la connexion. Le mot de passe actuel ou un code récent est requis.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param id path string true "Identifiant du second facteur"
+// @Param reauth body TwoFactorReauthIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[TwoFactorMethodOut]
+// @Router /users/me/two-factor/methods/{id}/default [put]
+func (h *TwoFactorHandler) SetDefaultHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload TwoFactorReauthIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	method, err := h.twoFactorService.WithContext(ctx.Request().Context()).SetDefault(fmt.Sprintf("%v", ctx.Get("userId")), ctx.Param("id"),
+		service.TwoFactorReauth{CurrentPassword: payload.CurrentPassword, SessionId: payload.SessionId, Code: payload.Code})
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[TwoFactorMethodOut]{
+		Message:   "Le second facteur par défaut a bien été modifié",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toTwoFactorMethodOut(method),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// RemoveMethodHandler gère la requête pour supprimer un second facteur
+// @Summary Supprime un second facteur
+// @Description Supprime un second facteur ; la double authentification est désactivée avec le dernier. Refusé si un rôle l'impose. Le mot de passe actuel ou un code récent est requis.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param id path string true "Identifiant du second facteur"
+// @Param reauth body TwoFactorReauthIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[any]
+// @Router /users/me/two-factor/methods/{id} [delete]
+func (h *TwoFactorHandler) RemoveMethodHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload TwoFactorReauthIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.twoFactorService.WithContext(ctx.Request().Context()).RemoveMethod(fmt.Sprintf("%v", ctx.Get("userId")), ctx.Param("id"),
+		service.TwoFactorReauth{CurrentPassword: payload.CurrentPassword, SessionId: payload.SessionId, Code: payload.Code},
+		claimRoleNames(ctx))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Le second facteur a bien été supprimé",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// ChallengeHandler gère la requête pour demander un code sur un second facteur
+// @Summary Demande un code de vérification
+// @Description Demande un code sur un second facteur confirmé (le facteur par défaut si aucun n'est indiqué), utilisable pour confirmer son identité avant une modification.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param challenge body TwoFactorChallengeIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[TwoFactorChallengeOut]
+// @Router /users/me/two-factor/challenge [post]
+func (h *TwoFactorHandler) ChallengeHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload TwoFactorChallengeIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	challenge, err := h.twoFactorService.WithContext(ctx.Request().Context()).Challenge(fmt.Sprintf("%v", ctx.Get("userId")), payload.MethodId)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[TwoFactorChallengeOut]{
+		Message:   "Un code de vérification est attendu",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data:      toTwoFactorChallengeOut(challenge),
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// GetRolesHandler gère la requête pour lister l'obligation de double authentification par rôle
+// @Summary Liste l'obligation de double authentification par rôle
+// @Description Liste les rôles et indique ceux qui imposent la double authentification.
+// @Tags TwoFactor
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]RoleTwoFactorOut]
+// @Router /two-factor/roles [get]
+func (h *TwoFactorHandler) GetRolesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_two_factor")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	roles, err := h.twoFactorService.WithContext(ctx.Request().Context()).GetRoles()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucun rôle trouvé",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	roleList := []RoleTwoFactorOut{}
+	for i := 0; i < len(roles); i++ {
+		roleList = append(roleList, toRoleTwoFactorOut(roles[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]RoleTwoFactorOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      roleList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// UpdateRoleHandler gère la requête pour imposer la double authentification à un rôle
+// @Summary Impose la double authentification à un rôle
+// @Description Les utilisateurs du rôle sans second facteur devront en enrôler un à leur prochaine connexion.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param name path string true "Nom du rôle"
+// @Param role body RoleTwoFactorIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[RoleTwoFactorOut]
+// @Router /two-factor/roles/{name} [put]
+func (h *TwoFactorHandler) UpdateRoleHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_two_factor")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload RoleTwoFactorIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	role, err := h.twoFactorService.WithContext(ctx.Request().Context()).SetRoleRequirement(ctx.Param("name"), payload.Required)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[RoleTwoFactorOut]{
+		Message:   "Le rôle a bien été mis à jour",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toRoleTwoFactorOut(role),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// claimRoleNames retourne le rôle système et les rôles dans l'organisation active du token
+func claimRoleNames(ctx echo.Context) []string {
+	claims := ctx.Get("claims").(*model.Claims)
+	return append([]string{claims.Role}, claims.Roles...)
+}
+
+func toTwoFactorMethodOut(method model.TwoFactorMethod) TwoFactorMethodOut {
+	return TwoFactorMethodOut{
+		Id:          method.Id,
+		Type:        method.Type,
+		Label:       method.Label,
+		IsDefault:   method.IsDefault,
+		IsConfirmed: method.IsConfirmed(),
+		ConfirmedAt: method.ConfirmedAt,
+		LastUsedAt:  method.LastUsedAt,
+		CreatedAt:   method.CreatedAt,
+	}
+}
+
+func toTwoFactorChallengeOut(challenge service.TwoFactorChallenge) TwoFactorChallengeOut {
+	return TwoFactorChallengeOut{
+		SessionId: challenge.SessionId,
+		MethodId:  challenge.MethodId,
+		Type:      challenge.Type,
+		ExpiresAt: challenge.ExpiresAt,
+	}
+}
+
+func toRoleTwoFactorOut(role model.Role) RoleTwoFactorOut {
+	return RoleTwoFactorOut{
+		Id:               role.Id,
+		Name:             role.Name,
+		RequireTwoFactor: role.RequireTwoFactor,
+	}
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "oneof":
+		return fieldName + " must be one of " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
@@ -0,0 +1,24 @@
+package twofactor
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterSelfTwoFactorRoutes enregistre les routes de gestion des seconds facteurs de l'utilisateur connecté.
+// Elles acceptent le token d'enrôlement émis lorsqu'un rôle impose la double authentification.
+func RegisterSelfTwoFactorRoutes(apiGroup *echo.Group, handler *TwoFactorHandler, guard *middlewares.Guard) {
+	apiGroup.GET("", handler.GetStatusHandler, guard.IsTwoFactorEnrollmentMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.POST("/methods", handler.StartEnrollmentHandler, guard.AuditMiddle("two_factor.enroll", ""), guard.IsTwoFactorEnrollmentMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.POST("/methods/:id/confirm", handler.ConfirmEnrollmentHandler, guard.AuditMiddle("two_factor.confirm", "id"), guard.IsTwoFactorEnrollmentMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.PUT("/methods/:id/default", handler.SetDefaultHandler, guard.AuditMiddle("two_factor.default", "id"), guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/methods/:id", handler.RemoveMethodHandler, guard.AuditMiddle("two_factor.remove", "id"), guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.POST("/challenge", handler.ChallengeHandler, guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
+
+func RegisterTwoFactorRoutes(apiGroup *echo.Group, handler *TwoFactorHandler, guard *middlewares.Guard) {
+	//Admin method
+	apiGroup.GET("/roles", handler.GetRolesHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.PUT("/roles/:name", handler.UpdateRoleHandler, guard.AuditMiddle("role.two_factor_requirement", "name"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
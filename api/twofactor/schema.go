@@ -0,0 +1,67 @@
+package twofactor
+
+import "time"
+
+type TwoFactorMethodIn struct {
+	Type            string `json:"type" validate:"required,oneof=totp email"`
+	Label           string `json:"label" validate:"max=120"`
+	CurrentPassword string `json:"current_password"`
+	SessionId       string `json:"session_id"`
+	Code            string `json:"code" validate:"max=10"`
+}
+
+type TwoFactorReauthIn struct {
+	CurrentPassword string `json:"current_password"`
+	SessionId       string `json:"session_id"`
+	Code            string `json:"code" validate:"max=10"`
+}
+
+type TwoFactorConfirmIn struct {
+	SessionId string `json:"session_id"`
+	Code      string `json:"code" validate:"required,max=10"`
+}
+
+type TwoFactorChallengeIn struct {
+	MethodId string `json:"method_id"`
+}
+
+type RoleTwoFactorIn struct {
+	Required bool `json:"required"`
+}
+
+type TwoFactorMethodOut struct {
+	Id          string    `json:"id"`
+	Type        string    `json:"type"`
+	Label       string    `json:"label"`
+	IsDefault   bool      `json:"is_default"`
+	IsConfirmed bool      `json:"is_confirmed"`
+	ConfirmedAt time.Time `json:"confirmed_at,omitempty"`
+	LastUsedAt  time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time `json:"create_at,omitempty"`
+}
+
+type TwoFactorStatusOut struct {
+	Enabled  bool                 `json:"enabled"`
+	Required bool                 `json:"required"`
+	Methods  []TwoFactorMethodOut `json:"methods"`
+}
+
+type TwoFactorChallengeOut struct {
+	SessionId string    `json:"session_id"`
+	MethodId  string    `json:"method_id"`
+	Type      string    `json:"type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type TwoFactorEnrollmentOut struct {
+	Method    TwoFactorMethodOut     `json:"method"`
+	Secret    string                 `json:"secret,omitempty"`
+	Url       string                 `json:"url,omitempty"`
+	Challenge *TwoFactorChallengeOut `json:"challenge,omitempty"`
+}
+
+type RoleTwoFactorOut struct {
+	Id               string `json:"id"`
+	Name             string `json:"name"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
+}
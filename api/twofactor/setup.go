@@ -0,0 +1,25 @@
+package twofactor
+
+import (
+	"auth/config"
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupTwoFactor config TwoFactor
+func SetupTwoFactor(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	methodRepo := repository.NewTwoFactorMethodRepository(db)
+	otpRepo := repository.NewOtpRepository(db)
+	twoFactorService := service.NewTwoFactorService(userRepo, roleRepo, methodRepo, otpRepo, service.NewNotifier(appConfig.Smtp),
+		appConfig.Auth.OtpLength, appConfig.Auth.OtpTTL)
+	twoFactorHandler := NewTwoFactorHandler(twoFactorService)
+
+	RegisterSelfTwoFactorRoutes(apiGroup.Group("/users/me/two-factor"), twoFactorHandler, guard)
+	RegisterTwoFactorRoutes(apiGroup.Group("/two-factor"), twoFactorHandler, guard)
+}
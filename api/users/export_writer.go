@@ -0,0 +1,193 @@
+package users
+
+import (
+	"auth/model"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportFlushEvery est le nombre d'utilisateurs écrits entre deux envois au client
+const exportFlushEvery = 100
+
+// exportField décrit une colonne de l'export des utilisateurs
+type exportField struct {
+	name  string
+	value func(user model.User) interface{}
+}
+
+// exportFields liste les champs exportables, dans l'ordre par défaut. Le mot de passe n'en fait jamais partie.
+var exportFields = []exportField{
+	{"id", func(user model.User) interface{} { return user.Id }},
+	{"name", func(user model.User) interface{} { return user.Name }},
+	{"sername", func(user model.User) interface{} { return user.Sername }},
+	{"email", func(user model.User) interface{} { return user.Email }},
+	{"username", func(user model.User) interface{} { return user.Username }},
+	{"role", func(user model.User) interface{} { return user.RoleId }},
+	{"use_otp", func(user model.User) interface{} { return user.UseOTP }},
+	{"is_available", func(user model.User) interface{} { return user.IsAvailable }},
+	{"created_at", func(user model.User) interface{} { return user.CreatedAt }},
+	{"updated_at", func(user model.User) interface{} { return user.UpdatedAt }},
+}
+
+// selectExportFields retourne les champs demandés, séparés par des virgules, ou tous les champs
+func selectExportFields(fields string) ([]exportField, error) {
+	if strings.TrimSpace(fields) == "" {
+		return exportFields, nil
+	}
+
+	var selected []exportField
+	names := strings.Split(fields, ",")
+	for i := 0; i < len(names); i++ {
+		name := strings.TrimSpace(names[i])
+		found := false
+		for j := 0; j < len(exportFields); j++ {
+			if exportFields[j].name == name {
+				selected = append(selected, exportFields[j])
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("champ d'export inconnu : %s", name)
+		}
+	}
+	return selected, nil
+}
+
+// userExportWriter écrit les utilisateurs au fil de l'eau en CSV, JSON ou JSON Lines
+type userExportWriter struct {
+	format  string
+	fields  []exportField
+	writer  io.Writer
+	flusher http.Flusher
+	csv     *csv.Writer
+	count   int
+}
+
+func newUserExportWriter(format string, fields []exportField, writer io.Writer) *userExportWriter {
+	exportWriter := &userExportWriter{format: format, fields: fields, writer: writer}
+	if flusher, ok := writer.(http.Flusher); ok {
+		exportWriter.flusher = flusher
+	}
+	if format == "csv" {
+		exportWriter.csv = csv.NewWriter(writer)
+	}
+	return exportWriter
+}
+
+// exportContentType retourne le type MIME et l'extension de fichier d'un format d'export
+func exportContentType(format string) (string, string) {
+	switch format {
+	case "json":
+		return "application/json", "json"
+	case "jsonl":
+		return "application/x-ndjson", "jsonl"
+	}
+	return "text/csv; charset=utf-8", "csv"
+}
+
+// Write ajoute un utilisateur à l'export
+func (w *userExportWriter) Write(user model.User) error {
+	if w.count == 0 {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch w.format {
+	case "csv":
+		record := make([]string, len(w.fields))
+		for i := 0; i < len(w.fields); i++ {
+			record[i] = formatExportValue(w.fields[i].value(user))
+		}
+		err = w.csv.Write(record)
+	default:
+		item := map[string]interface{}{}
+		for i := 0; i < len(w.fields); i++ {
+			item[w.fields[i].name] = w.fields[i].value(user)
+		}
+
+		var content []byte
+		if content, err = json.Marshal(item); err != nil {
+			return err
+		}
+		switch {
+		case w.format == "jsonl":
+			content = append(content, '\n')
+		case w.count > 0:
+			content = append([]byte(",\n"), content...)
+		}
+		_, err = w.writer.Write(content)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.count++
+	if w.count%exportFlushEvery == 0 {
+		w.flush()
+	}
+	return nil
+}
+
+// Close termine l'export ; un export vide contient tout de même l'en-tête
+func (w *userExportWriter) Close() error {
+	if w.count == 0 {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	if w.format == "json" {
+		if _, err := io.WriteString(w.writer, "\n]\n"); err != nil {
+			return err
+		}
+	}
+	w.flush()
+	if w.csv != nil {
+		return w.csv.Error()
+	}
+	return nil
+}
+
+func (w *userExportWriter) writeHeader() error {
+	switch w.format {
+	case "csv":
+		header := make([]string, len(w.fields))
+		for i := 0; i < len(w.fields); i++ {
+			header[i] = w.fields[i].name
+		}
+		return w.csv.Write(header)
+	case "json":
+		_, err := io.WriteString(w.writer, "[\n")
+		return err
+	}
+	return nil
+}
+
+func (w *userExportWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+}
+
+func formatExportValue(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case bool:
+		return strconv.FormatBool(typed)
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%v", value)
+}
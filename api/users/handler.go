@@ -1,11 +1,16 @@
 package users
 
 import (
+	"auth/logging"
+	"auth/model"
 	"auth/service"
 	"auth/utils"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -13,29 +18,91 @@ import (
 
 // UserHandler gère les requêtes liées aux utilisateurs
 type UserHandler struct {
-	userService *service.UserService
+	userService      *service.UserService
+	groupService     *service.GroupService
+	importService    *service.UserImportService
+	exportService    *service.DataExportService
+	attributeService *service.UserAttributeService
+	profileService   *service.ProfileService
 }
 
 // NewUserHandler crée une nouvelle instance de UserHandler
-func NewUserHandler(userService *service.UserService) *UserHandler {
+func NewUserHandler(
+	userService *service.UserService,
+	groupService *service.GroupService,
+	importService *service.UserImportService,
+	exportService *service.DataExportService,
+	attributeService *service.UserAttributeService,
+	profileService *service.ProfileService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:      userService,
+		groupService:     groupService,
+		importService:    importService,
+		exportService:    exportService,
+		attributeService: attributeService,
+		profileService:   profileService,
 	}
 }
 
+// tenantService limite le service à l'organisation active du token
+func (h *UserHandler) tenantService(ctx echo.Context) *service.UserService {
+	return h.userService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
 // GetAllUsersHandler gère la requête pour récupérer tous les utilisateurs
 // @Summary Récupère tous les utilisateurs
-// @Description Récupère la liste de tous les utilisateurs.
+// @Description Récupère une page d'utilisateurs de l'organisation active avec le nombre total de résultats.
+// @Description Pagination par offset (limit, offset) ou par curseur (cursor, retourné dans next_cursor).
+// @Description Le tri accepte name, email, username, created_at et updated_at, préfixés par "-" pour un ordre décroissant.
 // @Tags Users
 // @Produce json
-// @Success 200 {array} model.User
+// @Param limit query int false "Taille de la page (20 par défaut, 100 au maximum)"
+// @Param offset query int false "Nombre d'utilisateurs à ignorer"
+// @Param cursor query string false "Curseur de la page suivante"
+// @Param sort query string false "Champ de tri, par exemple -created_at"
+// @Param role query string false "Nom du rôle"
+// @Param email_domain query string false "Domaine de l'adresse email"
+// @Param created_from query string false "Date de création minimale (RFC3339 ou AAAA-MM-JJ)"
+// @Param created_to query string false "Date de création maximale, exclue (RFC3339 ou AAAA-MM-JJ)"
+// @Param is_available query bool false "Disponibilité du compte"
+// @Param use_otp query bool false "Double authentification activée"
+// @Success 200 {object} utils.HttpResponse[UserListOut]
 // @Router /users [get]
 func (h *UserHandler) GetAllUsersHandler(ctx echo.Context) error {
 
-	users, err := h.userService.GetAllUsers()
+	var payload UserListIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	listQuery, err := toUserListQuery(payload)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Aucun utilisateur trouvé",
+			Message:   err.Error(),
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -43,11 +110,21 @@ func (h *UserHandler) GetAllUsersHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	var userList []UserOut
+	page, err := h.tenantService(ctx).GetAllUsers(listQuery)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
 
-	for i := 0; i < len(users); i++ {
-		userItem := users[i]
-		currentData := UserOut{
+	userList := []UserOut{}
+	for i := 0; i < len(page.Users); i++ {
+		userItem := page.Users[i]
+		userList = append(userList, UserOut{
 			Id:        userItem.Id,
 			Name:      userItem.Name,
 			Email:     userItem.Email,
@@ -56,38 +133,201 @@ func (h *UserHandler) GetAllUsersHandler(ctx echo.Context) error {
 			Username:  userItem.Username,
 			CreatedAt: userItem.CreatedAt,
 			UpdatedAt: userItem.UpdatedAt,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[UserListOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: UserListOut{
+			Items:      userList,
+			Total:      page.Total,
+			Limit:      page.Limit,
+			Offset:     page.Offset,
+			NextCursor: page.NextCursor,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// SearchUsersHandler gère la recherche d'utilisateurs
+// @Summary Recherche des utilisateurs
+// @Description Recherche sans tenir compte de la casse dans le nom, le prénom, le nom d'utilisateur et l'email.
+// @Description Chaque mot doit apparaître dans l'un de ces champs ; les correspondances exactes puis par préfixe sont classées en premier.
+// @Tags Users
+// @Produce json
+// @Param q query string true "Texte recherché"
+// @Param limit query int false "Taille de la page (20 par défaut, 100 au maximum)"
+// @Param offset query int false "Nombre de résultats à ignorer"
+// @Success 200 {object} utils.HttpResponse[UserListOut]
+// @Router /users/search [get]
+func (h *UserHandler) SearchUsersHandler(ctx echo.Context) error {
+
+	var payload UserSearchIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
 		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
 
-		userList = append(userList, currentData)
+	page, err := h.tenantService(ctx).SearchUsers(payload.Q, payload.Limit, payload.Offset)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	if len(userList) == 0 {
-		userList = []UserOut{}
+	userList := []UserOut{}
+	for i := 0; i < len(page.Users); i++ {
+		userItem := page.Users[i]
+		userList = append(userList, UserOut{
+			Id:        userItem.Id,
+			Name:      userItem.Name,
+			Email:     userItem.Email,
+			Role:      userItem.RoleId,
+			Sername:   userItem.Sername,
+			Username:  userItem.Username,
+			CreatedAt: userItem.CreatedAt,
+			UpdatedAt: userItem.UpdatedAt,
+		})
 	}
 
-	jsonResponse := utils.HttpResponse[[]UserOut]{
+	jsonResponse := utils.HttpResponse[UserListOut]{
 		Message:   "Données récupérée avec succès",
 		Success:   true,
 		CodeError: http.StatusOK,
-		Data:      userList,
+		Data: UserListOut{
+			Items:  userList,
+			Total:  page.Total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
 	}
 	return ctx.JSON(http.StatusOK, jsonResponse)
 }
 
-// GetUserByIdHandler gère la requête pour récupérer un utilisateur par son ID
-// @Summary Récupère un utilisateur par ID
-// @Description Récupère un utilisateur en fonction de son ID.
+// ImportUsersHandler gère l'import d'utilisateurs en masse
+// @Summary Importe des utilisateurs
+// @Description Crée des utilisateurs dans l'organisation active depuis un fichier CSV (en-tête name,sername,email,username,password,role)
+// @Description ou JSON Lines, envoyé dans le champ "file" d'un formulaire multipart ou directement dans le corps.
+// @Description Chaque ligne est validée comme UserIn ; le rôle vaut "client" par défaut.
+// @Description Avec dry_run, le rapport est retourné sans rien écrire. Sinon l'import est transactionnel :
+// @Description une seule ligne invalide annule l'ensemble.
 // @Tags Users
+// @Accept mpfd
 // @Produce json
-// @Param id path string true "ID de l'utilisateur"
-// @Success 200 {object} model.User
-// @Router /users/{id} [get]
-func (h *UserHandler) GetUserByIdHandler(ctx echo.Context) error {
+// @Param file formData file false "Fichier CSV ou JSONL"
+// @Param dry_run query bool false "Simulation sans écriture"
+// @Param send_invitations query bool false "Envoie une invitation aux utilisateurs créés"
+// @Param format query string false "csv ou jsonl, déduit du fichier par défaut"
+// @Success 201 {object} utils.HttpResponse[service.ImportReport]
+// @Failure 422 {object} utils.HttpResponse[service.ImportReport]
+// @Router /users/import [post]
+func (h *UserHandler) ImportUsersHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "import_users")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
 
-	userId := ctx.Param("id")
-	if userId == "" {
+	var query UserImportQueryIn
+	if err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &query); err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Invalid user Id",
+			Message:   "Paramètres invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	body := ctx.Request().Body
+	filename := ""
+	contentType := ctx.Request().Header.Get(echo.HeaderContentType)
+	if strings.HasPrefix(contentType, echo.MIMEMultipartForm) {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			jsonResponse := utils.HttpResponse[any]{
+				Message:   "Le fichier est manquant",
+				Success:   false,
+				CodeError: http.StatusBadRequest,
+				Data:      nil,
+			}
+			return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			jsonResponse := utils.HttpResponse[any]{
+				Message:   "Le fichier est illisible",
+				Success:   false,
+				CodeError: http.StatusBadRequest,
+				Data:      nil,
+			}
+			return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		}
+		defer file.Close()
+
+		body = file
+		filename = fileHeader.Filename
+		contentType = fileHeader.Header.Get(echo.HeaderContentType)
+	}
+
+	format, err := detectImportFormat(query.Format, filename, contentType)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -95,10 +335,10 @@ func (h *UserHandler) GetUserByIdHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	userProfil, err := h.userService.GetUserById(fmt.Sprintf("%s", userId))
+	rows, err := parseImportFile(body, format)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Ce compte n'existe dans le système",
+			Message:   err.Error(),
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -106,50 +346,131 @@ func (h *UserHandler) GetUserByIdHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	jsonResponse := utils.HttpResponse[UserOut]{
-		Message:   "Données récupérée avec succès",
+	report, err := h.importService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx)).Import(rows, service.ImportOptions{
+		DryRun:          query.DryRun,
+		SendInvitations: query.SendInvitations,
+	})
+	if errors.Is(err, service.ErrImportRejected) {
+		jsonResponse := utils.HttpResponse[service.ImportReport]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      report,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	if query.DryRun {
+		jsonResponse := utils.HttpResponse[service.ImportReport]{
+			Message:   "Simulation terminée, aucun utilisateur n'a été créé",
+			Success:   true,
+			CodeError: http.StatusOK,
+			Data:      report,
+		}
+		return ctx.JSON(http.StatusOK, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[service.ImportReport]{
+		Message:   "Les utilisateurs ont été importés",
 		Success:   true,
-		CodeError: http.StatusOK,
-		Data: UserOut{
-			Id:        userProfil.Id,
-			Name:      userProfil.Name,
-			Email:     userProfil.Email,
-			Role:      userProfil.RoleId,
-			Sername:   userProfil.Sername,
-			Username:  userProfil.Username,
-			CreatedAt: userProfil.CreatedAt,
-			UpdatedAt: userProfil.UpdatedAt,
-		},
+		CodeError: http.StatusCreated,
+		Data:      report,
 	}
-	return ctx.JSON(http.StatusOK, jsonResponse)
+	return ctx.JSON(http.StatusCreated, jsonResponse)
 }
 
-// GetUserProfileHandler View user profile info
-// @Summary User profile
-// @Description view user profile info.
+// ExportUsersHandler exporte les utilisateurs de l'organisation active
+// @Summary Exporte les utilisateurs
+// @Description Exporte en continu tous les utilisateurs correspondant aux filtres, au format CSV, JSON ou JSON Lines.
+// @Description Les champs exportables sont id, name, sername, email, username, role, use_otp, is_available, created_at et updated_at.
 // @Tags Users
+// @Produce text/csv
 // @Produce json
-// @Success 200 {object} utils.HttpResponse[users.UserOut]
-// @Router /users/profile [get]
-func (h *UserHandler) GetUserProfileHandler(ctx echo.Context) error {
+// @Param format query string false "csv (par défaut), json ou jsonl"
+// @Param fields query string false "Champs exportés, séparés par des virgules (tous par défaut)"
+// @Param sort query string false "Champ de tri, par exemple -created_at"
+// @Param role query string false "Nom du rôle"
+// @Param email_domain query string false "Domaine de l'adresse email"
+// @Param created_from query string false "Date de création minimale (RFC3339 ou AAAA-MM-JJ)"
+// @Param created_to query string false "Date de création maximale, exclue (RFC3339 ou AAAA-MM-JJ)"
+// @Param is_available query bool false "Disponibilité du compte"
+// @Param use_otp query bool false "Double authentification activée"
+// @Success 200 {file} file
+// @Router /users/export [get]
+func (h *UserHandler) ExportUsersHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "export_users")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
 
-	userId := ctx.Get("userId")
+	var payload UserExportIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres d'export invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
 
-	err := utils.VerifyPermission(ctx, "view_user_profile")
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres d'export invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	fields, err := selectExportFields(payload.Fields)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Message:   err.Error(),
 			Success:   false,
-			CodeError: http.StatusUnauthorized,
+			CodeError: http.StatusBadRequest,
 			Data:      nil,
 		}
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	userProfil, err := h.userService.GetUserById(fmt.Sprintf("%s", userId))
+	listQuery, err := toUserListQuery(UserListIn{
+		Sort:        payload.Sort,
+		Role:        payload.Role,
+		EmailDomain: payload.EmailDomain,
+		CreatedFrom: payload.CreatedFrom,
+		CreatedTo:   payload.CreatedTo,
+		IsAvailable: payload.IsAvailable,
+		UseOTP:      payload.UseOTP,
+	})
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Ce compte n'existe dans le système",
+			Message:   err.Error(),
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -157,39 +478,1229 @@ func (h *UserHandler) GetUserProfileHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	jsonResponse := utils.HttpResponse[UserOut]{
-		Message:   "Données récupérée avec succès",
+	format := payload.Format
+	if format == "" {
+		format = "csv"
+	}
+	contentType, extension := exportContentType(format)
+	fileName := fmt.Sprintf("users-%s.%s", time.Now().Format("20060102-150405"), extension)
+
+	response := ctx.Response()
+	response.Header().Set(echo.HeaderContentType, contentType)
+	response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+
+	exportWriter := newUserExportWriter(format, fields, response)
+	err = h.tenantService(ctx).StreamUsers(listQuery, exportWriter.Write)
+	if err == nil {
+		err = exportWriter.Close()
+	}
+	if err != nil {
+		return exportFailure(ctx, err, "nous avons rencontré un problème durant l'export des utilisateurs")
+	}
+	return nil
+}
+
+// ExportMyDataHandler télécharge l'archive des données personnelles de l'utilisateur connecté
+// @Summary Exporte mes données personnelles
+// @Description Archive zip contenant le profil, les organisations, les groupes, la double authentification, les impersonations, les attributs personnalisés et les sessions.
+// @Tags Users
+// @Produce application/zip
+// @Success 200 {file} file
+// @Router /users/me/data [get]
+func (h *UserHandler) ExportMyDataHandler(ctx echo.Context) error {
+	return h.writeUserArchive(ctx, fmt.Sprintf("%v", ctx.Get("userId")))
+}
+
+// ExportUserDataHandler télécharge l'archive des données personnelles d'un utilisateur de l'organisation
+// @Summary Exporte les données personnelles d'un utilisateur
+// @Description Archive zip contenant le profil, les organisations, les groupes, la double authentification, les impersonations, les attributs personnalisés et les sessions.
+// @Tags Users
+// @Produce application/zip
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {file} file
+// @Router /users/{id}/data [get]
+func (h *UserHandler) ExportUserDataHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "export_user_data")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	user, err := h.tenantService(ctx).GetUserById(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Ce compte n'existe dans le système",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.writeUserArchive(ctx, user.Id)
+}
+
+func (h *UserHandler) writeUserArchive(ctx echo.Context, userId string) error {
+	fileName := fmt.Sprintf("personal-data-%s.zip", time.Now().Format("20060102-150405"))
+
+	response := ctx.Response()
+	response.Header().Set(echo.HeaderContentType, "application/zip")
+	response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+
+	if err := h.exportService.WithContext(ctx.Request().Context()).WriteUserArchive(userId, response); err != nil {
+		return exportFailure(ctx, err, err.Error())
+	}
+	return nil
+}
+
+// exportFailure répond en JSON tant que rien n'a été envoyé ; sinon le flux est simplement interrompu
+func exportFailure(ctx echo.Context, err error, message string) error {
+	if ctx.Response().Committed {
+		logging.FromContext(ctx.Request().Context()).Warn("Export interrupted", "error", err)
+		return nil
+	}
+
+	ctx.Response().Header().Del(echo.HeaderContentDisposition)
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   message,
+		Success:   false,
+		CodeError: http.StatusBadRequest,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusBadRequest, jsonResponse)
+}
+
+// GetUserByIdHandler gère la requête pour récupérer un utilisateur par son ID
+// @Summary Récupère un utilisateur par ID
+// @Description Récupère un utilisateur en fonction de son ID.
+// @Tags Users
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {object} model.User
+// @Router /users/{id} [get]
+func (h *UserHandler) GetUserByIdHandler(ctx echo.Context) error {
+
+	userId := ctx.Param("id")
+	if userId == "" {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Invalid user Id",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	userProfil, err := h.tenantService(ctx).GetUserById(fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Ce compte n'existe dans le système",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: UserOut{
+			Id:        userProfil.Id,
+			Name:      userProfil.Name,
+			Email:     userProfil.Email,
+			Role:      userProfil.RoleId,
+			Sername:   userProfil.Sername,
+			Username:  userProfil.Username,
+			CreatedAt: userProfil.CreatedAt,
+			UpdatedAt: userProfil.UpdatedAt,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetUserProfileHandler View user profile info
+// @Summary User profile
+// @Description view user profile info.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[users.UserOut]
+// @Router /users/profile [get]
+func (h *UserHandler) GetUserProfileHandler(ctx echo.Context) error {
+
+	userId := ctx.Get("userId")
+
+	err := utils.VerifyPermission(ctx, "view_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	userProfil, err := h.tenantService(ctx).GetUserById(fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Ce compte n'existe dans le système",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: UserOut{
+			Id:        userProfil.Id,
+			Name:      userProfil.Name,
+			Email:     userProfil.Email,
+			Role:      userProfil.RoleId,
+			Sername:   userProfil.Sername,
+			Username:  userProfil.Username,
+			CreatedAt: userProfil.CreatedAt,
+			UpdatedAt: userProfil.UpdatedAt,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// CreateUserHandler gère la requête pour créer un nouvel utilisateur
+// @Summary Crée un nouvel utilisateur
+// @Description Crée un nouvel utilisateur avec les détails fournis.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param user body UserIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[UserOut]
+// @Router /users [post]
+func (h *UserHandler) CreateUserHandler(ctx echo.Context) error {
+
+	var payload UserIn
+
+	/** Verify user permission
+	err := utils.VerifyPermission(ctx, "create_user")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+	*/
+
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		// Parcourez les erreurs de validation
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	newUser := service.User(payload)
+
+	createdUser, err := h.userService.WithContext(ctx.Request().Context()).CreateUser(newUser)
+	utils.SetAuditTarget(ctx, createdUser.Id)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserOut]{
+		Message:   "User has been created",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data: UserOut{
+			Id:        createdUser.Id,
+			Username:  createdUser.Username,
+			Email:     createdUser.Email,
+			Role:      createdUser.RoleId,
+			Sername:   createdUser.Sername,
+			Name:      createdUser.Name,
+			CreatedAt: createdUser.CreatedAt,
+		},
+	}
+
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// UpdateUserByIdHandler gère la requête pour mettre à jour un utilisateur
+// @Summary Met à jour un utilisateur
+// @Description Met à jour un utilisateur en fonction de son ID avec les détails fournis.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Param user body UserIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[UserOut]
+// @Router /users/{id} [put]
+func (h *UserHandler) UpdateUserByIdHandler(ctx echo.Context) error {
+
+	userId := ctx.Param("id")
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	if userId == "" {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Invalid user Id",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload UpdateUserIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	existingUser, err := h.tenantService(ctx).GetUserById(fmt.Sprintf("%s", userId))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Ce compte n'existe dans le système",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	if payload.Name != existingUser.Name {
+		existingUser.Name = payload.Name
+	}
+
+	if payload.Sername != existingUser.Sername {
+		existingUser.Sername = payload.Sername
+	}
+
+	if payload.Email != existingUser.Email {
+		_, err = h.tenantService(ctx).GetUserByEmail(payload.Email)
+		if err == nil {
+			jsonResponse := utils.HttpResponse[any]{
+				Message:   "Ce email a déjà été utilisé",
+				Success:   false,
+				CodeError: http.StatusBadRequest,
+				Data:      nil,
+			}
+			return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		}
+
+		existingUser.Email = payload.Email
+	}
+
+	newUser := service.User{
+		Name:     existingUser.Name,
+		Email:    existingUser.Email,
+		Sername:  existingUser.Sername,
+		Username: existingUser.Username,
+		Password: existingUser.Password,
+	}
+
+	updateUserResponse, err := h.tenantService(ctx).UpdateUser(userId, newUser)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserOut]{
+		Message:   "Compte utilisateur mise à jour avec succès",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data: UserOut{
+			Id:        updateUserResponse.Id,
+			Name:      updateUserResponse.Name,
+			Email:     updateUserResponse.Email,
+			Sername:   updateUserResponse.Sername,
+			Username:  updateUserResponse.Username,
+			CreatedAt: updateUserResponse.CreatedAt,
+			UpdatedAt: updateUserResponse.UpdatedAt,
+			Role:      updateUserResponse.RoleId,
+		},
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// DeleteUserHandler gère la requête pour supprimer un utilisateur
+// @Summary Supprime un utilisateur
+// @Description Supprime un utilisateur en fonction de son ID.
+// @Tags Users
+// @Param id path int true "ID de l'utilisateur"
+// @Success 204 "Aucun contenu"
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUserHandler(ctx echo.Context) error {
+
+	userId := ctx.Param("id")
+
+	err := utils.VerifyPermission(ctx, "delete_user")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	if userId == "" {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Invalid user Id",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).DeleteUser(userId)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Invalid user Id",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "User has been deleted",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// RestoreUserHandler annule la suppression d'un utilisateur
+// @Summary Restaure un utilisateur supprimé
+// @Description Rend de nouveau actif un utilisateur supprimé, tant que la durée de rétention n'est pas écoulée.
+// @Tags Users
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {object} utils.HttpResponse[users.UserOut]
+// @Router /users/{id}/restore [post]
+func (h *UserHandler) RestoreUserHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "restore_user")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	restoredUser, err := h.tenantService(ctx).RestoreUser(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserOut]{
+		Message:   "L'utilisateur a été restauré",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: UserOut{
+			Id:        restoredUser.Id,
+			Name:      restoredUser.Name,
+			Email:     restoredUser.Email,
+			Role:      restoredUser.RoleId,
+			Sername:   restoredUser.Sername,
+			Username:  restoredUser.Username,
+			CreatedAt: restoredUser.CreatedAt,
+			UpdatedAt: restoredUser.UpdatedAt,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// DisableUserHandler désactive un compte utilisateur
+// @Summary Désactive un utilisateur
+// @Description Désactive le compte et révoque immédiatement tous ses tokens, y compris les refresh tokens.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Param disable body DisableUserIn false "Motif de la désactivation"
+// @Success 200 {object} utils.HttpResponse[users.AccountStateOut]
+// @Router /users/{id}/disable [put]
+func (h *UserHandler) DisableUserHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_account_states")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload DisableUserIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Validation failed",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	actorId := fmt.Sprintf("%v", ctx.Get("userId"))
+	disabledUser, err := h.tenantService(ctx).DisableUser(ctx.Param("id"), actorId, payload.Reason)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[AccountStateOut]{
+		Message:   "Le compte a été désactivé",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toAccountStateOut(disabledUser),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// EnableUserHandler réactive un compte utilisateur
+// @Summary Active un utilisateur
+// @Description Rend actif un compte désactivé, verrouillé ou en attente d'activation.
+// @Description Pour un mot de passe expiré, lève l'expiration sans renouvellement.
+// @Tags Users
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {object} utils.HttpResponse[users.AccountStateOut]
+// @Router /users/{id}/enable [put]
+func (h *UserHandler) EnableUserHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_account_states")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	enabledUser, err := h.tenantService(ctx).EnableUser(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[AccountStateOut]{
+		Message:   "Le compte a été activé",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toAccountStateOut(enabledUser),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// ExpirePasswordHandler fait expirer le mot de passe d'un utilisateur
+// @Summary Fait expirer le mot de passe d'un utilisateur
+// @Description Les tokens de l'utilisateur sont refusés jusqu'au renouvellement de son mot de passe par PUT /auth/renew_password.
+// @Tags Users
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {object} utils.HttpResponse[users.AccountStateOut]
+// @Router /users/{id}/expire-password [put]
+func (h *UserHandler) ExpirePasswordHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_account_states")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	actorId := fmt.Sprintf("%v", ctx.Get("userId"))
+	expiredUser, err := h.tenantService(ctx).ExpirePassword(ctx.Param("id"), actorId)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[AccountStateOut]{
+		Message:   "Le mot de passe de l'utilisateur a expiré",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toAccountStateOut(expiredUser),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// AssignRoleHandler gère la requête pour assigner un rôle à un utilisateur
+// @Summary Assigner un rôle à un utilisateur
+// @Description Ajoute le rôle aux rôles du membre dans l'organisation active. Avec global à true, remplace le rôle système de l'utilisateur, réservé aux administrateurs système.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Param request body users.AssignRoleIn true "Rôle à assigner"
+// @Success 200 {object} utils.HttpResponse[users.UserRolesOut]
+// @Router /users/{id}/assign-role [put]
+func (h *UserHandler) AssignRoleHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_organization_members")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	userId := ctx.Param("id")
+	if userId == "" {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "ID invalide",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload AssignRoleIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Validation failed",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var rolesOut UserRolesOut
+	if payload.Global {
+		actorRole := ""
+		if claims, ok := ctx.Get("claims").(*model.Claims); ok {
+			actorRole = claims.Role
+		}
+		var updatedUser model.User
+		updatedUser, err = h.tenantService(ctx).AssignSystemRole(userId, payload.Role, actorRole)
+		rolesOut = UserRolesOut{UserId: updatedUser.Id, Role: updatedUser.RoleId}
+	} else {
+		var memberRoles service.MemberRoles
+		memberRoles, err = h.tenantService(ctx).AssignRole(userId, payload.Role)
+		rolesOut = toUserRolesOut(memberRoles)
+	}
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserRolesOut]{
+		Message:   "Le rôle a été assigné",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      rolesOut,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// RemoveRoleHandler gère la requête pour supprimer un rôle d'un utilisateur
+// @Summary Supprimer un rôle d'un utilisateur
+// @Description Retire le rôle des rôles du membre dans l'organisation active. Le rôle système de l'utilisateur n'est pas modifié.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Param request body users.RemoveRoleIn true "Rôle à retirer"
+// @Success 200 {object} utils.HttpResponse[users.UserRolesOut]
+// @Router /users/{id}/remove-role [put]
+func (h *UserHandler) RemoveRoleHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_organization_members")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: 401,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	userId := ctx.Param("id")
+	if userId == "" {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "ID invalide",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload RemoveRoleIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Validation failed",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	memberRoles, err := h.tenantService(ctx).RemoveRole(userId, payload.Role)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[UserRolesOut]{
+		Message:   "Le rôle a été retiré",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toUserRolesOut(memberRoles),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+func toUserRolesOut(memberRoles service.MemberRoles) UserRolesOut {
+	roles := memberRoles.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return UserRolesOut{UserId: memberRoles.UserId, OrgId: memberRoles.OrgId, Roles: roles}
+}
+
+// GetUserGroupsHandler gère la requête pour lister les groupes d'un utilisateur
+// @Summary Groupes d'un utilisateur
+// @Description Liste les groupes de l'utilisateur dans l'organisation active avec leurs rôles.
+// @Tags Users
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {object} utils.HttpResponse[[]UserGroupOut]
+// @Router /users/{id}/groups [get]
+func (h *UserHandler) GetUserGroupsHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_groups")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	groups, err := h.groupService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx)).GetUserGroups(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	groupList := []UserGroupOut{}
+	for i := 0; i < len(groups); i++ {
+		groupList = append(groupList, UserGroupOut{
+			Id:    groups[i].Group.Id,
+			Name:  groups[i].Group.Name,
+			Roles: groups[i].Roles,
+		})
+	}
+
+	jsonResponse := utils.HttpResponse[[]UserGroupOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      groupList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetMyAttributesHandler gère la requête pour récupérer ses propres attributs personnalisés
+// @Summary Mes attributs personnalisés
+// @Description Récupère les attributs personnalisés de l'utilisateur connecté dans l'organisation active.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[any]
+// @Router /users/me/attributes [get]
+func (h *UserHandler) GetMyAttributesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.getUserAttributes(ctx, fmt.Sprintf("%v", ctx.Get("userId")))
+}
+
+// UpdateMyAttributesHandler gère la requête pour modifier ses propres attributs personnalisés
+// @Summary Modifie mes attributs personnalisés
+// @Description Modifie les attributs personnalisés de l'utilisateur connecté. Seuls les attributs modifiables par l'utilisateur sont acceptés ; une valeur null supprime l'attribut.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param attributes body UserAttributesIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /users/me/attributes [put]
+func (h *UserHandler) UpdateMyAttributesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.updateUserAttributes(ctx, fmt.Sprintf("%v", ctx.Get("userId")), true)
+}
+
+// GetUserAttributesHandler gère la requête pour récupérer les attributs personnalisés d'un utilisateur
+// @Summary Attributs personnalisés d'un utilisateur
+// @Description Récupère les attributs personnalisés d'un utilisateur de l'organisation active.
+// @Tags Users
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Success 200 {object} utils.HttpResponse[any]
+// @Router /users/{id}/attributes [get]
+func (h *UserHandler) GetUserAttributesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.getUserAttributes(ctx, ctx.Param("id"))
+}
+
+// UpdateUserAttributesHandler gère la requête pour modifier les attributs personnalisés d'un utilisateur
+// @Summary Modifie les attributs personnalisés d'un utilisateur
+// @Description Modifie les attributs personnalisés d'un utilisateur de l'organisation active ; une valeur null supprime l'attribut.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de l'utilisateur"
+// @Param attributes body UserAttributesIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /users/{id}/attributes [put]
+func (h *UserHandler) UpdateUserAttributesHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_attributes")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	return h.updateUserAttributes(ctx, ctx.Param("id"), false)
+}
+
+func (h *UserHandler) getUserAttributes(ctx echo.Context, userId string) error {
+	attributes, err := h.attributeService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx)).GetUserAttributes(userId)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[map[string]interface{}]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      attributes,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+func (h *UserHandler) updateUserAttributes(ctx echo.Context, userId string, selfService bool) error {
+	var payload UserAttributesIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	attributes, err := h.attributeService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx)).
+		UpdateUserAttributes(userId, payload.Attributes, selfService)
+	if err != nil {
+		var attributeErr *service.AttributeValidationError
+		if errors.As(err, &attributeErr) {
+			jsonResponse := utils.HttpResponse[any]{
+				Message:   err.Error(),
+				Success:   false,
+				CodeError: http.StatusBadRequest,
+				Data:      attributeErr.Errors,
+			}
+			return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[map[string]interface{}]{
+		Message:   "Les attributs ont bien été mis à jour",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      attributes,
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+// GetMeHandler gère la requête pour récupérer le profil de l'utilisateur connecté
+// @Summary Mon profil
+// @Description Récupère le profil de l'utilisateur connecté, avec sa langue, sa photo et les changements d'identifiants en attente.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[ProfileOut]
+// @Router /users/me [get]
+func (h *UserHandler) GetMeHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "view_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	user, err := h.profileService.WithContext(ctx.Request().Context()).GetProfile(fmt.Sprintf("%v", ctx.Get("userId")))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[ProfileOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      h.toProfileOut(ctx, user),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// UpdateMeHandler gère la requête pour modifier le profil de l'utilisateur connecté
+// @Summary Modifie mon profil
+// @Description Modifie le nom, le prénom et la langue préférée de l'utilisateur connecté.
+// @Description L'email et le nom d'utilisateur se modifient via /users/me/email et /users/me/username.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param profile body UpdateProfileIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[ProfileOut]
+// @Router /users/me [put]
+func (h *UserHandler) UpdateMeHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload UpdateProfileIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	user, err := h.profileService.WithContext(ctx.Request().Context()).UpdateProfile(fmt.Sprintf("%v", ctx.Get("userId")), service.ProfileUpdate(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[ProfileOut]{
+		Message:   "Compte utilisateur mise à jour avec succès",
 		Success:   true,
-		CodeError: http.StatusOK,
-		Data: UserOut{
-			Id:        userProfil.Id,
-			Name:      userProfil.Name,
-			Email:     userProfil.Email,
-			Role:      userProfil.RoleId,
-			Sername:   userProfil.Sername,
-			Username:  userProfil.Username,
-			CreatedAt: userProfil.CreatedAt,
-			UpdatedAt: userProfil.UpdatedAt,
-		},
+		CodeError: http.StatusAccepted,
+		Data:      h.toProfileOut(ctx, user),
 	}
-	return ctx.JSON(http.StatusOK, jsonResponse)
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
 }
 
-// CreateUserHandler gère la requête pour créer un nouvel utilisateur
-// @Summary Crée un nouvel utilisateur
-// @Description Crée un nouvel utilisateur avec les détails fournis.
+// RequestEmailChangeHandler gère la demande de changement d'email de l'utilisateur connecté
+// @Summary Change mon adresse email
+// @Description Vérifie le mot de passe actuel et envoie un lien de confirmation à la nouvelle adresse.
+// @Description L'adresse n'est modifiée qu'après confirmation, et toutes les sessions sont alors révoquées.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param user body UserIn true "Body data"
-// @Success 201 {object} utils.HttpResponse[UserOut]
-// @Router /users [post]
-func (h *UserHandler) CreateUserHandler(ctx echo.Context) error {
+// @Param change body ChangeEmailIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[IdentityChangeOut]
+// @Router /users/me/email [post]
+func (h *UserHandler) RequestEmailChangeHandler(ctx echo.Context) error {
 
-	var payload UserIn
-
-	/** Verify user permission
-	err := utils.VerifyPermission(ctx, "create_user")
+	err := utils.VerifyPermission(ctx, "update_user_profile")
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
@@ -199,8 +1710,8 @@ func (h *UserHandler) CreateUserHandler(ctx echo.Context) error {
 		}
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
-	*/
 
+	var payload ChangeEmailIn
 	if err := ctx.Bind(&payload); err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   "Données JSON invalides",
@@ -216,7 +1727,6 @@ func (h *UserHandler) CreateUserHandler(ctx echo.Context) error {
 	if err := validate.Struct(payload); err != nil {
 		var validationErrors []string
 
-		// Parcourez les erreurs de validation
 		for _, err := range err.(validator.ValidationErrors) {
 			validationErrors = append(validationErrors, formatValidationError(err))
 		}
@@ -230,9 +1740,7 @@ func (h *UserHandler) CreateUserHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	newUser := service.User(payload)
-
-	createdUser, err := h.userService.CreateUser(newUser)
+	change, err := h.profileService.WithContext(ctx.Request().Context()).RequestEmailChange(fmt.Sprintf("%v", ctx.Get("userId")), payload.CurrentPassword, payload.Email)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   err.Error(),
@@ -243,36 +1751,26 @@ func (h *UserHandler) CreateUserHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
 	}
 
-	jsonResponse := utils.HttpResponse[UserOut]{
-		Message:   "User has been created",
+	jsonResponse := utils.HttpResponse[IdentityChangeOut]{
+		Message:   "Un lien de confirmation a été envoyé à la nouvelle adresse email",
 		Success:   true,
-		CodeError: http.StatusCreated,
-		Data: UserOut{
-			Id:        createdUser.Id,
-			Username:  createdUser.Username,
-			Email:     createdUser.Email,
-			Role:      createdUser.RoleId,
-			Sername:   createdUser.Sername,
-			Name:      createdUser.Name,
-			CreatedAt: createdUser.CreatedAt,
-		},
+		CodeError: http.StatusAccepted,
+		Data:      toIdentityChangeOut(change),
 	}
-
-	return ctx.JSON(http.StatusCreated, jsonResponse)
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
 }
 
-// UpdateUserHandler gère la requête pour mettre à jour un utilisateur
-// @Summary Met à jour un utilisateur
-// @Description Met à jour un utilisateur en fonction de son ID avec les détails fournis.
+// RequestUsernameChangeHandler gère la demande de changement de nom d'utilisateur de l'utilisateur connecté
+// @Summary Change mon nom d'utilisateur
+// @Description Vérifie le mot de passe actuel et envoie un lien de confirmation à l'adresse email du compte.
+// @Description Le nom d'utilisateur n'est modifié qu'après confirmation, et toutes les sessions sont alors révoquées.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param user body UserIn true "Détails de l'utilisateur à mettre à jour"
-// @Success 200 {object} utils.HttpResponse[UserOut]
-// @Router /users [put]
-func (h *UserHandler) UpdateUserHandler(ctx echo.Context) error {
-
-	userId := ctx.Get("userId")
+// @Param change body ChangeUsernameIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[IdentityChangeOut]
+// @Router /users/me/username [post]
+func (h *UserHandler) RequestUsernameChangeHandler(ctx echo.Context) error {
 
 	err := utils.VerifyPermission(ctx, "update_user_profile")
 	if err != nil {
@@ -285,7 +1783,7 @@ func (h *UserHandler) UpdateUserHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	var payload UpdateUserIn
+	var payload ChangeUsernameIn
 	if err := ctx.Bind(&payload); err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   "Données JSON invalides",
@@ -314,49 +1812,68 @@ func (h *UserHandler) UpdateUserHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	existingUser, err := h.userService.GetUserById(fmt.Sprintf("%s", userId))
+	change, err := h.profileService.WithContext(ctx.Request().Context()).RequestUsernameChange(fmt.Sprintf("%v", ctx.Get("userId")), payload.CurrentPassword, payload.Username)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Ce compte n'existe dans le système",
+			Message:   err.Error(),
 			Success:   false,
-			CodeError: http.StatusBadRequest,
+			CodeError: http.StatusUnprocessableEntity,
 			Data:      nil,
 		}
-		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
 	}
 
-	if payload.Name != existingUser.Name {
-		existingUser.Name = payload.Name
+	jsonResponse := utils.HttpResponse[IdentityChangeOut]{
+		Message:   "Un lien de confirmation a été envoyé à votre adresse email",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toIdentityChangeOut(change),
 	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
 
-	if payload.Sername != existingUser.Sername {
-		existingUser.Sername = payload.Sername
-	}
+// ConfirmIdentityChangeHandler gère la confirmation d'un changement d'email ou de nom d'utilisateur
+// @Summary Confirme un changement d'identifiant
+// @Description Applique le changement associé au lien reçu par email. Le lien n'est utilisable qu'une fois.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param confirmation body ConfirmIdentityChangeIn true "Body data"
+// @Success 202 {object} utils.HttpResponse[IdentityChangeOut]
+// @Router /users/confirm-change [post]
+func (h *UserHandler) ConfirmIdentityChangeHandler(ctx echo.Context) error {
 
-	if payload.Email != existingUser.Email {
-		_, err = h.userService.GetUserByEmail(payload.Email)
-		if err == nil {
-			jsonResponse := utils.HttpResponse[any]{
-				Message:   "Ce email a déjà été utilisé",
-				Success:   false,
-				CodeError: http.StatusBadRequest,
-				Data:      nil,
-			}
-			return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	var payload ConfirmIdentityChangeIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
 		}
-
-		existingUser.Name = payload.Name
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	newUser := service.User{
-		Name:     existingUser.Name,
-		Email:    existingUser.Email,
-		Sername:  existingUser.Sername,
-		Username: existingUser.Username,
-		Password: existingUser.Password,
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	updateUserResponse, err := h.userService.UpdateUser(newUser)
+	change, err := h.profileService.WithContext(ctx.Request().Context()).ConfirmIdentityChange(payload.Token)
+	utils.SetAuditTarget(ctx, change.UserId)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   err.Error(),
@@ -367,37 +1884,25 @@ func (h *UserHandler) UpdateUserHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
 	}
 
-	jsonResponse := utils.HttpResponse[UserOut]{
-		Message:   "Compte utilisateur mise à jour avec succès",
+	jsonResponse := utils.HttpResponse[IdentityChangeOut]{
+		Message:   "Le changement a bien été appliqué, veuillez vous reconnecter",
 		Success:   true,
 		CodeError: http.StatusAccepted,
-		Data: UserOut{
-			Id:        updateUserResponse.Id,
-			Name:      updateUserResponse.Name,
-			Email:     updateUserResponse.Email,
-			Sername:   updateUserResponse.Sername,
-			Username:  updateUserResponse.Username,
-			CreatedAt: updateUserResponse.CreatedAt,
-			UpdatedAt: updateUserResponse.UpdatedAt,
-			Role:      updateUserResponse.RoleId,
-		},
+		Data:      toIdentityChangeOut(change),
 	}
 	return ctx.JSON(http.StatusAccepted, jsonResponse)
 }
 
-// UpdateUserByIdHandler gère la requête pour mettre à jour un utilisateur
-// @Summary Met à jour un utilisateur
-// @Description Met à jour un utilisateur en fonction de son ID avec les détails fournis.
+// UploadAvatarHandler gère l'envoi de la photo de profil de l'utilisateur connecté
+// @Summary Envoie ma photo de profil
+// @Description Remplace la photo de profil. Images PNG, JPEG ou GIF uniquement, dans la limite de la taille configurée (AVATAR_MAX_SIZE).
 // @Tags Users
-// @Accept json
+// @Accept multipart/form-data
 // @Produce json
-// @Param id path string true "ID de l'utilisateur"
-// @Param user body UserIn true "Body data"
-// @Success 200 {object} utils.HttpResponse[UserOut]
-// @Router /users/{id} [put]
-func (h *UserHandler) UpdateUserByIdHandler(ctx echo.Context) error {
-
-	userId := ctx.Param("id")
+// @Param avatar formData file true "Image"
+// @Success 202 {object} utils.HttpResponse[ProfileOut]
+// @Router /users/me/avatar [put]
+func (h *UserHandler) UploadAvatarHandler(ctx echo.Context) error {
 
 	err := utils.VerifyPermission(ctx, "update_user_profile")
 	if err != nil {
@@ -410,9 +1915,10 @@ func (h *UserHandler) UpdateUserByIdHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	if userId == "" {
+	fileHeader, err := ctx.FormFile("avatar")
+	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Invalid user Id",
+			Message:   "Le fichier avatar est requis",
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -420,39 +1926,34 @@ func (h *UserHandler) UpdateUserByIdHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	var payload UpdateUserIn
-	if err := ctx.Bind(&payload); err != nil {
+	maxSize := h.profileService.AvatarMaxSize()
+	if fileHeader.Size > maxSize {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Données JSON invalides",
+			Message:   fmt.Sprintf("l'image ne doit pas dépasser %d octets", maxSize),
 			Success:   false,
-			CodeError: http.StatusBadRequest,
+			CodeError: http.StatusRequestEntityTooLarge,
 			Data:      nil,
 		}
-		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+		return ctx.JSON(http.StatusRequestEntityTooLarge, jsonResponse)
 	}
 
-	// Validation des données
-	validate := validator.New()
-	if err := validate.Struct(payload); err != nil {
-		var validationErrors []string
-
-		for _, err := range err.(validator.ValidationErrors) {
-			validationErrors = append(validationErrors, formatValidationError(err))
-		}
-
+	file, err := fileHeader.Open()
+	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Données JSON invalides",
+			Message:   "Le fichier avatar est illisible",
 			Success:   false,
 			CodeError: http.StatusBadRequest,
-			Data:      validationErrors,
+			Data:      nil,
 		}
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
+	defer file.Close()
 
-	existingUser, err := h.userService.GetUserById(fmt.Sprintf("%s", userId))
+	// La lecture est bornée : la taille annoncée par le client n'est pas une garantie
+	content, err := io.ReadAll(io.LimitReader(file, maxSize+1))
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Ce compte n'existe dans le système",
+			Message:   "Le fichier avatar est illisible",
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -460,38 +1961,7 @@ func (h *UserHandler) UpdateUserByIdHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	if payload.Name != existingUser.Name {
-		existingUser.Name = payload.Name
-	}
-
-	if payload.Sername != existingUser.Sername {
-		existingUser.Sername = payload.Sername
-	}
-
-	if payload.Email != existingUser.Email {
-		_, err = h.userService.GetUserByEmail(payload.Email)
-		if err == nil {
-			jsonResponse := utils.HttpResponse[any]{
-				Message:   "Ce email a déjà été utilisé",
-				Success:   false,
-				CodeError: http.StatusBadRequest,
-				Data:      nil,
-			}
-			return ctx.JSON(http.StatusBadRequest, jsonResponse)
-		}
-
-		existingUser.Email = payload.Email
-	}
-
-	newUser := service.User{
-		Name:     existingUser.Name,
-		Email:    existingUser.Email,
-		Sername:  existingUser.Sername,
-		Username: existingUser.Username,
-		Password: existingUser.Password,
-	}
-
-	updateUserResponse, err := h.userService.UpdateUser(newUser)
+	user, err := h.profileService.WithContext(ctx.Request().Context()).SetAvatar(fmt.Sprintf("%v", ctx.Get("userId")), content)
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   err.Error(),
@@ -502,36 +1972,25 @@ func (h *UserHandler) UpdateUserByIdHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
 	}
 
-	jsonResponse := utils.HttpResponse[UserOut]{
-		Message:   "Compte utilisateur mise à jour avec succès",
+	jsonResponse := utils.HttpResponse[ProfileOut]{
+		Message:   "La photo de profil a bien été enregistrée",
 		Success:   true,
 		CodeError: http.StatusAccepted,
-		Data: UserOut{
-			Id:        updateUserResponse.Id,
-			Name:      updateUserResponse.Name,
-			Email:     updateUserResponse.Email,
-			Sername:   updateUserResponse.Sername,
-			Username:  updateUserResponse.Username,
-			CreatedAt: updateUserResponse.CreatedAt,
-			UpdatedAt: updateUserResponse.UpdatedAt,
-			Role:      updateUserResponse.RoleId,
-		},
+		Data:      h.toProfileOut(ctx, user),
 	}
 	return ctx.JSON(http.StatusAccepted, jsonResponse)
 }
 
-// DeleteUserHandler gère la requête pour supprimer un utilisateur
-// @Summary Supprime un utilisateur
-// @Description Supprime un utilisateur en fonction de son ID.
+// GetMyAvatarHandler gère la requête pour télécharger la photo de profil de l'utilisateur connecté
+// @Summary Ma photo de profil
+// @Description Télécharge la photo de profil de l'utilisateur connecté.
 // @Tags Users
-// @Param id path int true "ID de l'utilisateur"
-// @Success 204 "Aucun contenu"
-// @Router /users/{id} [delete]
-func (h *UserHandler) DeleteUserHandler(ctx echo.Context) error {
-
-	userId := ctx.Param("id")
+// @Produce image/png,image/jpeg,image/gif
+// @Success 200 {file} file
+// @Router /users/me/avatar [get]
+func (h *UserHandler) GetMyAvatarHandler(ctx echo.Context) error {
 
-	err := utils.VerifyPermission(ctx, "delete_user")
+	err := utils.VerifyPermission(ctx, "view_user_profile")
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
 			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
@@ -542,20 +2001,44 @@ func (h *UserHandler) DeleteUserHandler(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	if userId == "" {
+	path, err := h.profileService.WithContext(ctx.Request().Context()).GetAvatarPath(fmt.Sprintf("%v", ctx.Get("userId")))
+	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Invalid user Id",
+			Message:   err.Error(),
 			Success:   false,
-			CodeError: http.StatusBadRequest,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	ctx.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return ctx.File(path)
+}
+
+// DeleteAvatarHandler gère la suppression de la photo de profil de l'utilisateur connecté
+// @Summary Supprime ma photo de profil
+// @Description Supprime la photo de profil de l'utilisateur connecté.
+// @Tags Users
+// @Success 202 {object} utils.HttpResponse[any]
+// @Router /users/me/avatar [delete]
+func (h *UserHandler) DeleteAvatarHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "update_user_profile")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
 			Data:      nil,
 		}
 		return ctx.JSON(http.StatusBadRequest, jsonResponse)
 	}
 
-	err = h.userService.DeleteUser(userId)
+	err = h.profileService.WithContext(ctx.Request().Context()).DeleteAvatar(fmt.Sprintf("%v", ctx.Get("userId")))
 	if err != nil {
 		jsonResponse := utils.HttpResponse[any]{
-			Message:   "Invalid user Id",
+			Message:   err.Error(),
 			Success:   false,
 			CodeError: http.StatusBadRequest,
 			Data:      nil,
@@ -564,7 +2047,7 @@ func (h *UserHandler) DeleteUserHandler(ctx echo.Context) error {
 	}
 
 	jsonResponse := utils.HttpResponse[any]{
-		Message:   "User has been deleted",
+		Message:   "La photo de profil a bien été supprimée",
 		Success:   true,
 		CodeError: http.StatusAccepted,
 		Data:      nil,
@@ -572,56 +2055,86 @@ func (h *UserHandler) DeleteUserHandler(ctx echo.Context) error {
 	return ctx.JSON(http.StatusAccepted, jsonResponse)
 }
 
-// AssignRoleHandler gère la requête pour assigner un rôle à un utilisateur
-// @Summary Assigner un rôle à un utilisateur
-// @Description Assigner un rôle spécifié à un utilisateur en fonction de son ID.
-// @Tags Users
-// @Param id path int true "ID de l'utilisateur"
-// @Param role path string true "Rôle à assigner"
-// @Success 204 "Aucun contenu"
-// @Router /users/{id}/assign-role/{role} [post]
-func (h *UserHandler) AssignRoleHandler(c echo.Context) error {
-	userId := c.Param("id")
-	if userId != "" {
-		return c.JSON(http.StatusBadRequest, "ID invalide")
+func (h *UserHandler) toProfileOut(ctx echo.Context, user model.User) ProfileOut {
+	profile := ProfileOut{
+		Id:             user.Id,
+		Name:           user.Name,
+		Sername:        user.Sername,
+		Email:          user.Email,
+		Username:       user.Username,
+		Locale:         user.Locale,
+		PendingChanges: []IdentityChangeOut{},
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
 	}
-
-	var roleRequest struct {
-		Role string `json:"role"`
+	if user.AvatarFile != "" {
+		profile.AvatarUrl = "/api/v1/users/me/avatar"
 	}
 
-	if err := c.Bind(&roleRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, "Données JSON invalides")
+	changes, _ := h.profileService.WithContext(ctx.Request().Context()).GetPendingChanges(user.Id)
+	for i := 0; i < len(changes); i++ {
+		profile.PendingChanges = append(profile.PendingChanges, toIdentityChangeOut(changes[i]))
 	}
+	return profile
+}
 
-	updatedUser, err := h.userService.AssignRole(userId, roleRequest.Role)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+func toIdentityChangeOut(change model.IdentityChange) IdentityChangeOut {
+	return IdentityChangeOut{
+		Id:        change.Id,
+		Field:     change.Field,
+		NewValue:  change.NewValue,
+		ExpiresAt: change.ExpiresAt,
 	}
+}
 
-	return c.JSON(http.StatusOK, updatedUser)
+func toAccountStateOut(user model.User) AccountStateOut {
+	return AccountStateOut{
+		Id:              user.Id,
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusChangedAt: user.StatusChangedAt,
+		LockedUntil:     user.LockedUntil,
+	}
 }
 
-// RemoveRoleHandler gère la requête pour supprimer un rôle d'un utilisateur
-// @Summary Supprimer un rôle d'un utilisateur
-// @Description Supprimer un rôle spécifié d'un utilisateur en fonction de son ID.
-// @Tags Users
-// @Param id path int true "ID de l'utilisateur"
-// @Param role path string true "Rôle à supprimer"
-// @Success 204 "Aucun contenu"
-// @Router /users/{id}/remove-role/{role} [post]
-func (h *UserHandler) RemoveRoleHandler(c echo.Context) error {
-	userId := c.Param("id")
-	if userId != "" {
-		return c.JSON(http.StatusBadRequest, "ID invalide")
+// toUserListQuery convertit les paramètres de la requête en critères de recherche
+func toUserListQuery(payload UserListIn) (service.UserListQuery, error) {
+	listQuery := service.UserListQuery{
+		Role:        payload.Role,
+		EmailDomain: payload.EmailDomain,
+		Sort:        payload.Sort,
+		Limit:       payload.Limit,
+		Offset:      payload.Offset,
+		Cursor:      payload.Cursor,
 	}
 
-	updatedUser, err := h.userService.RemoveRole(userId)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+	var err error
+	if listQuery.CreatedFrom, err = parseDateParam(payload.CreatedFrom); err != nil {
+		return service.UserListQuery{}, fmt.Errorf("created_from doit être une date RFC3339 ou AAAA-MM-JJ")
+	}
+	if listQuery.CreatedTo, err = parseDateParam(payload.CreatedTo); err != nil {
+		return service.UserListQuery{}, fmt.Errorf("created_to doit être une date RFC3339 ou AAAA-MM-JJ")
+	}
+
+	if payload.IsAvailable != "" {
+		isAvailable := payload.IsAvailable == "true"
+		listQuery.IsAvailable = &isAvailable
 	}
+	if payload.UseOTP != "" {
+		useOTP := payload.UseOTP == "true"
+		listQuery.UseOTP = &useOTP
+	}
+	return listQuery, nil
+}
 
-	return c.JSON(http.StatusOK, updatedUser)
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Parse("2006-01-02", value)
 }
 
 // Fonction pour formater les erreurs de validation
@@ -638,6 +2151,10 @@ func formatValidationError(err validator.FieldError) string {
 		return fieldName + " must be a valid email address"
 	case "uuid4":
 		return fieldName + " must be a valid UUID (version 4)"
+	case "oneof":
+		return fieldName + " must be one of " + err.Param()
+	case "fqdn":
+		return fieldName + " must be a valid domain name"
 	default:
 		return fieldName + " is invalid"
 	}
@@ -0,0 +1,162 @@
+package users
+
+import (
+	"auth/service"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// maxImportRows limite le nombre de lignes d'un fichier d'import
+const maxImportRows = 5000
+
+var importColumns = []string{"name", "sername", "email", "username", "password", "role"}
+
+// detectImportFormat déduit le format du fichier depuis le paramètre, le nom du fichier ou le Content-Type
+func detectImportFormat(format string, filename string, contentType string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+
+	filename = strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(filename, ".csv"), strings.HasPrefix(contentType, "text/csv"):
+		return "csv", nil
+	case strings.HasSuffix(filename, ".jsonl"), strings.HasSuffix(filename, ".ndjson"),
+		strings.HasPrefix(contentType, "application/x-ndjson"), strings.HasPrefix(contentType, "application/jsonl"):
+		return "jsonl", nil
+	}
+	return "", errors.New("format du fichier inconnu : utilisez csv ou jsonl")
+}
+
+// parseImportFile lit le fichier et valide chaque ligne avec les règles de UserIn
+func parseImportFile(reader io.Reader, format string) ([]service.ImportRow, error) {
+	var rows []service.ImportRow
+	var err error
+	if format == "csv" {
+		rows, err = parseImportCSV(reader)
+	} else {
+		rows, err = parseImportJSONL(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("le fichier ne contient aucun utilisateur")
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("le fichier dépasse la limite de %d utilisateurs", maxImportRows)
+	}
+	return rows, nil
+}
+
+func parseImportCSV(reader io.Reader) ([]service.ImportRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.New("fichier CSV invalide : en-tête manquant")
+	}
+
+	positions := map[string]int{}
+	for i := 0; i < len(header); i++ {
+		positions[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))] = i
+	}
+	for i := 0; i < len(importColumns); i++ {
+		if _, ok := positions[importColumns[i]]; !ok && importColumns[i] != "role" {
+			return nil, fmt.Errorf("fichier CSV invalide : colonne %s manquante", importColumns[i])
+		}
+	}
+
+	var rows []service.ImportRow
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) {
+				return nil, errors.New("fichier CSV illisible")
+			}
+			rows = append(rows, service.ImportRow{Line: parseError.StartLine, Errors: []string{"ligne CSV invalide"}})
+			continue
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		column := func(name string) string {
+			position, ok := positions[name]
+			if !ok || position >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[position])
+		}
+
+		rows = append(rows, toImportRow(line, UserImportIn{
+			Name:     column("name"),
+			Sername:  column("sername"),
+			Email:    column("email"),
+			Username: column("username"),
+			Password: column("password"),
+			Role:     column("role"),
+		}))
+	}
+	return rows, nil
+}
+
+func parseImportJSONL(reader io.Reader) ([]service.ImportRow, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []service.ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := strings.TrimSpace(scanner.Text())
+		if content == "" {
+			continue
+		}
+
+		var payload UserImportIn
+		if err := json.Unmarshal([]byte(content), &payload); err != nil {
+			rows = append(rows, service.ImportRow{Line: line, Errors: []string{"JSON invalide"}})
+			continue
+		}
+		rows = append(rows, toImportRow(line, payload))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("fichier JSONL illisible")
+	}
+	return rows, nil
+}
+
+func toImportRow(line int, payload UserImportIn) service.ImportRow {
+	row := service.ImportRow{
+		Line: line,
+		User: service.User{
+			Name:     payload.Name,
+			Email:    payload.Email,
+			Username: payload.Username,
+			Sername:  payload.Sername,
+			Password: payload.Password,
+		},
+		Role: payload.Role,
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			row.Errors = append(row.Errors, formatValidationError(err))
+		}
+	}
+	return row
+}
@@ -0,0 +1,115 @@
+package users
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectImportFormat(t *testing.T) {
+	cases := []struct {
+		format, filename, contentType string
+		want                          string
+	}{
+		{"jsonl", "users.csv", "", "jsonl"},
+		{"", "Users.CSV", "", "csv"},
+		{"", "users.jsonl", "", "jsonl"},
+		{"", "users.ndjson", "", "jsonl"},
+		{"", "upload", "text/csv; charset=utf-8", "csv"},
+		{"", "upload", "application/x-ndjson", "jsonl"},
+	}
+	for _, c := range cases {
+		got, err := detectImportFormat(c.format, c.filename, c.contentType)
+		if err != nil || got != c.want {
+			t.Errorf("detectImportFormat(%q, %q, %q) = %q, %v ; %q attendu", c.format, c.filename, c.contentType, got, err, c.want)
+		}
+	}
+
+	if _, err := detectImportFormat("", "users.xlsx", "application/octet-stream"); err == nil {
+		t.Error("un format inconnu doit être refusé")
+	}
+}
+
+func TestParseImportCSV(t *testing.T) {
+	content := "\ufeffEmail, Name,sername,username,password\n" +
+		"jane@example.com,Jane,Doe,jane,Secret@123\n" +
+		"not-an-email,John,Doe,,Secret@123\n" +
+		"short,row\n"
+
+	rows, err := parseImportFile(strings.NewReader(content), "csv")
+	if err != nil {
+		t.Fatalf("lecture : %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d lignes lues, 3 attendues", len(rows))
+	}
+
+	jane := rows[0]
+	if jane.Line != 2 || jane.User.Email != "jane@example.com" || jane.User.Name != "Jane" || jane.User.Username != "jane" || len(jane.Errors) != 0 {
+		t.Errorf("ligne 2 = %+v", jane)
+	}
+	if jane.Role != "" {
+		t.Errorf("rôle = %q, la colonne role est facultative", jane.Role)
+	}
+
+	john := rows[1]
+	if john.Line != 3 || !containsError(john.Errors, "email must be a valid email address") || !containsError(john.Errors, "username is required") {
+		t.Errorf("ligne 3 = %+v, erreurs d'email et de nom d'utilisateur attendues", john)
+	}
+
+	if rows[2].Line != 4 || len(rows[2].Errors) == 0 {
+		t.Errorf("ligne 4 = %+v, une ligne incomplète doit être signalée", rows[2])
+	}
+}
+
+func TestParseImportCSVRequiresColumns(t *testing.T) {
+	if _, err := parseImportFile(strings.NewReader("name,email\nJane,jane@example.com\n"), "csv"); err == nil {
+		t.Error("un en-tête sans les colonnes obligatoires doit être refusé")
+	}
+	if _, err := parseImportFile(strings.NewReader("name,sername,email,username,password\n"), "csv"); err == nil {
+		t.Error("un fichier sans utilisateur doit être refusé")
+	}
+}
+
+func TestParseImportJSONL(t *testing.T) {
+	content := `{"name":"Jane","sername":"Doe","email":"jane@example.com","username":"jane","password":"Secret@123","role":"manager"}` + "\n" +
+		"\n" +
+		`{"name":"John",` + "\n" +
+		`{"name":"Ann","sername":"Lee","email":"ann@example.com","username":"ann"}` + "\n"
+
+	rows, err := parseImportFile(strings.NewReader(content), "jsonl")
+	if err != nil {
+		t.Fatalf("lecture : %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d lignes lues, 3 attendues (la ligne vide est ignorée)", len(rows))
+	}
+
+	if rows[0].Line != 1 || rows[0].Role != "manager" || rows[0].User.Username != "jane" || len(rows[0].Errors) != 0 {
+		t.Errorf("ligne 1 = %+v", rows[0])
+	}
+	if rows[1].Line != 3 || !containsError(rows[1].Errors, "JSON invalide") {
+		t.Errorf("ligne 3 = %+v, JSON invalide attendu", rows[1])
+	}
+	if rows[2].Line != 4 || !containsError(rows[2].Errors, "password is required") {
+		t.Errorf("ligne 4 = %+v, mot de passe manquant attendu", rows[2])
+	}
+}
+
+func TestParseImportFileLimitsRows(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i <= maxImportRows; i++ {
+		content.WriteString(`{"name":"a"}` + "\n")
+	}
+	if _, err := parseImportFile(strings.NewReader(content.String()), "jsonl"); err == nil {
+		t.Errorf("un fichier de plus de %d lignes doit être refusé", maxImportRows)
+	}
+}
+
+func containsError(errors []string, want string) bool {
+	for i := 0; i < len(errors); i++ {
+		if errors[i] == want {
+			return true
+		}
+	}
+	return false
+}
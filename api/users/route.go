@@ -6,17 +6,38 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterUserRoutes(apiGroup *echo.Group, handler *UserHandler) {
-	apiGroup.POST("", handler.CreateUserHandler)                                                                       //OK
-	apiGroup.PUT("", handler.UpdateUserHandler, middlewares.IsAuthorizedMiddle, middlewares.GetPermission)             //OK
-	apiGroup.GET("/profile", handler.GetUserProfileHandler, middlewares.IsAuthorizedMiddle, middlewares.GetPermission) //OK
+func RegisterUserRoutes(apiGroup *echo.Group, handler *UserHandler, guard *middlewares.Guard) {
+	apiGroup.POST("", handler.CreateUserHandler, guard.AuditMiddle("user.create", ""))                     //OK
+	apiGroup.GET("/profile", handler.GetUserProfileHandler, guard.IsAuthorizedMiddle, guard.GetPermission) //OK
+	apiGroup.POST("/confirm-change", handler.ConfirmIdentityChangeHandler, guard.AuditMiddle("user.identity_change", ""))
+	apiGroup.GET("/me", handler.GetMeHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.PUT("/me", handler.UpdateMeHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.POST("/me/email", handler.RequestEmailChangeHandler, guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.POST("/me/username", handler.RequestUsernameChangeHandler, guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("/me/avatar", handler.GetMyAvatarHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.PUT("/me/avatar", handler.UploadAvatarHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.DELETE("/me/avatar", handler.DeleteAvatarHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.GET("/me/data", handler.ExportMyDataHandler, guard.IsAuthorizedMiddle, middlewares.DenyImpersonationMiddle)
+	apiGroup.GET("/me/attributes", handler.GetMyAttributesHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
+	apiGroup.PUT("/me/attributes", handler.UpdateMyAttributesHandler, guard.IsAuthorizedMiddle, guard.GetPermission)
 
 	//Admin method
-	apiGroup.GET("", handler.GetAllUsersHandler, middlewares.IsAdminMiddle, middlewares.GetPermission)        //OK
-	apiGroup.GET("/:id", handler.GetUserByIdHandler, middlewares.IsAdminMiddle, middlewares.GetPermission)    //OK
-	apiGroup.PUT("/:id", handler.UpdateUserByIdHandler, middlewares.IsAdminMiddle, middlewares.GetPermission) //OK
+	apiGroup.GET("", handler.GetAllUsersHandler, guard.IsAdminMiddle, guard.GetPermission) //OK
+	apiGroup.GET("/search", handler.SearchUsersHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.POST("/import", handler.ImportUsersHandler, guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("/export", handler.ExportUsersHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:id", handler.GetUserByIdHandler, guard.IsAdminMiddle, guard.GetPermission)                                         //OK
+	apiGroup.PUT("/:id", handler.UpdateUserByIdHandler, guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission) //OK
 
-	apiGroup.DELETE("/:id", handler.DeleteUserHandler, middlewares.IsAdminMiddle, middlewares.GetPermission) //OK
-	apiGroup.PUT("/:id/remove-role", handler.RemoveRoleHandler, middlewares.IsAdminMiddle, middlewares.GetPermission)
-	apiGroup.PUT("/:id/assign-role", handler.AssignRoleHandler, middlewares.IsAdminMiddle, middlewares.GetPermission)
+	apiGroup.DELETE("/:id", handler.DeleteUserHandler, guard.AuditMiddle("user.delete", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission) //OK
+	apiGroup.PUT("/:id/disable", handler.DisableUserHandler, guard.AuditMiddle("user.disable", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id/enable", handler.EnableUserHandler, guard.AuditMiddle("user.enable", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id/expire-password", handler.ExpirePasswordHandler, guard.AuditMiddle("user.password_expire", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.POST("/:id/restore", handler.RestoreUserHandler, guard.AuditMiddle("user.restore", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id/remove-role", handler.RemoveRoleHandler, guard.AuditMiddle("user.role_remove", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id/assign-role", handler.AssignRoleHandler, guard.AuditMiddle("user.role_assign", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("/:id/data", handler.ExportUserDataHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:id/groups", handler.GetUserGroupsHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/:id/attributes", handler.GetUserAttributesHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id/attributes", handler.UpdateUserAttributesHandler, guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
 }
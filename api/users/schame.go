@@ -26,3 +26,135 @@ type UserOut struct {
 	CreatedAt time.Time `json:"create_at,omitempty" validate:"-"`
 	UpdatedAt time.Time `json:"updated_at,omitempty" validate:"-"`
 }
+
+type UserGroupOut struct {
+	Id    string   `json:"id"`
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type UserListIn struct {
+	Limit       int    `query:"limit" validate:"min=0,max=100"`
+	Offset      int    `query:"offset" validate:"min=0"`
+	Cursor      string `query:"cursor"`
+	Sort        string `query:"sort" validate:"omitempty,oneof=name -name email -email username -username created_at -created_at updated_at -updated_at"`
+	Role        string `query:"role"`
+	EmailDomain string `query:"email_domain" validate:"omitempty,fqdn"`
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	IsAvailable string `query:"is_available" validate:"omitempty,oneof=true false"`
+	UseOTP      string `query:"use_otp" validate:"omitempty,oneof=true false"`
+}
+
+type UserListOut struct {
+	Items      []UserOut `json:"items"`
+	Total      int64     `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+type UserSearchIn struct {
+	Q      string `query:"q" validate:"required,max=100"`
+	Limit  int    `query:"limit" validate:"min=0,max=100"`
+	Offset int    `query:"offset" validate:"min=0"`
+}
+
+type UserImportIn struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Username string `json:"username" validate:"required"`
+	Sername  string `json:"sername" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	Role     string `json:"role"`
+}
+
+type UserImportQueryIn struct {
+	DryRun          bool   `query:"dry_run"`
+	SendInvitations bool   `query:"send_invitations"`
+	Format          string `query:"format" validate:"omitempty,oneof=csv jsonl"`
+}
+
+type UserExportIn struct {
+	Format      string `query:"format" validate:"omitempty,oneof=csv json jsonl"`
+	Fields      string `query:"fields"`
+	Sort        string `query:"sort" validate:"omitempty,oneof=name -name email -email username -username created_at -created_at updated_at -updated_at"`
+	Role        string `query:"role"`
+	EmailDomain string `query:"email_domain" validate:"omitempty,fqdn"`
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	IsAvailable string `query:"is_available" validate:"omitempty,oneof=true false"`
+	UseOTP      string `query:"use_otp" validate:"omitempty,oneof=true false"`
+}
+
+type DisableUserIn struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type AssignRoleIn struct {
+	Role   string `json:"role" validate:"required"`
+	Global bool   `json:"global"`
+}
+
+type RemoveRoleIn struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type UserRolesOut struct {
+	UserId string   `json:"user_id"`
+	OrgId  string   `json:"org_id,omitempty"`
+	Role   string   `json:"role,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+}
+
+type AccountStateOut struct {
+	Id              string    `json:"id"`
+	Status          string    `json:"status"`
+	StatusReason    string    `json:"status_reason,omitempty"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+	LockedUntil     time.Time `json:"locked_until,omitempty"`
+}
+
+type UserAttributesIn struct {
+	Attributes map[string]interface{} `json:"attributes" validate:"required"`
+}
+
+type UpdateProfileIn struct {
+	Name    string `json:"name" validate:"required,max=80"`
+	Sername string `json:"sername" validate:"required,max=80"`
+	Locale  string `json:"locale" validate:"max=20"`
+}
+
+type ChangeEmailIn struct {
+	Email           string `json:"email" validate:"required,email"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+type ChangeUsernameIn struct {
+	Username        string `json:"username" validate:"required,min=3,max=50"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+type ConfirmIdentityChangeIn struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type IdentityChangeOut struct {
+	Id        string    `json:"id"`
+	Field     string    `json:"field"`
+	NewValue  string    `json:"new_value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ProfileOut struct {
+	Id             string              `json:"id"`
+	Name           string              `json:"name"`
+	Sername        string              `json:"sername"`
+	Email          string              `json:"email"`
+	Username       string              `json:"username"`
+	Locale         string              `json:"locale"`
+	AvatarUrl      string              `json:"avatar_url,omitempty"`
+	PendingChanges []IdentityChangeOut `json:"pending_changes"`
+	CreatedAt      time.Time           `json:"create_at,omitempty"`
+	UpdatedAt      time.Time           `json:"updated_at,omitempty"`
+}
@@ -1,6 +1,8 @@
 package users
 
 import (
+	"auth/config"
+	"auth/middlewares"
 	"auth/repository"
 	"auth/service"
 
@@ -9,12 +11,37 @@ import (
 )
 
 // SetupUser config User
-func SetupUser(apiGroup *echo.Group, db *gorm.DB) {
+func SetupUser(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
 	userRepo := repository.NewUserRepository(db)
 	roleRepo := repository.NewRoleRepository(db)
-	userService := service.NewUserService(userRepo, roleRepo)
-	userHandler := NewUserHandler(userService)
+	orgRepo := repository.NewOrganizationRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	otpRepo := repository.NewOtpRepository(db)
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	attributeRepo := repository.NewAttributeRepository(db)
+	changeRepo := repository.NewIdentityChangeRepository(db)
+	methodRepo := repository.NewTwoFactorMethodRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	loginRepo := repository.NewLoginEventRepository(db)
+	userService := service.NewUserService(userRepo, roleRepo, orgRepo, appConfig.Auth.Security())
+	groupService := service.NewGroupService(groupRepo, userRepo, roleRepo)
+	importService := service.NewUserImportService(userRepo, roleRepo, orgRepo, service.NewNotifier(appConfig.Smtp), appConfig.Auth.Security())
+	exportService := service.NewDataExportService(userRepo, roleRepo, orgRepo, groupRepo, otpRepo, impersonationRepo, attributeRepo, methodRepo, sessionRepo, loginRepo)
+	attributeService := service.NewUserAttributeService(attributeRepo, userRepo)
+	profileService := service.NewProfileService(userRepo, changeRepo, service.NewNotifier(appConfig.Smtp),
+		service.NewAvatarStorage(appConfig.Profile.AvatarDir, appConfig.Profile.AvatarMaxSize),
+		appConfig.Profile.IdentityChangeUrl, appConfig.Profile.IdentityChangeTTL, appConfig.Profile.SupportedLocales())
+	userHandler := NewUserHandler(userService, groupService, importService, exportService, attributeService, profileService)
 
 	userGroup := apiGroup.Group("/users")
-	RegisterUserRoutes(userGroup, userHandler)
+	RegisterUserRoutes(userGroup, userHandler, guard)
+}
+
+// StartUserPurge lance la purge périodique des utilisateurs supprimés
+func StartUserPurge(db *gorm.DB, appConfig config.AppConfig) {
+	userRepo := repository.NewUserRepository(db)
+	avatars := service.NewAvatarStorage(appConfig.Profile.AvatarDir, appConfig.Profile.AvatarMaxSize)
+	retentionService := service.NewUserRetentionService(userRepo, repository.NewJobLockRepository(db), avatars,
+		appConfig.Retention.Retention(), appConfig.Retention.UserPurgeMode)
+	retentionService.Start(appConfig.Retention.UserPurgeInterval)
 }
@@ -0,0 +1,520 @@
+package webhooks
+
+import (
+	"auth/model"
+	"auth/service"
+	"auth/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler gère les requêtes liées aux webhooks
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler crée une nouvelle instance de WebhookHandler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// tenantService limite le service à l'organisation active du token
+func (h *WebhookHandler) tenantService(ctx echo.Context) *service.WebhookService {
+	return h.webhookService.WithContext(ctx.Request().Context()).ForOrganization(utils.GetOrganizationId(ctx))
+}
+
+// CreateWebhookHandler gère la requête pour créer un webhook
+// @Summary Crée un webhook
+// @Description Abonne une adresse aux évènements des utilisateurs de l'organisation active : user.created, user.updated, user.deleted, user.role_changed, user.password_reset.
+// @Description Chaque envoi porte les en-têtes X-Webhook-Id, X-Webhook-Event, X-Webhook-Timestamp et X-Webhook-Signature ("sha256=" suivi du HMAC-SHA256 de "<timestamp>.<corps>").
+// @Description Le secret de signature n'est retourné qu'à la création.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body WebhookIn true "Body data"
+// @Success 201 {object} utils.HttpResponse[WebhookCreatedOut]
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhookHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload WebhookIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	subscription, secret, err := h.tenantService(ctx).CreateSubscription(toWebhookInput(payload), fmt.Sprintf("%v", ctx.Get("userId")))
+	utils.SetAuditTarget(ctx, subscription.Id)
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[WebhookCreatedOut]{
+		Message:   "Le webhook a bien été créé, conservez son secret : il ne sera plus affiché",
+		Success:   true,
+		CodeError: http.StatusCreated,
+		Data: WebhookCreatedOut{
+			WebhookOut: toWebhookOut(subscription),
+			Secret:     secret,
+		},
+	}
+	return ctx.JSON(http.StatusCreated, jsonResponse)
+}
+
+// GetWebhooksHandler gère la requête pour lister les webhooks
+// @Summary Liste les webhooks
+// @Description Liste les webhooks de l'organisation active.
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {object} utils.HttpResponse[[]WebhookOut]
+// @Router /webhooks [get]
+func (h *WebhookHandler) GetWebhooksHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	subscriptions, err := h.tenantService(ctx).GetSubscriptions()
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Aucun webhook trouvé",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	webhookList := []WebhookOut{}
+	for i := 0; i < len(subscriptions); i++ {
+		webhookList = append(webhookList, toWebhookOut(subscriptions[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[[]WebhookOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      webhookList,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetWebhookHandler gère la requête pour consulter un webhook
+// @Summary Consulte un webhook
+// @Description Retourne un webhook de l'organisation active, sans son secret.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Identifiant du webhook"
+// @Success 200 {object} utils.HttpResponse[WebhookOut]
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	subscription, err := h.tenantService(ctx).GetSubscription(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[WebhookOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toWebhookOut(subscription),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// UpdateWebhookHandler gère la requête pour modifier un webhook
+// @Summary Modifie un webhook
+// @Description Modifie l'adresse, la description, les évènements ou l'activation d'un webhook. Les envois en attente d'un webhook désactivé passent en lettre morte.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Identifiant du webhook"
+// @Param webhook body WebhookIn true "Body data"
+// @Success 200 {object} utils.HttpResponse[WebhookOut]
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhookHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload WebhookIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Données JSON invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	subscription, err := h.tenantService(ctx).UpdateSubscription(ctx.Param("id"), toWebhookInput(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[WebhookOut]{
+		Message:   "Le webhook a bien été modifié",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      toWebhookOut(subscription),
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// DeleteWebhookHandler gère la requête pour supprimer un webhook
+// @Summary Supprime un webhook
+// @Description Supprime un webhook de l'organisation active ; ses envois en attente passent en lettre morte.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Identifiant du webhook"
+// @Success 200 {object} utils.HttpResponse[any]
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhookHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	err = h.tenantService(ctx).DeleteSubscription(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusNotFound,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusNotFound, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[any]{
+		Message:   "Le webhook a bien été supprimé",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data:      nil,
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+// GetDeliveriesHandler gère la requête pour consulter les envois des webhooks
+// @Summary Liste les envois des webhooks
+// @Description Liste les envois de l'organisation active, les plus récents en premier, avec le nombre de tentatives et la dernière erreur.
+// @Tags Webhooks
+// @Produce json
+// @Param subscription_id query string false "Identifiant du webhook"
+// @Param status query string false "Statut : pending, delivered ou dead"
+// @Param limit query int false "Nombre d'envois par page (200 au maximum)"
+// @Param offset query int false "Nombre d'envois à ignorer"
+// @Success 200 {object} utils.HttpResponse[DeliveryListOut]
+// @Router /webhooks/deliveries [get]
+func (h *WebhookHandler) GetDeliveriesHandler(ctx echo.Context) error {
+	return h.getDeliveries(ctx, "")
+}
+
+// GetDeadLettersHandler gère la requête pour consulter les lettres mortes
+// @Summary Liste les lettres mortes
+// @Description Liste les envois abandonnés après le nombre maximal de tentatives, ou dont le webhook a été désactivé ou supprimé. Ils peuvent être renvoyés manuellement.
+// @Tags Webhooks
+// @Produce json
+// @Param subscription_id query string false "Identifiant du webhook"
+// @Param limit query int false "Nombre d'envois par page (200 au maximum)"
+// @Param offset query int false "Nombre d'envois à ignorer"
+// @Success 200 {object} utils.HttpResponse[DeliveryListOut]
+// @Router /webhooks/dead-letters [get]
+func (h *WebhookHandler) GetDeadLettersHandler(ctx echo.Context) error {
+	return h.getDeliveries(ctx, model.WebhookDeliveryDead)
+}
+
+// RedeliverHandler gère la requête pour renvoyer un évènement
+// @Summary Renvoie un évènement
+// @Description Remet en attente un envoi livré ou en lettre morte : il est renvoyé au prochain passage, avec le même identifiant d'évènement et un nouveau compteur de tentatives.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Identifiant de l'envoi"
+// @Success 202 {object} utils.HttpResponse[DeliveryOut]
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) RedeliverHandler(ctx echo.Context) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	delivery, err := h.tenantService(ctx).Redeliver(ctx.Param("id"))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusUnprocessableEntity,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, jsonResponse)
+	}
+
+	jsonResponse := utils.HttpResponse[DeliveryOut]{
+		Message:   "L'évènement sera renvoyé au prochain passage",
+		Success:   true,
+		CodeError: http.StatusAccepted,
+		Data:      toDeliveryOut(delivery),
+	}
+	return ctx.JSON(http.StatusAccepted, jsonResponse)
+}
+
+func (h *WebhookHandler) getDeliveries(ctx echo.Context, status string) error {
+
+	err := utils.VerifyPermission(ctx, "manage_webhooks")
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Vous n'avez pas suffisament les droits pour continuer cette opération",
+			Success:   false,
+			CodeError: http.StatusUnauthorized,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	var payload DeliveryListIn
+	if err := ctx.Bind(&payload); err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+	if status != "" {
+		payload.Status = status
+	}
+
+	// Validation des données
+	validate := validator.New()
+	if err := validate.Struct(payload); err != nil {
+		var validationErrors []string
+
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, formatValidationError(err))
+		}
+
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   "Paramètres de recherche invalides",
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      validationErrors,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	page, err := h.tenantService(ctx).GetDeliveries(service.WebhookDeliveryListQuery(payload))
+	if err != nil {
+		jsonResponse := utils.HttpResponse[any]{
+			Message:   err.Error(),
+			Success:   false,
+			CodeError: http.StatusBadRequest,
+			Data:      nil,
+		}
+		return ctx.JSON(http.StatusBadRequest, jsonResponse)
+	}
+
+	deliveryList := []DeliveryOut{}
+	for i := 0; i < len(page.Deliveries); i++ {
+		deliveryList = append(deliveryList, toDeliveryOut(page.Deliveries[i]))
+	}
+
+	jsonResponse := utils.HttpResponse[DeliveryListOut]{
+		Message:   "Données récupérée avec succès",
+		Success:   true,
+		CodeError: http.StatusOK,
+		Data: DeliveryListOut{
+			Items:  deliveryList,
+			Total:  page.Total,
+			Limit:  page.Limit,
+			Offset: page.Offset,
+		},
+	}
+	return ctx.JSON(http.StatusOK, jsonResponse)
+}
+
+func toWebhookInput(payload WebhookIn) service.WebhookInput {
+	isActive := true
+	if payload.IsActive != nil {
+		isActive = *payload.IsActive
+	}
+	return service.WebhookInput{
+		Url:         payload.Url,
+		Description: payload.Description,
+		Events:      payload.Events,
+		IsActive:    isActive,
+	}
+}
+
+func toWebhookOut(subscription model.WebhookSubscription) WebhookOut {
+	return WebhookOut{
+		Id:          subscription.Id,
+		Url:         subscription.Url,
+		Description: subscription.Description,
+		Events:      subscription.EventList(),
+		IsActive:    subscription.IsAvailable,
+		CreatedBy:   subscription.CreatedBy,
+		CreatedAt:   subscription.CreatedAt,
+		UpdatedAt:   subscription.UpdatedAt,
+	}
+}
+
+func toDeliveryOut(delivery model.WebhookDelivery) DeliveryOut {
+	deliveryOut := DeliveryOut{
+		Id:             delivery.Id,
+		SubscriptionId: delivery.SubscriptionId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if json.Valid([]byte(delivery.Payload)) {
+		deliveryOut.Payload = json.RawMessage(delivery.Payload)
+	}
+	return deliveryOut
+}
+
+// Fonction pour formater les erreurs de validation
+func formatValidationError(err validator.FieldError) string {
+	fieldName := strings.ToLower(err.Field())
+	switch err.Tag() {
+	case "required":
+		return fieldName + " is required"
+	case "url":
+		return fieldName + " must be a valid url"
+	case "uuid":
+		return fieldName + " must be a valid uuid"
+	case "min":
+		return fieldName + " must be at least " + err.Param()
+	case "max":
+		return fieldName + " must be at most " + err.Param()
+	case "oneof":
+		return fieldName + " must be one of " + err.Param()
+	default:
+		return fieldName + " is invalid"
+	}
+}
@@ -0,0 +1,19 @@
+package webhooks
+
+import (
+	"auth/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterWebhookRoutes(apiGroup *echo.Group, handler *WebhookHandler, guard *middlewares.Guard) {
+	//Admin method
+	apiGroup.POST("", handler.CreateWebhookHandler, guard.AuditMiddle("webhook.create", ""), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("", handler.GetWebhooksHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/deliveries", handler.GetDeliveriesHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.GET("/dead-letters", handler.GetDeadLettersHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.POST("/deliveries/:id/redeliver", handler.RedeliverHandler, guard.AuditMiddle("webhook.redeliver", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.GET("/:id", handler.GetWebhookHandler, guard.IsAdminMiddle, guard.GetPermission)
+	apiGroup.PUT("/:id", handler.UpdateWebhookHandler, guard.AuditMiddle("webhook.update", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+	apiGroup.DELETE("/:id", handler.DeleteWebhookHandler, guard.AuditMiddle("webhook.delete", "id"), guard.IsAdminMiddle, middlewares.DenyImpersonationMiddle, guard.GetPermission)
+}
@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookIn struct {
+	Url         string   `json:"url" validate:"required,url,max=500"`
+	Description string   `json:"description" validate:"max=255"`
+	Events      []string `json:"events" validate:"required,min=1,dive,required,max=80"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+type WebhookOut struct {
+	Id          string    `json:"id"`
+	Url         string    `json:"url"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"create_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookCreatedOut struct {
+	WebhookOut
+	Secret string `json:"secret"`
+}
+
+type DeliveryListIn struct {
+	SubscriptionId string `query:"subscription_id" validate:"omitempty,uuid"`
+	Status         string `query:"status" validate:"omitempty,oneof=pending delivered dead"`
+	Limit          int    `query:"limit" validate:"min=0,max=200"`
+	Offset         int    `query:"offset" validate:"min=0"`
+}
+
+type DeliveryOut struct {
+	Id             string          `json:"id"`
+	SubscriptionId string          `json:"subscription_id"`
+	EventId        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastAttemptAt  time.Time       `json:"last_attempt_at"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	DeliveredAt    time.Time       `json:"delivered_at"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"create_at"`
+}
+
+type DeliveryListOut struct {
+	Items  []DeliveryOut `json:"items"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
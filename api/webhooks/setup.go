@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"auth/config"
+	"auth/middlewares"
+	"auth/repository"
+	"auth/service"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// SetupWebhook config Webhook
+func SetupWebhook(apiGroup *echo.Group, db *gorm.DB, appConfig config.AppConfig, guard *middlewares.Guard) {
+	webhookHandler := NewWebhookHandler(newWebhookService(db, appConfig.Webhook))
+
+	webhookGroup := apiGroup.Group("/webhooks")
+	RegisterWebhookRoutes(webhookGroup, webhookHandler, guard)
+}
+
+// StartWebhookDispatcher lance la distribution périodique des évènements de l'outbox aux webhooks
+func StartWebhookDispatcher(db *gorm.DB, webhookConfig config.WebhookConfig) {
+	newWebhookService(db, webhookConfig).Start(webhookConfig.DispatchInterval)
+}
+
+func newWebhookService(db *gorm.DB, webhookConfig config.WebhookConfig) *service.WebhookService {
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	return service.NewWebhookService(webhookRepo, outboxRepo, orgRepo, webhookConfig.Timeout,
+		webhookConfig.MaxAttempts, webhookConfig.RetryBaseDelay, webhookConfig.RetryMaxDelay, webhookConfig.AllowPrivateIPs)
+}
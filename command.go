@@ -0,0 +1,65 @@
+package main
+
+import (
+	"auth/migrations"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// runCommand exécute la commande passée au lieu de démarrer le serveur :
+//
+//	migrate up          applique les migrations en attente
+//	migrate down [n]    annule les n dernières migrations appliquées (1 par défaut)
+//	migrate status      affiche l'état de chaque migration
+func runCommand(migrator *migrations.Migrator, args []string) error {
+	if args[0] != "migrate" || len(args) < 2 {
+		return fmt.Errorf("commande inconnue : %v (attendu : migrate up | migrate down [n] | migrate status)", args)
+	}
+
+	switch args[1] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Printf("appliquée   %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("aucune migration en attente")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				return fmt.Errorf("nombre de migrations à annuler invalide : %s", args[2])
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(steps)
+		for _, migration := range reverted {
+			fmt.Printf("annulée     %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("aucune migration à annuler")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNOM\tÉTAT\tAPPLIQUÉE LE")
+		for _, status := range statuses {
+			state, appliedAt := "en attente", ""
+			if status.Applied {
+				state, appliedAt = "appliquée", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("commande migrate inconnue : %s (attendu : up, down [n] ou status)", args[1])
+}
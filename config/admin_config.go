@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strings"
+)
+
+type AdminConfig struct {
+	Username string `mapstructure:"ADMIN_USERNAME"`
+	Email    string `mapstructure:"ADMIN_EMAIL"`
+	Password string `mapstructure:"ADMIN_PASSWORD"`
+}
+
+var adminSettings = []setting{
+	{key: "ADMIN_USERNAME", defaultValue: "admin", usage: "nom d'utilisateur du super utilisateur créé au premier démarrage"},
+	{key: "ADMIN_EMAIL", defaultValue: "noreply@cmagic.com", usage: "email du super utilisateur créé au premier démarrage"},
+	{key: "ADMIN_PASSWORD", defaultValue: "", usage: "mot de passe du super utilisateur, requis tant qu'il n'existe pas", secret: true},
+}
+
+func (c AdminConfig) validate() (problems []string) {
+	if c.Username == "" {
+		problems = append(problems, "ADMIN_USERNAME est requis")
+	}
+	if !strings.Contains(c.Email, "@") {
+		problems = append(problems, "ADMIN_EMAIL doit être une adresse email")
+	}
+	if c.Password != "" && len(c.Password) < 8 {
+		problems = append(problems, "ADMIN_PASSWORD doit contenir au moins 8 caractères")
+	}
+	return
+}
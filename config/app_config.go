@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// setting décrit une clé de configuration, sa valeur par défaut et son rôle. Chaque clé peut être
+// fournie par le fichier de configuration, par la variable d'environnement de même nom ou par
+// l'option de ligne de commande correspondante (DB_HOST : --db-host), par ordre croissant de priorité.
+type setting struct {
+	key          string
+	defaultValue interface{}
+	usage        string
+	// secret indique que la valeur peut être une référence "file:<chemin>" ou "env:<VARIABLE>",
+	// résolue au chargement pour ne pas exposer le secret dans le fichier ou la ligne de commande
+	secret bool
+}
+
+// AppConfig regroupe la configuration du service, chargée et validée au démarrage
+type AppConfig struct {
+	Db         DbConfig         `mapstructure:",squash"`
+	Server     ServerConfig     `mapstructure:",squash"`
+	Auth       AuthConfig       `mapstructure:",squash"`
+	Admin      AdminConfig      `mapstructure:",squash"`
+	Retention  RetentionConfig  `mapstructure:",squash"`
+	Invitation InvitationConfig `mapstructure:",squash"`
+	Profile    ProfileConfig    `mapstructure:",squash"`
+	LoginAlert LoginAlertConfig `mapstructure:",squash"`
+	Webhook    WebhookConfig    `mapstructure:",squash"`
+	Smtp       SmtpConfig       `mapstructure:",squash"`
+	Tracing    TracingConfig    `mapstructure:",squash"`
+	Logging    LoggingConfig    `mapstructure:",squash"`
+
+	// File est le fichier de configuration lu, vide lorsqu'aucun fichier n'a été trouvé
+	File string `mapstructure:"-"`
+	// Args sont les arguments restants après les options, par exemple la commande "migrate up"
+	Args []string `mapstructure:"-"`
+}
+
+func settings() [][]setting {
+	return [][]setting{
+		dbSettings,
+		serverSettings,
+		authSettings,
+		adminSettings,
+		retentionSettings,
+		invitationSettings,
+		profileSettings,
+		loginAlertSettings,
+		webhookSettings,
+		smtpSettings,
+		tracingSettings,
+		loggingSettings,
+	}
+}
+
+// LoadAppConfig charge la configuration depuis le fichier (--config, app.yaml par défaut),
+// l'environnement et les options de ligne de commande args, résout les références de secrets
+// puis valide l'ensemble. Retourne pflag.ErrHelp lorsque l'aide a été demandée.
+func LoadAppConfig(args []string) (config AppConfig, err error) {
+	v := viper.New()
+	flags := pflag.NewFlagSet("auth", pflag.ContinueOnError)
+	configFile := flags.String("config", "app.yaml", "fichier de configuration (yaml)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage : auth [options] [migrate up | migrate down [n] | migrate status]")
+		fmt.Fprintln(os.Stderr)
+		flags.PrintDefaults()
+	}
+
+	for _, section := range settings() {
+		for _, s := range section {
+			v.SetDefault(s.key, s.defaultValue)
+
+			usage := s.usage
+			defaultValue := fmt.Sprint(s.defaultValue)
+			if s.secret {
+				usage += ` ; accepte "file:<chemin>" ou "env:<VARIABLE>"`
+				defaultValue = ""
+			}
+			flags.String(flagName(s.key), defaultValue, usage)
+			if err = v.BindPFlag(s.key, flags.Lookup(flagName(s.key))); err != nil {
+				return
+			}
+		}
+	}
+
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	// Le fichier est facultatif lorsque la configuration vient de l'environnement,
+	// sauf s'il a été désigné explicitement
+	if _, statErr := os.Stat(*configFile); statErr == nil {
+		v.SetConfigFile(*configFile)
+		if err = v.ReadInConfig(); err != nil {
+			return
+		}
+	} else if flags.Changed("config") {
+		err = fmt.Errorf("fichier de configuration introuvable : %s", *configFile)
+		return
+	}
+
+	//auto loading env variable
+	v.AutomaticEnv()
+
+	for _, section := range settings() {
+		for _, s := range section {
+			if !s.secret {
+				continue
+			}
+			value, resolveErr := resolveSecret(v.GetString(s.key))
+			if resolveErr != nil {
+				err = fmt.Errorf("%s : %w", s.key, resolveErr)
+				return
+			}
+			v.Set(s.key, value)
+		}
+	}
+
+	if err = v.Unmarshal(&config); err != nil {
+		return
+	}
+	config.File = v.ConfigFileUsed()
+	config.Args = flags.Args()
+
+	err = config.Validate()
+	return
+}
+
+// Validate contrôle la cohérence de la configuration et liste toutes les valeurs invalides
+func (c AppConfig) Validate() error {
+	var problems []string
+	problems = append(problems, c.Db.validate()...)
+	problems = append(problems, c.Server.validate()...)
+	problems = append(problems, c.Auth.validate()...)
+	problems = append(problems, c.Admin.validate()...)
+	problems = append(problems, c.Retention.validate()...)
+	problems = append(problems, c.Invitation.validate()...)
+	problems = append(problems, c.Profile.validate()...)
+	problems = append(problems, c.LoginAlert.validate()...)
+	problems = append(problems, c.Webhook.validate()...)
+	problems = append(problems, c.Smtp.validate()...)
+	problems = append(problems, c.Tracing.validate()...)
+	problems = append(problems, c.Logging.validate()...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("configuration invalide :\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// resolveSecret remplace une référence "file:<chemin>" par le contenu du fichier
+// et "env:<VARIABLE>" par la valeur de la variable ; toute autre valeur est conservée
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "file:"):
+		content, err := os.ReadFile(strings.TrimPrefix(value, "file:"))
+		if err != nil {
+			return "", fmt.Errorf("lecture du secret impossible : %w", err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("variable d'environnement %s non définie", name)
+		}
+		return secret, nil
+	}
+	return value, nil
+}
+
+// flagName retourne l'option de ligne de commande d'une clé : DB_HOST devient db-host
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// oneOf indique si value fait partie des valeurs admises
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
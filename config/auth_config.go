@@ -0,0 +1,56 @@
+package config
+
+import (
+	"auth/utils"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minJwtSecretLength est la taille minimale de la clé HS256, égale à celle de l'empreinte
+const minJwtSecretLength = 32
+
+type AuthConfig struct {
+	JwtSecret       string        `mapstructure:"JWT_SECRET"`
+	AccessTokenTTL  time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+	OtpLength       int           `mapstructure:"OTP_LENGTH"`
+	OtpTTL          time.Duration `mapstructure:"OTP_TTL"`
+	BcryptCost      int           `mapstructure:"BCRYPT_COST"`
+}
+
+var authSettings = []setting{
+	{key: "JWT_SECRET", defaultValue: "", usage: "clé de signature des tokens, au moins 32 octets", secret: true},
+	{key: "ACCESS_TOKEN_TTL", defaultValue: "1h", usage: "durée de validité des access tokens"},
+	{key: "REFRESH_TOKEN_TTL", defaultValue: "6h", usage: "durée de validité des refresh tokens"},
+	{key: "OTP_LENGTH", defaultValue: 6, usage: "nombre de chiffres des codes envoyés par email"},
+	{key: "OTP_TTL", defaultValue: "5m", usage: "durée de validité d'un défi de double authentification"},
+	{key: "BCRYPT_COST", defaultValue: 14, usage: "coût bcrypt des mots de passe"},
+}
+
+// Security retourne les paramètres cryptographiques partagés par les services et les middlewares
+func (c AuthConfig) Security() utils.Security {
+	return utils.NewSecurity([]byte(c.JwtSecret), c.BcryptCost)
+}
+
+func (c AuthConfig) validate() (problems []string) {
+	if len(c.JwtSecret) < minJwtSecretLength {
+		problems = append(problems, "JWT_SECRET doit contenir au moins 32 octets")
+	}
+	if c.AccessTokenTTL <= 0 {
+		problems = append(problems, "ACCESS_TOKEN_TTL doit être positif")
+	}
+	if c.RefreshTokenTTL < c.AccessTokenTTL {
+		problems = append(problems, "REFRESH_TOKEN_TTL doit être supérieur ou égal à ACCESS_TOKEN_TTL")
+	}
+	if c.OtpLength < 4 || c.OtpLength > 10 {
+		problems = append(problems, "OTP_LENGTH doit être compris entre 4 et 10")
+	}
+	if c.OtpTTL <= 0 {
+		problems = append(problems, "OTP_TTL doit être positif")
+	}
+	if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+		problems = append(problems, "BCRYPT_COST doit être compris entre 4 et 31")
+	}
+	return
+}
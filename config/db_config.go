@@ -1,9 +1,7 @@
 package config
 
 import (
-	"os"
-
-	"github.com/spf13/viper"
+	"strconv"
 )
 
 type DbConfig struct {
@@ -13,24 +11,35 @@ type DbConfig struct {
 	DbName     string `mapstructure:"DB_NAME"`
 	DbUser     string `mapstructure:"DB_USER"`
 	DbPassword string `mapstructure:"DB_PASSWORD"`
+	// AutoMigrate applique les migrations en attente au démarrage ; sinon le service refuse de démarrer
+	// tant qu'elles n'ont pas été appliquées avec la commande migrate
+	AutoMigrate bool `mapstructure:"DB_AUTO_MIGRATE"`
 }
 
-func LoadDBonfig() (config DbConfig, err error) {
-	configFileName := "app.yaml"
+var dbSettings = []setting{
+	{key: "DB_PROVIDER", defaultValue: "pg", usage: "base de données : pg, mysql ou sqlite"},
+	{key: "DB_HOST", defaultValue: "localhost", usage: "hôte de la base de données"},
+	{key: "DB_PORT", defaultValue: "5432", usage: "port de la base de données"},
+	{key: "DB_NAME", defaultValue: "auth", usage: "nom de la base (nom du fichier pour sqlite)"},
+	{key: "DB_USER", defaultValue: "", usage: "utilisateur de la base de données"},
+	{key: "DB_PASSWORD", defaultValue: "", usage: "mot de passe de la base de données", secret: true},
+	{key: "DB_AUTO_MIGRATE", defaultValue: true, usage: "applique les migrations en attente au démarrage"},
+}
 
-	if _, err := os.Stat(configFileName); !os.IsNotExist(err) {
-		viper.SetConfigFile(configFileName)
+func (c DbConfig) validate() (problems []string) {
+	if !oneOf(c.DbProvider, "pg", "mysql", "sqlite") {
+		problems = append(problems, "DB_PROVIDER doit valoir pg, mysql ou sqlite")
 	}
-
-	//auto loading env variable
-	viper.AutomaticEnv()
-
-	err = viper.ReadInConfig()
-	if err != nil {
-		return
+	if c.DbName == "" {
+		problems = append(problems, "DB_NAME est requis")
+	}
+	if c.DbProvider != "sqlite" {
+		if c.DbHost == "" {
+			problems = append(problems, "DB_HOST est requis")
+		}
+		if port, err := strconv.Atoi(c.DbPort); err != nil || port <= 0 || port > 65535 {
+			problems = append(problems, "DB_PORT doit être un port valide")
+		}
 	}
-
-	err = viper.Unmarshal(&config)
-
 	return
 }
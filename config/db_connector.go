@@ -1,10 +1,12 @@
 package config
 
 import (
+	"auth/logging"
 	"auth/model"
 	"auth/utils"
 	"fmt"
 	"github.com/google/uuid"
+	slog "github.com/sagikazarmark/slog-shim"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -23,43 +25,30 @@ func GetDB(config DbConfig) (*gorm.DB, error) {
 		dbDns = fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=disable",
 			config.DbHost, config.DbUser, config.DbPassword, config.DbName, config.DbPort)
 		db, err = gorm.Open(postgres.Open(dbDns), &gorm.Config{
+			Logger:         logging.NewGormLogger(),
 			TranslateError: true,
 		})
 	case "mysql":
 		dbDns = fmt.Sprintf("%v:%v@tcp(%v:%v)/%v?charset=utf8mb4&parseTime=True&loc=Local",
 			config.DbUser, config.DbPassword, config.DbHost, config.DbPort, config.DbName)
 		db, err = gorm.Open(mysql.Open(dbDns), &gorm.Config{
+			Logger:         logging.NewGormLogger(),
 			TranslateError: true,
 		})
 	case "sqlite":
 		dbDns = fmt.Sprintf("%v.sqlite", config.DbName)
-		db, err = gorm.Open(sqlite.Open(dbDns), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open(dbDns), &gorm.Config{
+			Logger:         logging.NewGormLogger(),
+			TranslateError: true,
+		})
+	default:
+		err = fmt.Errorf("fournisseur de base de données inconnu : %q", config.DbProvider)
 	}
 
 	return db, err
 }
 
-func CreateUpdateTable(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&model.Role{},
-		&model.User{},
-		&model.Otp{},
-		&model.Permission{},
-		&model.RolePermission{},
-	)
-}
-
-func DropTable(db *gorm.DB) error {
-	return db.Migrator().DropTable(
-		&model.User{},
-		&model.Role{},
-		&model.Otp{},
-		&model.Permission{},
-		&model.RolePermission{},
-	)
-}
-
-func InitSystem(db *gorm.DB) error {
+func InitSystem(db *gorm.DB, adminConfig AdminConfig, security utils.Security, logger *slog.Logger) error {
 	/* create basic permission */
 	permissions := []model.Permission{
 		{Name: "create_roles", Describe: "insert role table"},
@@ -74,9 +63,44 @@ func InitSystem(db *gorm.DB) error {
 		//user permissios
 		{Name: "create_user", Describe: "insert user"},
 		{Name: "update_user_profile", Describe: "update user profile"},
+		{Name: "view_user_profile", Describe: "View user profiles"},
+		{Name: "init_user_password", Describe: "Initialize the password of a user"},
 		{Name: "remove_user", Describe: "remove user"},
+		{Name: "delete_user", Describe: "Delete users"},
 		{Name: "chang_password", Describe: "Chang user password"},
 		{Name: "create_new_password", Describe: "Create new password"},
+		{Name: "import_users", Describe: "Import users in bulk from CSV or JSON Lines"},
+		{Name: "export_users", Describe: "Export users as CSV, JSON or JSON Lines"},
+		{Name: "export_user_data", Describe: "Download the personal data archive of a user"},
+		{Name: "restore_user", Describe: "Restore a deleted user before the retention period ends"},
+		{Name: "manage_account_states", Describe: "Enable and disable user accounts"},
+		{Name: "invite_users", Describe: "Invite users, resend and revoke invitations"},
+		{Name: "view_invitations", Describe: "List pending invitations"},
+		{Name: "manage_user_attributes", Describe: "Define custom user attributes"},
+		{Name: "view_user_attributes", Describe: "View custom attribute definitions and user values"},
+		{Name: "update_user_attributes", Describe: "Update custom attribute values of users"},
+		{Name: "manage_two_factor", Describe: "Require two-factor authentication per role"},
+		{Name: "view_user_sessions", Describe: "List the active sessions of users"},
+		{Name: "revoke_user_sessions", Describe: "Revoke the sessions of users"},
+		{Name: "view_audit_log", Describe: "Search the audit log and verify its integrity"},
+		{Name: "manage_webhooks", Describe: "Manage webhook subscriptions and redeliver events"},
+
+		//organization permissions
+		{Name: "manage_organizations", Describe: "Create organizations"},
+		{Name: "view_organizations", Describe: "View organizations and their members"},
+		{Name: "manage_organization_members", Describe: "Add, update and remove organization members"},
+
+		//group permissions
+		{Name: "manage_groups", Describe: "Manage groups, their members and roles"},
+		{Name: "view_groups", Describe: "View groups, their members and user groups"},
+
+		//policy permissions
+		{Name: "manage_policies", Describe: "Create, version and deactivate access policies"},
+		{Name: "view_policies", Describe: "View access policies and their versions"},
+
+		//impersonation permissions
+		{Name: "impersonate_users", Describe: "Start and end impersonation sessions"},
+		{Name: "view_impersonations", Describe: "View impersonation sessions and their actions"},
 	}
 
 	/*create system all roles*/
@@ -93,8 +117,6 @@ func InitSystem(db *gorm.DB) error {
 
 	if count == 0 {
 		db.Create(roles)
-		db.Create(permissions)
-		db.Commit()
 	}
 
 	var adminRoleModel model.Role
@@ -102,58 +124,167 @@ func InitSystem(db *gorm.DB) error {
 	}
 
 	if _, err := uuid.Parse(adminRoleModel.Id); err != nil {
+		return fmt.Errorf("le rôle admin est introuvable")
 	}
 
-	count = 0
-	if err := db.Model(model.RolePermission{}).Count(&count).Error; err != nil {
+	// Les permissions sont rapprochées par nom à chaque démarrage : celles ajoutées par une
+	// nouvelle version sont créées sur les bases existantes et accordées au rôle admin
+	if err := upsertPermissions(db, permissions); err != nil {
+		return fmt.Errorf("initialisation des permissions : %w", err)
+	}
+	if err := grantAllPermissions(db, adminRoleModel); err != nil {
+		return fmt.Errorf("attribution des permissions au rôle admin : %w", err)
 	}
 
-	if count == 0 {
-		var permissionModel []model.Permission
-		var rolePermissions []model.RolePermission
+	if err := createSystemSuperUser(db, adminRoleModel, adminConfig, security, logger); err != nil {
+		return err
+	}
+	createDefaultOrganization(db, logger)
+	return nil
+}
 
-		db.Find(&permissionModel)
+// upsertPermissions crée les permissions absentes et met à jour la description des autres
+func upsertPermissions(db *gorm.DB, permissions []model.Permission) error {
+	var existing []model.Permission
+	if err := db.Find(&existing).Error; err != nil {
+		return err
+	}
+	byName := map[string]model.Permission{}
+	for i := 0; i < len(existing); i++ {
+		byName[existing[i].Name] = existing[i]
+	}
 
-		for i := 0; i < len(permissionModel); i++ {
-			currentItem := model.RolePermission{
-				RoleId:       adminRoleModel.Id,
-				PermissionId: permissionModel[i].Id,
-				Describe:     "",
+	for i := 0; i < len(permissions); i++ {
+		current, ok := byName[permissions[i].Name]
+		if !ok {
+			if err := db.Create(&permissions[i]).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if current.Describe != permissions[i].Describe {
+			if err := db.Model(&current).Update("describe", permissions[i].Describe).Error; err != nil {
+				return err
 			}
-
-			rolePermissions = append(rolePermissions, currentItem)
 		}
+	}
+	return nil
+}
 
-		db.Create(rolePermissions)
-		db.Commit()
+// grantAllPermissions accorde au rôle les permissions qui ne lui sont pas encore liées
+func grantAllPermissions(db *gorm.DB, role model.Role) error {
+	var permissions []model.Permission
+	if err := db.Find(&permissions).Error; err != nil {
+		return err
+	}
+	var linked []model.RolePermission
+	if err := db.Where("role_id = ?", role.Id).Find(&linked).Error; err != nil {
+		return err
+	}
+	granted := map[string]bool{}
+	for i := 0; i < len(linked); i++ {
+		granted[linked[i].PermissionId] = true
 	}
 
-	createSystemSuperUser(db, adminRoleModel)
-	return nil
+	var rolePermissions []model.RolePermission
+	for i := 0; i < len(permissions); i++ {
+		if granted[permissions[i].Id] {
+			continue
+		}
+		rolePermissions = append(rolePermissions, model.RolePermission{
+			RoleId:       role.Id,
+			PermissionId: permissions[i].Id,
+			Describe:     "",
+		})
+	}
+	if len(rolePermissions) == 0 {
+		return nil
+	}
+	return db.Create(&rolePermissions).Error
 }
 
-func createSystemSuperUser(db *gorm.DB, adminRoleModel model.Role) {
+// createSystemSuperUser crée le super utilisateur au premier démarrage ; ADMIN_PASSWORD n'est requis
+// que tant qu'il n'existe pas
+func createSystemSuperUser(db *gorm.DB, adminRoleModel model.Role, adminConfig AdminConfig, security utils.Security, logger *slog.Logger) error {
 	var user model.User
 
-	tx := db.Model(model.User{}).Find(&user, "email = ?", "noreply@cmagic.com")
+	tx := db.Model(model.User{}).Find(&user, "email = ?", adminConfig.Email)
 	if tx.Error != nil {
-		fmt.Println("Error creating user Error :> ", tx.Error)
+		logger.Error("Failed to look up super user", "error", tx.Error)
 	}
 
 	if user.Id == "" {
-		adminPassword, _ := utils.GenerateHashPassword("Dycode@123456")
+		if adminConfig.Password == "" {
+			return fmt.Errorf("ADMIN_PASSWORD est requis pour créer le super utilisateur %s", adminConfig.Email)
+		}
+		adminPassword, _ := security.GenerateHashPassword(adminConfig.Password)
 		user = model.User{
-			Name:     "admin",
-			Email:    "noreply@cmagic.com",
-			Sername:  "admin",
-			Username: "admin",
+			Name:     adminConfig.Username,
+			Email:    adminConfig.Email,
+			Sername:  adminConfig.Username,
+			Username: adminConfig.Username,
 			Password: adminPassword,
 			RoleId:   adminRoleModel.Id,
 		}
 		tx := db.Create(&user)
 		if tx.Error != nil {
-			fmt.Println("Error creating user Error :> ", tx.Error)
+			logger.Error("Failed to create super user", "error", tx.Error)
+		}
+	}
+	return nil
+}
+
+// createDefaultOrganization crée l'organisation par défaut et y rattache
+// les utilisateurs qui ne sont membres d'aucune organisation
+func createDefaultOrganization(db *gorm.DB, logger *slog.Logger) {
+	var organization model.Organization
+
+	tx := db.Model(model.Organization{}).Find(&organization, "slug = ?", model.DefaultOrganizationSlug)
+	if tx.Error != nil {
+		logger.Error("Failed to look up default organization", "error", tx.Error)
+		return
+	}
+
+	if organization.Id == "" {
+		organization = model.Organization{
+			Name:     "Default",
+			Slug:     model.DefaultOrganizationSlug,
+			Describe: "Default organization",
+		}
+		if err := db.Create(&organization).Error; err != nil {
+			logger.Error("Failed to create default organization", "error", err)
+			return
+		}
+	}
+
+	var users []model.User
+	tx = db.Model(model.User{}).
+		Where("id NOT IN (?)", db.Model(model.Membership{}).Select("user_id")).Find(&users)
+	if tx.Error != nil {
+		logger.Error("Failed to list users without membership", "error", tx.Error)
+		return
+	}
+
+	for i := 0; i < len(users); i++ {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			membership := model.Membership{
+				UserId:         users[i].Id,
+				OrganizationId: organization.Id,
+			}
+			if err := tx.Create(&membership).Error; err != nil {
+				return err
+			}
+
+			if users[i].RoleId == "" {
+				return nil
+			}
+			return tx.Create(&model.MembershipRole{
+				MembershipId: membership.Id,
+				RoleId:       users[i].RoleId,
+			}).Error
+		})
+		if err != nil {
+			logger.Error("Failed to create default membership", "user_id", users[i].Id, "error", err)
 		}
-		db.Commit()
 	}
 }
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"time"
+)
+
+type InvitationConfig struct {
+	InvitationUrl string        `mapstructure:"INVITATION_URL"`
+	InvitationTTL time.Duration `mapstructure:"INVITATION_TTL"`
+}
+
+var invitationSettings = []setting{
+	{key: "INVITATION_URL", defaultValue: "http://localhost:3000/invitations/accept", usage: "adresse du lien d'acceptation envoyé aux invités"},
+	{key: "INVITATION_TTL", defaultValue: "72h", usage: "durée de validité d'une invitation"},
+}
+
+func (c InvitationConfig) validate() (problems []string) {
+	if c.InvitationUrl == "" {
+		problems = append(problems, "INVITATION_URL est requis")
+	}
+	if c.InvitationTTL <= 0 {
+		problems = append(problems, "INVITATION_TTL doit être positif")
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"strings"
+)
+
+type LoggingConfig struct {
+	Level  string `mapstructure:"LOG_LEVEL"`
+	Format string `mapstructure:"LOG_FORMAT"`
+}
+
+var loggingSettings = []setting{
+	{key: "LOG_LEVEL", defaultValue: "info", usage: "niveau des journaux : debug, info, warn ou error"},
+	{key: "LOG_FORMAT", defaultValue: "json", usage: "format des journaux : json ou text"},
+}
+
+func (c LoggingConfig) validate() (problems []string) {
+	if !oneOf(strings.ToLower(c.Level), "debug", "info", "warn", "warning", "error") {
+		problems = append(problems, "LOG_LEVEL doit valoir debug, info, warn ou error")
+	}
+	if !oneOf(c.Format, "json", "text") {
+		problems = append(problems, "LOG_FORMAT doit valoir json ou text")
+	}
+	return
+}
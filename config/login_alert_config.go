@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+)
+
+type LoginAlertConfig struct {
+	ReportUrl     string        `mapstructure:"LOGIN_REPORT_URL"`
+	ReportTTL     time.Duration `mapstructure:"LOGIN_REPORT_TTL"`
+	GeoIpDatabase string        `mapstructure:"GEOIP_DATABASE"`
+}
+
+// Le fichier de géolocalisation des adresses IP est un CSV au format DB-IP lite ;
+// sans fichier, les connexions ne sont pas localisées
+var loginAlertSettings = []setting{
+	{key: "LOGIN_REPORT_URL", defaultValue: "http://localhost:3000/logins/report", usage: "adresse du lien \"ce n'était pas moi\" des alertes de connexion"},
+	{key: "LOGIN_REPORT_TTL", defaultValue: "168h", usage: "durée de validité du lien de signalement"},
+	{key: "GEOIP_DATABASE", defaultValue: "ressources/geoip.csv", usage: "fichier local de géolocalisation des adresses IP"},
+}
+
+func (c LoginAlertConfig) validate() (problems []string) {
+	if c.ReportUrl == "" {
+		problems = append(problems, "LOGIN_REPORT_URL est requis")
+	}
+	if c.ReportTTL <= 0 {
+		problems = append(problems, "LOGIN_REPORT_TTL doit être positif")
+	}
+	return
+}
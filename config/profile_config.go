@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+type ProfileConfig struct {
+	IdentityChangeUrl string        `mapstructure:"IDENTITY_CHANGE_URL"`
+	IdentityChangeTTL time.Duration `mapstructure:"IDENTITY_CHANGE_TTL"`
+	AvatarDir         string        `mapstructure:"AVATAR_DIR"`
+	AvatarMaxSize     int64         `mapstructure:"AVATAR_MAX_SIZE"`
+	Locales           string        `mapstructure:"SUPPORTED_LOCALES"`
+}
+
+var profileSettings = []setting{
+	{key: "IDENTITY_CHANGE_URL", defaultValue: "http://localhost:3000/profile/confirm", usage: "adresse du lien de confirmation d'un changement d'email ou de nom d'utilisateur"},
+	{key: "IDENTITY_CHANGE_TTL", defaultValue: "24h", usage: "durée de validité du lien de confirmation"},
+	{key: "AVATAR_DIR", defaultValue: "storage/avatars", usage: "répertoire de stockage des photos de profil"},
+	{key: "AVATAR_MAX_SIZE", defaultValue: 2 * 1024 * 1024, usage: "taille maximale d'une photo de profil, en octets"},
+	{key: "SUPPORTED_LOCALES", defaultValue: "fr,en", usage: "langues proposées aux utilisateurs, séparées par des virgules"},
+}
+
+// SupportedLocales retourne la liste des langues proposées aux utilisateurs
+func (c ProfileConfig) SupportedLocales() []string {
+	var locales []string
+	for _, locale := range strings.Split(c.Locales, ",") {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+func (c ProfileConfig) validate() (problems []string) {
+	if c.IdentityChangeUrl == "" {
+		problems = append(problems, "IDENTITY_CHANGE_URL est requis")
+	}
+	if c.IdentityChangeTTL <= 0 {
+		problems = append(problems, "IDENTITY_CHANGE_TTL doit être positif")
+	}
+	if c.AvatarDir == "" {
+		problems = append(problems, "AVATAR_DIR est requis")
+	}
+	if c.AvatarMaxSize <= 0 {
+		problems = append(problems, "AVATAR_MAX_SIZE doit être positif")
+	}
+	if len(c.SupportedLocales()) == 0 {
+		problems = append(problems, "SUPPORTED_LOCALES doit contenir au moins une langue")
+	}
+	return
+}
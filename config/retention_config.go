@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+)
+
+type RetentionConfig struct {
+	UserRetentionDays int           `mapstructure:"USER_RETENTION_DAYS"`
+	UserPurgeMode     string        `mapstructure:"USER_PURGE_MODE"`
+	UserPurgeInterval time.Duration `mapstructure:"USER_PURGE_INTERVAL"`
+}
+
+var retentionSettings = []setting{
+	{key: "USER_RETENTION_DAYS", defaultValue: 30, usage: "nombre de jours pendant lesquels un utilisateur supprimé peut être restauré"},
+	{key: "USER_PURGE_MODE", defaultValue: "anonymize", usage: "traitement des utilisateurs expirés : anonymize ou delete"},
+	{key: "USER_PURGE_INTERVAL", defaultValue: "1h", usage: "fréquence de la purge des utilisateurs supprimés"},
+}
+
+// Retention retourne la durée pendant laquelle un utilisateur supprimé peut être restauré
+func (c RetentionConfig) Retention() time.Duration {
+	return time.Duration(c.UserRetentionDays) * 24 * time.Hour
+}
+
+func (c RetentionConfig) validate() (problems []string) {
+	if c.UserRetentionDays < 0 {
+		problems = append(problems, "USER_RETENTION_DAYS ne peut pas être négatif")
+	}
+	if !oneOf(c.UserPurgeMode, "anonymize", "delete") {
+		problems = append(problems, "USER_PURGE_MODE doit valoir anonymize ou delete")
+	}
+	if c.UserPurgeInterval <= 0 {
+		problems = append(problems, "USER_PURGE_INTERVAL doit être positif")
+	}
+	return
+}
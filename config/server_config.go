@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+)
+
+type ServerConfig struct {
+	Address          string `mapstructure:"SERVER_ADDRESS"`
+	AllowOrigins     string `mapstructure:"CORS_ALLOW_ORIGINS"`
+	AllowCredentials bool   `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+}
+
+var serverSettings = []setting{
+	{key: "SERVER_ADDRESS", defaultValue: ":8000", usage: "adresse d'écoute du serveur HTTP"},
+	{key: "CORS_ALLOW_ORIGINS", defaultValue: "*", usage: "origines autorisées par CORS, séparées par des virgules (* : toutes)"},
+	{key: "CORS_ALLOW_CREDENTIALS", defaultValue: false, usage: "autorise l'envoi des cookies et en-têtes d'authentification par CORS"},
+}
+
+// CorsOrigins retourne la liste des origines autorisées par CORS
+func (c ServerConfig) CorsOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.AllowOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+func (c ServerConfig) validate() (problems []string) {
+	if c.Address == "" {
+		problems = append(problems, "SERVER_ADDRESS est requis")
+	}
+	origins := c.CorsOrigins()
+	if len(origins) == 0 {
+		problems = append(problems, "CORS_ALLOW_ORIGINS est requis")
+	}
+	// Les navigateurs refusent les identifiants vers une origine quelconque
+	if c.AllowCredentials && oneOf("*", origins...) {
+		problems = append(problems, "CORS_ALLOW_CREDENTIALS exige des origines explicites dans CORS_ALLOW_ORIGINS")
+	}
+	return
+}
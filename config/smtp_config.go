@@ -0,0 +1,48 @@
+package config
+
+import (
+	"net/mail"
+	"time"
+)
+
+type SmtpConfig struct {
+	Host     string        `mapstructure:"SMTP_HOST"`
+	Port     int           `mapstructure:"SMTP_PORT"`
+	Username string        `mapstructure:"SMTP_USERNAME"`
+	Password string        `mapstructure:"SMTP_PASSWORD"`
+	From     string        `mapstructure:"SMTP_FROM"`
+	Timeout  time.Duration `mapstructure:"SMTP_TIMEOUT"`
+}
+
+// Les codes de vérification, invitations, alertes de connexion et confirmations de changement
+// d'identité sont envoyés par email. Sans SMTP_HOST, ils ne sont qu'écrits dans les logs :
+// ce mode est réservé au développement.
+var smtpSettings = []setting{
+	{key: "SMTP_HOST", defaultValue: "", usage: "serveur SMTP d'envoi des notifications ; vide, elles sont seulement écrites dans les logs"},
+	{key: "SMTP_PORT", defaultValue: 587, usage: "port du serveur SMTP, STARTTLS est utilisé s'il est proposé"},
+	{key: "SMTP_USERNAME", defaultValue: "", usage: "utilisateur SMTP, vide pour un envoi sans authentification"},
+	{key: "SMTP_PASSWORD", defaultValue: "", usage: "mot de passe SMTP", secret: true},
+	{key: "SMTP_FROM", defaultValue: "", usage: "adresse d'expédition des notifications"},
+	{key: "SMTP_TIMEOUT", defaultValue: "10s", usage: "délai d'attente d'un envoi"},
+}
+
+// Enabled indique si les notifications sont réellement envoyées
+func (c SmtpConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+func (c SmtpConfig) validate() (problems []string) {
+	if !c.Enabled() {
+		return
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		problems = append(problems, "SMTP_PORT doit être compris entre 1 et 65535")
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		problems = append(problems, "SMTP_FROM doit être une adresse email valide lorsque SMTP_HOST est défini")
+	}
+	if c.Timeout <= 0 {
+		problems = append(problems, "SMTP_TIMEOUT doit être positif")
+	}
+	return
+}
@@ -0,0 +1,31 @@
+package config
+
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"TRACING_EXPORTER"`
+	Endpoint    string  `mapstructure:"TRACING_ENDPOINT"`
+	Insecure    bool    `mapstructure:"TRACING_INSECURE"`
+	File        string  `mapstructure:"TRACING_FILE"`
+	ServiceName string  `mapstructure:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+}
+
+// L'exportateur des traces est none (désactivé), otlp (collecteur OTLP/HTTP à l'adresse
+// TRACING_ENDPOINT), stdout ou file (TRACING_FILE) pour un usage local
+var tracingSettings = []setting{
+	{key: "TRACING_EXPORTER", defaultValue: "none", usage: "exportateur des traces : none, otlp, stdout ou file"},
+	{key: "TRACING_ENDPOINT", defaultValue: "localhost:4318", usage: "adresse du collecteur OTLP/HTTP"},
+	{key: "TRACING_INSECURE", defaultValue: true, usage: "envoie les traces au collecteur sans TLS"},
+	{key: "TRACING_FILE", defaultValue: "traces.json", usage: "fichier des traces de l'exportateur file"},
+	{key: "TRACING_SERVICE_NAME", defaultValue: "auth", usage: "nom du service dans les traces"},
+	{key: "TRACING_SAMPLE_RATIO", defaultValue: 1.0, usage: "proportion des traces échantillonnées, entre 0 et 1"},
+}
+
+func (c TracingConfig) validate() (problems []string) {
+	if !oneOf(c.Exporter, "none", "otlp", "stdout", "file") {
+		problems = append(problems, "TRACING_EXPORTER doit valoir none, otlp, stdout ou file")
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		problems = append(problems, "TRACING_SAMPLE_RATIO doit être compris entre 0 et 1")
+	}
+	return
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"time"
+)
+
+type WebhookConfig struct {
+	DispatchInterval time.Duration `mapstructure:"WEBHOOK_DISPATCH_INTERVAL"`
+	Timeout          time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	MaxAttempts      int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	RetryBaseDelay   time.Duration `mapstructure:"WEBHOOK_RETRY_BASE_DELAY"`
+	RetryMaxDelay    time.Duration `mapstructure:"WEBHOOK_RETRY_MAX_DELAY"`
+	AllowPrivateIPs  bool          `mapstructure:"WEBHOOK_ALLOW_PRIVATE_IPS"`
+}
+
+// Le délai entre deux tentatives d'envoi est doublé à chaque échec, dans la limite du plafond
+var webhookSettings = []setting{
+	{key: "WEBHOOK_DISPATCH_INTERVAL", defaultValue: "5s", usage: "fréquence de distribution des évènements de l'outbox"},
+	{key: "WEBHOOK_TIMEOUT", defaultValue: "10s", usage: "délai d'attente d'un envoi"},
+	{key: "WEBHOOK_MAX_ATTEMPTS", defaultValue: 8, usage: "nombre de tentatives avant l'abandon d'un envoi"},
+	{key: "WEBHOOK_RETRY_BASE_DELAY", defaultValue: "30s", usage: "délai avant la première nouvelle tentative"},
+	{key: "WEBHOOK_RETRY_MAX_DELAY", defaultValue: "6h", usage: "délai maximal entre deux tentatives"},
+	{key: "WEBHOOK_ALLOW_PRIVATE_IPS", defaultValue: false, usage: "autorise les webhooks vers des adresses privées ou locales, à réserver au développement"},
+}
+
+func (c WebhookConfig) validate() (problems []string) {
+	if c.DispatchInterval <= 0 {
+		problems = append(problems, "WEBHOOK_DISPATCH_INTERVAL doit être positif")
+	}
+	if c.Timeout <= 0 {
+		problems = append(problems, "WEBHOOK_TIMEOUT doit être positif")
+	}
+	if c.MaxAttempts < 1 {
+		problems = append(problems, "WEBHOOK_MAX_ATTEMPTS doit être au moins 1")
+	}
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		problems = append(problems, "WEBHOOK_RETRY_BASE_DELAY doit être positif et inférieur à WEBHOOK_RETRY_MAX_DELAY")
+	}
+	return
+}
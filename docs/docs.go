@@ -24,208 +24,332 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/forget_password": {
-            "put": {
-                "description": "Voir le profil d'un utilisateur",
+        "/attributes": {
+            "get": {
+                "description": "Liste les attributs de profil définis pour l'organisation active.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentications"
+                    "Attributes"
+                ],
+                "summary": "Liste les attributs personnalisés",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_attributes_AttributeDefinitionOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Définit un nouvel attribut de profil (type, validation, exposition dans les tokens) pour l'organisation active.",
+                "consumes": [
+                    "application/json"
                 ],
-                "summary": "Voir le profil d'un utilisateur",
-                "responses": {}
-            }
-        },
-        "/auth/init_password": {
-            "put": {
-                "description": "Initialisation du mot de passe utilisateur",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentications"
+                    "Attributes"
                 ],
-                "summary": "Initialisation du mot de passe utilisateur",
+                "summary": "Crée un attribut personnalisé",
                 "parameters": [
                     {
                         "description": "Body data",
-                        "name": "init_password",
+                        "name": "attribute",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/authentications.InitUserPasswordIn"
+                            "$ref": "#/definitions/attributes.AttributeDefinitionIn"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-any"
+                            "$ref": "#/definitions/utils.HttpResponse-attributes_AttributeDefinitionOut"
                         }
                     }
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Authentifier un utlisateur",
+        "/attributes/{name}": {
+            "get": {
+                "description": "Récupère un attribut de profil en fonction de son nom.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentications"
+                    "Attributes"
                 ],
-                "summary": "Authentifier un utlisateur",
+                "summary": "Récupère un attribut personnalisé",
                 "parameters": [
                     {
-                        "description": "Détails de l'utilisateur",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/authentications.AuthIn"
-                        }
+                        "type": "string",
+                        "description": "Nom de l'attribut",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-authentications_AuthOut"
+                            "$ref": "#/definitions/utils.HttpResponse-attributes_AttributeDefinitionOut"
                         }
                     }
                 }
-            }
-        },
-        "/auth/me": {
-            "get": {
-                "description": "Voir le profil d'un utilisateur",
+            },
+            "put": {
+                "description": "Modifie le libellé, la validation et l'exposition d'un attribut. Son nom et son type ne changent pas.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentications"
+                    "Attributes"
+                ],
+                "summary": "Modifie un attribut personnalisé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom de l'attribut",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "attribute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/attributes.AttributeDefinitionUpdateIn"
+                        }
+                    }
                 ],
-                "summary": "Voir le profil d'un utilisateur",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                            "$ref": "#/definitions/utils.HttpResponse-attributes_AttributeDefinitionOut"
                         }
                     }
                 }
-            }
-        },
-        "/auth/refresh_token": {
-            "put": {
-                "description": "Rafraichir le token",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Supprime un attribut de profil et ses valeurs pour tous les utilisateurs de l'organisation.",
                 "tags": [
-                    "Authentications"
+                    "Attributes"
+                ],
+                "summary": "Supprime un attribut personnalisé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom de l'attribut",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Rafraichir le token",
                 "responses": {
                     "202": {
                         "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-authentications_RefreshTokenOut"
+                            "$ref": "#/definitions/utils.HttpResponse-any"
                         }
                     }
                 }
             }
         },
-        "/auth/reset_password": {
-            "put": {
-                "description": "Changer de mot de passe",
+        "/audit/events": {
+            "get": {
+                "description": "Liste les évènements de sécurité (connexions, codes otp, mots de passe, rôles, suppressions, permissions, impersonations), les plus récents en premier.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentications"
+                    "Audit"
                 ],
-                "summary": "Changer de mot de passe",
+                "summary": "Consulte le journal d'audit",
                 "parameters": [
                     {
-                        "description": "Détails de l'utilisateur",
-                        "name": "reset_password",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/authentications.ResetPasswordIn"
-                        }
+                        "type": "integer",
+                        "description": "Nombre d'évènements par page (200 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'évènements à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, par exemple auth.login",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Résultat : success ou failure",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Auteur de l'action, ou administrateur en impersonation",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cible de l'action",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organisation active de l'auteur",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifiant de la requête (X-Request-Id)",
+                        "name": "correlation_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Adresse IP du client",
+                        "name": "ip_address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date minimale (RFC3339 ou AAAA-MM-JJ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date maximale, exclue (RFC3339 ou AAAA-MM-JJ)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-authentications_AuthOut"
+                            "$ref": "#/definitions/utils.HttpResponse-audit_AuditListOut"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/audit/verify": {
             "get": {
-                "description": "Récupère la liste de tous les utilisateurs.",
+                "description": "Recalcule la chaîne d'empreintes et signale la première entrée modifiée, supprimée ou insérée.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Audit"
                 ],
-                "summary": "Récupère tous les utilisateurs",
+                "summary": "Contrôle l'intégrité du journal d'audit",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
+                            "$ref": "#/definitions/utils.HttpResponse-audit_AuditVerificationOut"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/forget_password": {
             "put": {
-                "description": "Met à jour un utilisateur en fonction de son ID avec les détails fournis.",
-                "consumes": [
+                "description": "Voir le profil d'un utilisateur",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Authentications"
+                ],
+                "summary": "Voir le profil d'un utilisateur",
+                "responses": {}
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authentifier un utlisateur\nAvec la double authentification, le code est envoyé par email et la réponse porte la session à vérifier sur /auth/two_factor_verification.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentications"
                 ],
-                "summary": "Met à jour un utilisateur",
+                "summary": "Authentifier un utlisateur",
                 "parameters": [
                     {
-                        "description": "Détails de l'utilisateur à mettre à jour",
+                        "description": "Détails de l'utilisateur",
                         "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/users.UserIn"
+                            "$ref": "#/definitions/authentications.AuthIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authentications_AuthResponse-authentications_AuthOut"
                         }
                     }
+                }
+            }
+        },
+        "/auth/logins/report": {
+            "get": {
+                "description": "Retourne la connexion signalée tant que le lien est valide.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Logins"
+                ],
+                "summary": "Vérifie un lien de signalement de connexion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token du lien reçu dans l'alerte",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                            "$ref": "#/definitions/utils.HttpResponse-logins_LoginEventOut"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Crée un nouvel utilisateur avec les détails fournis.",
+                "description": "Déconnecte tous les appareils de l'utilisateur et remplace son mot de passe. Le lien ne peut être utilisé qu'une fois.",
                 "consumes": [
                     "application/json"
                 ],
@@ -233,43 +357,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Logins"
                 ],
-                "summary": "Crée un nouvel utilisateur",
+                "summary": "Signale une connexion frauduleuse",
                 "parameters": [
                     {
                         "description": "Body data",
-                        "name": "user",
+                        "name": "report",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/users.UserIn"
+                            "$ref": "#/definitions/logins.ReportLoginIn"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                            "$ref": "#/definitions/utils.HttpResponse-any"
                         }
                     }
                 }
             }
         },
-        "/users/profile": {
+        "/auth/me": {
             "get": {
-                "description": "view user profile info.",
+                "description": "Voir le profil d'un utilisateur",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentications"
                 ],
-                "summary": "User profile",
+                "summary": "Voir le profil d'un utilisateur",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
                         }
@@ -277,423 +401,6070 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/auth/organizations": {
             "get": {
-                "description": "Récupère un utilisateur en fonction de son ID.",
+                "description": "Lister les organisations dont l'utilisateur connecté est membre",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Récupère un utilisateur par ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Authentications"
                 ],
+                "summary": "Lister les organisations de l'utilisateur",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/utils.HttpResponse-array_authentications_OrganizationOut"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh_token": {
             "put": {
-                "description": "Met à jour un utilisateur en fonction de son ID avec les détails fournis.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Rafraichir le token",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentications"
                 ],
-                "summary": "Met à jour un utilisateur",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
+                "summary": "Rafraichir le token",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authentications_RefreshTokenOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/renew_password": {
+            "put": {
+                "description": "Sans session : l'ancien mot de passe prouve l'identité, puis le compte redevient actif.\nL'utilisateur se connecte ensuite avec son nouveau mot de passe.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentications"
+                ],
+                "summary": "Renouveler un mot de passe expiré",
+                "parameters": [
+                    {
+                        "description": "Identifiants et nouveau mot de passe",
+                        "name": "renew_password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authentications.RenewPasswordIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset_password": {
+            "put": {
+                "description": "Changer de mot de passe",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentications"
+                ],
+                "summary": "Changer de mot de passe",
+                "parameters": [
+                    {
+                        "description": "Détails de l'utilisateur",
+                        "name": "reset_password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authentications.ResetPasswordIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authentications_AuthOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/switch_organization": {
+            "put": {
+                "description": "Génère de nouveaux tokens pour une autre organisation de l'utilisateur",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentications"
+                ],
+                "summary": "Changer d'organisation active",
+                "parameters": [
                     {
                         "description": "Body data",
-                        "name": "user",
+                        "name": "switch_organization",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/users.UserIn"
+                            "$ref": "#/definitions/authentications.SwitchOrganizationIn"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                            "$ref": "#/definitions/utils.HttpResponse-authentications_AuthOut"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Supprime un utilisateur en fonction de son ID.",
+            }
+        },
+        "/auth/two_factor_verification": {
+            "post": {
+                "description": "Authentifier un utlisateur",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Users"
+                    "Authentications"
                 ],
-                "summary": "Supprime un utilisateur",
+                "summary": "Authentifier un utlisateur",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Session et code reçu par email",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authentications.TwoFactorIn"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Aucun contenu"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authentications_AuthOut"
+                        }
                     }
                 }
             }
         },
-        "/users/{id}/assign-role/{role}": {
+        "/authz/check": {
             "post": {
-                "description": "Assigner un rôle spécifié à un utilisateur en fonction de son ID.",
+                "description": "Évalue les politiques actives de l'organisation active pour un sujet, une ressource, une action et un environnement.\nSans sujet, les attributs du token appelant sont utilisés.\nLe rôle, les rôles et les permissions du sujet sont chargés depuis ses rôles enregistrés (subject.id et subject.org_id) ; ceux fournis dans la requête sont ignorés.\nÀ défaut de politique applicable, l'action est accordée si ces rôles donnent la permission \u003caction\u003e_\u003ctype\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Users"
+                    "Authorizations"
                 ],
-                "summary": "Assigner un rôle à un utilisateur",
+                "summary": "Décision d'autorisation",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
+                        "description": "Body data",
+                        "name": "check",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authorizations.CheckIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-service_AuthorizationDecision"
+                        }
+                    }
+                }
+            }
+        },
+        "/authz/check/batch": {
+            "post": {
+                "description": "Évalue une liste de couples (action, ressource) pour l'utilisateur connecté.\nUne action est accordée par une politique active ou, à défaut, par la permission \u003caction\u003e_\u003ctype\u003e des rôles enregistrés de l'utilisateur dans l'organisation active.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Décisions d'autorisation groupées",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Rôle à assigner",
-                        "name": "role",
-                        "in": "path",
-                        "required": true
+                        "description": "Body data",
+                        "name": "checks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authorizations.BatchCheckIn"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Aucun contenu"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_authorizations_BatchDecisionOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/authz/permissions": {
+            "get": {
+                "description": "Retourne les rôles portés par le token dans l'organisation active et les permissions qui en découlent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Permissions effectives",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authorizations_EffectivePermissionsOut"
+                        }
                     }
                 }
             }
         },
-        "/users/{id}/remove-role/{role}": {
+        "/authz/policies": {
+            "get": {
+                "description": "Liste la version active de chaque politique d'accès de l'organisation active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Liste les politiques actives",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_authorizations_PolicyOut"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Supprimer un rôle spécifié d'un utilisateur en fonction de son ID.",
+                "description": "Crée la première version d'une politique d'accès.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Users"
+                    "Authorizations"
                 ],
-                "summary": "Supprimer un rôle d'un utilisateur",
+                "summary": "Crée une politique",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de l'utilisateur",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
+                        "description": "Body data",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authorizations.PolicyIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authorizations_PolicyOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/authz/policies/{name}": {
+            "get": {
+                "description": "Récupère la version active d'une politique par son nom.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Récupère une politique",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Rôle à supprimer",
-                        "name": "role",
+                        "description": "Nom de la politique",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Aucun contenu"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authorizations_PolicyOut"
+                        }
                     }
                 }
+            },
+            "put": {
+                "description": "Enregistre une nouvelle version active ; les versions précédentes restent consultables.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Nouvelle version d'une politique",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom de la politique",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/authorizations.UpdatePolicyIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-authorizations_PolicyOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Retire une politique de l'évaluation en conservant son historique.",
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Désactive une politique",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom de la politique",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/authz/policies/{name}/versions": {
+            "get": {
+                "description": "Liste toutes les versions d'une politique, la plus récente en premier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorizations"
+                ],
+                "summary": "Historique d'une politique",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom de la politique",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_authorizations_PolicyOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
+                "description": "Récupère la liste des groupes de l'organisation active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Récupère tous les groupes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_groups_GroupOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Crée un nouveau groupe dans l'organisation active.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Crée un nouveau groupe",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/groups.GroupIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-groups_GroupOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "description": "Récupère un groupe en fonction de son ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Récupère un groupe par ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-groups_GroupOut"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Met à jour le nom et la description d'un groupe.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Met à jour un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/groups.GroupIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-groups_GroupOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Supprime un groupe avec ses adhésions et ses rôles.",
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Supprime un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/members": {
+            "get": {
+                "description": "Liste les utilisateurs membres d'un groupe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Liste les membres d'un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_groups_GroupMemberOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Ajoute un utilisateur de l'organisation active à un groupe.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Ajoute un membre à un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/groups.GroupMemberIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/members/{userId}": {
+            "delete": {
+                "description": "Retire un utilisateur d'un groupe.",
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Retire un membre d'un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/roles": {
+            "post": {
+                "description": "Les membres du groupe héritent des permissions du rôle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Attache un rôle à un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/groups.GroupRoleIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-groups_GroupOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/roles/{role}": {
+            "delete": {
+                "description": "Détache un rôle d'un groupe.",
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Détache un rôle d'un groupe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID du groupe",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rôle à retirer",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-groups_GroupOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Répond 200 tant que le serveur traite des requêtes ; aucune dépendance n'est vérifiée.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Sonde de vie",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-health_HealthOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations": {
+            "get": {
+                "description": "Liste les sessions d'impersonation de l'organisation active, les plus récentes en premier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Impersonations"
+                ],
+                "summary": "Liste les impersonations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_impersonations_ImpersonationOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Génère un access token de courte durée pour un utilisateur de l'organisation active.\nLe token porte le claim \"act\" de l'administrateur, ne peut pas être rafraîchi\net ne permet ni changement de mot de passe ni modification du 2FA.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Impersonations"
+                ],
+                "summary": "Démarre une impersonation",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "impersonation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/impersonations.ImpersonationIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-impersonations_StartImpersonationOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations/end": {
+            "post": {
+                "description": "Termine la session d'impersonation du token utilisé ; il est refusé dès la requête suivante.",
+                "tags": [
+                    "Impersonations"
+                ],
+                "summary": "Termine l'impersonation en cours",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/impersonations/{id}": {
+            "get": {
+                "description": "Récupère une session d'impersonation et les requêtes effectuées sous son token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Impersonations"
+                ],
+                "summary": "Détail d'une impersonation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de la session",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-impersonations_ImpersonationOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Termine une session de l'organisation active ; son token est refusé dès la requête suivante.",
+                "tags": [
+                    "Impersonations"
+                ],
+                "summary": "Termine une impersonation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de la session",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations": {
+            "get": {
+                "description": "Invitations ni acceptées ni révoquées de l'organisation active ; les invitations expirées peuvent être renvoyées.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Liste les invitations en attente",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_invitations_InvitationOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Sans user_id, crée un compte en attente d'activation dans l'organisation active et envoie le lien d'invitation.\nAvec user_id, envoie à un membre existant un lien pour définir un nouveau mot de passe.\nLe lien est à usage unique et expire ; une nouvelle invitation révoque les précédentes du même utilisateur.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Invite un utilisateur",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/invitations.InvitationIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-invitations_InvitationOut"
+                        }
+                    },
+                    "502": {
+                        "description": "Invitation enregistrée mais email non envoyé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-invitations_InvitationOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/accept": {
+            "get": {
+                "description": "Retourne l'adresse email invitée tant que le lien est valide.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Vérifie un lien d'invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token du lien d'invitation",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-invitations_InvitationPreviewOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Enregistre le mot de passe choisi et active le compte. Le lien ne peut être utilisé qu'une fois.\nUn compte désactivé ou verrouillé ne peut pas être activé par ce lien.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Accepte une invitation",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "accept",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/invitations.AcceptInvitationIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{id}": {
+            "delete": {
+                "description": "Le lien de l'invitation devient immédiatement invalide.\nUn compte jamais activé, sans mot de passe, est supprimé avec l'invitation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Révoque une invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'invitation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/invitations/{id}/resend": {
+            "post": {
+                "description": "Génère un nouveau lien avec une nouvelle date d'expiration ; le lien précédent devient invalide.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invitations"
+                ],
+                "summary": "Renvoie une invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'invitation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-invitations_InvitationOut"
+                        }
+                    },
+                    "502": {
+                        "description": "Invitation enregistrée mais email non envoyé",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-invitations_InvitationOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations": {
+            "get": {
+                "description": "Récupère la liste de toutes les organisations.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Récupère toutes les organisations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_organizations_OrganizationOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Crée une nouvelle organisation avec les détails fournis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Crée une nouvelle organisation",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.OrganizationIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-organizations_OrganizationOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}": {
+            "get": {
+                "description": "Récupère une organisation en fonction de son ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Récupère une organisation par ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'organisation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-organizations_OrganizationOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members": {
+            "get": {
+                "description": "Liste les membres d'une organisation avec leurs rôles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Liste les membres d'une organisation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'organisation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_organizations_MemberOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Ajoute un utilisateur existant à une organisation avec les rôles fournis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Ajoute un membre à une organisation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'organisation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.MemberIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-organizations_MemberOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{id}/members/{userId}": {
+            "put": {
+                "description": "Remplace les rôles d'un membre dans une organisation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Modifie les rôles d'un membre",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'organisation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "roles",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/organizations.MemberRolesIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-organizations_MemberOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Retire un utilisateur d'une organisation.",
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Retire un membre d'une organisation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'organisation",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Vérifie la connexion à la base de données, l'absence de migration en attente et la clé de signature des tokens.\nRépond 503 si une vérification échoue, avec le détail de chacune.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Sonde de disponibilité",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-health_ReadinessOut"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-health_ReadinessOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor/roles": {
+            "get": {
+                "description": "Liste les rôles et indique ceux qui imposent la double authentification.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Liste l'obligation de double authentification par rôle",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_twofactor_RoleTwoFactorOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor/roles/{name}": {
+            "put": {
+                "description": "Les utilisateurs du rôle sans second facteur devront en enrôler un à leur prochaine connexion.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Impose la double authentification à un rôle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nom du rôle",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactor.RoleTwoFactorIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-twofactor_RoleTwoFactorOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Récupère une page d'utilisateurs de l'organisation active avec le nombre total de résultats.\nPagination par offset (limit, offset) ou par curseur (cursor, retourné dans next_cursor).\nLe tri accepte name, email, username, created_at et updated_at, préfixés par \"-\" pour un ordre décroissant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupère tous les utilisateurs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Taille de la page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'utilisateurs à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curseur de la page suivante",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Champ de tri, par exemple -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nom du rôle",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domaine de l'adresse email",
+                        "name": "email_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date de création minimale (RFC3339 ou AAAA-MM-JJ)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date de création maximale, exclue (RFC3339 ou AAAA-MM-JJ)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disponibilité du compte",
+                        "name": "is_available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Double authentification activée",
+                        "name": "use_otp",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserListOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Crée un nouvel utilisateur avec les détails fournis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Crée un nouvel utilisateur",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UserIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/confirm-change": {
+            "post": {
+                "description": "Applique le changement associé au lien reçu par email. Le lien n'est utilisable qu'une fois.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Confirme un changement d'identifiant",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "confirmation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.ConfirmIdentityChangeIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_IdentityChangeOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/export": {
+            "get": {
+                "description": "Exporte en continu tous les utilisateurs correspondant aux filtres, au format CSV, JSON ou JSON Lines.\nLes champs exportables sont id, name, sername, email, username, role, use_otp, is_available, created_at et updated_at.",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Exporte les utilisateurs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (par défaut), json ou jsonl",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Champs exportés, séparés par des virgules (tous par défaut)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Champ de tri, par exemple -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nom du rôle",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domaine de l'adresse email",
+                        "name": "email_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date de création minimale (RFC3339 ou AAAA-MM-JJ)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date de création maximale, exclue (RFC3339 ou AAAA-MM-JJ)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disponibilité du compte",
+                        "name": "is_available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Double authentification activée",
+                        "name": "use_otp",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/import": {
+            "post": {
+                "description": "Crée des utilisateurs dans l'organisation active depuis un fichier CSV (en-tête name,sername,email,username,password,role)\nou JSON Lines, envoyé dans le champ \"file\" d'un formulaire multipart ou directement dans le corps.\nChaque ligne est validée comme UserIn ; le rôle vaut \"client\" par défaut.\nAvec dry_run, le rapport est retourné sans rien écrire. Sinon l'import est transactionnel :\nune seule ligne invalide annule l'ensemble.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Importe des utilisateurs",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Fichier CSV ou JSONL",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Simulation sans écriture",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Envoie une invitation aux utilisateurs créés",
+                        "name": "send_invitations",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv ou jsonl, déduit du fichier par défaut",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-service_ImportReport"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-service_ImportReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "description": "Récupère le profil de l'utilisateur connecté, avec sa langue, sa photo et les changements d'identifiants en attente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Mon profil",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_ProfileOut"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Modifie le nom, le prénom et la langue préférée de l'utilisateur connecté.\nL'email et le nom d'utilisateur se modifient via /users/me/email et /users/me/username.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modifie mon profil",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UpdateProfileIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_ProfileOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/attributes": {
+            "get": {
+                "description": "Récupère les attributs personnalisés de l'utilisateur connecté dans l'organisation active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Mes attributs personnalisés",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Modifie les attributs personnalisés de l'utilisateur connecté. Seuls les attributs modifiables par l'utilisateur sont acceptés ; une valeur null supprime l'attribut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modifie mes attributs personnalisés",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "attributes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UserAttributesIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/avatar": {
+            "get": {
+                "description": "Télécharge la photo de profil de l'utilisateur connecté.",
+                "produces": [
+                    "image/png",
+                    "image/jpeg",
+                    "image/gif"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Ma photo de profil",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Remplace la photo de profil. Images PNG, JPEG ou GIF uniquement, dans la limite de la taille configurée (AVATAR_MAX_SIZE).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Envoie ma photo de profil",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_ProfileOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Supprime la photo de profil de l'utilisateur connecté.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Supprime ma photo de profil",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/data": {
+            "get": {
+                "description": "Archive zip contenant le profil, les organisations, les groupes, la double authentification, les impersonations, les attributs personnalisés et les sessions.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Exporte mes données personnelles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/email": {
+            "post": {
+                "description": "Vérifie le mot de passe actuel et envoie un lien de confirmation à la nouvelle adresse.\nL'adresse n'est modifiée qu'après confirmation, et toutes les sessions sont alors révoquées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change mon adresse email",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.ChangeEmailIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_IdentityChangeOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/logins": {
+            "get": {
+                "description": "Liste les connexions réussies de l'utilisateur connecté, les plus récentes en premier, avec l'appareil et la localisation approximative.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Logins"
+                ],
+                "summary": "Liste ses dernières connexions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Nombre de connexions par page (100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de connexions à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-logins_LoginHistoryOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions": {
+            "get": {
+                "description": "Liste les appareils sur lesquels l'utilisateur connecté est authentifié ; la session de la requête est marquée \"current\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Liste ses sessions actives",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_sessions_SessionOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions/{id}": {
+            "delete": {
+                "description": "Déconnecte un appareil : le refresh token et les access tokens de la session sont refusés.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Révoque une de ses sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de la session",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/two-factor": {
+            "get": {
+                "description": "Indique si la double authentification est active ou imposée par un rôle et liste les seconds facteurs enrôlés.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Consulte ses seconds facteurs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-twofactor_TwoFactorStatusOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/two-factor/challenge": {
+            "post": {
+                "description": "Demande un code sur un second facteur confirmé (le facteur par défaut si aucun n'est indiqué), utilisable pour confirmer son identité avant une modification.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Demande un code de vérification",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "challenge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactor.TwoFactorChallengeIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-twofactor_TwoFactorChallengeOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/two-factor/methods": {
+            "post": {
+                "description": "Ajoute un second facteur totp (clé et adresse otpauth retournées une seule fois) ou email (code envoyé). Le mot de passe actuel ou un code récent est requis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Enrôle un second facteur",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "method",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactor.TwoFactorMethodIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-twofactor_TwoFactorEnrollmentOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/two-factor/methods/{id}": {
+            "delete": {
+                "description": "Supprime un second facteur ; la double authentification est désactivée avec le dernier. Refusé si un rôle l'impose. Le mot de passe actuel ou un code récent est requis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Supprime un second facteur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du second facteur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "reauth",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactor.TwoFactorReauthIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/two-factor/methods/{id}/confirm": {
+            "post": {
+                "description": "Active un second facteur enrôlé avec un premier code : celui de l'application pour totp, celui reçu (et sa session) pour email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Confirme un second facteur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du second facteur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactor.TwoFactorConfirmIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-twofactor_TwoFactorMethodOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/two-factor/methods/{id}/default": {
+            "put": {
+                "description": "Le second facteur par défaut est demandé à la connexion. Le mot de passe actuel ou un code récent est requis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "TwoFactor"
+                ],
+                "summary": "Choisit le second facteur par défaut",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du second facteur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "reauth",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/twofactor.TwoFactorReauthIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-twofactor_TwoFactorMethodOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/username": {
+            "post": {
+                "description": "Vérifie le mot de passe actuel et envoie un lien de confirmation à l'adresse email du compte.\nLe nom d'utilisateur n'est modifié qu'après confirmation, et toutes les sessions sont alors révoquées.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change mon nom d'utilisateur",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.ChangeUsernameIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_IdentityChangeOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "description": "view user profile info.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/search": {
+            "get": {
+                "description": "Recherche sans tenir compte de la casse dans le nom, le prénom, le nom d'utilisateur et l'email.\nChaque mot doit apparaître dans l'un de ces champs ; les correspondances exactes puis par préfixe sont classées en premier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Recherche des utilisateurs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texte recherché",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Taille de la page (20 par défaut, 100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de résultats à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserListOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Récupère un utilisateur en fonction de son ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Récupère un utilisateur par ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Met à jour un utilisateur en fonction de son ID avec les détails fournis.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Met à jour un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UserIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Supprime un utilisateur en fonction de son ID.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Supprime un utilisateur",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Aucun contenu"
+                    }
+                }
+            }
+        },
+        "/users/{id}/assign-role": {
+            "put": {
+                "description": "Ajoute le rôle aux rôles du membre dans l'organisation active. Avec global à true, remplace le rôle système de l'utilisateur, réservé aux administrateurs système.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Assigner un rôle à un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rôle à assigner",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.AssignRoleIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserRolesOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/attributes": {
+            "get": {
+                "description": "Récupère les attributs personnalisés d'un utilisateur de l'organisation active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Attributs personnalisés d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Modifie les attributs personnalisés d'un utilisateur de l'organisation active ; une valeur null supprime l'attribut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Modifie les attributs personnalisés d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "attributes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.UserAttributesIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/data": {
+            "get": {
+                "description": "Archive zip contenant le profil, les organisations, les groupes, la double authentification, les impersonations, les attributs personnalisés et les sessions.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Exporte les données personnelles d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/disable": {
+            "put": {
+                "description": "Désactive le compte et révoque immédiatement tous ses tokens, y compris les refresh tokens.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Désactive un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Motif de la désactivation",
+                        "name": "disable",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/users.DisableUserIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_AccountStateOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/enable": {
+            "put": {
+                "description": "Rend actif un compte désactivé, verrouillé ou en attente d'activation.\nPour un mot de passe expiré, lève l'expiration sans renouvellement.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Active un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_AccountStateOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/expire-password": {
+            "put": {
+                "description": "Les tokens de l'utilisateur sont refusés jusqu'au renouvellement de son mot de passe par PUT /auth/renew_password.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Fait expirer le mot de passe d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_AccountStateOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/groups": {
+            "get": {
+                "description": "Liste les groupes de l'utilisateur dans l'organisation active avec leurs rôles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Groupes d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_users_UserGroupOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/logins": {
+            "get": {
+                "description": "Liste les connexions réussies d'un utilisateur de l'organisation active, les plus récentes en premier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Logins"
+                ],
+                "summary": "Liste les dernières connexions d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de connexions par page (100 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre de connexions à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-logins_LoginHistoryOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/remove-role": {
+            "put": {
+                "description": "Retire le rôle des rôles du membre dans l'organisation active. Le rôle système de l'utilisateur n'est pas modifié.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Supprimer un rôle d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rôle à retirer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/users.RemoveRoleIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserRolesOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restore": {
+            "post": {
+                "description": "Rend de nouveau actif un utilisateur supprimé, tant que la durée de rétention n'est pas écoulée.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Restaure un utilisateur supprimé",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-users_UserOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions": {
+            "get": {
+                "description": "Liste les appareils sur lesquels un utilisateur de l'organisation active est authentifié.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Liste les sessions actives d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_sessions_SessionOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions/{sessionId}": {
+            "delete": {
+                "description": "Déconnecte un appareil d'un utilisateur de l'organisation active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Révoque une session d'un utilisateur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de l'utilisateur",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifiant de la session",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Version, commit et date de build du service, ainsi que la version de Go utilisée.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Informations de build",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-health_VersionOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Liste les webhooks de l'organisation active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Liste les webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-array_webhooks_WebhookOut"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Abonne une adresse aux évènements des utilisateurs de l'organisation active : user.created, user.updated, user.deleted, user.role_changed, user.password_reset.\nChaque envoi porte les en-têtes X-Webhook-Id, X-Webhook-Event, X-Webhook-Timestamp et X-Webhook-Signature (\"sha256=\" suivi du HMAC-SHA256 de \"\u003ctimestamp\u003e.\u003ccorps\u003e\").\nLe secret de signature n'est retourné qu'à la création.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Crée un webhook",
+                "parameters": [
+                    {
+                        "description": "Body data",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.WebhookIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-webhooks_WebhookCreatedOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/dead-letters": {
+            "get": {
+                "description": "Liste les envois abandonnés après le nombre maximal de tentatives, ou dont le webhook a été désactivé ou supprimé. Ils peuvent être renvoyés manuellement.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Liste les lettres mortes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du webhook",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'envois par page (200 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'envois à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-webhooks_DeliveryListOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries": {
+            "get": {
+                "description": "Liste les envois de l'organisation active, les plus récents en premier, avec le nombre de tentatives et la dernière erreur.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Liste les envois des webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du webhook",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statut : pending, delivered ou dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'envois par page (200 au maximum)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Nombre d'envois à ignorer",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-webhooks_DeliveryListOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/redeliver": {
+            "post": {
+                "description": "Remet en attente un envoi livré ou en lettre morte : il est renvoyé au prochain passage, avec le même identifiant d'évènement et un nouveau compteur de tentatives.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Renvoie un évènement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant de l'envoi",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-webhooks_DeliveryOut"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Retourne un webhook de l'organisation active, sans son secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Consulte un webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-webhooks_WebhookOut"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Modifie l'adresse, la description, les évènements ou l'activation d'un webhook. Les envois en attente d'un webhook désactivé passent en lettre morte.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Modifie un webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body data",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.WebhookIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-webhooks_WebhookOut"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Supprime un webhook de l'organisation active ; ses envois en attente passent en lettre morte.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Supprime un webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifiant du webhook",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HttpResponse-any"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "attributes.AttributeDefinitionIn": {
+            "type": "object",
+            "required": [
+                "label",
+                "name",
+                "options",
+                "type"
+            ],
+            "properties": {
+                "claim_name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "label": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "max_length": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "options": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "boolean",
+                        "date",
+                        "enum"
+                    ]
+                },
+                "user_editable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "attributes.AttributeDefinitionOut": {
+            "type": "object",
+            "properties": {
+                "claim_name": {
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "max_length": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_editable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "attributes.AttributeDefinitionUpdateIn": {
+            "type": "object",
+            "required": [
+                "label",
+                "options",
+                "type"
+            ],
+            "properties": {
+                "claim_name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "label": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "max_length": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "options": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "boolean",
+                        "date",
+                        "enum"
+                    ]
+                },
+                "user_editable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "audit.AuditEventOut": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_email": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "correlation_id": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "impersonator_id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "previous_hash": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.AuditListOut": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/audit.AuditEventOut"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "audit.AuditVerificationOut": {
+            "type": "object",
+            "properties": {
+                "broken_at": {
+                    "type": "integer"
+                },
+                "checked": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "authentications.AuthIn": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.AuthOut": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "token": {
+                    "$ref": "#/definitions/authentications.Token"
+                }
+            }
+        },
+        "authentications.AuthResponse-authentications_AuthOut": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "$ref": "#/definitions/authentications.AuthOut"
+                },
+                "is_authenticated": {
+                    "type": "boolean"
+                },
+                "use_otp": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.OrganizationOut": {
+            "type": "object",
+            "properties": {
+                "describe": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.RefreshTokenOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "org_id": {
+                    "type": "string"
+                },
+                "token": {
+                    "$ref": "#/definitions/authentications.Token"
+                }
+            }
+        },
+        "authentications.RenewPasswordIn": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password",
+                "username"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.ResetPasswordIn": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.SwitchOrganizationIn": {
+            "type": "object",
+            "required": [
+                "organization_id"
+            ],
+            "properties": {
+                "organization_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.Token": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "authentications.TwoFactorIn": {
+            "type": "object",
+            "properties": {
+                "otp": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "authorizations.BatchCheckIn": {
+            "type": "object",
+            "required": [
+                "checks"
+            ],
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/authorizations.BatchCheckItemIn"
+                    }
+                },
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "authorizations.BatchCheckItemIn": {
+            "type": "object",
+            "required": [
+                "action",
+                "resource"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "authorizations.BatchDecisionOut": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "allowed": {
+                    "type": "boolean"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "matched_policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "resource": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "authorizations.CheckIn": {
+            "type": "object",
+            "required": [
+                "action",
+                "resource"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "resource": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "subject": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "authorizations.EffectivePermissionsOut": {
+            "type": "object",
+            "properties": {
+                "org_id": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "authorizations.PolicyIn": {
+            "type": "object",
+            "required": [
+                "document",
+                "name"
+            ],
+            "properties": {
+                "describe": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "document": {
+                    "$ref": "#/definitions/service.PolicyDocument"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 120
+                }
+            }
+        },
+        "authorizations.PolicyOut": {
+            "type": "object",
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "describe": {
+                    "type": "string"
+                },
+                "document": {
+                    "$ref": "#/definitions/service.PolicyDocument"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "authorizations.UpdatePolicyIn": {
+            "type": "object",
+            "required": [
+                "document"
+            ],
+            "properties": {
+                "describe": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "document": {
+                    "$ref": "#/definitions/service.PolicyDocument"
+                }
+            }
+        },
+        "groups.GroupIn": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "describe": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 80
+                }
+            }
+        },
+        "groups.GroupMemberIn": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "groups.GroupMemberOut": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "groups.GroupOut": {
+            "type": "object",
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "describe": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "members_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "groups.GroupRoleIn": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.HealthCheckOut": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.HealthOut": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.ReadinessOut": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.HealthCheckOut"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.VersionOut": {
+            "type": "object",
+            "properties": {
+                "build_date": {
+                    "type": "string"
+                },
+                "commit": {
+                    "type": "string"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "modified": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "impersonations.ImpersonationAction": {
+            "type": "object",
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "impersonations.ImpersonationIn": {
+            "type": "object",
+            "required": [
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "impersonations.ImpersonationOut": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/impersonations.ImpersonationAction"
+                    }
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "ended_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "target_user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "impersonations.StartImpersonationOut": {
+            "type": "object",
+            "properties": {
+                "impersonation": {
+                    "$ref": "#/definitions/impersonations.ImpersonationOut"
+                },
+                "token": {
+                    "$ref": "#/definitions/impersonations.Token"
+                }
+            }
+        },
+        "impersonations.Token": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "invitations.AcceptInvitationIn": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "invitations.InvitationIn": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 80
+                },
+                "role": {
+                    "type": "string"
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "invitations.InvitationOut": {
+            "type": "object",
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "is_expired": {
+                    "type": "boolean"
+                },
+                "last_sent_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "sent_count": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "invitations.InvitationPreviewOut": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "logins.LoginEventOut": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "new_device": {
+                    "type": "boolean"
+                },
+                "new_location": {
+                    "type": "boolean"
+                },
+                "reported": {
+                    "type": "boolean"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "logins.LoginHistoryOut": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logins.LoginEventOut"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logins.ReportLoginIn": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Otp": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "expire_has": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "is_used": {
+                    "type": "boolean"
+                },
+                "is_visible": {
+                    "type": "boolean"
+                },
+                "method_id": {
+                    "description": "MethodId est le second facteur interrogé ; vide pour un code envoyé à l'adresse du compte",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "role_id",
+                "sername",
+                "username"
+            ],
+            "properties": {
+                "anonymized_at": {
+                    "description": "AnonymizedAt est renseigné lorsque la purge a effacé les données personnelles du compte",
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "failed_login_attempts": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "is_visible": {
+                    "type": "boolean"
+                },
+                "locale": {
+                    "description": "Locale est la langue préférée de l'utilisateur, AvatarFile le fichier de sa photo de profil",
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "otps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Otp"
+                    }
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "type": "string"
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status est l'état du compte : active, disabled, locked, pending_activation ou password_expired",
+                    "type": "string"
+                },
+                "status_changed_at": {
+                    "type": "string"
+                },
+                "status_reason": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "use_otp": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "organizations.MemberIn": {
+            "type": "object",
+            "required": [
+                "roles",
+                "user_id"
+            ],
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "organizations.MemberOut": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "membership_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "organizations.MemberRolesIn": {
+            "type": "object",
+            "required": [
+                "roles"
+            ],
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "organizations.OrganizationIn": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "describe": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 120
+                }
+            }
+        },
+        "organizations.OrganizationOut": {
+            "type": "object",
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "describe": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AuthorizationDecision": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "matched_policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "service.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "invited": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ImportRowResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PolicyCondition": {
+            "type": "object",
+            "required": [
+                "attribute",
+                "operator"
+            ],
+            "properties": {
+                "attribute": {
+                    "type": "string"
+                },
+                "attribute_ref": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string",
+                    "enum": [
+                        "eq",
+                        "ne",
+                        "in",
+                        "not_in",
+                        "contains",
+                        "gt",
+                        "gte",
+                        "lt",
+                        "lte",
+                        "between",
+                        "exists",
+                        "starts_with",
+                        "ends_with"
+                    ]
+                },
+                "value": {}
+            }
+        },
+        "service.PolicyDocument": {
+            "type": "object",
+            "required": [
+                "actions",
+                "effect",
+                "resources"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "all": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PolicyCondition"
+                    }
+                },
+                "any": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PolicyCondition"
+                    }
+                },
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ]
+                },
+                "resources": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "sessions.SessionOut": {
+            "type": "object",
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.RoleTwoFactorIn": {
+            "type": "object",
+            "properties": {
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "twofactor.RoleTwoFactorOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "require_two_factor": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "twofactor.TwoFactorChallengeIn": {
+            "type": "object",
+            "properties": {
+                "method_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.TwoFactorChallengeOut": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "method_id": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.TwoFactorConfirmIn": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.TwoFactorEnrollmentOut": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "$ref": "#/definitions/twofactor.TwoFactorChallengeOut"
+                },
+                "method": {
+                    "$ref": "#/definitions/twofactor.TwoFactorMethodOut"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.TwoFactorMethodIn": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "current_password": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "totp",
+                        "email"
+                    ]
+                }
+            }
+        },
+        "twofactor.TwoFactorMethodOut": {
+            "type": "object",
+            "properties": {
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_confirmed": {
+                    "type": "boolean"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.TwoFactorReauthIn": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "current_password": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "twofactor.TwoFactorStatusOut": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "methods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/twofactor.TwoFactorMethodOut"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "users.AccountStateOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_changed_at": {
+                    "type": "string"
+                },
+                "status_reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.AssignRoleIn": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "global": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.ChangeEmailIn": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "email"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.ChangeUsernameIn": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "username"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "users.ConfirmIdentityChangeIn": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.DisableUserIn": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "users.IdentityChangeOut": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.ProfileOut": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "create_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pending_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.IdentityChangeOut"
+                    }
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.RemoveRoleIn": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.UpdateProfileIn": {
+            "type": "object",
+            "required": [
+                "name",
+                "sername"
+            ],
+            "properties": {
+                "locale": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 80
+                },
+                "sername": {
+                    "type": "string",
+                    "maxLength": 80
+                }
+            }
+        },
+        "users.UserAttributesIn": {
+            "type": "object",
+            "required": [
+                "attributes"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "users.UserGroupOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "users.UserIn": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "sername",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.UserListOut": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.UserOut"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "users.UserOut": {
+            "type": "object",
+            "required": [
+                "email",
+                "id",
+                "name",
+                "role",
+                "sername",
+                "username"
+            ],
+            "properties": {
+                "create_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "sername": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.UserRolesOut": {
+            "type": "object",
+            "properties": {
+                "org_id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.HttpResponse-any": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_attributes_AttributeDefinitionOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/attributes.AttributeDefinitionOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_authentications_OrganizationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/authentications.OrganizationOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_authorizations_BatchDecisionOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/authorizations.BatchDecisionOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_authorizations_PolicyOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/authorizations.PolicyOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_groups_GroupMemberOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/groups.GroupMemberOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_groups_GroupOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/groups.GroupOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_impersonations_ImpersonationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/impersonations.ImpersonationOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_invitations_InvitationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invitations.InvitationOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_organizations_MemberOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/organizations.MemberOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_organizations_OrganizationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/organizations.OrganizationOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_sessions_SessionOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sessions.SessionOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_twofactor_RoleTwoFactorOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/twofactor.RoleTwoFactorOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_users_UserGroupOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.UserGroupOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-array_webhooks_WebhookOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhooks.WebhookOut"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-attributes_AttributeDefinitionOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/attributes.AttributeDefinitionOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-audit_AuditListOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/audit.AuditListOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-audit_AuditVerificationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/audit.AuditVerificationOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-authentications_AuthOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/authentications.AuthOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-authentications_AuthResponse-authentications_AuthOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/authentications.AuthResponse-authentications_AuthOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-authentications_RefreshTokenOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/authentications.RefreshTokenOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-authorizations_EffectivePermissionsOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/authorizations.EffectivePermissionsOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-authorizations_PolicyOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/authorizations.PolicyOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-groups_GroupOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/groups.GroupOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-health_HealthOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/health.HealthOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-health_ReadinessOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/health.ReadinessOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-health_VersionOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/health.VersionOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-impersonations_ImpersonationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/impersonations.ImpersonationOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-impersonations_StartImpersonationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/impersonations.StartImpersonationOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-invitations_InvitationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/invitations.InvitationOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-invitations_InvitationPreviewOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/invitations.InvitationPreviewOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-logins_LoginEventOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/logins.LoginEventOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-logins_LoginHistoryOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/logins.LoginHistoryOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-organizations_MemberOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/organizations.MemberOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-organizations_OrganizationOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/organizations.OrganizationOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-service_AuthorizationDecision": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/service.AuthorizationDecision"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-service_ImportReport": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/service.ImportReport"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-twofactor_RoleTwoFactorOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/twofactor.RoleTwoFactorOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-twofactor_TwoFactorChallengeOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/twofactor.TwoFactorChallengeOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-twofactor_TwoFactorEnrollmentOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/twofactor.TwoFactorEnrollmentOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-twofactor_TwoFactorMethodOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/twofactor.TwoFactorMethodOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-twofactor_TwoFactorStatusOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/twofactor.TwoFactorStatusOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-users_AccountStateOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/users.AccountStateOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-users_IdentityChangeOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/users.IdentityChangeOut"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
             }
-        }
-    },
-    "definitions": {
-        "authentications.AuthIn": {
+        },
+        "utils.HttpResponse-users_ProfileOut": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "code_error": {
+                    "type": "integer"
                 },
-                "username": {
+                "data": {
+                    "$ref": "#/definitions/users.ProfileOut"
+                },
+                "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "authentications.AuthOut": {
+        "utils.HttpResponse-users_UserListOut": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "code_error": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/users.UserListOut"
                 },
-                "role": {
+                "message": {
                     "type": "string"
                 },
-                "token": {
-                    "$ref": "#/definitions/authentications.Token"
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "authentications.InitUserPasswordIn": {
+        "utils.HttpResponse-users_UserOut": {
             "type": "object",
-            "required": [
-                "password",
-                "user_id"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "code_error": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "data": {
+                    "$ref": "#/definitions/users.UserOut"
+                },
+                "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "authentications.RefreshTokenOut": {
+        "utils.HttpResponse-users_UserRolesOut": {
             "type": "object",
             "properties": {
-                "id": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/users.UserRolesOut"
+                },
+                "message": {
                     "type": "string"
                 },
-                "token": {
-                    "$ref": "#/definitions/authentications.Token"
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "authentications.ResetPasswordIn": {
+        "utils.HttpResponse-webhooks_DeliveryListOut": {
             "type": "object",
-            "required": [
-                "new_password",
-                "old_password"
-            ],
             "properties": {
-                "new_password": {
-                    "type": "string"
+                "code_error": {
+                    "type": "integer"
                 },
-                "old_password": {
+                "data": {
+                    "$ref": "#/definitions/webhooks.DeliveryListOut"
+                },
+                "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "authentications.Token": {
+        "utils.HttpResponse-webhooks_DeliveryOut": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "code_error": {
+                    "type": "integer"
                 },
-                "expires_at": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/webhooks.DeliveryOut"
                 },
-                "refresh_token": {
+                "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.User": {
+        "utils.HttpResponse-webhooks_WebhookCreatedOut": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "role_id",
-                "sername",
-                "username"
-            ],
             "properties": {
-                "create_at": {
-                    "type": "string"
+                "code_error": {
+                    "type": "integer"
                 },
-                "deleted_at": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/webhooks.WebhookCreatedOut"
                 },
-                "email": {
+                "message": {
                     "type": "string"
                 },
-                "id": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "utils.HttpResponse-webhooks_WebhookOut": {
+            "type": "object",
+            "properties": {
+                "code_error": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/webhooks.WebhookOut"
+                },
+                "message": {
                     "type": "string"
                 },
-                "is_available": {
+                "success": {
                     "type": "boolean"
+                }
+            }
+        },
+        "webhooks.DeliveryListOut": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhooks.DeliveryOut"
+                    }
                 },
-                "is_visible": {
-                    "type": "boolean"
+                "limit": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "offset": {
+                    "type": "integer"
                 },
-                "password": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "webhooks.DeliveryOut": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "create_at": {
                     "type": "string"
                 },
-                "role_id": {
+                "delivered_at": {
                     "type": "string"
                 },
-                "sername": {
+                "event_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "event_type": {
                     "type": "string"
                 },
-                "username": {
+                "id": {
                     "type": "string"
-                }
-            }
-        },
-        "users.UserIn": {
-            "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "sername",
-                "username"
-            ],
-            "properties": {
-                "email": {
+                },
+                "last_attempt_at": {
                     "type": "string"
                 },
-                "name": {
+                "last_error": {
                     "type": "string"
                 },
-                "password": {
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "sername": {
+                "payload": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
                     "type": "string"
                 },
-                "username": {
+                "subscription_id": {
                     "type": "string"
                 }
             }
         },
-        "users.UserOut": {
+        "webhooks.WebhookCreatedOut": {
             "type": "object",
-            "required": [
-                "email",
-                "id",
-                "name",
-                "role",
-                "sername",
-                "username"
-            ],
             "properties": {
                 "create_at": {
                     "type": "string"
                 },
-                "email": {
+                "created_by": {
                     "type": "string"
                 },
-                "id": {
+                "description": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "role": {
+                "id": {
                     "type": "string"
                 },
-                "sername": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "secret": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
                 },
-                "username": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "utils.HttpResponse-any": {
+        "webhooks.WebhookIn": {
             "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
             "properties": {
-                "code_error": {
-                    "type": "integer"
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
                 },
-                "data": {},
-                "message": {
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "success": {
+                "is_active": {
                     "type": "boolean"
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 500
                 }
             }
         },
-        "utils.HttpResponse-authentications_AuthOut": {
+        "webhooks.WebhookOut": {
             "type": "object",
             "properties": {
-                "code_error": {
-                    "type": "integer"
-                },
-                "data": {
-                    "$ref": "#/definitions/authentications.AuthOut"
+                "create_at": {
+                    "type": "string"
                 },
-                "message": {
+                "created_by": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "utils.HttpResponse-authentications_RefreshTokenOut": {
-            "type": "object",
-            "properties": {
-                "code_error": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "data": {
-                    "$ref": "#/definitions/authentications.RefreshTokenOut"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
+                "id": {
                     "type": "string"
                 },
-                "success": {
+                "is_active": {
                     "type": "boolean"
-                }
-            }
-        },
-        "utils.HttpResponse-users_UserOut": {
-            "type": "object",
-            "properties": {
-                "code_error": {
-                    "type": "integer"
                 },
-                "data": {
-                    "$ref": "#/definitions/users.UserOut"
-                },
-                "message": {
+                "updated_at": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "url": {
+                    "type": "string"
                 }
             }
         }
@@ -716,7 +6487,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "CMagic Auth",
-	Description:      "Service d'authentification.",
+	Description:      "Service authentication.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
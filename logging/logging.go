@@ -0,0 +1,66 @@
+// Package logging fournit le logger structuré du service. Les journaux sont écrits en JSON et les
+// champs sensibles (mots de passe, codes, tokens, secrets) sont masqués avant l'écriture.
+package logging
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	slog "github.com/sagikazarmark/slog-shim"
+)
+
+type contextKey struct{}
+
+// New crée un logger JSON ou texte, au niveau fourni (debug, info, warn ou error)
+func New(w io.Writer, format string, level string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level:       parseLevel(level),
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	if format == "text" {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+	return slog.New(handler)
+}
+
+// Setup crée le logger du service sur la sortie standard et l'installe comme logger par défaut.
+// Les messages écrits avec le package log passent alors par ce logger.
+func Setup(format string, level string) *slog.Logger {
+	logger := New(os.Stdout, format, level)
+	slog.SetDefault(logger)
+	return logger
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// NewContext retourne un contexte portant le logger fourni
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext retourne le logger porté par le contexte, enrichi des attributs de la requête
+// (request_id, trace_id), à défaut le logger par défaut
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+
+	slog "github.com/sagikazarmark/slog-shim"
+)
+
+// Redacted remplace la valeur d'un champ sensible
+const Redacted = "[REDACTED]"
+
+// sensitiveWords sont les mots qui, présents dans le nom d'un champ, rendent sa valeur secrète
+var sensitiveWords = map[string]bool{
+	"password":      true,
+	"passwd":        true,
+	"secret":        true,
+	"token":         true,
+	"code":          true,
+	"otp":           true,
+	"hash":          true,
+	"authorization": true,
+	"cookie":        true,
+}
+
+// publicFields sont des champs contenant un mot sensible mais dont la valeur ne l'est pas
+var publicFields = map[string]bool{
+	"status_code":      true,
+	"last_status_code": true,
+	"code_error":       true,
+	"error_code":       true,
+	"use_otp":          true,
+}
+
+// IsSensitive indique si la valeur d'un champ doit être masquée. Le nom est découpé en mots
+// (snake_case, kebab-case, camelCase) : "refresh_token", "RefreshTokenHash" ou "codeOtp" sont masqués.
+func IsSensitive(key string) bool {
+	words := splitWords(key)
+	if publicFields[strings.Join(words, "_")] {
+		return false
+	}
+	for i := 0; i < len(words); i++ {
+		if sensitiveWords[words[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+func splitWords(key string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// redactAttr masque les attributs sensibles. Les structures, maps et listes sont parcourues
+// à travers leur forme JSON pour masquer aussi leurs champs sensibles.
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	if attr.Value.Kind() != slog.KindAny {
+		return attr
+	}
+
+	value := attr.Value.Any()
+	if err, ok := value.(error); ok {
+		return slog.String(attr.Key, err.Error())
+	}
+	if !isComposite(value) {
+		return attr
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return slog.String(attr.Key, Redacted)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		return slog.String(attr.Key, Redacted)
+	}
+	return slog.Any(attr.Key, redactValue(decoded))
+}
+
+func isComposite(value interface{}) bool {
+	kind := reflect.ValueOf(value).Kind()
+	if kind == reflect.Pointer {
+		kind = reflect.ValueOf(value).Elem().Kind()
+	}
+	switch kind {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			if IsSensitive(key) {
+				typed[key] = Redacted
+			} else {
+				typed[key] = redactValue(item)
+			}
+		}
+		return typed
+	case []interface{}:
+		for i := 0; i < len(typed); i++ {
+			typed[i] = redactValue(typed[i])
+		}
+		return typed
+	}
+	return value
+}
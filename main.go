@@ -3,9 +3,21 @@ package main
 import (
 	"auth/api"
 	"auth/config"
+	"auth/logging"
+	"auth/metrics"
+	"auth/middlewares"
+	"auth/migrations"
+	"auth/tracing"
+	"context"
+	"errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/pflag"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"net/http"
+	"os"
 )
 
 // @title           CMagic Auth
@@ -28,34 +40,112 @@ import (
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
+	// os.Exit ne lance pas les defer : le processus ne quitte qu'une fois run terminé
+	if err := run(os.Args[1:]); err != nil {
+		os.Exit(1)
+	}
+}
 
-	server := echo.New()
+// run démarre le service et retourne l'erreur qui l'a arrêté, déjà signalée dans les logs ou sur la
+// sortie d'erreur
+func run(args []string) error {
 
-	server.Use(middleware.Recover())
-	server.Use(middleware.Logger())
-	server.Use(middleware.CORS())
+	appConfig, err := config.LoadAppConfig(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	logger := logging.Setup(appConfig.Logging.Format, appConfig.Logging.Level)
+	logger.Info("Configuration loaded", "file", appConfig.File)
+	if !appConfig.Smtp.Enabled() {
+		logger.Warn("No SMTP server configured, notifications are only written to the logs")
+	}
 
-	dbConfig, err := config.LoadDBonfig()
+	shutdownTracing, err := tracing.Setup(appConfig.Tracing)
 	if err != nil {
-		panic("File not found")
+		logger.Error("Invalid tracing configuration", "error", err)
+		return err
 	}
+	defer shutdownTracing(context.Background())
 
-	db, err := config.GetDB(dbConfig)
-	if err == nil {
-		err = config.CreateUpdateTable(db)
-		if err != nil {
-			return
+	// Sans base de données ni schéma à jour, le service s'arrête : l'orchestrateur le redémarre
+	// au lieu de garder un serveur sans routes
+	db, err := config.GetDB(appConfig.Db)
+	if err != nil {
+		logger.Error("Database unavailable", "provider", appConfig.Db.DbProvider, "error", err)
+		return err
+	}
+	if err = db.Use(tracing.NewGormPlugin(appConfig.Db.DbProvider)); err != nil {
+		logger.Error("Failed to install tracing plugin", "error", err)
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		_ = metrics.RegisterDBStats(sqlDB, appConfig.Db.DbName)
+	}
+
+	migrator := migrations.NewMigrator(db, appConfig.Db.DbProvider)
+	if len(appConfig.Args) > 0 {
+		if err = runCommand(migrator, appConfig.Args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
 		}
-		err = config.InitSystem(db)
+		return err
+	}
+
+	// Le verrou des migrations permet à plusieurs réplicas de démarrer en même temps. Sans application
+	// automatique, les migrations sont lancées avant le déploiement par la commande migrate up.
+	if appConfig.Db.AutoMigrate {
+		applied, err := migrator.Up()
 		if err != nil {
-			return
+			logger.Error("Database migration failed", "error", err)
+			return err
+		}
+		for _, migration := range applied {
+			logger.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+		}
+	} else {
+		pending, err := migrator.Pending()
+		if err != nil || len(pending) > 0 {
+			logger.Error("Database schema is not up to date", "pending", len(pending), "error", err)
+			if err == nil {
+				err = fmt.Errorf("%d migration(s) en attente", len(pending))
+			}
+			return err
 		}
-		api.GlobalSetup(server, db)
 	}
 
-	server.GET("/swagger/*", echoSwagger.WrapHandler)
-	err = server.Start(":8000")
+	err = config.InitSystem(db, appConfig.Admin, appConfig.Auth.Security(), logger)
 	if err != nil {
-		return
+		logger.Error("System initialization failed", "error", err)
+		return err
+	}
+
+	guard := middlewares.NewGuard(appConfig.Auth.Security())
+
+	server := echo.New()
+
+	server.Use(middlewares.MetricsMiddle)
+	server.Use(middleware.Recover())
+	server.Use(middleware.RequestID())
+	server.Use(middlewares.TracingMiddle)
+	server.Use(middlewares.LoggerMiddle(logger))
+	server.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     appConfig.Server.CorsOrigins(),
+		AllowCredentials: appConfig.Server.AllowCredentials,
+	}))
+	server.Use(guard.ImpersonationAuditMiddle)
+
+	api.GlobalSetup(server, db, appConfig, guard)
+	api.StartBackgroundJobs(db, appConfig)
+
+	server.GET("/swagger/*", echoSwagger.WrapHandler)
+	server.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+	err = server.Start(appConfig.Server.Address)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Server stopped", "error", err)
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,109 @@
+// Package metrics déclare les métriques Prometheus du service, exposées sur /metrics
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Nombre de requêtes HTTP traitées, par route et statut.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Durée de traitement des requêtes HTTP, par route et statut.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Tentatives de connexion, par méthode et résultat.",
+	}, []string{"method", "outcome"})
+
+	otpVerifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_otp_verifications_total",
+		Help: "Vérifications de codes de double authentification, par résultat.",
+	}, []string{"outcome"})
+
+	tokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_tokens_issued_total",
+		Help: "Tokens émis, par type.",
+	}, []string{"type"})
+
+	tokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_token_refreshes_total",
+		Help: "Renouvellements de tokens, par résultat.",
+	}, []string{"outcome"})
+
+	lockouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "auth_account_lockouts_total",
+		Help: "Comptes verrouillés après trop d'échecs de connexion.",
+	})
+
+	passwordHashing = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "auth_password_hash_duration_seconds",
+		Help:    "Durée des calculs bcrypt, par opération.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8},
+	}, []string{"operation"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		httpRequests,
+		httpDuration,
+		logins,
+		otpVerifications,
+		tokensIssued,
+		tokenRefreshes,
+		lockouts,
+		passwordHashing,
+	)
+}
+
+// ObserveRequest compte une requête HTTP et sa durée. route est le chemin déclaré
+// (par exemple /api/v1/users/:id) pour borner le nombre de séries.
+func ObserveRequest(method string, route string, status string, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, status).Inc()
+	httpDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// ObserveLogin compte une tentative de connexion
+func ObserveLogin(method string, outcome string) {
+	logins.WithLabelValues(method, outcome).Inc()
+}
+
+// ObserveOtpVerification compte une vérification de code de double authentification
+func ObserveOtpVerification(outcome string) {
+	otpVerifications.WithLabelValues(outcome).Inc()
+}
+
+// ObserveTokenIssued compte un token émis
+func ObserveTokenIssued(tokenType string) {
+	tokensIssued.WithLabelValues(tokenType).Inc()
+}
+
+// ObserveTokenRefresh compte un renouvellement de tokens
+func ObserveTokenRefresh(outcome string) {
+	tokenRefreshes.WithLabelValues(outcome).Inc()
+}
+
+// ObserveLockout compte un verrouillage de compte
+func ObserveLockout() {
+	lockouts.Inc()
+}
+
+// ObservePasswordHashing mesure un calcul bcrypt commencé à start
+func ObservePasswordHashing(operation string, start time.Time) {
+	passwordHashing.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// RegisterDBStats expose les statistiques du pool de connexions de la base
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, dbName))
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"auth/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuditRecorder ajoute un évènement de sécurité au journal d'audit
+type AuditRecorder func(ctx context.Context, event model.AuditEvent)
+
+// RegisterAuditRecorder ajoute un enregistreur appelé par AuditMiddle
+func (g *Guard) RegisterAuditRecorder(recorder AuditRecorder) {
+	g.auditRecorders = append(g.auditRecorders, recorder)
+}
+
+// AuditMiddle trace l'action de la route dans le journal d'audit, réussie ou non. La cible est celle
+// désignée par le handler (utils.SetAuditTarget), à défaut le paramètre de chemin targetParam ou l'auteur.
+// Doit être placé avant le middleware d'authentification pour tracer aussi les accès refusés.
+func (g *Guard) AuditMiddle(action string, targetParam string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			err := next(ctx)
+
+			status := ctx.Response().Status
+			var httpError *echo.HTTPError
+			if errors.As(err, &httpError) {
+				status = httpError.Code
+			}
+
+			event := model.AuditEvent{
+				Action:        action,
+				Outcome:       model.AuditOutcomeSuccess,
+				TargetType:    strings.SplitN(action, ".", 2)[0],
+				IpAddress:     ctx.RealIP(),
+				UserAgent:     ctx.Request().UserAgent(),
+				CorrelationId: ctx.Response().Header().Get(echo.HeaderXRequestID),
+			}
+			if status >= http.StatusBadRequest {
+				event.Outcome = model.AuditOutcomeFailure
+			}
+
+			if claims, ok := ctx.Get("claims").(*model.Claims); ok {
+				event.ActorId = claims.Id
+				event.ActorEmail = claims.Subject
+				event.OrganizationId = claims.OrgId
+				if claims.Act != nil {
+					event.ImpersonatorId = claims.Act.Subject
+				}
+			}
+
+			if target, ok := ctx.Get("auditTarget").(string); ok {
+				event.TargetId = target
+			} else if targetParam != "" {
+				event.TargetId = ctx.Param(targetParam)
+			} else {
+				// Sans cible désignée, l'action porte sur l'auteur lui-même
+				event.TargetId = event.ActorId
+			}
+
+			params := map[string]string{}
+			for _, name := range ctx.ParamNames() {
+				params[name] = ctx.Param(name)
+			}
+			details, _ := json.Marshal(map[string]interface{}{
+				"method": ctx.Request().Method,
+				"path":   ctx.Request().URL.Path,
+				"status": status,
+				"params": params,
+			})
+			event.Details = string(details)
+
+			for i := 0; i < len(g.auditRecorders); i++ {
+				g.auditRecorders[i](ctx.Request().Context(), event)
+			}
+			return err
+		}
+	}
+}
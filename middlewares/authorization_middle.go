@@ -1,25 +1,48 @@
 package middlewares
 
 import (
-	"auth/config"
 	"auth/utils"
+	"errors"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 	"net/http"
 )
 
-func getDBInstance() *gorm.DB {
-	dbConfig, err := config.LoadDBonfig()
-	if err != nil {
-		panic("File not found")
-	}
+// Guard porte l'état des middlewares d'authentification : la clé de vérification des tokens
+// et les vérifications et enregistreurs ajoutés par les modules à leur installation
+type Guard struct {
+	security        utils.Security
+	tokenCheckers   []TokenChecker
+	actionRecorders []ActionRecorder
+	auditRecorders  []AuditRecorder
+}
 
-	db, err := config.GetDB(dbConfig)
+// NewGuard crée une nouvelle instance de Guard
+func NewGuard(security utils.Security) *Guard {
+	return &Guard{security: security}
+}
 
-	return db
+// codedError est une erreur portant un code exploitable par les clients, par exemple "account_disabled"
+type codedError interface {
+	ErrorCode() string
 }
 
-func IsAuthorizedMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+// rejectToken répond 401 lorsqu'une vérification de token échoue, avec le code d'erreur s'il existe
+func rejectToken(ctx echo.Context, err error) error {
+	var data interface{}
+	var coded codedError
+	if errors.As(err, &coded) {
+		data = map[string]interface{}{"code": coded.ErrorCode()}
+	}
+
+	return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+		"message":    err.Error(),
+		"success":    false,
+		"code_error": http.StatusUnauthorized,
+		"data":       data,
+	})
+}
+
+func (g *Guard) IsAuthorizedMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		token := ctx.Request().Header.Get("Authorization")
 		extractToken := utils.ExtractToken(token)
@@ -32,8 +55,8 @@ func IsAuthorizedMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
-		claims, err := utils.ParseToken(extractToken)
-		if err != nil || claims.Source == "refresh_token" {
+		claims, err := g.security.ParseToken(extractToken)
+		if err != nil || claims.Source != "access_token" {
 			return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
 				"message":    "Access token has not valid",
 				"success":    false,
@@ -42,13 +65,19 @@ func IsAuthorizedMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		if err := g.checkToken(ctx.Request().Context(), claims); err != nil {
+			return rejectToken(ctx, err)
+		}
+
 		ctx.Set("userId", claims.Id)
+		ctx.Set("orgId", claims.OrgId)
+		ctx.Set("claims", claims)
 
 		return next(ctx)
 	}
 }
 
-func IsRefreshTokenMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+func (g *Guard) IsRefreshTokenMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		token := ctx.Request().Header.Get("Authorization")
 		extractToken := utils.ExtractToken(token)
@@ -61,8 +90,8 @@ func IsRefreshTokenMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
-		claims, err := utils.ParseToken(extractToken)
-		if err != nil || claims.Source == "access_token" {
+		claims, err := g.security.ParseToken(extractToken)
+		if err != nil || claims.Source != "refresh_token" {
 			return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
 				"message":    "Refresh token has not valid",
 				"success":    false,
@@ -71,13 +100,19 @@ func IsRefreshTokenMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		if err := g.checkToken(ctx.Request().Context(), claims); err != nil {
+			return rejectToken(ctx, err)
+		}
+
 		ctx.Set("userId", claims.Id)
+		ctx.Set("orgId", claims.OrgId)
+		ctx.Set("claims", claims)
 
 		return next(ctx)
 	}
 }
 
-func IsAdminMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+func (g *Guard) IsAdminMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		token := ctx.Request().Header.Get("Authorization")
 		extractToken := utils.ExtractToken(token)
@@ -90,8 +125,8 @@ func IsAdminMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
-		claims, err := utils.ParseToken(extractToken)
-		if err != nil || claims.Source == "refresh_token" {
+		claims, err := g.security.ParseToken(extractToken)
+		if err != nil || claims.Source != "access_token" {
 			return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
 				"message":    "Token non valid",
 				"success":    false,
@@ -109,13 +144,19 @@ func IsAdminMiddle(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		if err := g.checkToken(ctx.Request().Context(), claims); err != nil {
+			return rejectToken(ctx, err)
+		}
+
 		ctx.Set("userId", claims.Id)
+		ctx.Set("orgId", claims.OrgId)
+		ctx.Set("claims", claims)
 
 		return next(ctx)
 	}
 }
 
-func GetPermission(next echo.HandlerFunc) echo.HandlerFunc {
+func (g *Guard) GetPermission(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		token := ctx.Request().Header.Get("Authorization")
 		extractToken := utils.ExtractToken(token)
@@ -128,8 +169,18 @@ func GetPermission(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
-		claims, _ := utils.ParseToken(extractToken)
-		roles := utils.LoadPermissionByRoleName(claims.Role)
+		claims, err := g.security.ParseToken(extractToken)
+		if err != nil {
+			return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"message":    "Token non valid",
+				"success":    false,
+				"code_error": http.StatusUnauthorized,
+				"data":       nil,
+			})
+		}
+
+		// Permissions du rôle système et des rôles dans l'organisation active
+		roles := utils.LoadPermissionByRoleNames(append([]string{claims.Role}, claims.Roles...)...)
 
 		ctx.Set("roles", roles)
 
@@ -0,0 +1,77 @@
+package middlewares
+
+import (
+	"auth/model"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TokenChecker valide un token signé au regard de l'état conservé côté serveur
+type TokenChecker func(ctx context.Context, claims *model.Claims) error
+
+// ActionRecorder enregistre une requête effectuée sous un token d'impersonation
+type ActionRecorder func(ctx context.Context, claims *model.Claims, method string, path string, status int, ip string)
+
+// RegisterTokenChecker ajoute une vérification exécutée par les middlewares d'authentification
+func (g *Guard) RegisterTokenChecker(checker TokenChecker) {
+	g.tokenCheckers = append(g.tokenCheckers, checker)
+}
+
+// RegisterActionRecorder ajoute un enregistreur appelé après chaque requête impersonnée
+func (g *Guard) RegisterActionRecorder(recorder ActionRecorder) {
+	g.actionRecorders = append(g.actionRecorders, recorder)
+}
+
+func (g *Guard) checkToken(ctx context.Context, claims *model.Claims) error {
+	for i := 0; i < len(g.tokenCheckers); i++ {
+		if err := g.tokenCheckers[i](ctx, claims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImpersonationAuditMiddle attribue chaque requête faite sous un token d'impersonation
+// à l'administrateur et à l'utilisateur ciblé
+func (g *Guard) ImpersonationAuditMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		err := next(ctx)
+
+		claims, ok := ctx.Get("claims").(*model.Claims)
+		if !ok || claims.Act == nil {
+			return err
+		}
+
+		status := ctx.Response().Status
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			status = httpError.Code
+		}
+
+		for i := 0; i < len(g.actionRecorders); i++ {
+			g.actionRecorders[i](ctx.Request().Context(), claims, ctx.Request().Method, ctx.Request().URL.Path, status, ctx.RealIP())
+		}
+		return err
+	}
+}
+
+// DenyImpersonationMiddle refuse la route aux tokens d'impersonation.
+// Doit être placé après un middleware d'authentification.
+func DenyImpersonationMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		claims, ok := ctx.Get("claims").(*model.Claims)
+		if ok && claims.Act != nil {
+			return ctx.JSON(http.StatusForbidden, map[string]interface{}{
+				"message":    "Cette opération est interdite pendant une impersonation",
+				"success":    false,
+				"code_error": http.StatusForbidden,
+				"data":       nil,
+			})
+		}
+
+		return next(ctx)
+	}
+}
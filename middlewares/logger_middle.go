@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"auth/logging"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	slog "github.com/sagikazarmark/slog-shim"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoggerMiddle rattache à la requête un logger portant son request_id et son trace_id, repris par
+// les services et repositories (logging.FromContext), puis journalise la requête terminée.
+// Seul le chemin est journalisé : la query string peut contenir un token.
+// Doit être placé après les middlewares RequestID et TracingMiddle.
+func LoggerMiddle(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			request := ctx.Request()
+
+			requestLogger := logger.With("request_id", ctx.Response().Header().Get(echo.HeaderXRequestID))
+			if spanContext := trace.SpanContextFromContext(request.Context()); spanContext.IsValid() {
+				requestLogger = requestLogger.With("trace_id", spanContext.TraceID().String())
+			}
+			ctx.SetRequest(request.WithContext(logging.NewContext(request.Context(), requestLogger)))
+
+			err := next(ctx)
+
+			status := ctx.Response().Status
+			var httpError *echo.HTTPError
+			if errors.As(err, &httpError) {
+				status = httpError.Code
+			} else if err != nil {
+				status = http.StatusInternalServerError
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			attrs := []interface{}{
+				"method", request.Method,
+				"route", ctx.Path(),
+				"path", request.URL.Path,
+				"status", status,
+				"latency_ms", time.Since(start).Milliseconds(),
+				"ip", ctx.RealIP(),
+				"user_agent", request.UserAgent(),
+			}
+			if err != nil {
+				attrs = append(attrs, "error", err)
+			}
+			requestLogger.Log(request.Context(), level, "request", attrs...)
+			return err
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"auth/metrics"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsMiddle compte chaque requête et sa durée par route déclarée et statut. Les requêtes
+// sans route correspondante sont regroupées pour ne pas créer une série par chemin inconnu.
+func MetricsMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		start := time.Now()
+		err := next(ctx)
+
+		status := ctx.Response().Status
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			status = httpError.Code
+		} else if err != nil {
+			status = http.StatusInternalServerError
+		}
+
+		route := ctx.Path()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.ObserveRequest(ctx.Request().Method, route, strconv.Itoa(status), time.Since(start))
+		return err
+	}
+}
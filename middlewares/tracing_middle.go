@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"auth/tracing"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddle ouvre un span serveur par requête, enfant du contexte reçu dans l'en-tête
+// traceparent, et le transmet aux couches suivantes par le contexte de la requête.
+// L'identifiant de trace est renvoyé au client dans l'en-tête traceparent.
+func TracingMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		request := ctx.Request()
+		propagator := otel.GetTextMapPropagator()
+		parent := propagator.Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+
+		route := ctx.Path()
+		if route == "" {
+			route = "unmatched"
+		}
+		spanCtx, span := tracing.Tracer().Start(parent, request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethodKey.String(request.Method),
+				semconv.HTTPRouteKey.String(route),
+				semconv.HTTPTargetKey.String(request.URL.Path),
+				semconv.NetSockPeerAddrKey.String(ctx.RealIP()),
+				semconv.HTTPUserAgentKey.String(request.UserAgent()),
+			))
+		defer span.End()
+
+		ctx.SetRequest(request.WithContext(spanCtx))
+		propagator.Inject(spanCtx, propagation.HeaderCarrier(ctx.Response().Header()))
+
+		err := next(ctx)
+
+		status := ctx.Response().Status
+		var httpError *echo.HTTPError
+		if errors.As(err, &httpError) {
+			status = httpError.Code
+		} else if err != nil {
+			status = http.StatusInternalServerError
+		}
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+		if requestId := ctx.Response().Header().Get(echo.HeaderXRequestID); requestId != "" {
+			span.SetAttributes(attribute.String("http.request_id", requestId))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"auth/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IsTwoFactorEnrollmentMiddle accepte un access token ou le token d'enrôlement émis à la connexion
+// lorsqu'un rôle impose la double authentification à un utilisateur sans second facteur
+func (g *Guard) IsTwoFactorEnrollmentMiddle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		token := ctx.Request().Header.Get("Authorization")
+		extractToken := utils.ExtractToken(token)
+		if len(extractToken) == 0 {
+			return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"message":    "Vous n'etes pas autorisé à executer cette route",
+				"success":    false,
+				"code_error": http.StatusUnauthorized,
+				"data":       nil,
+			})
+		}
+
+		claims, err := g.security.ParseToken(extractToken)
+		if err != nil || (claims.Source != "access_token" && claims.Source != "two_factor_enrollment") {
+			return ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"message":    "Access token has not valid",
+				"success":    false,
+				"code_error": http.StatusUnauthorized,
+				"data":       nil,
+			})
+		}
+
+		if err := g.checkToken(ctx.Request().Context(), claims); err != nil {
+			return rejectToken(ctx, err)
+		}
+
+		ctx.Set("userId", claims.Id)
+		ctx.Set("orgId", claims.OrgId)
+		ctx.Set("claims", claims)
+
+		return next(ctx)
+	}
+}
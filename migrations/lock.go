@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// advisoryLockKey identifie le verrou des migrations parmi les verrous consultatifs de PostgreSQL
+	advisoryLockKey = 4_215_309_117
+
+	// lockName et lockTimeout désignent le verrou nommé de MySQL et l'attente maximale, en secondes
+	lockName    = "auth_schema_migrations"
+	lockTimeout = 300
+)
+
+// errLockTimeout signale qu'une autre instance applique les migrations depuis trop longtemps
+var errLockTimeout = errors.New("verrou des migrations non obtenu : une autre instance les applique")
+
+// lock prend le verrou des migrations sur la connexion et retourne la fonction qui le libère.
+// Le verrou est attaché à la session : conn doit rester la même connexion jusqu'à la libération.
+// SQLite n'a pas de verrou consultatif ; sa base n'est pas partagée entre plusieurs instances.
+func lock(conn *gorm.DB, provider string) (unlock func(), err error) {
+	switch provider {
+	case "pg":
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", advisoryLockKey).Error; err != nil {
+			return nil, err
+		}
+		return func() {
+			conn.Exec("SELECT pg_advisory_unlock(?)", advisoryLockKey)
+		}, nil
+	case "mysql":
+		var acquired *int
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&acquired).Error; err != nil {
+			return nil, err
+		}
+		if acquired == nil || *acquired != 1 {
+			return nil, errLockTimeout
+		}
+		return func() {
+			conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		}, nil
+	}
+	return func() {}, nil
+}
@@ -0,0 +1,146 @@
+// Package migrations applique les migrations SQL versionnées du schéma. Chaque fournisseur de base
+// (pg, mysql, sqlite) a ses fichiers sql/<fournisseur>/<version>_<nom>.up.sql et .down.sql ; une
+// instruction se termine par un point-virgule en fin de ligne. Les versions appliquées sont
+// enregistrées dans la table schema_migrations.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed sql
+var files embed.FS
+
+// Migration est une évolution du schéma et son retour arrière
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load retourne les migrations du fournisseur, par version croissante
+func Load(provider string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, path.Join("sql", provider))
+	if err != nil {
+		return nil, fmt.Errorf("aucune migration pour le fournisseur %q", provider)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		var direction string
+		base := entry.Name()
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction, base = "up", strings.TrimSuffix(base, ".up.sql")
+		case strings.HasSuffix(base, ".down.sql"):
+			direction, base = "down", strings.TrimSuffix(base, ".down.sql")
+		default:
+			continue
+		}
+
+		parts := strings.SplitN(base, "_", 2)
+		version, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("nom de migration invalide : %s", entry.Name())
+		}
+
+		content, err := files.ReadFile(path.Join("sql", provider, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = migration
+		}
+		if migration.Name != parts[1] {
+			return nil, fmt.Errorf("la version %d porte deux noms : %s et %s", version, migration.Name, parts[1])
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("la migration %d n'a pas de fichier .up.sql", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// statements découpe un script en instructions : chacune se termine par un point-virgule en fin de
+// ligne, les lignes de commentaire sont ignorées
+func statements(script string) []string {
+	var result []string
+	var current []string
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			result = append(result, strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";"))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+	return result
+}
+
+// createdTable est une table créée par un script et ses colonnes
+type createdTable struct {
+	Name    string
+	Columns []string
+}
+
+// createdTables retourne les tables créées par les instructions CREATE TABLE du script. Chaque
+// colonne est déclarée sur sa propre ligne, son nom entre guillemets ou accents graves.
+func createdTables(script string) []createdTable {
+	var tables []createdTable
+	for _, statement := range statements(script) {
+		lines := strings.Split(statement, "\n")
+		if !strings.HasPrefix(lines[0], "CREATE TABLE ") {
+			continue
+		}
+
+		table := createdTable{Name: quotedName(strings.TrimPrefix(lines[0], "CREATE TABLE "))}
+		for _, line := range lines[1:] {
+			if column := quotedName(strings.TrimSpace(line)); column != "" {
+				table.Columns = append(table.Columns, column)
+			}
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// quotedName retourne l'identifiant entre guillemets ou accents graves qui ouvre s, vide sinon
+func quotedName(s string) string {
+	if s == "" || (s[0] != '"' && s[0] != '`') {
+		return ""
+	}
+	end := strings.IndexByte(s[1:], s[0])
+	if end < 0 {
+		return ""
+	}
+	return s[1 : end+1]
+}
@@ -0,0 +1,283 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// baselineTable est une table du schéma initial : sa présence sans historique de migrations signale
+// une base créée auparavant par AutoMigrate
+const baselineTable = "users"
+
+// appliedMigration est une ligne de la table schema_migrations
+type appliedMigration struct {
+	Version   int64 `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (appliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus indique si une migration a été appliquée, et quand
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applique et annule les migrations d'une base
+type Migrator struct {
+	db       *gorm.DB
+	provider string
+}
+
+// NewMigrator crée une nouvelle instance de Migrator pour la base du fournisseur provider (pg, mysql ou sqlite)
+func NewMigrator(db *gorm.DB, provider string) *Migrator {
+	return &Migrator{
+		db:       db,
+		provider: provider,
+	}
+}
+
+// WithContext retourne une copie du migrator dont les requêtes portent le contexte fourni
+func (m *Migrator) WithContext(ctx context.Context) *Migrator {
+	return &Migrator{
+		db:       m.db.WithContext(ctx),
+		provider: m.provider,
+	}
+}
+
+// Up applique les migrations en attente et retourne celles qui ont été appliquées. Une base créée
+// par AutoMigrate est d'abord rattachée à l'historique : le schéma initial y est marqué appliqué
+// s'il y est complet, sinon Up échoue sans rien modifier.
+func (m *Migrator) Up() (applied []Migration, err error) {
+	migrations, err := Load(m.provider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.withLock(func(conn *gorm.DB) error {
+		if err := m.baseline(conn, migrations); err != nil {
+			return err
+		}
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.run(conn, migration.Up, func(tx *gorm.DB) error {
+				return tx.Create(&appliedMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now().UTC(),
+				}).Error
+			}); err != nil {
+				return fmt.Errorf("migration %d_%s : %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down annule les steps dernières migrations appliquées, de la plus récente à la plus ancienne
+func (m *Migrator) Down(steps int) (reverted []Migration, err error) {
+	migrations, err := Load(m.provider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.withLock(func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("la migration %d_%s est irréversible : aucun fichier .down.sql", migration.Version, migration.Name)
+			}
+			if err := m.run(conn, migration.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&appliedMigration{}, "version = ?", migration.Version).Error
+			}); err != nil {
+				return fmt.Errorf("retour arrière de %d_%s : %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status retourne l'état de chaque migration connue
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	migrations, err := Load(m.provider)
+	if err != nil {
+		return nil, err
+	}
+	// Sans table d'historique, aucune migration n'a été appliquée
+	done := map[int64]appliedMigration{}
+	if m.db.Migrator().HasTable(&appliedMigration{}) {
+		if done, err = appliedVersions(m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if row, ok := done[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending retourne les migrations qui restent à appliquer
+func (m *Migrator) Pending() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// withLock exécute fn sur une connexion dédiée qui détient le verrou des migrations, pour que
+// des réplicas démarrés en même temps ne les appliquent pas deux fois
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		unlock, err := lock(conn, m.provider)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := ensureTable(conn, m.provider); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// run exécute le script et met à jour l'historique dans une même transaction. MySQL valide
+// implicitement chaque instruction DDL : une migration interrompue doit y être corrigée à la main.
+func (m *Migrator) run(conn *gorm.DB, script string, record func(tx *gorm.DB) error) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+// baseline marque le schéma initial comme appliqué sur une base créée par AutoMigrate. La base
+// doit déjà contenir chaque table et colonne créée par ce schéma : une base plus ancienne serait
+// sinon déclarée à jour alors qu'il lui manque des tables, elle est donc refusée.
+func (m *Migrator) baseline(conn *gorm.DB, migrations []Migration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := conn.Model(&appliedMigration{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 || !conn.Migrator().HasTable(baselineTable) {
+		return nil
+	}
+
+	initial := migrations[0]
+	missing, err := missingSchema(conn, initial)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("la base existante ne contient pas tout le schéma %d_%s, elle ne peut pas y être rattachée "+
+			"(manquant : %s) ; mettez-la d'abord à jour avec la dernière version du service antérieure aux migrations versionnées",
+			initial.Version, initial.Name, strings.Join(missing, ", "))
+	}
+
+	return conn.Create(&appliedMigration{
+		Version:   initial.Version,
+		Name:      initial.Name,
+		AppliedAt: time.Now().UTC(),
+	}).Error
+}
+
+// missingSchema liste les tables et les colonnes (table.colonne) créées par la migration
+// qui sont absentes de la base
+func missingSchema(conn *gorm.DB, migration Migration) ([]string, error) {
+	var missing []string
+	for _, table := range createdTables(migration.Up) {
+		if !conn.Migrator().HasTable(table.Name) {
+			missing = append(missing, table.Name)
+			continue
+		}
+
+		columnTypes, err := conn.Migrator().ColumnTypes(table.Name)
+		if err != nil {
+			return nil, err
+		}
+		existing := make(map[string]bool, len(columnTypes))
+		for _, columnType := range columnTypes {
+			existing[columnType.Name()] = true
+		}
+		for _, column := range table.Columns {
+			if !existing[column] {
+				missing = append(missing, table.Name+"."+column)
+			}
+		}
+	}
+	return missing, nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int64]appliedMigration, error) {
+	var rows []appliedMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	done := make(map[int64]appliedMigration, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row
+	}
+	return done, nil
+}
+
+func ensureTable(db *gorm.DB, provider string) error {
+	statement, ok := map[string]string{
+		"pg":     "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint PRIMARY KEY, name varchar(255) NOT NULL, applied_at timestamptz NOT NULL)",
+		"mysql":  "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint PRIMARY KEY, name varchar(255) NOT NULL, applied_at datetime(3) NOT NULL)",
+		"sqlite": "CREATE TABLE IF NOT EXISTS schema_migrations (version integer PRIMARY KEY, name text NOT NULL, applied_at datetime NOT NULL)",
+	}[provider]
+	if !ok {
+		return fmt.Errorf("fournisseur de base de données inconnu : %q", provider)
+	}
+	return db.Exec(statement).Error
+}
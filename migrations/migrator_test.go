@@ -0,0 +1,185 @@
+package migrations
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// preSeriesSchema reproduit les tables créées par AutoMigrate avant les migrations versionnées :
+// seuls les rôles, utilisateurs, OTP et permissions existaient
+const preSeriesSchema = `
+CREATE TABLE roles (id text, created_at datetime, updated_at datetime, deleted_at datetime, is_available numeric DEFAULT true, is_visible numeric DEFAULT true, name text, describe text, PRIMARY KEY (id));
+CREATE TABLE users (id text, created_at datetime, updated_at datetime, deleted_at datetime, is_available numeric DEFAULT true, is_visible numeric DEFAULT true, name text, email text UNIQUE, username text UNIQUE, role_id text, sername text, password text, use_otp numeric, PRIMARY KEY (id));
+CREATE TABLE otps (id text, created_at datetime, updated_at datetime, deleted_at datetime, is_available numeric DEFAULT true, is_visible numeric DEFAULT true, code text, is_used numeric, user_id text, expire_has datetime, PRIMARY KEY (id));
+CREATE TABLE permissions (id text, created_at datetime, updated_at datetime, deleted_at datetime, is_available numeric DEFAULT true, is_visible numeric DEFAULT true, name text, describe text, PRIMARY KEY (id));
+CREATE TABLE role_permissions (id text, created_at datetime, updated_at datetime, deleted_at datetime, is_available numeric DEFAULT true, is_visible numeric DEFAULT true, role_id text, permission_id text, describe text, PRIMARY KEY (id));
+INSERT INTO users (id, name, email, username, password) VALUES ('u1', 'Admin', 'admin@example.com', 'admin', 'hash');
+`
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.sqlite")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("ouverture de la base : %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("connexion à la base : %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func execScript(t *testing.T, db *gorm.DB, script string) {
+	t.Helper()
+	for _, statement := range statements(script) {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("exécution de %q : %v", statement, err)
+		}
+	}
+}
+
+func TestUpAppliesInitialSchemaOnEmptyDatabase(t *testing.T) {
+	migrator := NewMigrator(newTestDB(t), "sqlite")
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up : %v", err)
+	}
+	if len(applied) == 0 || applied[0].Version != 1 {
+		t.Fatalf("Up a appliqué %v, la migration 1 est attendue", applied)
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("Pending = %v, %v ; aucune migration en attente attendue", pending, err)
+	}
+}
+
+func TestUpBaselinesCompleteLegacySchema(t *testing.T) {
+	db := newTestDB(t)
+	migrations, err := Load("sqlite")
+	if err != nil {
+		t.Fatalf("Load : %v", err)
+	}
+	// Base créée par AutoMigrate à la dernière version antérieure aux migrations versionnées
+	execScript(t, db, migrations[0].Up)
+
+	applied, err := NewMigrator(db, "sqlite").Up()
+	if err != nil {
+		t.Fatalf("Up : %v", err)
+	}
+	for _, migration := range applied {
+		if migration.Version == migrations[0].Version {
+			t.Fatalf("le schéma initial a été réappliqué au lieu d'être rattaché à l'historique")
+		}
+	}
+
+	var version int64
+	if err := db.Raw("SELECT version FROM schema_migrations ORDER BY version LIMIT 1").Scan(&version).Error; err != nil || version != migrations[0].Version {
+		t.Fatalf("version enregistrée = %d, %v ; %d attendue", version, err, migrations[0].Version)
+	}
+}
+
+func TestUpRefusesPreSeriesSchema(t *testing.T) {
+	db := newTestDB(t)
+	execScript(t, db, preSeriesSchema)
+	migrator := NewMigrator(db, "sqlite")
+
+	_, err := migrator.Up()
+	if err == nil {
+		t.Fatal("Up a rattaché une base incomplète à l'historique")
+	}
+	for _, expected := range []string{"organizations", "users.status", "users.locked_until"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("l'erreur %q ne signale pas %s", err, expected)
+		}
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil || len(pending) == 0 || pending[0].Version != 1 {
+		t.Fatalf("Pending = %v, %v ; la migration 1 doit rester en attente", pending, err)
+	}
+
+	var count int64
+	if err := db.Raw("SELECT count(*) FROM users").Scan(&count).Error; err != nil || count != 1 {
+		t.Fatalf("utilisateurs = %d, %v ; les données existantes doivent être conservées", count, err)
+	}
+}
+
+func TestDownRevertsInitialSchema(t *testing.T) {
+	db := newTestDB(t)
+	migrator := NewMigrator(db, "sqlite")
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up : %v", err)
+	}
+
+	reverted, err := migrator.Down(len(applied))
+	if err != nil || len(reverted) != len(applied) {
+		t.Fatalf("Down = %v, %v ; %d migrations annulées attendues", reverted, err, len(applied))
+	}
+	if db.Migrator().HasTable("users") {
+		t.Fatal("la table users existe encore après le retour arrière")
+	}
+}
+
+func TestPolicyOrganizationAttachesExistingPolicies(t *testing.T) {
+	db := newTestDB(t)
+	migrations, err := Load("sqlite")
+	if err != nil {
+		t.Fatalf("Load : %v", err)
+	}
+	execScript(t, db, migrations[0].Up)
+	execScript(t, db, `
+INSERT INTO organizations (id, name, slug) VALUES ('org-default', 'Default', 'default');
+INSERT INTO policies (id, name, version, document, is_active) VALUES ('p1', 'office-hours', 1, '{}', true);
+`)
+
+	if _, err := NewMigrator(db, "sqlite").Up(); err != nil {
+		t.Fatalf("Up : %v", err)
+	}
+
+	var organizationId string
+	if err := db.Raw("SELECT organization_id FROM policies WHERE id = 'p1'").Scan(&organizationId).Error; err != nil || organizationId != "org-default" {
+		t.Fatalf("organization_id = %q, %v ; org-default attendue", organizationId, err)
+	}
+
+	// Deux organisations peuvent avoir une politique de même nom et de même version
+	err = db.Exec("INSERT INTO policies (id, organization_id, name, version, document, is_active) VALUES ('p2', 'org-other', 'office-hours', 1, '{}', true)").Error
+	if err != nil {
+		t.Fatalf("politique d'une autre organisation refusée : %v", err)
+	}
+	err = db.Exec("INSERT INTO policies (id, organization_id, name, version, document, is_active) VALUES ('p3', 'org-default', 'office-hours', 1, '{}', true)").Error
+	if err == nil {
+		t.Fatal("une version en double dans la même organisation a été acceptée")
+	}
+}
+
+func TestCreatedTablesParsesEveryProvider(t *testing.T) {
+	for _, provider := range []string{"pg", "mysql", "sqlite"} {
+		migrations, err := Load(provider)
+		if err != nil {
+			t.Fatalf("Load(%s) : %v", provider, err)
+		}
+
+		var users *createdTable
+		tables := createdTables(migrations[0].Up)
+		for i := range tables {
+			if tables[i].Name == "users" {
+				users = &tables[i]
+			}
+		}
+		if users == nil {
+			t.Fatalf("%s : la table users n'a pas été trouvée parmi %d tables", provider, len(tables))
+		}
+		if !strings.Contains(strings.Join(users.Columns, ","), "sessions_revoked_at") {
+			t.Errorf("%s : colonnes de users incomplètes : %v", provider, users.Columns)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+-- Schéma initial du service, équivalent aux tables créées auparavant par AutoMigrate
+
+CREATE TABLE `roles` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `name` varchar(80),
+    `describe` varchar(500),
+    `require_two_factor` boolean,
+    PRIMARY KEY (`id`),
+    INDEX `idx_roles_name` (`name`)
+);
+
+CREATE TABLE `users` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `name` varchar(80),
+    `email` varchar(191) UNIQUE,
+    `username` varchar(191) UNIQUE,
+    `role_id` varchar(191),
+    `sername` longtext,
+    `password` longtext,
+    `use_otp` boolean,
+    `status` varchar(30) DEFAULT 'active',
+    `status_reason` longtext,
+    `status_changed_at` datetime(3) NULL,
+    `locked_until` datetime(3) NULL,
+    `failed_login_attempts` bigint,
+    `sessions_revoked_at` datetime(3) NULL,
+    `locale` varchar(20),
+    `avatar_file` varchar(120),
+    `anonymized_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_roles_users` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`)
+);
+
+CREATE TABLE `otps` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `code` longtext,
+    `is_used` boolean,
+    `user_id` varchar(191),
+    `expire_has` datetime(3) NULL,
+    `method_id` varchar(120),
+    `attempts` bigint,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_users_otps` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `permissions` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `name` varchar(80),
+    `describe` varchar(500),
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `role_permissions` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `role_id` varchar(120),
+    `permission_id` varchar(120),
+    `describe` varchar(500),
+    PRIMARY KEY (`id`),
+    INDEX `idx_role_permissions_role_id` (`role_id`),
+    INDEX `idx_role_permissions_permission_id` (`permission_id`),
+    CONSTRAINT `fk_permissions_roles_permissions` FOREIGN KEY (`permission_id`) REFERENCES `permissions`(`id`),
+    CONSTRAINT `fk_roles_roles_permissions` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`)
+);
+
+CREATE TABLE `organizations` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `name` varchar(120),
+    `slug` varchar(120) UNIQUE,
+    `describe` varchar(500),
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `memberships` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `user_id` varchar(120),
+    `organization_id` varchar(120),
+    PRIMARY KEY (`id`),
+    INDEX `idx_memberships_user_id` (`user_id`),
+    INDEX `idx_memberships_organization_id` (`organization_id`),
+    CONSTRAINT `fk_organizations_memberships` FOREIGN KEY (`organization_id`) REFERENCES `organizations`(`id`)
+);
+
+CREATE TABLE `membership_roles` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `membership_id` varchar(120),
+    `role_id` varchar(120),
+    PRIMARY KEY (`id`),
+    INDEX `idx_membership_roles_role_id` (`role_id`),
+    INDEX `idx_membership_roles_membership_id` (`membership_id`),
+    CONSTRAINT `fk_memberships_membership_roles` FOREIGN KEY (`membership_id`) REFERENCES `memberships`(`id`)
+);
+
+CREATE TABLE `groups` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `organization_id` varchar(120),
+    `name` varchar(80),
+    `describe` varchar(500),
+    PRIMARY KEY (`id`),
+    INDEX `idx_groups_organization_id` (`organization_id`)
+);
+
+CREATE TABLE `group_members` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `group_id` varchar(120),
+    `user_id` varchar(120),
+    PRIMARY KEY (`id`),
+    INDEX `idx_group_members_user_id` (`user_id`),
+    INDEX `idx_group_members_group_id` (`group_id`),
+    CONSTRAINT `fk_groups_group_members` FOREIGN KEY (`group_id`) REFERENCES `groups`(`id`)
+);
+
+CREATE TABLE `group_roles` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `group_id` varchar(120),
+    `role_id` varchar(120),
+    PRIMARY KEY (`id`),
+    INDEX `idx_group_roles_group_id` (`group_id`),
+    INDEX `idx_group_roles_role_id` (`role_id`),
+    CONSTRAINT `fk_groups_group_roles` FOREIGN KEY (`group_id`) REFERENCES `groups`(`id`)
+);
+
+CREATE TABLE `policies` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `name` varchar(120),
+    `version` bigint,
+    `describe` varchar(500),
+    `document` text,
+    `is_active` boolean,
+    `created_by` varchar(120),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_policies_name_version` (`name`,`version`),
+    INDEX `idx_policies_is_active` (`is_active`)
+);
+
+CREATE TABLE `impersonations` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `actor_id` varchar(120),
+    `target_user_id` varchar(120),
+    `organization_id` varchar(120),
+    `reason` varchar(500),
+    `expires_at` datetime(3) NULL,
+    `ended_at` datetime(3) NULL,
+    `ended_by` varchar(120),
+    PRIMARY KEY (`id`),
+    INDEX `idx_impersonations_actor_id` (`actor_id`),
+    INDEX `idx_impersonations_target_user_id` (`target_user_id`),
+    INDEX `idx_impersonations_organization_id` (`organization_id`)
+);
+
+CREATE TABLE `impersonation_actions` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `impersonation_id` varchar(120),
+    `actor_id` varchar(120),
+    `target_user_id` varchar(120),
+    `method` varchar(10),
+    `path` varchar(500),
+    `status` bigint,
+    `ip` varchar(64),
+    PRIMARY KEY (`id`),
+    INDEX `idx_impersonation_actions_impersonation_id` (`impersonation_id`),
+    CONSTRAINT `fk_impersonations_actions` FOREIGN KEY (`impersonation_id`) REFERENCES `impersonations`(`id`)
+);
+
+CREATE TABLE `invitations` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `email` varchar(120),
+    `user_id` varchar(120),
+    `organization_id` varchar(120),
+    `invited_by` varchar(120),
+    `token_hash` varchar(64),
+    `expires_at` datetime(3) NULL,
+    `sent_count` bigint,
+    `last_sent_at` datetime(3) NULL,
+    `accepted_at` datetime(3) NULL,
+    `revoked_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_invitations_email` (`email`),
+    INDEX `idx_invitations_user_id` (`user_id`),
+    INDEX `idx_invitations_organization_id` (`organization_id`),
+    UNIQUE INDEX `idx_invitations_token_hash` (`token_hash`)
+);
+
+CREATE TABLE `attribute_definitions` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `organization_id` varchar(120),
+    `name` varchar(50),
+    `label` varchar(120),
+    `type` varchar(20),
+    `required` boolean,
+    `user_editable` boolean,
+    `options` text,
+    `pattern` varchar(255),
+    `max_length` bigint,
+    `claim_name` varchar(50),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_attribute_definitions_org_name` (`organization_id`,`name`)
+);
+
+CREATE TABLE `user_attributes` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `user_id` varchar(120),
+    `definition_id` varchar(120),
+    `organization_id` varchar(120),
+    `value` text,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_user_attributes_user_definition` (`user_id`,`definition_id`),
+    INDEX `idx_user_attributes_definition_id` (`definition_id`),
+    INDEX `idx_user_attributes_organization_id` (`organization_id`)
+);
+
+CREATE TABLE `identity_changes` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `user_id` varchar(120),
+    `field` varchar(20),
+    `new_value` varchar(120),
+    `token_hash` varchar(64),
+    `expires_at` datetime(3) NULL,
+    `confirmed_at` datetime(3) NULL,
+    `cancelled_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_identity_changes_user_id` (`user_id`),
+    UNIQUE INDEX `idx_identity_changes_token_hash` (`token_hash`)
+);
+
+CREATE TABLE `two_factor_methods` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `user_id` varchar(120),
+    `type` varchar(20),
+    `label` varchar(80),
+    `secret` varchar(64),
+    `is_default` boolean,
+    `confirmed_at` datetime(3) NULL,
+    `last_used_at` datetime(3) NULL,
+    `last_used_step` bigint,
+    PRIMARY KEY (`id`),
+    INDEX `idx_two_factor_methods_user_id` (`user_id`)
+);
+
+CREATE TABLE `sessions` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `user_id` varchar(120),
+    `device` varchar(120),
+    `user_agent` varchar(512),
+    `ip_address` varchar(64),
+    `refresh_token_hash` varchar(64),
+    `last_seen_at` datetime(3) NULL,
+    `expires_at` datetime(3) NULL,
+    `revoked_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_sessions_user_id` (`user_id`)
+);
+
+CREATE TABLE `login_events` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `user_id` varchar(120),
+    `method` varchar(20),
+    `device` varchar(120),
+    `device_fingerprint` varchar(64),
+    `user_agent` varchar(512),
+    `ip_address` varchar(64),
+    `ip_range` varchar(64),
+    `country` varchar(120),
+    `city` varchar(120),
+    `new_device` boolean,
+    `new_location` boolean,
+    `report_token_hash` varchar(64),
+    `report_expires_at` datetime(3) NULL,
+    `reported_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_login_events_user_id` (`user_id`),
+    INDEX `idx_login_events_device_fingerprint` (`device_fingerprint`),
+    INDEX `idx_login_events_report_token_hash` (`report_token_hash`)
+);
+
+CREATE TABLE `audit_events` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `sequence` bigint,
+    `occurred_at` datetime(3) NULL,
+    `action` varchar(80),
+    `outcome` varchar(20),
+    `actor_id` varchar(120),
+    `actor_email` varchar(120),
+    `impersonator_id` varchar(120),
+    `organization_id` varchar(120),
+    `target_type` varchar(40),
+    `target_id` varchar(120),
+    `ip_address` varchar(64),
+    `user_agent` varchar(512),
+    `correlation_id` varchar(120),
+    `details` text,
+    `previous_hash` varchar(64),
+    `hash` varchar(64),
+    PRIMARY KEY (`id`),
+    INDEX `idx_audit_events_target_id` (`target_id`),
+    INDEX `idx_audit_events_correlation_id` (`correlation_id`),
+    UNIQUE INDEX `idx_audit_events_sequence` (`sequence`),
+    INDEX `idx_audit_events_occurred_at` (`occurred_at`),
+    INDEX `idx_audit_events_action` (`action`),
+    INDEX `idx_audit_events_actor_id` (`actor_id`),
+    INDEX `idx_audit_events_organization_id` (`organization_id`)
+);
+
+CREATE TABLE `outbox_events` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `event_type` varchar(80),
+    `organization_id` varchar(120),
+    `subject_id` varchar(120),
+    `payload` text,
+    `occurred_at` datetime(3) NULL,
+    `dispatched_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_outbox_events_event_type` (`event_type`),
+    INDEX `idx_outbox_events_subject_id` (`subject_id`),
+    INDEX `idx_outbox_events_dispatched_at` (`dispatched_at`)
+);
+
+CREATE TABLE `webhook_subscriptions` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `organization_id` varchar(120),
+    `url` varchar(500),
+    `description` varchar(255),
+    `events` varchar(500),
+    `secret` varchar(120),
+    `created_by` varchar(120),
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_subscriptions_organization_id` (`organization_id`)
+);
+
+CREATE TABLE `webhook_deliveries` (
+    `id` varchar(191),
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `is_available` boolean DEFAULT true,
+    `is_visible` boolean DEFAULT true,
+    `subscription_id` varchar(120),
+    `organization_id` varchar(120),
+    `event_id` varchar(120),
+    `event_type` varchar(80),
+    `payload` text,
+    `status` varchar(20),
+    `attempts` bigint,
+    `next_attempt_at` datetime(3) NULL,
+    `last_attempt_at` datetime(3) NULL,
+    `last_status_code` bigint,
+    `last_error` varchar(500),
+    `delivered_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_deliveries_event_id` (`event_id`),
+    INDEX `idx_webhook_deliveries_status` (`status`),
+    INDEX `idx_webhook_deliveries_next_attempt_at` (`next_attempt_at`),
+    INDEX `idx_webhook_deliveries_subscription_id` (`subscription_id`),
+    INDEX `idx_webhook_deliveries_organization_id` (`organization_id`)
+);
+
+-- Recherche plein texte des utilisateurs
+CREATE FULLTEXT INDEX `idx_users_search` ON `users` (`name`, `sername`, `username`, `email`);
@@ -0,0 +1,5 @@
+-- Échoue si deux organisations ont une politique de même nom et de même version
+
+DROP INDEX `idx_policies_organization_name_version` ON `policies`;
+ALTER TABLE `policies` DROP COLUMN `organization_id`;
+CREATE UNIQUE INDEX `idx_policies_name_version` ON `policies` (`name`,`version`);
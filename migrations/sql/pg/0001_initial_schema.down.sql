@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhook_subscriptions";
+DROP TABLE IF EXISTS "outbox_events";
+DROP TABLE IF EXISTS "audit_events";
+DROP TABLE IF EXISTS "login_events";
+DROP TABLE IF EXISTS "sessions";
+DROP TABLE IF EXISTS "two_factor_methods";
+DROP TABLE IF EXISTS "identity_changes";
+DROP TABLE IF EXISTS "user_attributes";
+DROP TABLE IF EXISTS "attribute_definitions";
+DROP TABLE IF EXISTS "invitations";
+DROP TABLE IF EXISTS "impersonation_actions";
+DROP TABLE IF EXISTS "impersonations";
+DROP TABLE IF EXISTS "policies";
+DROP TABLE IF EXISTS "group_roles";
+DROP TABLE IF EXISTS "group_members";
+DROP TABLE IF EXISTS "groups";
+DROP TABLE IF EXISTS "membership_roles";
+DROP TABLE IF EXISTS "memberships";
+DROP TABLE IF EXISTS "organizations";
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "otps";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "roles";
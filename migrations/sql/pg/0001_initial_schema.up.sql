@@ -0,0 +1,479 @@
+-- Schéma initial du service, équivalent aux tables créées auparavant par AutoMigrate
+
+CREATE TABLE "roles" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "name" varchar(80),
+    "describe" varchar(500),
+    "require_two_factor" boolean,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_roles_name" ON "roles" ("name");
+
+CREATE TABLE "users" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "name" varchar(80),
+    "email" text UNIQUE,
+    "username" text UNIQUE,
+    "role_id" text,
+    "sername" text,
+    "password" text,
+    "use_otp" boolean,
+    "status" varchar(30) DEFAULT 'active',
+    "status_reason" text,
+    "status_changed_at" timestamptz,
+    "locked_until" timestamptz,
+    "failed_login_attempts" bigint,
+    "sessions_revoked_at" timestamptz,
+    "locale" varchar(20),
+    "avatar_file" varchar(120),
+    "anonymized_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_roles_users" FOREIGN KEY ("role_id") REFERENCES "roles"("id")
+);
+
+CREATE TABLE "otps" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "code" text,
+    "is_used" boolean,
+    "user_id" text,
+    "expire_has" timestamptz,
+    "method_id" varchar(120),
+    "attempts" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_otps" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+
+CREATE TABLE "permissions" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "name" varchar(80),
+    "describe" varchar(500),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "role_permissions" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "role_id" varchar(120),
+    "permission_id" varchar(120),
+    "describe" varchar(500),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_roles_roles_permissions" FOREIGN KEY ("role_id") REFERENCES "roles"("id"),
+    CONSTRAINT "fk_permissions_roles_permissions" FOREIGN KEY ("permission_id") REFERENCES "permissions"("id")
+);
+CREATE INDEX "idx_role_permissions_permission_id" ON "role_permissions" ("permission_id");
+CREATE INDEX "idx_role_permissions_role_id" ON "role_permissions" ("role_id");
+
+CREATE TABLE "organizations" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "name" varchar(120),
+    "slug" varchar(120) UNIQUE,
+    "describe" varchar(500),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "memberships" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "user_id" varchar(120),
+    "organization_id" varchar(120),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_organizations_memberships" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id")
+);
+CREATE INDEX "idx_memberships_organization_id" ON "memberships" ("organization_id");
+CREATE INDEX "idx_memberships_user_id" ON "memberships" ("user_id");
+
+CREATE TABLE "membership_roles" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "membership_id" varchar(120),
+    "role_id" varchar(120),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_memberships_membership_roles" FOREIGN KEY ("membership_id") REFERENCES "memberships"("id")
+);
+CREATE INDEX "idx_membership_roles_membership_id" ON "membership_roles" ("membership_id");
+CREATE INDEX "idx_membership_roles_role_id" ON "membership_roles" ("role_id");
+
+CREATE TABLE "groups" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "organization_id" varchar(120),
+    "name" varchar(80),
+    "describe" varchar(500),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_groups_organization_id" ON "groups" ("organization_id");
+
+CREATE TABLE "group_members" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "group_id" varchar(120),
+    "user_id" varchar(120),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_groups_group_members" FOREIGN KEY ("group_id") REFERENCES "groups"("id")
+);
+CREATE INDEX "idx_group_members_group_id" ON "group_members" ("group_id");
+CREATE INDEX "idx_group_members_user_id" ON "group_members" ("user_id");
+
+CREATE TABLE "group_roles" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "group_id" varchar(120),
+    "role_id" varchar(120),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_groups_group_roles" FOREIGN KEY ("group_id") REFERENCES "groups"("id")
+);
+CREATE INDEX "idx_group_roles_group_id" ON "group_roles" ("group_id");
+CREATE INDEX "idx_group_roles_role_id" ON "group_roles" ("role_id");
+
+CREATE TABLE "policies" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "name" varchar(120),
+    "version" bigint,
+    "describe" varchar(500),
+    "document" text,
+    "is_active" boolean,
+    "created_by" varchar(120),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_policies_is_active" ON "policies" ("is_active");
+CREATE UNIQUE INDEX "idx_policies_name_version" ON "policies" ("name","version");
+
+CREATE TABLE "impersonations" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "actor_id" varchar(120),
+    "target_user_id" varchar(120),
+    "organization_id" varchar(120),
+    "reason" varchar(500),
+    "expires_at" timestamptz,
+    "ended_at" timestamptz,
+    "ended_by" varchar(120),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_impersonations_actor_id" ON "impersonations" ("actor_id");
+CREATE INDEX "idx_impersonations_organization_id" ON "impersonations" ("organization_id");
+CREATE INDEX "idx_impersonations_target_user_id" ON "impersonations" ("target_user_id");
+
+CREATE TABLE "impersonation_actions" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "impersonation_id" varchar(120),
+    "actor_id" varchar(120),
+    "target_user_id" varchar(120),
+    "method" varchar(10),
+    "path" varchar(500),
+    "status" bigint,
+    "ip" varchar(64),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_impersonations_actions" FOREIGN KEY ("impersonation_id") REFERENCES "impersonations"("id")
+);
+CREATE INDEX "idx_impersonation_actions_impersonation_id" ON "impersonation_actions" ("impersonation_id");
+
+CREATE TABLE "invitations" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "email" varchar(120),
+    "user_id" varchar(120),
+    "organization_id" varchar(120),
+    "invited_by" varchar(120),
+    "token_hash" varchar(64),
+    "expires_at" timestamptz,
+    "sent_count" bigint,
+    "last_sent_at" timestamptz,
+    "accepted_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_invitations_email" ON "invitations" ("email");
+CREATE INDEX "idx_invitations_organization_id" ON "invitations" ("organization_id");
+CREATE INDEX "idx_invitations_user_id" ON "invitations" ("user_id");
+CREATE UNIQUE INDEX "idx_invitations_token_hash" ON "invitations" ("token_hash");
+
+CREATE TABLE "attribute_definitions" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "organization_id" varchar(120),
+    "name" varchar(50),
+    "label" varchar(120),
+    "type" varchar(20),
+    "required" boolean,
+    "user_editable" boolean,
+    "options" text,
+    "pattern" varchar(255),
+    "max_length" bigint,
+    "claim_name" varchar(50),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_attribute_definitions_org_name" ON "attribute_definitions" ("organization_id","name");
+
+CREATE TABLE "user_attributes" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "user_id" varchar(120),
+    "definition_id" varchar(120),
+    "organization_id" varchar(120),
+    "value" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_user_attributes_definition_id" ON "user_attributes" ("definition_id");
+CREATE INDEX "idx_user_attributes_organization_id" ON "user_attributes" ("organization_id");
+CREATE UNIQUE INDEX "idx_user_attributes_user_definition" ON "user_attributes" ("user_id","definition_id");
+
+CREATE TABLE "identity_changes" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "user_id" varchar(120),
+    "field" varchar(20),
+    "new_value" varchar(120),
+    "token_hash" varchar(64),
+    "expires_at" timestamptz,
+    "confirmed_at" timestamptz,
+    "cancelled_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_identity_changes_user_id" ON "identity_changes" ("user_id");
+CREATE UNIQUE INDEX "idx_identity_changes_token_hash" ON "identity_changes" ("token_hash");
+
+CREATE TABLE "two_factor_methods" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "user_id" varchar(120),
+    "type" varchar(20),
+    "label" varchar(80),
+    "secret" varchar(64),
+    "is_default" boolean,
+    "confirmed_at" timestamptz,
+    "last_used_at" timestamptz,
+    "last_used_step" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_two_factor_methods_user_id" ON "two_factor_methods" ("user_id");
+
+CREATE TABLE "sessions" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "user_id" varchar(120),
+    "device" varchar(120),
+    "user_agent" varchar(512),
+    "ip_address" varchar(64),
+    "refresh_token_hash" varchar(64),
+    "last_seen_at" timestamptz,
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_sessions_user_id" ON "sessions" ("user_id");
+
+CREATE TABLE "login_events" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "user_id" varchar(120),
+    "method" varchar(20),
+    "device" varchar(120),
+    "device_fingerprint" varchar(64),
+    "user_agent" varchar(512),
+    "ip_address" varchar(64),
+    "ip_range" varchar(64),
+    "country" varchar(120),
+    "city" varchar(120),
+    "new_device" boolean,
+    "new_location" boolean,
+    "report_token_hash" varchar(64),
+    "report_expires_at" timestamptz,
+    "reported_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_login_events_device_fingerprint" ON "login_events" ("device_fingerprint");
+CREATE INDEX "idx_login_events_report_token_hash" ON "login_events" ("report_token_hash");
+CREATE INDEX "idx_login_events_user_id" ON "login_events" ("user_id");
+
+CREATE TABLE "audit_events" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "sequence" bigint,
+    "occurred_at" timestamptz,
+    "action" varchar(80),
+    "outcome" varchar(20),
+    "actor_id" varchar(120),
+    "actor_email" varchar(120),
+    "impersonator_id" varchar(120),
+    "organization_id" varchar(120),
+    "target_type" varchar(40),
+    "target_id" varchar(120),
+    "ip_address" varchar(64),
+    "user_agent" varchar(512),
+    "correlation_id" varchar(120),
+    "details" text,
+    "previous_hash" varchar(64),
+    "hash" varchar(64),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_audit_events_action" ON "audit_events" ("action");
+CREATE INDEX "idx_audit_events_actor_id" ON "audit_events" ("actor_id");
+CREATE INDEX "idx_audit_events_correlation_id" ON "audit_events" ("correlation_id");
+CREATE INDEX "idx_audit_events_occurred_at" ON "audit_events" ("occurred_at");
+CREATE INDEX "idx_audit_events_organization_id" ON "audit_events" ("organization_id");
+CREATE INDEX "idx_audit_events_target_id" ON "audit_events" ("target_id");
+CREATE UNIQUE INDEX "idx_audit_events_sequence" ON "audit_events" ("sequence");
+
+CREATE TABLE "outbox_events" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "event_type" varchar(80),
+    "organization_id" varchar(120),
+    "subject_id" varchar(120),
+    "payload" text,
+    "occurred_at" timestamptz,
+    "dispatched_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_outbox_events_dispatched_at" ON "outbox_events" ("dispatched_at");
+CREATE INDEX "idx_outbox_events_event_type" ON "outbox_events" ("event_type");
+CREATE INDEX "idx_outbox_events_subject_id" ON "outbox_events" ("subject_id");
+
+CREATE TABLE "webhook_subscriptions" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "organization_id" varchar(120),
+    "url" varchar(500),
+    "description" varchar(255),
+    "events" varchar(500),
+    "secret" varchar(120),
+    "created_by" varchar(120),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_webhook_subscriptions_organization_id" ON "webhook_subscriptions" ("organization_id");
+
+CREATE TABLE "webhook_deliveries" (
+    "id" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_available" boolean DEFAULT true,
+    "is_visible" boolean DEFAULT true,
+    "subscription_id" varchar(120),
+    "organization_id" varchar(120),
+    "event_id" varchar(120),
+    "event_type" varchar(80),
+    "payload" text,
+    "status" varchar(20),
+    "attempts" bigint,
+    "next_attempt_at" timestamptz,
+    "last_attempt_at" timestamptz,
+    "last_status_code" bigint,
+    "last_error" varchar(500),
+    "delivered_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_webhook_deliveries_event_id" ON "webhook_deliveries" ("event_id");
+CREATE INDEX "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+CREATE INDEX "idx_webhook_deliveries_organization_id" ON "webhook_deliveries" ("organization_id");
+CREATE INDEX "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE INDEX "idx_webhook_deliveries_subscription_id" ON "webhook_deliveries" ("subscription_id");
+
+-- Recherche plein texte des utilisateurs
+CREATE INDEX "idx_users_search" ON "users" USING GIN (
+    to_tsvector('simple', COALESCE(name, '') || ' ' || COALESCE(sername, '') || ' ' || COALESCE(username, '') || ' ' || COALESCE(email, ''))
+);
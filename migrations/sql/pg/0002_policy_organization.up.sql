@@ -0,0 +1,7 @@
+-- Les politiques d'accès appartiennent à une organisation ; les politiques existantes sont
+-- rattachées à l'organisation par défaut
+
+ALTER TABLE "policies" ADD COLUMN "organization_id" varchar(120);
+UPDATE "policies" SET "organization_id" = (SELECT "id" FROM "organizations" WHERE "slug" = 'default');
+DROP INDEX "idx_policies_name_version";
+CREATE UNIQUE INDEX "idx_policies_organization_name_version" ON "policies" ("organization_id","name","version");
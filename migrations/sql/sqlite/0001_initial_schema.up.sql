@@ -0,0 +1,474 @@
+-- Schéma initial du service, équivalent aux tables créées auparavant par AutoMigrate
+
+CREATE TABLE `roles` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `name` text,
+    `describe` text,
+    `require_two_factor` numeric,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_roles_name` ON `roles` (`name`);
+
+CREATE TABLE `users` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `name` text,
+    `email` text UNIQUE,
+    `username` text UNIQUE,
+    `role_id` text,
+    `sername` text,
+    `password` text,
+    `use_otp` numeric,
+    `status` text DEFAULT 'active',
+    `status_reason` text,
+    `status_changed_at` datetime,
+    `locked_until` datetime,
+    `failed_login_attempts` integer,
+    `sessions_revoked_at` datetime,
+    `locale` text,
+    `avatar_file` text,
+    `anonymized_at` datetime,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_roles_users` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`)
+);
+
+CREATE TABLE `otps` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `code` text,
+    `is_used` numeric,
+    `user_id` text,
+    `expire_has` datetime,
+    `method_id` text,
+    `attempts` integer,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_users_otps` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `permissions` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `name` text,
+    `describe` text,
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `role_permissions` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `role_id` text,
+    `permission_id` text,
+    `describe` text,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_roles_roles_permissions` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`),
+    CONSTRAINT `fk_permissions_roles_permissions` FOREIGN KEY (`permission_id`) REFERENCES `permissions`(`id`)
+);
+CREATE INDEX `idx_role_permissions_permission_id` ON `role_permissions` (`permission_id`);
+CREATE INDEX `idx_role_permissions_role_id` ON `role_permissions` (`role_id`);
+
+CREATE TABLE `organizations` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `name` text,
+    `slug` text UNIQUE,
+    `describe` text,
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `memberships` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `user_id` text,
+    `organization_id` text,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_organizations_memberships` FOREIGN KEY (`organization_id`) REFERENCES `organizations`(`id`)
+);
+CREATE INDEX `idx_memberships_organization_id` ON `memberships` (`organization_id`);
+CREATE INDEX `idx_memberships_user_id` ON `memberships` (`user_id`);
+
+CREATE TABLE `membership_roles` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `membership_id` text,
+    `role_id` text,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_memberships_membership_roles` FOREIGN KEY (`membership_id`) REFERENCES `memberships`(`id`)
+);
+CREATE INDEX `idx_membership_roles_membership_id` ON `membership_roles` (`membership_id`);
+CREATE INDEX `idx_membership_roles_role_id` ON `membership_roles` (`role_id`);
+
+CREATE TABLE `groups` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `organization_id` text,
+    `name` text,
+    `describe` text,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_groups_organization_id` ON `groups` (`organization_id`);
+
+CREATE TABLE `group_members` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `group_id` text,
+    `user_id` text,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_groups_group_members` FOREIGN KEY (`group_id`) REFERENCES `groups`(`id`)
+);
+CREATE INDEX `idx_group_members_group_id` ON `group_members` (`group_id`);
+CREATE INDEX `idx_group_members_user_id` ON `group_members` (`user_id`);
+
+CREATE TABLE `group_roles` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `group_id` text,
+    `role_id` text,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_groups_group_roles` FOREIGN KEY (`group_id`) REFERENCES `groups`(`id`)
+);
+CREATE INDEX `idx_group_roles_group_id` ON `group_roles` (`group_id`);
+CREATE INDEX `idx_group_roles_role_id` ON `group_roles` (`role_id`);
+
+CREATE TABLE `policies` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `name` text,
+    `version` integer,
+    `describe` text,
+    `document` text,
+    `is_active` numeric,
+    `created_by` text,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_policies_is_active` ON `policies` (`is_active`);
+CREATE UNIQUE INDEX `idx_policies_name_version` ON `policies` (`name`,`version`);
+
+CREATE TABLE `impersonations` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `actor_id` text,
+    `target_user_id` text,
+    `organization_id` text,
+    `reason` text,
+    `expires_at` datetime,
+    `ended_at` datetime,
+    `ended_by` text,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_impersonations_actor_id` ON `impersonations` (`actor_id`);
+CREATE INDEX `idx_impersonations_organization_id` ON `impersonations` (`organization_id`);
+CREATE INDEX `idx_impersonations_target_user_id` ON `impersonations` (`target_user_id`);
+
+CREATE TABLE `impersonation_actions` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `impersonation_id` text,
+    `actor_id` text,
+    `target_user_id` text,
+    `method` text,
+    `path` text,
+    `status` integer,
+    `ip` text,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_impersonations_actions` FOREIGN KEY (`impersonation_id`) REFERENCES `impersonations`(`id`)
+);
+CREATE INDEX `idx_impersonation_actions_impersonation_id` ON `impersonation_actions` (`impersonation_id`);
+
+CREATE TABLE `invitations` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `email` text,
+    `user_id` text,
+    `organization_id` text,
+    `invited_by` text,
+    `token_hash` text,
+    `expires_at` datetime,
+    `sent_count` integer,
+    `last_sent_at` datetime,
+    `accepted_at` datetime,
+    `revoked_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_invitations_email` ON `invitations` (`email`);
+CREATE INDEX `idx_invitations_organization_id` ON `invitations` (`organization_id`);
+CREATE INDEX `idx_invitations_user_id` ON `invitations` (`user_id`);
+CREATE UNIQUE INDEX `idx_invitations_token_hash` ON `invitations` (`token_hash`);
+
+CREATE TABLE `attribute_definitions` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `organization_id` text,
+    `name` text,
+    `label` text,
+    `type` text,
+    `required` numeric,
+    `user_editable` numeric,
+    `options` text,
+    `pattern` text,
+    `max_length` integer,
+    `claim_name` text,
+    PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX `idx_attribute_definitions_org_name` ON `attribute_definitions` (`organization_id`,`name`);
+
+CREATE TABLE `user_attributes` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `user_id` text,
+    `definition_id` text,
+    `organization_id` text,
+    `value` text,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_user_attributes_definition_id` ON `user_attributes` (`definition_id`);
+CREATE INDEX `idx_user_attributes_organization_id` ON `user_attributes` (`organization_id`);
+CREATE UNIQUE INDEX `idx_user_attributes_user_definition` ON `user_attributes` (`user_id`,`definition_id`);
+
+CREATE TABLE `identity_changes` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `user_id` text,
+    `field` text,
+    `new_value` text,
+    `token_hash` text,
+    `expires_at` datetime,
+    `confirmed_at` datetime,
+    `cancelled_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_identity_changes_user_id` ON `identity_changes` (`user_id`);
+CREATE UNIQUE INDEX `idx_identity_changes_token_hash` ON `identity_changes` (`token_hash`);
+
+CREATE TABLE `two_factor_methods` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `user_id` text,
+    `type` text,
+    `label` text,
+    `secret` text,
+    `is_default` numeric,
+    `confirmed_at` datetime,
+    `last_used_at` datetime,
+    `last_used_step` integer,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_two_factor_methods_user_id` ON `two_factor_methods` (`user_id`);
+
+CREATE TABLE `sessions` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `user_id` text,
+    `device` text,
+    `user_agent` text,
+    `ip_address` text,
+    `refresh_token_hash` text,
+    `last_seen_at` datetime,
+    `expires_at` datetime,
+    `revoked_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_sessions_user_id` ON `sessions` (`user_id`);
+
+CREATE TABLE `login_events` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `user_id` text,
+    `method` text,
+    `device` text,
+    `device_fingerprint` text,
+    `user_agent` text,
+    `ip_address` text,
+    `ip_range` text,
+    `country` text,
+    `city` text,
+    `new_device` numeric,
+    `new_location` numeric,
+    `report_token_hash` text,
+    `report_expires_at` datetime,
+    `reported_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_login_events_device_fingerprint` ON `login_events` (`device_fingerprint`);
+CREATE INDEX `idx_login_events_report_token_hash` ON `login_events` (`report_token_hash`);
+CREATE INDEX `idx_login_events_user_id` ON `login_events` (`user_id`);
+
+CREATE TABLE `audit_events` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `sequence` integer,
+    `occurred_at` datetime,
+    `action` text,
+    `outcome` text,
+    `actor_id` text,
+    `actor_email` text,
+    `impersonator_id` text,
+    `organization_id` text,
+    `target_type` text,
+    `target_id` text,
+    `ip_address` text,
+    `user_agent` text,
+    `correlation_id` text,
+    `details` text,
+    `previous_hash` text,
+    `hash` text,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_audit_events_action` ON `audit_events` (`action`);
+CREATE INDEX `idx_audit_events_actor_id` ON `audit_events` (`actor_id`);
+CREATE INDEX `idx_audit_events_correlation_id` ON `audit_events` (`correlation_id`);
+CREATE INDEX `idx_audit_events_occurred_at` ON `audit_events` (`occurred_at`);
+CREATE INDEX `idx_audit_events_organization_id` ON `audit_events` (`organization_id`);
+CREATE INDEX `idx_audit_events_target_id` ON `audit_events` (`target_id`);
+CREATE UNIQUE INDEX `idx_audit_events_sequence` ON `audit_events` (`sequence`);
+
+CREATE TABLE `outbox_events` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `event_type` text,
+    `organization_id` text,
+    `subject_id` text,
+    `payload` text,
+    `occurred_at` datetime,
+    `dispatched_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_outbox_events_dispatched_at` ON `outbox_events` (`dispatched_at`);
+CREATE INDEX `idx_outbox_events_event_type` ON `outbox_events` (`event_type`);
+CREATE INDEX `idx_outbox_events_subject_id` ON `outbox_events` (`subject_id`);
+
+CREATE TABLE `webhook_subscriptions` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `organization_id` text,
+    `url` text,
+    `description` text,
+    `events` text,
+    `secret` text,
+    `created_by` text,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_webhook_subscriptions_organization_id` ON `webhook_subscriptions` (`organization_id`);
+
+CREATE TABLE `webhook_deliveries` (
+    `id` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `is_available` numeric DEFAULT true,
+    `is_visible` numeric DEFAULT true,
+    `subscription_id` text,
+    `organization_id` text,
+    `event_id` text,
+    `event_type` text,
+    `payload` text,
+    `status` text,
+    `attempts` integer,
+    `next_attempt_at` datetime,
+    `last_attempt_at` datetime,
+    `last_status_code` integer,
+    `last_error` text,
+    `delivered_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_webhook_deliveries_event_id` ON `webhook_deliveries` (`event_id`);
+CREATE INDEX `idx_webhook_deliveries_next_attempt_at` ON `webhook_deliveries` (`next_attempt_at`);
+CREATE INDEX `idx_webhook_deliveries_organization_id` ON `webhook_deliveries` (`organization_id`);
+CREATE INDEX `idx_webhook_deliveries_status` ON `webhook_deliveries` (`status`);
+CREATE INDEX `idx_webhook_deliveries_subscription_id` ON `webhook_deliveries` (`subscription_id`);
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent est une entrée du journal d'audit. Le journal est en ajout seul : chaque entrée
+// porte l'empreinte de la précédente, toute modification ou suppression casse la chaîne.
+type AuditEvent struct {
+	AbstractModel
+	Sequence       int64     `json:"sequence" gorm:"uniqueIndex"`
+	OccurredAt     time.Time `json:"occurred_at" gorm:"index"`
+	Action         string    `json:"action" gorm:"size:80; index"`
+	Outcome        string    `json:"outcome" gorm:"size:20"`
+	ActorId        string    `json:"actor_id" gorm:"size:120; index"`
+	ActorEmail     string    `json:"actor_email" gorm:"size:120"`
+	ImpersonatorId string    `json:"impersonator_id" gorm:"size:120"`
+	OrganizationId string    `json:"organization_id" gorm:"size:120; index"`
+	TargetType     string    `json:"target_type" gorm:"size:40"`
+	TargetId       string    `json:"target_id" gorm:"size:120; index"`
+	IpAddress      string    `json:"ip_address" gorm:"size:64"`
+	UserAgent      string    `json:"user_agent" gorm:"size:512"`
+	CorrelationId  string    `json:"correlation_id" gorm:"size:120; index"`
+	Details        string    `json:"details" gorm:"type:text"`
+	PreviousHash   string    `json:"previous_hash" gorm:"size:64"`
+	Hash           string    `json:"hash" gorm:"size:64"`
+}
+
+// ComputeHash calcule l'empreinte de l'entrée à partir de son contenu et de l'empreinte précédente
+func (e AuditEvent) ComputeHash() string {
+	content := strings.Join([]string{
+		strconv.FormatInt(e.Sequence, 10),
+		e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		e.Action,
+		e.Outcome,
+		e.ActorId,
+		e.ActorEmail,
+		e.ImpersonatorId,
+		e.OrganizationId,
+		e.TargetType,
+		e.TargetId,
+		e.IpAddress,
+		e.UserAgent,
+		e.CorrelationId,
+		e.Details,
+		e.PreviousHash,
+	}, "\x1f")
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,7 +3,20 @@ package model
 import "github.com/dgrijalva/jwt-go"
 
 type Claims struct {
-	Role   string `json:"role"`
-	Source string `json:"source"`
+	Role   string                 `json:"role"`
+	Source string                 `json:"source"`
+	OrgId  string                 `json:"org_id,omitempty"`
+	Roles  []string               `json:"roles,omitempty"`
+	Act    *Actor                 `json:"act,omitempty"`
+	Attrs  map[string]interface{} `json:"attrs,omitempty"`
+	// SessionId lie le token à la session ouverte à la connexion
+	SessionId string `json:"sid,omitempty"`
 	jwt.StandardClaims
 }
+
+// Actor identifie l'administrateur qui agit au nom du sujet du token
+type Actor struct {
+	Subject   string `json:"sub"`
+	Email     string `json:"email,omitempty"`
+	SessionId string `json:"sid"`
+}
@@ -0,0 +1,10 @@
+package model
+
+type Group struct {
+	AbstractModel
+	OrganizationId string        `json:"organization_id" gorm:"size:120; index"`
+	Name           string        `json:"name" gorm:"size:80" validate:"required"`
+	Describe       string        `json:"describe" gorm:"size:500"`
+	GroupMembers   []GroupMember `json:"group_members" gorm:"foreignKey:GroupId"`
+	GroupRoles     []GroupRole   `json:"group_roles" gorm:"foreignKey:GroupId"`
+}
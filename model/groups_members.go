@@ -0,0 +1,7 @@
+package model
+
+type GroupMember struct {
+	AbstractModel
+	GroupId string `json:"group_id" gorm:"size:120; index"`
+	UserId  string `json:"user_id" gorm:"size:120; index"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type GroupRole struct {
+	AbstractModel
+	GroupId string `json:"group_id" gorm:"size:120; index"`
+	RoleId  string `json:"role_id" gorm:"size:120; index"`
+}
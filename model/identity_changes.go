@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// IdentityChange est une demande de changement d'email ou de nom d'utilisateur,
+// appliquée seulement après confirmation du lien envoyé par email
+type IdentityChange struct {
+	AbstractModel
+	UserId      string    `json:"user_id" gorm:"size:120; index"`
+	Field       string    `json:"field" gorm:"size:20"`
+	NewValue    string    `json:"new_value" gorm:"size:120"`
+	TokenHash   string    `json:"-" gorm:"size:64; uniqueIndex"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// IsPending indique si le changement peut encore être confirmé
+func (c IdentityChange) IsPending(now time.Time) bool {
+	return c.ConfirmedAt.IsZero() && c.CancelledAt.IsZero() && now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Impersonation trace une session durant laquelle un administrateur agit au nom d'un utilisateur
+type Impersonation struct {
+	AbstractModel
+	ActorId        string                `json:"actor_id" gorm:"size:120; index"`
+	TargetUserId   string                `json:"target_user_id" gorm:"size:120; index"`
+	OrganizationId string                `json:"organization_id" gorm:"size:120; index"`
+	Reason         string                `json:"reason" gorm:"size:500"`
+	ExpiresAt      time.Time             `json:"expires_at"`
+	EndedAt        time.Time             `json:"ended_at"`
+	EndedBy        string                `json:"ended_by" gorm:"size:120"`
+	Actions        []ImpersonationAction `json:"actions,omitempty" gorm:"foreignKey:ImpersonationId"`
+}
+
+// IsActive indique si la session n'a été ni terminée ni expirée
+func (i Impersonation) IsActive(now time.Time) bool {
+	return i.EndedAt.IsZero() && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,14 @@
+package model
+
+// ImpersonationAction enregistre une requête effectuée sous un token d'impersonation,
+// attribuée à la fois à l'administrateur et à l'utilisateur ciblé
+type ImpersonationAction struct {
+	AbstractModel
+	ImpersonationId string `json:"impersonation_id" gorm:"size:120; index"`
+	ActorId         string `json:"actor_id" gorm:"size:120"`
+	TargetUserId    string `json:"target_user_id" gorm:"size:120"`
+	Method          string `json:"method" gorm:"size:10"`
+	Path            string `json:"path" gorm:"size:500"`
+	Status          int    `json:"status"`
+	Ip              string `json:"ip" gorm:"size:64"`
+}
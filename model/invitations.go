@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Invitation est un lien à usage unique permettant à un utilisateur de définir son mot de passe
+type Invitation struct {
+	AbstractModel
+	Email          string    `json:"email" gorm:"size:120; index"`
+	UserId         string    `json:"user_id" gorm:"size:120; index"`
+	OrganizationId string    `json:"organization_id" gorm:"size:120; index"`
+	InvitedBy      string    `json:"invited_by" gorm:"size:120"`
+	TokenHash      string    `json:"-" gorm:"size:64; uniqueIndex"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	SentCount      int       `json:"sent_count"`
+	LastSentAt     time.Time `json:"last_sent_at"`
+	AcceptedAt     time.Time `json:"accepted_at"`
+	RevokedAt      time.Time `json:"revoked_at"`
+}
+
+// IsPending indique si l'invitation peut encore être acceptée
+func (i Invitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt.IsZero() && i.RevokedAt.IsZero() && now.Before(i.ExpiresAt)
+}
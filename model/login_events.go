@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// LoginEvent est une connexion réussie d'un utilisateur. L'empreinte de l'appareil et la plage
+// d'adresses IP permettent de reconnaître une connexion inhabituelle ; dans ce cas une alerte est
+// envoyée avec un lien "ce n'était pas moi", dont seule l'empreinte est conservée.
+type LoginEvent struct {
+	AbstractModel
+	UserId            string    `json:"user_id" gorm:"size:120; index"`
+	Method            string    `json:"method" gorm:"size:20"`
+	Device            string    `json:"device" gorm:"size:120"`
+	DeviceFingerprint string    `json:"-" gorm:"size:64; index"`
+	UserAgent         string    `json:"user_agent" gorm:"size:512"`
+	IpAddress         string    `json:"ip_address" gorm:"size:64"`
+	IpRange           string    `json:"ip_range" gorm:"size:64"`
+	Country           string    `json:"country" gorm:"size:120"`
+	City              string    `json:"city" gorm:"size:120"`
+	NewDevice         bool      `json:"new_device"`
+	NewLocation       bool      `json:"new_location"`
+	ReportTokenHash   string    `json:"-" gorm:"size:64; index"`
+	ReportExpiresAt   time.Time `json:"-"`
+	ReportedAt        time.Time `json:"reported_at"`
+}
+
+// IsReportable indique si le lien "ce n'était pas moi" de la connexion peut encore être utilisé
+func (e LoginEvent) IsReportable(now time.Time) bool {
+	return e.ReportTokenHash != "" && e.ReportedAt.IsZero() && now.Before(e.ReportExpiresAt)
+}
@@ -0,0 +1,8 @@
+package model
+
+type Membership struct {
+	AbstractModel
+	UserId          string           `json:"user_id" gorm:"size:120; index"`
+	OrganizationId  string           `json:"organization_id" gorm:"size:120; index"`
+	MembershipRoles []MembershipRole `json:"membership_roles" gorm:"foreignKey:MembershipId"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type MembershipRole struct {
+	AbstractModel
+	MembershipId string `json:"membership_id" gorm:"size:120; index"`
+	RoleId       string `json:"role_id" gorm:"size:120; index"`
+}
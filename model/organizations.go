@@ -0,0 +1,12 @@
+package model
+
+// DefaultOrganizationSlug identifie l'organisation créée au démarrage du système
+const DefaultOrganizationSlug = "default"
+
+type Organization struct {
+	AbstractModel
+	Name        string       `json:"name" gorm:"size:120" validate:"required"`
+	Slug        string       `json:"slug" gorm:"size:120; unique" validate:"required"`
+	Describe    string       `json:"describe" gorm:"size:500"`
+	Memberships []Membership `json:"memberships" gorm:"foreignKey:OrganizationId"`
+}
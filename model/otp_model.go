@@ -10,4 +10,8 @@ type Otp struct {
 	IsUsed    bool      `json:"is_used"`
 	UserId    string    `json:"user_id"`
 	ExpireHas time.Time `json:"expire_has"`
+
+	// MethodId est le second facteur interrogé ; vide pour un code envoyé à l'adresse du compte
+	MethodId string `json:"method_id" gorm:"size:120"`
+	Attempts int    `json:"attempts"`
 }
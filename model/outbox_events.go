@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Évènements du cycle de vie des utilisateurs publiés aux webhooks
+const (
+	EventUserCreated       = "user.created"
+	EventUserUpdated       = "user.updated"
+	EventUserDeleted       = "user.deleted"
+	EventUserRoleChanged   = "user.role_changed"
+	EventUserPasswordReset = "user.password_reset"
+)
+
+// UserEvents liste les évènements auxquels un webhook peut s'abonner
+var UserEvents = []string{
+	EventUserCreated,
+	EventUserUpdated,
+	EventUserDeleted,
+	EventUserRoleChanged,
+	EventUserPasswordReset,
+}
+
+// OutboxEvent est un évènement enregistré dans la transaction de la modification qui le produit.
+// Il n'est transmis aux webhooks qu'une fois la transaction validée, puis marqué comme distribué.
+type OutboxEvent struct {
+	AbstractModel
+	EventType      string    `json:"event_type" gorm:"size:80; index"`
+	OrganizationId string    `json:"organization_id" gorm:"size:120"`
+	SubjectId      string    `json:"subject_id" gorm:"size:120; index"`
+	Payload        string    `json:"payload" gorm:"type:text"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	DispatchedAt   time.Time `json:"dispatched_at" gorm:"index"`
+}
+
+// UserEventData est l'utilisateur décrit dans un évènement, sans données sensibles
+type UserEventData struct {
+	Id          string `json:"id"`
+	Email       string `json:"email"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	Sername     string `json:"sername"`
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+	IsAvailable bool   `json:"is_available"`
+}
@@ -0,0 +1,12 @@
+package model
+
+type Policy struct {
+	AbstractModel
+	OrganizationId string `json:"organization_id" gorm:"size:120; uniqueIndex:idx_policies_organization_name_version"`
+	Name           string `json:"name" gorm:"size:120; uniqueIndex:idx_policies_organization_name_version"`
+	Version        int    `json:"version" gorm:"uniqueIndex:idx_policies_organization_name_version"`
+	Describe       string `json:"describe" gorm:"size:500"`
+	Document       string `json:"document" gorm:"type:text"`
+	IsActive       bool   `json:"is_active" gorm:"index"`
+	CreatedBy      string `json:"created_by" gorm:"size:120"`
+}
@@ -4,6 +4,7 @@ type Role struct {
 	AbstractModel
 	Name             string           `json:"name" gorm:"size:80; index"`
 	Describe         string           `json:"describe" gorm:"size:500"`
+	RequireTwoFactor bool             `json:"require_two_factor"`
 	Users            []User           `json:"users" gorm:"foreignKey:RoleId"`
 	RolesPermissions []RolePermission `json:"roles_permissions" gorm:"foreignKey:RoleId"`
 }
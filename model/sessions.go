@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Session est une connexion d'un utilisateur sur un appareil. Elle est liée au refresh token
+// courant par son empreinte : chaque rafraîchissement remplace le token et prolonge la session.
+type Session struct {
+	AbstractModel
+	UserId           string    `json:"user_id" gorm:"size:120; index"`
+	Device           string    `json:"device" gorm:"size:120"`
+	UserAgent        string    `json:"user_agent" gorm:"size:512"`
+	IpAddress        string    `json:"ip_address" gorm:"size:64"`
+	RefreshTokenHash string    `json:"-" gorm:"size:64"`
+	LastSeenAt       time.Time `json:"last_seen_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RevokedAt        time.Time `json:"revoked_at"`
+}
+
+// IsActive indique si la session peut encore être utilisée
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt.IsZero() && now.Before(s.ExpiresAt)
+}
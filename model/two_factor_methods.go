@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// TwoFactorMethod est un second facteur enrôlé par un utilisateur : application d'authentification (totp) ou code par email
+type TwoFactorMethod struct {
+	AbstractModel
+	UserId      string    `json:"user_id" gorm:"size:120; index"`
+	Type        string    `json:"type" gorm:"size:20"`
+	Label       string    `json:"label" gorm:"size:80"`
+	Secret      string    `json:"-" gorm:"size:64"`
+	IsDefault   bool      `json:"is_default"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+
+	// LastUsedStep est le dernier pas de temps totp accepté, pour qu'un code ne serve qu'une fois
+	LastUsedStep int64 `json:"-"`
+}
+
+// IsConfirmed indique si l'utilisateur a prouvé la possession du facteur
+func (m TwoFactorMethod) IsConfirmed() bool {
+	return !m.ConfirmedAt.IsZero()
+}
@@ -0,0 +1,25 @@
+package model
+
+// AttributeDefinition décrit un attribut de profil personnalisé d'une organisation
+type AttributeDefinition struct {
+	AbstractModel
+	OrganizationId string `json:"organization_id" gorm:"size:120; uniqueIndex:idx_attribute_definitions_org_name"`
+	Name           string `json:"name" gorm:"size:50; uniqueIndex:idx_attribute_definitions_org_name"`
+	Label          string `json:"label" gorm:"size:120"`
+	Type           string `json:"type" gorm:"size:20"`
+	Required       bool   `json:"required"`
+	UserEditable   bool   `json:"user_editable"`
+	Options        string `json:"options" gorm:"type:text"`
+	Pattern        string `json:"pattern" gorm:"size:255"`
+	MaxLength      int    `json:"max_length"`
+	ClaimName      string `json:"claim_name" gorm:"size:50"`
+}
+
+// UserAttribute est la valeur d'un attribut personnalisé pour un utilisateur, encodée en JSON
+type UserAttribute struct {
+	AbstractModel
+	UserId         string `json:"user_id" gorm:"size:120; uniqueIndex:idx_user_attributes_user_definition"`
+	DefinitionId   string `json:"definition_id" gorm:"size:120; uniqueIndex:idx_user_attributes_user_definition; index"`
+	OrganizationId string `json:"organization_id" gorm:"size:120; index"`
+	Value          string `json:"value" gorm:"type:text"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type User struct {
 	AbstractModel
 	Name     string `json:"name" gorm:"size:80" validate:"required"`
@@ -10,4 +12,21 @@ type User struct {
 	Password string `json:"password" validate:"required"`
 	UseOTP   bool   `json:"use_otp"`
 	Otps     []Otp  `json:"otps" gorm:"foreignKey:UserId"`
+
+	// Status est l'état du compte : active, disabled, locked, pending_activation ou password_expired
+	Status              string    `json:"status" gorm:"size:30;default:active"`
+	StatusReason        string    `json:"status_reason"`
+	StatusChangedAt     time.Time `json:"status_changed_at"`
+	LockedUntil         time.Time `json:"locked_until"`
+	FailedLoginAttempts int       `json:"failed_login_attempts"`
+
+	// SessionsRevokedAt invalide tous les tokens émis avant cette date
+	SessionsRevokedAt time.Time `json:"-"`
+
+	// Locale est la langue préférée de l'utilisateur, AvatarFile le fichier de sa photo de profil
+	Locale     string `json:"locale" gorm:"size:20"`
+	AvatarFile string `json:"-" gorm:"size:120"`
+
+	// AnonymizedAt est renseigné lorsque la purge a effacé les données personnelles du compte
+	AnonymizedAt time.Time `json:"anonymized_at,omitempty"`
 }
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription est l'abonnement d'un service aux évènements d'une organisation.
+// Les envois sont signés par HMAC-SHA256 avec le secret de l'abonnement.
+type WebhookSubscription struct {
+	AbstractModel
+	OrganizationId string `json:"organization_id" gorm:"size:120; index"`
+	Url            string `json:"url" gorm:"size:500"`
+	Description    string `json:"description" gorm:"size:255"`
+	Events         string `json:"events" gorm:"size:500"`
+	Secret         string `json:"-" gorm:"size:120"`
+	CreatedBy      string `json:"created_by" gorm:"size:120"`
+}
+
+// EventList retourne les évènements de l'abonnement
+func (w WebhookSubscription) EventList() []string {
+	var events []string
+	for _, event := range strings.Split(w.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Accepts indique si l'abonnement reçoit le type d'évènement
+func (w WebhookSubscription) Accepts(eventType string) bool {
+	events := w.EventList()
+	for i := 0; i < len(events); i++ {
+		if events[i] == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery est l'envoi d'un évènement à un abonnement. Un envoi en échec est retenté avec
+// un délai croissant ; après le nombre maximal de tentatives, il passe en lettre morte.
+type WebhookDelivery struct {
+	AbstractModel
+	SubscriptionId string    `json:"subscription_id" gorm:"size:120; index"`
+	OrganizationId string    `json:"organization_id" gorm:"size:120; index"`
+	EventId        string    `json:"event_id" gorm:"size:120; index"`
+	EventType      string    `json:"event_type" gorm:"size:80"`
+	Payload        string    `json:"payload" gorm:"type:text"`
+	Status         string    `json:"status" gorm:"size:20; index"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at" gorm:"index"`
+	LastAttemptAt  time.Time `json:"last_attempt_at"`
+	LastStatusCode int       `json:"last_status_code"`
+	LastError      string    `json:"last_error" gorm:"size:500"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+}
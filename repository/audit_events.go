@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditAppendRetries est le nombre d'essais d'ajout lorsque deux instances écrivent le même numéro de séquence
+const auditAppendRetries = 5
+
+// auditAppendLock sérialise les ajouts au journal dans le processus
+var auditAppendLock sync.Mutex
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository crée une nouvelle instance de AuditRepository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *AuditRepository) WithContext(ctx context.Context) *AuditRepository {
+	return &AuditRepository{db: r.db.WithContext(ctx)}
+}
+
+// AuditQuery regroupe les filtres et la pagination de la recherche dans le journal d'audit
+type AuditQuery struct {
+	Action         string
+	Outcome        string
+	ActorId        string
+	TargetId       string
+	OrganizationId string
+	CorrelationId  string
+	IpAddress      string
+	From           time.Time
+	To             time.Time
+	Limit          int
+	Offset         int
+}
+
+// AppendEvent ajoute une entrée à la fin du journal et la chaîne à la précédente.
+// L'index unique sur la séquence garantit qu'une seule entrée suit chaque entrée.
+func (r *AuditRepository) AppendEvent(event model.AuditEvent) (model.AuditEvent, error) {
+	auditAppendLock.Lock()
+	defer auditAppendLock.Unlock()
+
+	var err error
+	for attempt := 0; attempt < auditAppendRetries; attempt++ {
+		appended := event
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			var last model.AuditEvent
+			lastTx := tx.Model(model.AuditEvent{}).Order("sequence desc").Limit(1).Find(&last)
+			if lastTx.Error != nil {
+				return lastTx.Error
+			}
+
+			appended.Sequence = last.Sequence + 1
+			appended.PreviousHash = last.Hash
+			appended.Hash = appended.ComputeHash()
+			appended.CreatedAt = appended.OccurredAt
+			appended.UpdatedAt = appended.OccurredAt
+			return tx.Model(model.AuditEvent{}).Create(&appended).Error
+		})
+		if err == nil {
+			return appended, nil
+		}
+	}
+	return model.AuditEvent{}, err
+}
+
+// FindEvents recherche les entrées du journal, les plus récentes en premier
+func (r *AuditRepository) FindEvents(query AuditQuery) ([]model.AuditEvent, int64, error) {
+	tx := r.db.Model(model.AuditEvent{})
+	if query.Action != "" {
+		tx = tx.Where("action = ?", query.Action)
+	}
+	if query.Outcome != "" {
+		tx = tx.Where("outcome = ?", query.Outcome)
+	}
+	if query.ActorId != "" {
+		tx = tx.Where("actor_id = ? or impersonator_id = ?", query.ActorId, query.ActorId)
+	}
+	if query.TargetId != "" {
+		tx = tx.Where("target_id = ?", query.TargetId)
+	}
+	if query.OrganizationId != "" {
+		tx = tx.Where("organization_id = ?", query.OrganizationId)
+	}
+	if query.CorrelationId != "" {
+		tx = tx.Where("correlation_id = ?", query.CorrelationId)
+	}
+	if query.IpAddress != "" {
+		tx = tx.Where("ip_address = ?", query.IpAddress)
+	}
+	if !query.From.IsZero() {
+		tx = tx.Where("occurred_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		tx = tx.Where("occurred_at < ?", query.To)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []model.AuditEvent
+	err := tx.Order("sequence desc").Limit(query.Limit).Offset(query.Offset).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetEventsAfter récupère les entrées qui suivent une séquence, dans l'ordre du journal
+func (r *AuditRepository) GetEventsAfter(sequence int64, limit int) ([]model.AuditEvent, error) {
+	var events []model.AuditEvent
+	tx := r.db.Model(model.AuditEvent{}).
+		Where("sequence > ?", sequence).Order("sequence").Limit(limit).Find(&events)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return events, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GroupRepository struct {
+	db             *gorm.DB
+	scoped         bool
+	organizationId string
+}
+
+// NewGroupRepository crée une nouvelle instance de GroupRepository
+func NewGroupRepository(db *gorm.DB) *GroupRepository {
+	return &GroupRepository{
+		db: db,
+	}
+}
+
+// ForOrganization retourne une copie du repository limitée aux groupes de l'organisation fournie
+func (r *GroupRepository) ForOrganization(organizationId string) *GroupRepository {
+	return &GroupRepository{
+		db:             r.db,
+		scoped:         true,
+		organizationId: organizationId,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *GroupRepository) WithContext(ctx context.Context) *GroupRepository {
+	return &GroupRepository{
+		db:             r.db.WithContext(ctx),
+		scoped:         r.scoped,
+		organizationId: r.organizationId,
+	}
+}
+
+func (r *GroupRepository) tenantScope(db *gorm.DB) *gorm.DB {
+	if !r.scoped {
+		return db
+	}
+	return db.Where("organization_id = ?", r.organizationId)
+}
+
+// GetAllGroups récupère tous les groupes visibles
+func (r *GroupRepository) GetAllGroups() ([]model.Group, error) {
+	var groups []model.Group
+	tx := r.db.Model(model.Group{}).Scopes(r.tenantScope).
+		Preload("GroupMembers").Preload("GroupRoles").
+		Where("is_visible = ?", true).Order("name").Find(&groups)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return groups, nil
+}
+
+// GetGroupById récupère un groupe avec ses membres et ses rôles
+func (r *GroupRepository) GetGroupById(id string) (model.Group, error) {
+	var group model.Group
+	tx := r.db.Model(model.Group{}).Scopes(r.tenantScope).
+		Preload("GroupMembers").Preload("GroupRoles").
+		First(&group, "id = ? and is_visible = ?", id, true)
+	if tx.Error != nil {
+		return model.Group{}, tx.Error
+	}
+	return group, nil
+}
+
+// GetGroupByName récupère un groupe par son nom
+func (r *GroupRepository) GetGroupByName(name string) (model.Group, error) {
+	var group model.Group
+	tx := r.db.Model(model.Group{}).Scopes(r.tenantScope).
+		First(&group, "name = ? and is_visible = ?", name, true)
+	if tx.Error != nil {
+		return model.Group{}, tx.Error
+	}
+	return group, nil
+}
+
+// GetGroupsByUser récupère les groupes dont l'utilisateur est membre
+func (r *GroupRepository) GetGroupsByUser(userId string) ([]model.Group, error) {
+	var groups []model.Group
+	tx := r.db.Model(model.Group{}).Scopes(r.tenantScope).Preload("GroupRoles").
+		Where("is_visible = ? and id IN (?)", true,
+			r.db.Model(model.GroupMember{}).Select("group_id").Where("user_id = ?", userId)).
+		Order("name").Find(&groups)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return groups, nil
+}
+
+// CreateGroup crée un nouveau groupe dans l'organisation du repository
+func (r *GroupRepository) CreateGroup(newGroup model.Group) (model.Group, error) {
+	newGroup.CreatedAt = time.Now()
+	if r.scoped {
+		newGroup.OrganizationId = r.organizationId
+	}
+
+	if err := r.db.Model(model.Group{}).Create(&newGroup).Error; err != nil {
+		return model.Group{}, err
+	}
+
+	logger(r.db).Info("Created new group", "group_id", newGroup.Id)
+	return newGroup, nil
+}
+
+// UpdateGroup met à jour le nom et la description d'un groupe
+func (r *GroupRepository) UpdateGroup(group model.Group) (model.Group, error) {
+	group.UpdatedAt = time.Now()
+	tx := r.db.Model(&group).Scopes(r.tenantScope).
+		Where("is_visible = ?", true).Select("name", "describe", "updated_at").Updates(&group)
+	if tx.Error != nil {
+		return model.Group{}, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.Group{}, gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Updated group", "group_id", group.Id)
+	return group, nil
+}
+
+// DeleteGroup supprime un groupe avec ses membres et ses rôles
+func (r *GroupRepository) DeleteGroup(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(r.tenantScope).Where("id = ?", id).Delete(&model.Group{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("group_id = ?", id).Delete(&model.GroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("group_id = ?", id).Delete(&model.GroupRole{}).Error
+	})
+}
+
+// AddMember ajoute un utilisateur à un groupe
+func (r *GroupRepository) AddMember(groupId string, userId string) (model.GroupMember, error) {
+	member := model.GroupMember{
+		GroupId: groupId,
+		UserId:  userId,
+	}
+
+	if err := r.db.Model(model.GroupMember{}).Create(&member).Error; err != nil {
+		return model.GroupMember{}, err
+	}
+
+	logger(r.db).Info("Added user to group", "user_id", userId, "group_id", groupId)
+	return member, nil
+}
+
+// RemoveMember retire un utilisateur d'un groupe
+func (r *GroupRepository) RemoveMember(groupId string, userId string) error {
+	result := r.db.Where("group_id = ? and user_id = ?", groupId, userId).Delete(&model.GroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AddRole attache un rôle à un groupe
+func (r *GroupRepository) AddRole(groupId string, roleId string) (model.GroupRole, error) {
+	groupRole := model.GroupRole{
+		GroupId: groupId,
+		RoleId:  roleId,
+	}
+
+	if err := r.db.Model(model.GroupRole{}).Create(&groupRole).Error; err != nil {
+		return model.GroupRole{}, err
+	}
+
+	logger(r.db).Info("Attached role to group", "role_id", roleId, "group_id", groupId)
+	return groupRole, nil
+}
+
+// RemoveRole détache un rôle d'un groupe
+func (r *GroupRepository) RemoveRole(groupId string, roleId string) error {
+	result := r.db.Where("group_id = ? and role_id = ?", groupId, roleId).Delete(&model.GroupRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type HealthRepository struct {
+	db *gorm.DB
+}
+
+// NewHealthRepository crée une nouvelle instance de HealthRepository
+func NewHealthRepository(db *gorm.DB) *HealthRepository {
+	return &HealthRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *HealthRepository) WithContext(ctx context.Context) *HealthRepository {
+	return &HealthRepository{db: r.db.WithContext(ctx)}
+}
+
+// Ping vérifie qu'une connexion à la base peut être établie
+func (r *HealthRepository) Ping() error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	ctx := r.db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdentityChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityChangeRepository crée une nouvelle instance de IdentityChangeRepository
+func NewIdentityChangeRepository(db *gorm.DB) *IdentityChangeRepository {
+	return &IdentityChangeRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *IdentityChangeRepository) WithContext(ctx context.Context) *IdentityChangeRepository {
+	return &IdentityChangeRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateIdentityChange enregistre une demande de changement et annule les demandes
+// en attente du même utilisateur pour le même champ
+func (r *IdentityChangeRepository) CreateIdentityChange(newChange model.IdentityChange) (model.IdentityChange, error) {
+	currentTime := time.Now()
+	newChange.CreatedAt = currentTime
+	newChange.UpdatedAt = currentTime
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.IdentityChange{}).
+			Where("user_id = ? and field = ? and confirmed_at < ? and cancelled_at < ?",
+				newChange.UserId, newChange.Field, unsetTime, unsetTime).
+			Updates(map[string]interface{}{"cancelled_at": currentTime, "updated_at": currentTime}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(model.IdentityChange{}).Create(&newChange).Error
+	})
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+
+	logger(r.db).Info("Created identity change", "field", newChange.Field, "change_id", newChange.Id, "user_id", newChange.UserId)
+	return newChange, nil
+}
+
+// GetIdentityChangeByTokenHash récupère la demande correspondant à l'empreinte d'un lien
+func (r *IdentityChangeRepository) GetIdentityChangeByTokenHash(tokenHash string) (model.IdentityChange, error) {
+	var change model.IdentityChange
+	tx := r.db.Model(model.IdentityChange{}).First(&change, "token_hash = ?", tokenHash)
+	if tx.Error != nil {
+		return model.IdentityChange{}, tx.Error
+	}
+	return change, nil
+}
+
+// GetPendingIdentityChanges récupère les demandes d'un utilisateur qui peuvent encore être confirmées
+func (r *IdentityChangeRepository) GetPendingIdentityChanges(userId string) ([]model.IdentityChange, error) {
+	var changes []model.IdentityChange
+	tx := r.db.Model(model.IdentityChange{}).
+		Where("user_id = ? and confirmed_at < ? and cancelled_at < ? and expires_at > ?",
+			userId, unsetTime, unsetTime, time.Now()).
+		Order("created_at").Find(&changes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return changes, nil
+}
+
+// ConfirmIdentityChange consomme la demande et applique le nouvel identifiant ; les sessions
+// ouvertes avec l'ancien identifiant sont révoquées. Le lien n'est utilisable qu'une fois.
+func (r *IdentityChangeRepository) ConfirmIdentityChange(change model.IdentityChange) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		confirmed := tx.Model(model.IdentityChange{}).
+			Where("id = ? and confirmed_at < ? and cancelled_at < ? and expires_at > ?",
+				change.Id, unsetTime, unsetTime, currentTime).
+			Updates(map[string]interface{}{"confirmed_at": currentTime, "updated_at": currentTime})
+		if confirmed.Error != nil {
+			return confirmed.Error
+		}
+		if confirmed.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		updated := tx.Model(model.User{}).
+			Where("id = ? and is_visible = ?", change.UserId, true).
+			Updates(map[string]interface{}{
+				change.Field:          change.NewValue,
+				"sessions_revoked_at": currentTime,
+				"updated_at":          currentTime,
+			})
+		if updated.Error != nil {
+			return updated.Error
+		}
+		if updated.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := enqueueUserEvent(tx, model.EventUserUpdated, change.UserId, ""); err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, change.UserId, currentTime)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Confirmed identity change", "field", change.Field, "change_id", change.Id, "user_id", change.UserId)
+	return nil
+}
+
+// CancelIdentityChange annule une demande en attente, par exemple quand son lien n'a pas pu être envoyé
+func (r *IdentityChangeRepository) CancelIdentityChange(id string) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.IdentityChange{}).
+		Where("id = ? and confirmed_at < ? and cancelled_at < ?", id, unsetTime, unsetTime).
+		Updates(map[string]interface{}{"cancelled_at": currentTime, "updated_at": currentTime})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	logger(r.db).Info("Cancelled identity change", "change_id", id)
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ImpersonationRepository struct {
+	db *gorm.DB
+}
+
+// NewImpersonationRepository crée une nouvelle instance de ImpersonationRepository
+func NewImpersonationRepository(db *gorm.DB) *ImpersonationRepository {
+	return &ImpersonationRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *ImpersonationRepository) WithContext(ctx context.Context) *ImpersonationRepository {
+	return &ImpersonationRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateImpersonation enregistre le début d'une session d'impersonation
+func (r *ImpersonationRepository) CreateImpersonation(newImpersonation model.Impersonation) (model.Impersonation, error) {
+	currentTime := time.Now()
+	newImpersonation.CreatedAt = currentTime
+	newImpersonation.UpdatedAt = currentTime
+
+	if err := r.db.Model(model.Impersonation{}).Create(&newImpersonation).Error; err != nil {
+		return model.Impersonation{}, err
+	}
+
+	logger(r.db).Info("Admin started impersonating user", "actor_id", newImpersonation.ActorId, "target_user_id", newImpersonation.TargetUserId, "impersonation_id", newImpersonation.Id)
+	return newImpersonation, nil
+}
+
+// GetImpersonationById récupère une session d'impersonation
+func (r *ImpersonationRepository) GetImpersonationById(id string) (model.Impersonation, error) {
+	var impersonation model.Impersonation
+	tx := r.db.Model(model.Impersonation{}).First(&impersonation, "id = ?", id)
+	if tx.Error != nil {
+		return model.Impersonation{}, tx.Error
+	}
+	return impersonation, nil
+}
+
+// GetImpersonationsByOrganization récupère les sessions d'une organisation, les plus récentes en premier
+func (r *ImpersonationRepository) GetImpersonationsByOrganization(organizationId string) ([]model.Impersonation, error) {
+	var impersonations []model.Impersonation
+	tx := r.db.Model(model.Impersonation{}).
+		Where("organization_id = ?", organizationId).Order("created_at desc").Find(&impersonations)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return impersonations, nil
+}
+
+// GetImpersonationsByTarget récupère les sessions durant lesquelles un utilisateur a été impersonné
+func (r *ImpersonationRepository) GetImpersonationsByTarget(userId string) ([]model.Impersonation, error) {
+	var impersonations []model.Impersonation
+	tx := r.db.Model(model.Impersonation{}).Preload("Actions").
+		Where("target_user_id = ?", userId).Order("created_at desc").Find(&impersonations)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return impersonations, nil
+}
+
+// EndImpersonation marque une session comme terminée
+func (r *ImpersonationRepository) EndImpersonation(id string, endedBy string) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.Impersonation{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"ended_at": currentTime, "ended_by": endedBy, "updated_at": currentTime})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Ended impersonation session", "impersonation_id", id, "ended_by", endedBy)
+	return nil
+}
+
+// CreateAction enregistre une requête effectuée pendant une session d'impersonation
+func (r *ImpersonationRepository) CreateAction(newAction model.ImpersonationAction) error {
+	currentTime := time.Now()
+	newAction.CreatedAt = currentTime
+	newAction.UpdatedAt = currentTime
+	return r.db.Model(model.ImpersonationAction{}).Create(&newAction).Error
+}
+
+// GetActions récupère les requêtes effectuées pendant une session, dans l'ordre chronologique
+func (r *ImpersonationRepository) GetActions(impersonationId string) ([]model.ImpersonationAction, error) {
+	var actions []model.ImpersonationAction
+	tx := r.db.Model(model.ImpersonationAction{}).
+		Where("impersonation_id = ?", impersonationId).Order("created_at").Find(&actions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return actions, nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository crée une nouvelle instance de InvitationRepository
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *InvitationRepository) WithContext(ctx context.Context) *InvitationRepository {
+	return &InvitationRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateInvitation enregistre une invitation et révoque les invitations en attente du même utilisateur
+func (r *InvitationRepository) CreateInvitation(newInvitation model.Invitation) (model.Invitation, error) {
+	currentTime := time.Now()
+	newInvitation.CreatedAt = currentTime
+	newInvitation.UpdatedAt = currentTime
+	newInvitation.LastSentAt = currentTime
+	newInvitation.SentCount = 1
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.Invitation{}).
+			Where("user_id = ? and accepted_at < ? and revoked_at < ?", newInvitation.UserId, unsetTime, unsetTime).
+			Updates(map[string]interface{}{"revoked_at": currentTime, "updated_at": currentTime}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(model.Invitation{}).Create(&newInvitation).Error
+	})
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	logger(r.db).Info("Created invitation", "invitation_id", newInvitation.Id, "user_id", newInvitation.UserId)
+	return newInvitation, nil
+}
+
+// GetInvitationById récupère une invitation d'une organisation
+func (r *InvitationRepository) GetInvitationById(id string, organizationId string) (model.Invitation, error) {
+	var invitation model.Invitation
+	tx := r.db.Model(model.Invitation{}).
+		First(&invitation, "id = ? and organization_id = ?", id, organizationId)
+	if tx.Error != nil {
+		return model.Invitation{}, tx.Error
+	}
+	return invitation, nil
+}
+
+// GetInvitationByTokenHash récupère l'invitation correspondant à l'empreinte d'un lien
+func (r *InvitationRepository) GetInvitationByTokenHash(tokenHash string) (model.Invitation, error) {
+	var invitation model.Invitation
+	tx := r.db.Model(model.Invitation{}).First(&invitation, "token_hash = ?", tokenHash)
+	if tx.Error != nil {
+		return model.Invitation{}, tx.Error
+	}
+	return invitation, nil
+}
+
+// GetPendingInvitations récupère les invitations ni acceptées ni révoquées d'une organisation,
+// y compris celles expirées qui peuvent encore être renvoyées
+func (r *InvitationRepository) GetPendingInvitations(organizationId string) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+	tx := r.db.Model(model.Invitation{}).
+		Where("organization_id = ? and accepted_at < ? and revoked_at < ?", organizationId, unsetTime, unsetTime).
+		Order("created_at desc").Find(&invitations)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return invitations, nil
+}
+
+// RenewInvitation remplace le lien d'une invitation en attente ; l'ancien lien devient invalide
+func (r *InvitationRepository) RenewInvitation(id string, tokenHash string, expiresAt time.Time) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.Invitation{}).
+		Where("id = ? and accepted_at < ? and revoked_at < ?", id, unsetTime, unsetTime).
+		Updates(map[string]interface{}{
+			"token_hash":   tokenHash,
+			"expires_at":   expiresAt,
+			"sent_count":   gorm.Expr("sent_count + 1"),
+			"last_sent_at": currentTime,
+			"updated_at":   currentTime,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Renewed invitation", "invitation_id", id)
+	return nil
+}
+
+// RevokeInvitation révoque une invitation en attente
+func (r *InvitationRepository) RevokeInvitation(id string) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.Invitation{}).
+		Where("id = ? and accepted_at < ? and revoked_at < ?", id, unsetTime, unsetTime).
+		Updates(map[string]interface{}{"revoked_at": currentTime, "updated_at": currentTime})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Revoked invitation", "invitation_id", id)
+	return nil
+}
+
+// AcceptInvitation consomme l'invitation, enregistre le mot de passe choisi par l'utilisateur
+// et active son compte ; les sessions ouvertes avec l'ancien mot de passe sont révoquées.
+// Le lien n'est utilisable qu'une fois : une invitation déjà acceptée, révoquée ou expirée est refusée,
+// de même que si l'état du compte n'est plus status, celui contrôlé par l'appelant.
+func (r *InvitationRepository) AcceptInvitation(invitation model.Invitation, status string, passwordHash string) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		accepted := tx.Model(model.Invitation{}).
+			Where("id = ? and accepted_at < ? and revoked_at < ? and expires_at > ?",
+				invitation.Id, unsetTime, unsetTime, currentTime).
+			Updates(map[string]interface{}{"accepted_at": currentTime, "updated_at": currentTime})
+		if accepted.Error != nil {
+			return accepted.Error
+		}
+		if accepted.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// L'activation d'un compte invité est une mise à jour, le nouveau mot de passe d'un membre une réinitialisation
+		eventType := model.EventUserPasswordReset
+		if status == "pending_activation" {
+			eventType = model.EventUserUpdated
+		}
+
+		updated := tx.Model(model.User{}).
+			Where("id = ? and is_visible = ? and status = ?", invitation.UserId, true, status).
+			Updates(map[string]interface{}{
+				"password":              passwordHash,
+				"status":                "active",
+				"status_reason":         "",
+				"status_changed_at":     currentTime,
+				"locked_until":          time.Time{},
+				"failed_login_attempts": 0,
+				"is_available":          true,
+				"sessions_revoked_at":   currentTime,
+				"updated_at":            currentTime,
+			})
+		if updated.Error != nil {
+			return updated.Error
+		}
+		if updated.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := enqueueUserEvent(tx, eventType, invitation.UserId, invitation.OrganizationId); err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, invitation.UserId, currentTime)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Accepted invitation", "invitation_id", invitation.Id, "user_id", invitation.UserId)
+	return nil
+}
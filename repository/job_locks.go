@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// JobLockRepository prend les verrous consultatifs qui réservent une tâche de fond à une seule instance
+type JobLockRepository struct {
+	db *gorm.DB
+}
+
+// NewJobLockRepository crée une nouvelle instance de JobLockRepository
+func NewJobLockRepository(db *gorm.DB) *JobLockRepository {
+	return &JobLockRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *JobLockRepository) WithContext(ctx context.Context) *JobLockRepository {
+	return &JobLockRepository{db: r.db.WithContext(ctx)}
+}
+
+// RunLocked exécute run si le verrou name est libre et retourne false, sans exécuter run, si une autre
+// instance le détient. Le verrou est pris sans attente et attaché à une connexion conservée jusqu'à
+// la fin de run. SQLite n'a pas de verrou consultatif ; sa base n'est pas partagée entre plusieurs instances.
+func (r *JobLockRepository) RunLocked(name string, run func() error) (bool, error) {
+	acquired := false
+	err := r.db.Connection(func(conn *gorm.DB) error {
+		switch conn.Dialector.Name() {
+		case "postgres":
+			if err := conn.Raw("SELECT pg_try_advisory_lock(hashtext(?))", name).Scan(&acquired).Error; err != nil {
+				return err
+			}
+			if !acquired {
+				return nil
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(hashtext(?))", name)
+		case "mysql":
+			var result *int
+			if err := conn.Raw("SELECT GET_LOCK(?, 0)", name).Scan(&result).Error; err != nil {
+				return err
+			}
+			acquired = result != nil && *result == 1
+			if !acquired {
+				return nil
+			}
+			defer conn.Exec("SELECT RELEASE_LOCK(?)", name)
+		default:
+			acquired = true
+		}
+		return run()
+	})
+	return acquired, err
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"auth/logging"
+
+	slog "github.com/sagikazarmark/slog-shim"
+	"gorm.io/gorm"
+)
+
+// logger retourne le logger de la requête portée par db (WithContext), à défaut le logger du service
+func logger(db *gorm.DB) *slog.Logger {
+	return logging.FromContext(db.Statement.Context)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginEventRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginEventRepository crée une nouvelle instance de LoginEventRepository
+func NewLoginEventRepository(db *gorm.DB) *LoginEventRepository {
+	return &LoginEventRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *LoginEventRepository) WithContext(ctx context.Context) *LoginEventRepository {
+	return &LoginEventRepository{db: r.db.WithContext(ctx)}
+}
+
+// LoginFootprint indique ce que l'historique d'un utilisateur connaît d'une nouvelle connexion
+type LoginFootprint struct {
+	HasHistory   bool
+	KnownDevice  bool
+	KnownIpRange bool
+}
+
+// CreateLoginEvent enregistre une connexion réussie
+func (r *LoginEventRepository) CreateLoginEvent(newEvent model.LoginEvent) (model.LoginEvent, error) {
+	currentTime := time.Now()
+	newEvent.CreatedAt = currentTime
+	newEvent.UpdatedAt = currentTime
+
+	if err := r.db.Model(model.LoginEvent{}).Create(&newEvent).Error; err != nil {
+		return model.LoginEvent{}, err
+	}
+
+	logger(r.db).Info("Recorded login", "login_id", newEvent.Id, "user_id", newEvent.UserId, "ip_address", newEvent.IpAddress)
+	return newEvent, nil
+}
+
+// GetLoginFootprint cherche l'appareil et la plage d'adresses IP dans l'historique de l'utilisateur.
+// Les connexions signalées comme frauduleuses ne sont pas prises en compte.
+func (r *LoginEventRepository) GetLoginFootprint(userId string, fingerprint string, ipRange string) (LoginFootprint, error) {
+	var footprint LoginFootprint
+	history := func() *gorm.DB {
+		return r.db.Model(model.LoginEvent{}).Where("user_id = ? and reported_at < ?", userId, unsetTime)
+	}
+
+	var count int64
+	if err := history().Count(&count).Error; err != nil {
+		return LoginFootprint{}, err
+	}
+	if count == 0 {
+		return footprint, nil
+	}
+	footprint.HasHistory = true
+
+	if err := history().Where("device_fingerprint = ?", fingerprint).Count(&count).Error; err != nil {
+		return LoginFootprint{}, err
+	}
+	footprint.KnownDevice = count > 0
+
+	if err := history().Where("ip_range = ?", ipRange).Count(&count).Error; err != nil {
+		return LoginFootprint{}, err
+	}
+	footprint.KnownIpRange = count > 0
+	return footprint, nil
+}
+
+// GetLoginEventsByUser récupère l'historique des connexions d'un utilisateur, les plus récentes en premier
+func (r *LoginEventRepository) GetLoginEventsByUser(userId string, limit int, offset int) ([]model.LoginEvent, int64, error) {
+	tx := r.db.Model(model.LoginEvent{}).Where("user_id = ?", userId)
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []model.LoginEvent
+	if err := tx.Order("created_at desc").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetAllLoginEventsByUser récupère tout l'historique des connexions d'un utilisateur, les plus récentes en premier
+func (r *LoginEventRepository) GetAllLoginEventsByUser(userId string) ([]model.LoginEvent, error) {
+	var events []model.LoginEvent
+	tx := r.db.Model(model.LoginEvent{}).Where("user_id = ?", userId).Order("created_at desc").Find(&events)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return events, nil
+}
+
+// GetLoginEventByReportHash récupère la connexion correspondant à l'empreinte d'un lien "ce n'était pas moi"
+func (r *LoginEventRepository) GetLoginEventByReportHash(tokenHash string) (model.LoginEvent, error) {
+	var event model.LoginEvent
+	tx := r.db.Model(model.LoginEvent{}).First(&event, "report_token_hash = ?", tokenHash)
+	if tx.Error != nil {
+		return model.LoginEvent{}, tx.Error
+	}
+	return event, nil
+}
+
+// ReportLoginEvent signale une connexion frauduleuse : le mot de passe est remplacé, un mot de passe
+// expiré est renouvelé et toutes les sessions de l'utilisateur sont révoquées.
+// Le lien n'est utilisable qu'une fois et avant son expiration.
+func (r *LoginEventRepository) ReportLoginEvent(event model.LoginEvent, passwordHash string) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reported := tx.Model(model.LoginEvent{}).
+			Where("id = ? and reported_at < ? and report_expires_at > ?", event.Id, unsetTime, currentTime).
+			Updates(map[string]interface{}{"reported_at": currentTime, "updated_at": currentTime})
+		if reported.Error != nil {
+			return reported.Error
+		}
+		if reported.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		updated := tx.Model(model.User{}).
+			Where("id = ? and is_visible = ?", event.UserId, true).
+			Updates(map[string]interface{}{
+				"password":              passwordHash,
+				"failed_login_attempts": 0,
+				"sessions_revoked_at":   currentTime,
+				"updated_at":            currentTime,
+			})
+		if updated.Error != nil {
+			return updated.Error
+		}
+		if updated.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		renewed := tx.Model(model.User{}).
+			Where("id = ? and status = ?", event.UserId, "password_expired").
+			Updates(map[string]interface{}{
+				"status":            "active",
+				"status_reason":     "",
+				"status_changed_at": currentTime,
+				"is_available":      true,
+			})
+		if renewed.Error != nil {
+			return renewed.Error
+		}
+		if err := enqueueUserEvent(tx, model.EventUserPasswordReset, event.UserId, ""); err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, event.UserId, currentTime)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Reported login, sessions revoked", "login_id", event.Id, "user_id", event.UserId)
+	return nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository crée une nouvelle instance de OrganizationRepository
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *OrganizationRepository) WithContext(ctx context.Context) *OrganizationRepository {
+	return &OrganizationRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateOrganization crée une nouvelle organisation dans la base de données
+func (r *OrganizationRepository) CreateOrganization(newOrganization model.Organization) (model.Organization, error) {
+	newOrganization.CreatedAt = time.Now()
+
+	if err := r.db.Model(model.Organization{}).Create(&newOrganization).Error; err != nil {
+		return model.Organization{}, err
+	}
+
+	logger(r.db).Info("Created new organization", "organization_id", newOrganization.Id)
+	return newOrganization, nil
+}
+
+// GetAllOrganizations récupère toutes les organisations visibles
+func (r *OrganizationRepository) GetAllOrganizations() ([]model.Organization, error) {
+	var organizations []model.Organization
+	tx := r.db.Model(model.Organization{}).
+		Where("is_visible = ?", true).Order("created_at").Find(&organizations)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return organizations, nil
+}
+
+// GetOrganizationById récupère une organisation basée sur l'ID
+func (r *OrganizationRepository) GetOrganizationById(id string) (model.Organization, error) {
+	var organization model.Organization
+	tx := r.db.Model(model.Organization{}).
+		First(&organization, "id = ? and is_visible = ?", id, true)
+	if tx.Error != nil {
+		return model.Organization{}, tx.Error
+	}
+	return organization, nil
+}
+
+// GetOrganizationBySlug récupère une organisation par son slug
+func (r *OrganizationRepository) GetOrganizationBySlug(slug string) (model.Organization, error) {
+	var organization model.Organization
+	tx := r.db.Model(model.Organization{}).
+		First(&organization, "slug = ? and is_visible = ?", slug, true)
+	if tx.Error != nil {
+		return model.Organization{}, tx.Error
+	}
+	return organization, nil
+}
+
+// GetOrganizationsByUser récupère les organisations dont l'utilisateur est membre
+func (r *OrganizationRepository) GetOrganizationsByUser(userId string) ([]model.Organization, error) {
+	var organizations []model.Organization
+	tx := r.db.Model(model.Organization{}).
+		Where("is_visible = ? and id IN (?)", true,
+			r.db.Model(model.Membership{}).Select("organization_id").Where("user_id = ?", userId)).
+		Order("created_at").Find(&organizations)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return organizations, nil
+}
+
+// GetMembership récupère l'adhésion d'un utilisateur à une organisation avec ses rôles
+func (r *OrganizationRepository) GetMembership(userId string, organizationId string) (model.Membership, error) {
+	var membership model.Membership
+	tx := r.db.Model(model.Membership{}).Preload("MembershipRoles").
+		First(&membership, "user_id = ? and organization_id = ?", userId, organizationId)
+	if tx.Error != nil {
+		return model.Membership{}, tx.Error
+	}
+	return membership, nil
+}
+
+// GetMembershipsByUser récupère toutes les adhésions d'un utilisateur, la plus ancienne en premier
+func (r *OrganizationRepository) GetMembershipsByUser(userId string) ([]model.Membership, error) {
+	var memberships []model.Membership
+	tx := r.db.Model(model.Membership{}).Preload("MembershipRoles").
+		Where("user_id = ?", userId).Order("created_at").Find(&memberships)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return memberships, nil
+}
+
+// GetMembershipsByOrganization récupère tous les membres d'une organisation
+func (r *OrganizationRepository) GetMembershipsByOrganization(organizationId string) ([]model.Membership, error) {
+	var memberships []model.Membership
+	tx := r.db.Model(model.Membership{}).Preload("MembershipRoles").
+		Where("organization_id = ?", organizationId).Order("created_at").Find(&memberships)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return memberships, nil
+}
+
+// CreateMembership ajoute un utilisateur à une organisation avec les rôles fournis
+func (r *OrganizationRepository) CreateMembership(userId string, organizationId string, roleIds []string) (model.Membership, error) {
+	membership := model.Membership{
+		UserId:         userId,
+		OrganizationId: organizationId,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model.Membership{}).Create(&membership).Error; err != nil {
+			return err
+		}
+
+		membershipRoles, err := createMembershipRoles(tx, membership.Id, roleIds)
+		if err != nil {
+			return err
+		}
+		membership.MembershipRoles = membershipRoles
+		return nil
+	})
+	if err != nil {
+		return model.Membership{}, err
+	}
+
+	logger(r.db).Info("Added user to organization", "user_id", userId, "organization_id", organizationId)
+	return membership, nil
+}
+
+// SetMembershipRoles remplace les rôles d'une adhésion et publie le changement de rôle du membre
+func (r *OrganizationRepository) SetMembershipRoles(membershipId string, roleIds []string) ([]model.MembershipRole, error) {
+	var membershipRoles []model.MembershipRole
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var membership model.Membership
+		if err := tx.First(&membership, "id = ?", membershipId).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("membership_id = ?", membershipId).Delete(&model.MembershipRole{}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		membershipRoles, err = createMembershipRoles(tx, membershipId, roleIds)
+		if err != nil {
+			return err
+		}
+		return enqueueUserEvent(tx, model.EventUserRoleChanged, membership.UserId, membership.OrganizationId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger(r.db).Info("Updated roles of membership", "membership_id", membershipId)
+	return membershipRoles, nil
+}
+
+// DeleteMembership retire un utilisateur d'une organisation
+func (r *OrganizationRepository) DeleteMembership(membershipId string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("membership_id = ?", membershipId).Delete(&model.MembershipRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", membershipId).Delete(&model.Membership{}).Error
+	})
+}
+
+func createMembershipRoles(tx *gorm.DB, membershipId string, roleIds []string) ([]model.MembershipRole, error) {
+	membershipRoles := []model.MembershipRole{}
+	for i := 0; i < len(roleIds); i++ {
+		membershipRoles = append(membershipRoles, model.MembershipRole{
+			MembershipId: membershipId,
+			RoleId:       roleIds[i],
+		})
+	}
+
+	if len(membershipRoles) == 0 {
+		return membershipRoles, nil
+	}
+
+	if err := tx.Model(model.MembershipRole{}).Create(&membershipRoles).Error; err != nil {
+		return nil, err
+	}
+	return membershipRoles, nil
+}
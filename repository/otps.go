@@ -2,9 +2,8 @@ package repository
 
 import (
 	"auth/model"
-	"fmt"
+	"context"
 	"gorm.io/gorm"
-	"log"
 	"time"
 )
 
@@ -19,6 +18,11 @@ func NewOtpRepository(db *gorm.DB) *OtpRepository {
 	}
 }
 
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *OtpRepository) WithContext(ctx context.Context) *OtpRepository {
+	return &OtpRepository{db: r.db.WithContext(ctx)}
+}
+
 // CreateUser crée un nouvel utilisateur dans la base de données
 func (r *OtpRepository) CreateOtp(newOtp model.Otp) (model.Otp, error) {
 	// Générez la date actuelle pour les champs créés et modifiés
@@ -31,8 +35,7 @@ func (r *OtpRepository) CreateOtp(newOtp model.Otp) (model.Otp, error) {
 		return model.Otp{}, err
 	}
 
-	msg := fmt.Sprintf("Created new otp: %+v", newOtp)
-	log.Println(msg)
+	logger(r.db).Info("Created new otp", "otp_id", newOtp.Id)
 	return newOtp, nil
 }
 
@@ -56,8 +59,48 @@ func (r *OtpRepository) UpdateOtp(otp model.Otp) (model.Otp, error) {
 		return model.Otp{}, tx.Error
 	}
 
-	msg := fmt.Sprintf("Updated otp with ID %v", otp.Id)
-	log.Println(msg)
+	logger(r.db).Info("Updated otp", "otp_id", otp.Id)
 
 	return otp, nil
 }
+
+// GetOtpsByUser récupère les codes otp générés pour un utilisateur, les plus récents en premier
+func (r *OtpRepository) GetOtpsByUser(userId string) ([]model.Otp, error) {
+	var otps []model.Otp
+	tx := r.db.Model(model.Otp{}).
+		Where("user_id = ?", userId).Order("created_at desc").Find(&otps)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return otps, nil
+}
+
+// MarkOtpUsed invalide un code otp. Un code déjà utilisé n'est pas mis à jour : une seule
+// vérification concurrente peut l'utiliser.
+func (r *OtpRepository) MarkOtpUsed(id string) error {
+	tx := r.db.Model(model.Otp{}).Where("id = ? and is_used = ?", id, false).
+		Updates(map[string]interface{}{"is_used": true, "updated_at": time.Now()})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Used otp", "otp_id", id)
+	return nil
+}
+
+// IncrementOtpAttempts compte une saisie erronée du code et retourne le nombre d'essais
+func (r *OtpRepository) IncrementOtpAttempts(id string) (int, error) {
+	var attempts int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.Otp{}).Where("id = ?", id).
+			UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(model.Otp{}).Where("id = ?", id).Select("attempts").Scan(&attempts).Error
+	})
+	return attempts, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository crée une nouvelle instance de OutboxRepository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *OutboxRepository) WithContext(ctx context.Context) *OutboxRepository {
+	return &OutboxRepository{db: r.db.WithContext(ctx)}
+}
+
+// GetPendingEvents récupère les évènements pas encore distribués, dans l'ordre où ils ont eu lieu
+func (r *OutboxRepository) GetPendingEvents(limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	tx := r.db.Model(model.OutboxEvent{}).
+		Where("dispatched_at < ?", unsetTime).Order("occurred_at").Limit(limit).Find(&events)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return events, nil
+}
+
+// DispatchEvent crée les envois d'un évènement et le marque comme distribué, dans une même transaction.
+// Un évènement déjà distribué par une autre instance est ignoré (gorm.ErrRecordNotFound).
+func (r *OutboxRepository) DispatchEvent(event model.OutboxEvent, deliveries []model.WebhookDelivery) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		dispatched := tx.Model(model.OutboxEvent{}).
+			Where("id = ? and dispatched_at < ?", event.Id, unsetTime).
+			Updates(map[string]interface{}{"dispatched_at": currentTime, "updated_at": currentTime})
+		if dispatched.Error != nil {
+			return dispatched.Error
+		}
+		if dispatched.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		for i := 0; i < len(deliveries); i++ {
+			deliveries[i].CreatedAt = currentTime
+			deliveries[i].UpdatedAt = currentTime
+			if err := tx.Model(model.WebhookDelivery{}).Create(&deliveries[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Dispatched event", "event_type", event.EventType, "event_id", event.Id, "webhooks", len(deliveries))
+	return nil
+}
+
+// enqueueUserEvent enregistre un évènement sur un utilisateur dans la transaction de la modification :
+// si la transaction est annulée, l'évènement n'est jamais publié
+func enqueueUserEvent(tx *gorm.DB, eventType string, userId string, organizationId string) error {
+	var user model.User
+	if err := tx.Model(model.User{}).First(&user, "id = ?", userId).Error; err != nil {
+		return err
+	}
+
+	var role model.Role
+	if user.RoleId != "" {
+		if err := tx.Model(model.Role{}).Limit(1).Find(&role, "id = ?", user.RoleId).Error; err != nil {
+			return err
+		}
+	}
+
+	payload, err := json.Marshal(model.UserEventData{
+		Id:          user.Id,
+		Email:       user.Email,
+		Username:    user.Username,
+		Name:        user.Name,
+		Sername:     user.Sername,
+		Role:        role.Name,
+		Status:      user.Status,
+		IsAvailable: user.IsAvailable,
+	})
+	if err != nil {
+		return err
+	}
+
+	currentTime := time.Now()
+	return tx.Model(model.OutboxEvent{}).Create(&model.OutboxEvent{
+		AbstractModel:  model.AbstractModel{CreatedAt: currentTime, UpdatedAt: currentTime},
+		EventType:      eventType,
+		OrganizationId: organizationId,
+		SubjectId:      user.Id,
+		Payload:        string(payload),
+		OccurredAt:     currentTime,
+	}).Error
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PolicyRepository struct {
+	db             *gorm.DB
+	scoped         bool
+	organizationId string
+}
+
+// NewPolicyRepository crée une nouvelle instance de PolicyRepository
+func NewPolicyRepository(db *gorm.DB) *PolicyRepository {
+	return &PolicyRepository{
+		db: db,
+	}
+}
+
+// ForOrganization retourne une copie du repository limitée aux politiques de l'organisation fournie
+func (r *PolicyRepository) ForOrganization(organizationId string) *PolicyRepository {
+	return &PolicyRepository{
+		db:             r.db,
+		scoped:         true,
+		organizationId: organizationId,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *PolicyRepository) WithContext(ctx context.Context) *PolicyRepository {
+	return &PolicyRepository{
+		db:             r.db.WithContext(ctx),
+		scoped:         r.scoped,
+		organizationId: r.organizationId,
+	}
+}
+
+func (r *PolicyRepository) tenantScope(db *gorm.DB) *gorm.DB {
+	if !r.scoped {
+		return db
+	}
+	return db.Where("organization_id = ?", r.organizationId)
+}
+
+// GetActivePolicies récupère la version active de chaque politique
+func (r *PolicyRepository) GetActivePolicies() ([]model.Policy, error) {
+	var policies []model.Policy
+	tx := r.db.Model(model.Policy{}).Scopes(r.tenantScope).
+		Where("is_active = ?", true).Order("name").Find(&policies)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return policies, nil
+}
+
+// GetActivePolicyByName récupère la version active d'une politique
+func (r *PolicyRepository) GetActivePolicyByName(name string) (model.Policy, error) {
+	var policy model.Policy
+	tx := r.db.Model(model.Policy{}).Scopes(r.tenantScope).
+		First(&policy, "name = ? and is_active = ?", name, true)
+	if tx.Error != nil {
+		return model.Policy{}, tx.Error
+	}
+	return policy, nil
+}
+
+// GetPolicyVersions récupère toutes les versions d'une politique, la plus récente en premier
+func (r *PolicyRepository) GetPolicyVersions(name string) ([]model.Policy, error) {
+	var policies []model.Policy
+	tx := r.db.Model(model.Policy{}).Scopes(r.tenantScope).
+		Where("name = ?", name).Order("version desc").Find(&policies)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return policies, nil
+}
+
+// CreatePolicyVersion enregistre une nouvelle version active d'une politique dans l'organisation
+// du repository. Les versions précédentes sont conservées mais désactivées.
+func (r *PolicyRepository) CreatePolicyVersion(newPolicy model.Policy) (model.Policy, error) {
+	if r.scoped {
+		newPolicy.OrganizationId = r.organizationId
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var lastVersion int
+		err := tx.Model(model.Policy{}).Scopes(r.tenantScope).Where("name = ?", newPolicy.Name).
+			Select("COALESCE(MAX(version), 0)").Scan(&lastVersion).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(model.Policy{}).Scopes(r.tenantScope).Where("name = ? and is_active = ?", newPolicy.Name, true).
+			Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+
+		newPolicy.Version = lastVersion + 1
+		newPolicy.IsActive = true
+		newPolicy.CreatedAt = time.Now()
+		return tx.Model(model.Policy{}).Create(&newPolicy).Error
+	})
+	if err != nil {
+		return model.Policy{}, err
+	}
+
+	logger(r.db).Info("Created policy", "organization_id", newPolicy.OrganizationId, "name", newPolicy.Name, "version", newPolicy.Version)
+	return newPolicy, nil
+}
+
+// DeactivatePolicy désactive la version active d'une politique
+func (r *PolicyRepository) DeactivatePolicy(name string) error {
+	tx := r.db.Model(model.Policy{}).Scopes(r.tenantScope).Where("name = ? and is_active = ?", name, true).
+		Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Deactivated policy", "organization_id", r.organizationId, "name", name)
+	return nil
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"auth/model"
+	"context"
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -16,6 +19,11 @@ func NewRoleRepository(db *gorm.DB) *RoleRepository {
 	}
 }
 
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *RoleRepository) WithContext(ctx context.Context) *RoleRepository {
+	return &RoleRepository{db: r.db.WithContext(ctx)}
+}
+
 // GetRoleById récupère un role basé sur l'ID depuis la base de données
 func (r *RoleRepository) GetRoleById(id string) (model.Role, error) {
 	var role model.Role
@@ -32,3 +40,48 @@ func (r *RoleRepository) GetRoleByName(roleName string) (model.Role, error) {
 	}
 	return role, nil
 }
+
+// GetRolesByIds récupère les rôles correspondant aux IDs fournis
+func (r *RoleRepository) GetRolesByIds(ids []string) ([]model.Role, error) {
+	var roles []model.Role
+	if len(ids) == 0 {
+		return roles, nil
+	}
+	if err := r.db.Model(model.Role{}).Where("id IN ?", ids).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetRolesByNames récupère les rôles correspondant aux noms fournis
+func (r *RoleRepository) GetRolesByNames(names []string) ([]model.Role, error) {
+	var roles []model.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+	if err := r.db.Model(model.Role{}).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetAllRoles récupère tous les rôles, triés par nom
+func (r *RoleRepository) GetAllRoles() ([]model.Role, error) {
+	var roles []model.Role
+	if err := r.db.Model(model.Role{}).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// UpdateRoleTwoFactor impose ou non la double authentification aux utilisateurs d'un rôle
+func (r *RoleRepository) UpdateRoleTwoFactor(id string, required bool) error {
+	tx := r.db.Model(model.Role{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"require_two_factor": required, "updated_at": time.Now()})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	logger(r.db).Info("Updated two factor requirement of role", "role_id", id, "required", required)
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository crée une nouvelle instance de SessionRepository
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *SessionRepository) WithContext(ctx context.Context) *SessionRepository {
+	return &SessionRepository{db: r.db.WithContext(ctx)}
+}
+
+// CreateSession enregistre une nouvelle connexion
+func (r *SessionRepository) CreateSession(newSession model.Session) (model.Session, error) {
+	currentTime := time.Now()
+	newSession.CreatedAt = currentTime
+	newSession.UpdatedAt = currentTime
+	newSession.LastSeenAt = currentTime
+
+	if err := r.db.Model(model.Session{}).Create(&newSession).Error; err != nil {
+		return model.Session{}, err
+	}
+
+	logger(r.db).Info("Created session", "session_id", newSession.Id, "user_id", newSession.UserId, "ip_address", newSession.IpAddress)
+	return newSession, nil
+}
+
+// GetSessionById récupère une session
+func (r *SessionRepository) GetSessionById(id string) (model.Session, error) {
+	var session model.Session
+	tx := r.db.Model(model.Session{}).First(&session, "id = ?", id)
+	if tx.Error != nil {
+		return model.Session{}, tx.Error
+	}
+	return session, nil
+}
+
+// GetActiveSessionsByUser récupère les sessions non révoquées et non expirées d'un utilisateur,
+// les plus récemment utilisées en premier
+func (r *SessionRepository) GetActiveSessionsByUser(userId string) ([]model.Session, error) {
+	var sessions []model.Session
+	tx := r.db.Model(model.Session{}).
+		Where("user_id = ? and revoked_at < ? and expires_at > ?", userId, unsetTime, time.Now()).
+		Order("last_seen_at desc").Find(&sessions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return sessions, nil
+}
+
+// GetSessionsByUser récupère toutes les sessions d'un utilisateur, les plus récentes en premier
+func (r *SessionRepository) GetSessionsByUser(userId string) ([]model.Session, error) {
+	var sessions []model.Session
+	tx := r.db.Model(model.Session{}).Where("user_id = ?", userId).Order("created_at desc").Find(&sessions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return sessions, nil
+}
+
+// RotateSession remplace le refresh token d'une session active et prolonge la session.
+// La mise à jour est conditionnée à l'empreinte du token présenté : un ancien token est refusé.
+func (r *SessionRepository) RotateSession(session model.Session, tokenHash string, expiresAt time.Time) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.Session{}).
+		Where("id = ? and refresh_token_hash = ? and revoked_at < ?", session.Id, session.RefreshTokenHash, unsetTime).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": tokenHash,
+			"expires_at":         expiresAt,
+			"last_seen_at":       currentTime,
+			"updated_at":         currentTime,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchSession met à jour la date de dernière activité, au plus une fois par intervalle
+func (r *SessionRepository) TouchSession(id string, interval time.Duration) error {
+	currentTime := time.Now()
+	return r.db.Model(model.Session{}).
+		Where("id = ? and last_seen_at < ?", id, currentTime.Add(-interval)).
+		UpdateColumn("last_seen_at", currentTime).Error
+}
+
+// RevokeSession révoque une session active d'un utilisateur
+func (r *SessionRepository) RevokeSession(id string, userId string) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.Session{}).
+		Where("id = ? and user_id = ? and revoked_at < ?", id, userId, unsetTime).
+		Updates(map[string]interface{}{"revoked_at": currentTime, "updated_at": currentTime})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Revoked session", "session_id", id, "user_id", userId)
+	return nil
+}
+
+// revokeUserSessions révoque toutes les sessions actives d'un utilisateur, dans la transaction
+// qui révoque ses tokens (sessions_revoked_at)
+func revokeUserSessions(tx *gorm.DB, userId string, revokedAt time.Time) error {
+	return tx.Model(model.Session{}).
+		Where("user_id = ? and revoked_at < ?", userId, unsetTime).
+		Updates(map[string]interface{}{"revoked_at": revokedAt, "updated_at": revokedAt}).Error
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorMethodRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorMethodRepository crée une nouvelle instance de TwoFactorMethodRepository
+func NewTwoFactorMethodRepository(db *gorm.DB) *TwoFactorMethodRepository {
+	return &TwoFactorMethodRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *TwoFactorMethodRepository) WithContext(ctx context.Context) *TwoFactorMethodRepository {
+	return &TwoFactorMethodRepository{db: r.db.WithContext(ctx)}
+}
+
+// GetMethodsByUser récupère les seconds facteurs d'un utilisateur, les plus anciens en premier
+func (r *TwoFactorMethodRepository) GetMethodsByUser(userId string) ([]model.TwoFactorMethod, error) {
+	var methods []model.TwoFactorMethod
+	tx := r.db.Model(model.TwoFactorMethod{}).Where("user_id = ?", userId).Order("created_at").Find(&methods)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return methods, nil
+}
+
+// GetMethodById récupère un second facteur d'un utilisateur
+func (r *TwoFactorMethodRepository) GetMethodById(id string, userId string) (model.TwoFactorMethod, error) {
+	var method model.TwoFactorMethod
+	tx := r.db.Model(model.TwoFactorMethod{}).First(&method, "id = ? and user_id = ?", id, userId)
+	if tx.Error != nil {
+		return model.TwoFactorMethod{}, tx.Error
+	}
+	return method, nil
+}
+
+// CreateMethod enregistre un second facteur en attente de confirmation
+func (r *TwoFactorMethodRepository) CreateMethod(newMethod model.TwoFactorMethod) (model.TwoFactorMethod, error) {
+	currentTime := time.Now()
+	newMethod.CreatedAt = currentTime
+	newMethod.UpdatedAt = currentTime
+
+	if err := r.db.Model(model.TwoFactorMethod{}).Create(&newMethod).Error; err != nil {
+		return model.TwoFactorMethod{}, err
+	}
+
+	logger(r.db).Info("Created second factor", "type", newMethod.Type, "method_id", newMethod.Id, "user_id", newMethod.UserId)
+	return newMethod, nil
+}
+
+// ConfirmMethod active un second facteur et la double authentification de l'utilisateur.
+// Le premier facteur confirmé devient le facteur par défaut.
+func (r *TwoFactorMethodRepository) ConfirmMethod(method model.TwoFactorMethod) (model.TwoFactorMethod, error) {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var defaults int64
+		err := tx.Model(model.TwoFactorMethod{}).
+			Where("user_id = ? and is_default = ? and confirmed_at > ?", method.UserId, true, unsetTime).
+			Count(&defaults).Error
+		if err != nil {
+			return err
+		}
+
+		method.ConfirmedAt = currentTime
+		method.IsDefault = defaults == 0
+		confirmed := tx.Model(model.TwoFactorMethod{}).
+			Where("id = ? and confirmed_at < ?", method.Id, unsetTime).
+			Updates(map[string]interface{}{
+				"confirmed_at": currentTime,
+				"is_default":   method.IsDefault,
+				"updated_at":   currentTime,
+			})
+		if confirmed.Error != nil {
+			return confirmed.Error
+		}
+		if confirmed.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(model.User{}).Where("id = ?", method.UserId).
+			Updates(map[string]interface{}{"use_otp": true, "updated_at": currentTime}).Error
+	})
+	if err != nil {
+		return model.TwoFactorMethod{}, err
+	}
+
+	logger(r.db).Info("Confirmed second factor", "type", method.Type, "method_id", method.Id, "user_id", method.UserId)
+	return method, nil
+}
+
+// SetDefaultMethod fait d'un second facteur confirmé le facteur demandé à la connexion
+func (r *TwoFactorMethodRepository) SetDefaultMethod(method model.TwoFactorMethod) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.TwoFactorMethod{}).
+			Where("user_id = ? and id <> ?", method.UserId, method.Id).
+			Updates(map[string]interface{}{"is_default": false, "updated_at": currentTime}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(model.TwoFactorMethod{}).
+			Where("id = ?", method.Id).
+			Updates(map[string]interface{}{"is_default": true, "updated_at": currentTime}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Set default second factor", "method_id", method.Id, "user_id", method.UserId)
+	return nil
+}
+
+// DeleteMethod supprime un second facteur. Si c'était le facteur par défaut, le plus ancien
+// facteur confirmé le remplace ; sans facteur confirmé, la double authentification est désactivée.
+func (r *TwoFactorMethodRepository) DeleteMethod(method model.TwoFactorMethod) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", method.Id).Delete(&model.TwoFactorMethod{}).Error; err != nil {
+			return err
+		}
+
+		var remaining []model.TwoFactorMethod
+		err := tx.Model(model.TwoFactorMethod{}).
+			Where("user_id = ? and confirmed_at > ?", method.UserId, unsetTime).
+			Order("confirmed_at").Find(&remaining).Error
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			return tx.Model(model.User{}).Where("id = ?", method.UserId).
+				Updates(map[string]interface{}{"use_otp": false, "updated_at": currentTime}).Error
+		}
+
+		if method.IsDefault {
+			return tx.Model(model.TwoFactorMethod{}).Where("id = ?", remaining[0].Id).
+				Updates(map[string]interface{}{"is_default": true, "updated_at": currentTime}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Deleted second factor", "type", method.Type, "method_id", method.Id, "user_id", method.UserId)
+	return nil
+}
+
+// MarkMethodUsed enregistre l'utilisation d'un second facteur. Pour un facteur totp, le pas de
+// temps doit être plus récent que le dernier accepté : un code déjà utilisé est refusé.
+func (r *TwoFactorMethodRepository) MarkMethodUsed(method model.TwoFactorMethod, step int64) error {
+	query := r.db.Model(model.TwoFactorMethod{}).Where("id = ?", method.Id)
+	values := map[string]interface{}{"last_used_at": time.Now()}
+	if step > 0 {
+		query = query.Where("last_used_step < ?", step)
+		values["last_used_step"] = step
+	}
+
+	tx := query.Updates(values)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
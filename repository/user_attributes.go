@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AttributeRepository struct {
+	db *gorm.DB
+}
+
+// NewAttributeRepository crée une nouvelle instance de AttributeRepository
+func NewAttributeRepository(db *gorm.DB) *AttributeRepository {
+	return &AttributeRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *AttributeRepository) WithContext(ctx context.Context) *AttributeRepository {
+	return &AttributeRepository{db: r.db.WithContext(ctx)}
+}
+
+// GetDefinitions récupère les attributs personnalisés d'une organisation
+func (r *AttributeRepository) GetDefinitions(organizationId string) ([]model.AttributeDefinition, error) {
+	var definitions []model.AttributeDefinition
+	tx := r.db.Model(model.AttributeDefinition{}).
+		Where("organization_id = ?", organizationId).Order("name").Find(&definitions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return definitions, nil
+}
+
+// GetDefinitionByName récupère un attribut personnalisé d'une organisation
+func (r *AttributeRepository) GetDefinitionByName(organizationId string, name string) (model.AttributeDefinition, error) {
+	var definition model.AttributeDefinition
+	tx := r.db.Model(model.AttributeDefinition{}).
+		First(&definition, "organization_id = ? and name = ?", organizationId, name)
+	if tx.Error != nil {
+		return model.AttributeDefinition{}, tx.Error
+	}
+	return definition, nil
+}
+
+// GetDefinitionsByIds récupère des attributs personnalisés à partir de leurs ids
+func (r *AttributeRepository) GetDefinitionsByIds(ids []string) ([]model.AttributeDefinition, error) {
+	var definitions []model.AttributeDefinition
+	tx := r.db.Model(model.AttributeDefinition{}).Where("id IN (?)", ids).Find(&definitions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return definitions, nil
+}
+
+// CreateDefinition enregistre un nouvel attribut personnalisé
+func (r *AttributeRepository) CreateDefinition(newDefinition model.AttributeDefinition) (model.AttributeDefinition, error) {
+	currentTime := time.Now()
+	newDefinition.CreatedAt = currentTime
+	newDefinition.UpdatedAt = currentTime
+
+	if err := r.db.Model(model.AttributeDefinition{}).Create(&newDefinition).Error; err != nil {
+		return model.AttributeDefinition{}, err
+	}
+
+	logger(r.db).Info("Created attribute definition", "name", newDefinition.Name, "organization_id", newDefinition.OrganizationId)
+	return newDefinition, nil
+}
+
+// UpdateDefinition met à jour un attribut personnalisé
+func (r *AttributeRepository) UpdateDefinition(definition model.AttributeDefinition) (model.AttributeDefinition, error) {
+	definition.UpdatedAt = time.Now()
+	tx := r.db.Model(&definition).Select("*").Updates(&definition)
+	if tx.Error != nil {
+		return model.AttributeDefinition{}, tx.Error
+	}
+
+	logger(r.db).Info("Updated attribute definition", "name", definition.Name, "organization_id", definition.OrganizationId)
+	return definition, nil
+}
+
+// DeleteDefinition supprime un attribut personnalisé et toutes ses valeurs
+func (r *AttributeRepository) DeleteDefinition(definition model.AttributeDefinition) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("definition_id = ?", definition.Id).Delete(&model.UserAttribute{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", definition.Id).Delete(&model.AttributeDefinition{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Deleted attribute definition", "name", definition.Name, "organization_id", definition.OrganizationId)
+	return nil
+}
+
+// GetUserAttributes récupère les valeurs des attributs personnalisés d'un utilisateur dans une organisation
+func (r *AttributeRepository) GetUserAttributes(userId string, organizationId string) ([]model.UserAttribute, error) {
+	var attributes []model.UserAttribute
+	tx := r.db.Model(model.UserAttribute{}).
+		Where("user_id = ? and organization_id = ?", userId, organizationId).Find(&attributes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return attributes, nil
+}
+
+// GetAllUserAttributes récupère les valeurs des attributs personnalisés d'un utilisateur dans toutes ses organisations
+func (r *AttributeRepository) GetAllUserAttributes(userId string) ([]model.UserAttribute, error) {
+	var attributes []model.UserAttribute
+	tx := r.db.Model(model.UserAttribute{}).Where("user_id = ?", userId).Find(&attributes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return attributes, nil
+}
+
+// SaveUserAttributes enregistre et supprime des valeurs d'attributs dans une seule transaction
+func (r *AttributeRepository) SaveUserAttributes(userId string, organizationId string, values []model.UserAttribute, removedDefinitionIds []string) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(removedDefinitionIds) > 0 {
+			err := tx.Where("user_id = ? and definition_id IN (?)", userId, removedDefinitionIds).
+				Delete(&model.UserAttribute{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(values); i++ {
+			value := values[i]
+			updated := tx.Model(model.UserAttribute{}).
+				Where("user_id = ? and definition_id = ?", userId, value.DefinitionId).
+				Updates(map[string]interface{}{"value": value.Value, "updated_at": currentTime})
+			if updated.Error != nil {
+				return updated.Error
+			}
+			if updated.RowsAffected > 0 {
+				continue
+			}
+
+			value.UserId = userId
+			value.OrganizationId = organizationId
+			value.CreatedAt = currentTime
+			value.UpdatedAt = currentTime
+			if err := tx.Model(model.UserAttribute{}).Create(&value).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Saved attributes of user", "count", len(values)+len(removedDefinitionIds), "user_id", userId, "organization_id", organizationId)
+	return nil
+}
@@ -2,16 +2,21 @@ package repository
 
 import (
 	"auth/model"
+	"context"
 	"fmt"
 	"github.com/google/uuid"
-	"log"
+	"strings"
 	"time"
+	"unicode"
 
 	"gorm.io/gorm"
 )
 
 type UserRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	scoped         bool
+	organizationId string
+	eventType      string
 }
 
 // NewUserRepository crée une nouvelle instance de UserRepository
@@ -21,21 +26,327 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	}
 }
 
-// GetAllUsers récupère tous les utilisateurs depuis la base de données
-func (r *UserRepository) GetAllUsers() ([]model.User, error) {
-	var users []model.User
-	tx := r.db.Model(model.User{}).
-		Where("is_visible = ?", true).Find(&users)
+// ForOrganization retourne une copie du repository dont toutes les requêtes
+// sont limitées aux membres de l'organisation fournie
+func (r *UserRepository) ForOrganization(organizationId string) *UserRepository {
+	return &UserRepository{
+		db:             r.db,
+		scoped:         true,
+		organizationId: organizationId,
+		eventType:      r.eventType,
+	}
+}
+
+// WithEvent retourne une copie du repository dont les créations et mises à jour d'utilisateurs
+// enregistrent l'évènement fourni dans la même transaction (outbox des webhooks)
+func (r *UserRepository) WithEvent(eventType string) *UserRepository {
+	return &UserRepository{
+		db:             r.db,
+		scoped:         r.scoped,
+		organizationId: r.organizationId,
+		eventType:      eventType,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *UserRepository) WithContext(ctx context.Context) *UserRepository {
+	return &UserRepository{
+		db:             r.db.WithContext(ctx),
+		scoped:         r.scoped,
+		organizationId: r.organizationId,
+		eventType:      r.eventType,
+	}
+}
+
+// publishEvent enregistre l'évènement du repository, s'il y en a un, pour l'utilisateur modifié
+func (r *UserRepository) publishEvent(tx *gorm.DB, userId string) error {
+	if r.eventType == "" {
+		return nil
+	}
+	return enqueueUserEvent(tx, r.eventType, userId, r.organizationId)
+}
+
+// unsetTime sépare les dates jamais renseignées (valeur zéro) des dates réelles
+var unsetTime = time.Unix(0, 0).UTC()
+
+// tenantScope filtre les utilisateurs sur l'organisation du repository.
+// Un repository limité sans organisation ne retourne aucun utilisateur.
+func (r *UserRepository) tenantScope(db *gorm.DB) *gorm.DB {
+	if !r.scoped {
+		return db
+	}
+	return db.Where("users.id IN (?)", r.db.Model(model.Membership{}).
+		Select("user_id").Where("organization_id = ?", r.organizationId))
+}
+
+// UserQuery décrit les filtres, le tri et la pagination de la liste des utilisateurs.
+// Avec un curseur, la pagination se fait par clé et Offset est ignoré.
+type UserQuery struct {
+	Role        string
+	EmailDomain string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	IsAvailable *bool
+	UseOTP      *bool
+	SortBy      string
+	SortDesc    bool
+	Limit       int
+	Offset      int
+	Cursor      *UserCursor
+}
+
+// UserCursor repère le dernier utilisateur d'une page pour la pagination par clé
+type UserCursor struct {
+	Value interface{}
+	Id    string
+}
+
+// UserWithRole est un utilisateur accompagné du nom de son rôle, chargé par jointure
+type UserWithRole struct {
+	model.User `gorm:"embedded"`
+	RoleName   string
+}
+
+// UserSortColumns associe les champs de tri autorisés à leur colonne
+var UserSortColumns = map[string]string{
+	"name":       "users.name",
+	"email":      "users.email",
+	"username":   "users.username",
+	"created_at": "users.created_at",
+	"updated_at": "users.updated_at",
+}
+
+// GetAllUsers récupère une page d'utilisateurs visibles avec le nom de leur rôle,
+// ainsi que le nombre total d'utilisateurs correspondant aux filtres
+func (r *UserRepository) GetAllUsers(query UserQuery) ([]UserWithRole, int64, error) {
+	sortColumn, ok := UserSortColumns[query.SortBy]
+	if !ok {
+		sortColumn = UserSortColumns["created_at"]
+	}
+
+	filtered := r.db.Model(model.User{}).Scopes(r.tenantScope).
+		Joins("LEFT JOIN roles ON roles.id = users.role_id").
+		Where("users.is_visible = ?", true).
+		Scopes(query.filterScope)
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction, comparator := "ASC", ">"
+	if query.SortDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	page := filtered.Session(&gorm.Session{}).Select("users.*, roles.name AS role_name")
+	if query.Cursor != nil {
+		page = page.Where(
+			fmt.Sprintf("(%s %s ?) OR (%s = ? AND users.id %s ?)", sortColumn, comparator, sortColumn, comparator),
+			query.Cursor.Value, query.Cursor.Value, query.Cursor.Id)
+	} else if query.Offset > 0 {
+		page = page.Offset(query.Offset)
+	}
+
+	var users []UserWithRole
+	tx := page.Order(sortColumn + " " + direction).Order("users.id " + direction).
+		Limit(query.Limit).Find(&users)
 	if tx.Error != nil {
-		return nil, tx.Error
+		return nil, 0, tx.Error
 	}
-	return users, nil
+	return users, total, nil
+}
+
+// StreamUsers parcourt les utilisateurs visibles correspondant aux filtres, ligne par ligne,
+// sans charger toute la table en mémoire. La pagination de la requête est ignorée.
+func (r *UserRepository) StreamUsers(query UserQuery, handle func(user UserWithRole) error) error {
+	sortColumn, ok := UserSortColumns[query.SortBy]
+	if !ok {
+		sortColumn = UserSortColumns["created_at"]
+	}
+	direction := "ASC"
+	if query.SortDesc {
+		direction = "DESC"
+	}
+
+	rows, err := r.db.Model(model.User{}).Scopes(r.tenantScope).
+		Joins("LEFT JOIN roles ON roles.id = users.role_id").
+		Where("users.is_visible = ?", true).
+		Scopes(query.filterScope).
+		Select("users.*, roles.name AS role_name").
+		Order(sortColumn + " " + direction).Order("users.id " + direction).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user UserWithRole
+		if err := r.db.ScanRows(rows, &user); err != nil {
+			return err
+		}
+		if err := handle(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (q UserQuery) filterScope(db *gorm.DB) *gorm.DB {
+	if q.Role != "" {
+		db = db.Where("roles.name = ?", q.Role)
+	}
+	if q.EmailDomain != "" {
+		db = db.Where("LOWER(users.email) LIKE ?", "%@"+strings.ToLower(q.EmailDomain))
+	}
+	if !q.CreatedFrom.IsZero() {
+		db = db.Where("users.created_at >= ?", q.CreatedFrom)
+	}
+	if !q.CreatedTo.IsZero() {
+		db = db.Where("users.created_at < ?", q.CreatedTo)
+	}
+	if q.IsAvailable != nil {
+		db = db.Where("users.is_available = ?", *q.IsAvailable)
+	}
+	if q.UseOTP != nil {
+		db = db.Where("users.use_otp = ?", *q.UseOTP)
+	}
+	return db
+}
+
+// searchFields pondère les colonnes interrogées par la recherche
+var searchFields = []struct {
+	column string
+	weight int
+}{
+	{"users.username", 3},
+	{"users.email", 3},
+	{"users.name", 2},
+	{"users.sername", 2},
+}
+
+// SearchUsers recherche les utilisateurs dont le nom, le prénom, le nom d'utilisateur ou l'email
+// contiennent chacun des termes, sans tenir compte de la casse. Les résultats sont classés
+// par pertinence : correspondance exacte, puis préfixe, puis sous-chaîne. Sur PostgreSQL et
+// MySQL, le score de la recherche plein texte native s'ajoute au classement.
+func (r *UserRepository) SearchUsers(terms []string, limit int, offset int) ([]UserWithRole, int64, error) {
+	var conditions []string
+	var conditionArgs []interface{}
+	var ranks []string
+	var rankArgs []interface{}
+
+	for i := 0; i < len(terms); i++ {
+		term := strings.ToLower(terms[i])
+		contains := "%" + escapeLike(term) + "%"
+		prefix := escapeLike(term) + "%"
+
+		var matches []string
+		for j := 0; j < len(searchFields); j++ {
+			field := searchFields[j]
+			matches = append(matches, "LOWER("+field.column+") LIKE ? ESCAPE '!'")
+			conditionArgs = append(conditionArgs, contains)
+
+			ranks = append(ranks, fmt.Sprintf(
+				"CASE WHEN LOWER(%[1]s) = ? THEN %[2]d WHEN LOWER(%[1]s) LIKE ? ESCAPE '!' THEN %[3]d "+
+					"WHEN LOWER(%[1]s) LIKE ? ESCAPE '!' THEN %[4]d ELSE 0 END",
+				field.column, field.weight*4, field.weight*2, field.weight))
+			rankArgs = append(rankArgs, term, prefix, contains)
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	condition := strings.Join(conditions, " AND ")
+	rank := strings.Join(ranks, " + ")
+
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		if query := tsPrefixQuery(terms); query != "" {
+			condition = "(" + condition + ") OR " + usersTsVector + " @@ to_tsquery('simple', ?)"
+			conditionArgs = append(conditionArgs, query)
+			rank += " + ts_rank(" + usersTsVector + ", to_tsquery('simple', ?)) * 10"
+			rankArgs = append(rankArgs, query)
+		}
+	case "mysql":
+		if query := booleanPrefixQuery(terms); query != "" {
+			condition = "(" + condition + ") OR MATCH(users.name, users.sername, users.username, users.email) AGAINST (? IN BOOLEAN MODE)"
+			conditionArgs = append(conditionArgs, query)
+			rank += " + MATCH(users.name, users.sername, users.username, users.email) AGAINST (? IN BOOLEAN MODE)"
+			rankArgs = append(rankArgs, query)
+		}
+	}
+
+	filtered := r.db.Model(model.User{}).Scopes(r.tenantScope).
+		Joins("LEFT JOIN roles ON roles.id = users.role_id").
+		Where("users.is_visible = ?", true).
+		Where("("+condition+")", conditionArgs...)
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []UserWithRole
+	tx := filtered.Session(&gorm.Session{}).
+		Select("users.*, roles.name AS role_name, ("+rank+") AS search_rank", rankArgs...).
+		Order("search_rank DESC").Order("users.username").
+		Limit(limit).Offset(offset).Find(&users)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	return users, total, nil
+}
+
+// usersTsVector est l'expression indexée par idx_users_search sur PostgreSQL
+const usersTsVector = "to_tsvector('simple', COALESCE(users.name, '') || ' ' || COALESCE(users.sername, '') || ' ' || " +
+	"COALESCE(users.username, '') || ' ' || COALESCE(users.email, ''))"
+
+// escapeLike neutralise les caractères spéciaux de LIKE avec le caractère d'échappement "!"
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
+
+// tsPrefixQuery construit une requête to_tsquery où chaque mot est recherché par préfixe
+func tsPrefixQuery(terms []string) string {
+	var parts []string
+	words := searchWords(terms, 1)
+	for i := 0; i < len(words); i++ {
+		parts = append(parts, words[i]+":*")
+	}
+	return strings.Join(parts, " & ")
+}
+
+// booleanPrefixQuery construit une requête MATCH ... AGAINST en mode booléen.
+// Les mots plus courts que innodb_ft_min_token_size (3 par défaut) ne sont pas indexés.
+func booleanPrefixQuery(terms []string) string {
+	var parts []string
+	words := searchWords(terms, 3)
+	for i := 0; i < len(words); i++ {
+		parts = append(parts, "+"+words[i]+"*")
+	}
+	return strings.Join(parts, " ")
+}
+
+// searchWords découpe les termes en mots alphanumériques, par exemple "bob@acme.io" en bob, acme et io
+func searchWords(terms []string, minLength int) []string {
+	var words []string
+	for i := 0; i < len(terms); i++ {
+		parts := strings.FieldsFunc(strings.ToLower(terms[i]), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for j := 0; j < len(parts); j++ {
+			if len([]rune(parts[j])) >= minLength {
+				words = append(words, parts[j])
+			}
+		}
+	}
+	return words
 }
 
 // GetUserById récupère un utilisateur basé sur l'ID depuis la base de données
 func (r *UserRepository) GetUserById(id string) (model.User, error) {
 	var user model.User
-	tx := r.db.Model(model.User{}).
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).
 		First(&user, "id = ? and is_visible = ?", id, true)
 	if tx.Error != nil {
 		return model.User{}, tx.Error
@@ -46,44 +357,328 @@ func (r *UserRepository) GetUserById(id string) (model.User, error) {
 // CreateUser crée un nouvel utilisateur dans la base de données
 func (r *UserRepository) CreateUser(newUser model.User) (model.User, error) {
 
+	// Insertion dans la base de données, avec l'adhésion à l'organisation courante
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		newUser, err = r.createUser(tx, newUser)
+		return err
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	logger(r.db).Info("Created new user", "user_id", newUser.Id)
+	return newUser, nil
+}
+
+// CreateUsers crée plusieurs utilisateurs dans une seule transaction :
+// si une insertion échoue, aucun utilisateur n'est créé
+func (r *UserRepository) CreateUsers(newUsers []model.User) ([]model.User, error) {
+	createdUsers := []model.User{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < len(newUsers); i++ {
+			createdUser, err := r.createUser(tx, newUsers[i])
+			if err != nil {
+				return err
+			}
+			createdUsers = append(createdUsers, createdUser)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger(r.db).Info("Created users", "count", len(createdUsers))
+	return createdUsers, nil
+}
+
+func (r *UserRepository) createUser(tx *gorm.DB, newUser model.User) (model.User, error) {
 	newUser.Id = uuid.New().String()
 	newUser.CreatedAt = time.Now()
 
-	// Insertion dans la base de données
-	if err := r.db.Model(model.User{}).Create(&newUser).Error; err != nil {
+	if err := tx.Model(model.User{}).Create(&newUser).Error; err != nil {
 		return model.User{}, err
 	}
 
-	msg := fmt.Sprintf("Created new user: %+v", newUser)
-	log.Println(msg)
-	return newUser, nil
+	if !r.scoped {
+		return newUser, r.publishEvent(tx, newUser.Id)
+	}
+
+	membership := model.Membership{
+		UserId:         newUser.Id,
+		OrganizationId: r.organizationId,
+	}
+	if err := tx.Model(model.Membership{}).Create(&membership).Error; err != nil {
+		return model.User{}, err
+	}
+
+	if _, err := createMembershipRoles(tx, membership.Id, []string{newUser.RoleId}); err != nil {
+		return model.User{}, err
+	}
+	return newUser, r.publishEvent(tx, newUser.Id)
+}
+
+// GetTakenIdentities retourne, parmi les emails et noms d'utilisateur fournis, ceux déjà
+// utilisés. La vérification porte sur toutes les organisations, comme les index uniques.
+func (r *UserRepository) GetTakenIdentities(emails []string, usernames []string) (map[string]bool, map[string]bool, error) {
+	takenEmails := map[string]bool{}
+	takenUsernames := map[string]bool{}
+	if len(emails) == 0 && len(usernames) == 0 {
+		return takenEmails, takenUsernames, nil
+	}
+
+	var users []model.User
+	tx := r.db.Model(model.User{}).Select("email", "username").
+		Where("email IN (?) OR username IN (?)", emails, usernames).Find(&users)
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+
+	for i := 0; i < len(users); i++ {
+		takenEmails[users[i].Email] = true
+		takenUsernames[users[i].Username] = true
+	}
+	return takenEmails, takenUsernames, nil
 }
 
 // UpdateUser met à jour un utilisateur dans la base de données
 func (r *UserRepository) UpdateUser(updatedUser model.User) (model.User, error) {
 	updatedUser.UpdatedAt = time.Now()
-	tx := r.db.Model(model.User{}).
-		Where("id = ? and is_visible = ?", updatedUser.Id, true).Save(&updatedUser)
-	if tx.Error != nil {
-		return model.User{}, tx.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updated := tx.Model(&updatedUser).Scopes(r.tenantScope).
+			Where("is_visible = ?", true).Select("*").Omit("Otps").Updates(&updatedUser)
+		if updated.Error != nil {
+			return updated.Error
+		}
+		if updated.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return r.publishEvent(tx, updatedUser.Id)
+	})
+	if err != nil {
+		return model.User{}, err
 	}
 
-	msg := fmt.Sprintf("Updated user with ID %v and new role %v", updatedUser.Id, updatedUser.RoleId)
-	log.Println(msg)
+	logger(r.db).Info("Updated user", "user_id", updatedUser.Id, "role_id", updatedUser.RoleId)
 
 	return updatedUser, nil
 }
 
-// DeleteUser supprime un utilisateur de la base de données
+// DeleteUser supprime définitivement un utilisateur avec ses codes otp, ses adhésions et ses groupes
 func (r *UserRepository) DeleteUser(id string) error {
-	// Suppression dans la base de données
-	if err := r.db.Delete(&model.User{}, id).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Scopes(r.tenantScope).Where("id = ?", id).Delete(&model.User{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return deleteUserRelations(tx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Deleted user", "user_id", id)
+	return nil
+}
+
+// AnonymizeUser efface les données personnelles d'un utilisateur supprimé. Le compte est conservé
+// sous une identité neutre afin que les références d'audit restent valides.
+func (r *UserRepository) AnonymizeUser(id string) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		anonymized := tx.Model(model.User{}).Scopes(r.tenantScope).
+			Where("id = ? and is_visible = ?", id, false).
+			Updates(map[string]interface{}{
+				"name":          "Deleted",
+				"sername":       "User",
+				"email":         fmt.Sprintf("deleted-%s@anonymized.invalid", id),
+				"username":      fmt.Sprintf("deleted-%s", id),
+				"password":      "",
+				"locale":        "",
+				"avatar_file":   "",
+				"use_otp":       false,
+				"is_available":  false,
+				"anonymized_at": currentTime,
+				"updated_at":    currentTime,
+			})
+		if anonymized.Error != nil {
+			return anonymized.Error
+		}
+		if anonymized.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return deleteUserRelations(tx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Anonymized user", "user_id", id)
+	return nil
+}
+
+// deleteUserRelations supprime les codes otp, les seconds facteurs, les sessions, l'historique des connexions,
+// les adhésions, les attributs personnalisés, les changements d'identifiants en attente et les appartenances
+// aux groupes d'un utilisateur
+func deleteUserRelations(tx *gorm.DB, userId string) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&model.Otp{}).Error; err != nil {
+		return err
+	}
+
+	memberships := tx.Model(model.Membership{}).Select("id").Where("user_id = ?", userId)
+	if err := tx.Where("membership_id IN (?)", memberships).Delete(&model.MembershipRole{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&model.Membership{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&model.UserAttribute{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&model.IdentityChange{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&model.TwoFactorMethod{}).Error; err != nil {
 		return err
 	}
+	if err := tx.Where("user_id = ?", userId).Delete(&model.Session{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&model.LoginEvent{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userId).Delete(&model.GroupMember{}).Error
+}
+
+// AccountStateUpdate décrit le nouvel état d'un compte
+type AccountStateUpdate struct {
+	Status         string
+	Reason         string
+	LockedUntil    time.Time
+	RevokeSessions bool
+}
+
+// UpdateAccountState change l'état d'un compte. Le compteur d'échecs de connexion est remis
+// à zéro et is_available suit l'état actif du compte.
+func (r *UserRepository) UpdateAccountState(id string, update AccountStateUpdate) error {
+	currentTime := time.Now()
+	values := map[string]interface{}{
+		"status":                update.Status,
+		"status_reason":         update.Reason,
+		"status_changed_at":     currentTime,
+		"locked_until":          update.LockedUntil,
+		"failed_login_attempts": 0,
+		"is_available":          update.Status == "active",
+		"updated_at":            currentTime,
+	}
+	if update.RevokeSessions {
+		values["sessions_revoked_at"] = currentTime
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updated := tx.Model(model.User{}).Scopes(r.tenantScope).
+			Where("id = ? and is_visible = ?", id, true).Updates(values)
+		if updated.Error != nil {
+			return updated.Error
+		}
+		if updated.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if update.RevokeSessions {
+			return revokeUserSessions(tx, id, currentTime)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Updated account state of user", "user_id", id, "status", update.Status)
+	return nil
+}
+
+// IncrementFailedLogins incrémente le compteur d'échecs de connexion et retourne sa nouvelle valeur
+func (r *UserRepository) IncrementFailedLogins(id string) (int, error) {
+	var attempts int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.User{}).Where("id = ?", id).
+			UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(model.User{}).Where("id = ?", id).
+			Select("failed_login_attempts").Scan(&attempts).Error
+	})
+	return attempts, err
+}
+
+// ResetFailedLogins remet à zéro le compteur d'échecs de connexion
+func (r *UserRepository) ResetFailedLogins(id string) error {
+	return r.db.Model(model.User{}).Where("id = ?", id).
+		UpdateColumn("failed_login_attempts", 0).Error
+}
+
+// UpdateAvatar enregistre le fichier de la photo de profil d'un utilisateur
+func (r *UserRepository) UpdateAvatar(id string, avatarFile string) error {
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).
+		Where("id = ? and is_visible = ?", id, true).
+		Updates(map[string]interface{}{"avatar_file": avatarFile, "updated_at": time.Now()})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
+	logger(r.db).Info("Updated avatar of user", "user_id", id)
 	return nil
 }
 
+// GetArchivedUserById récupère un utilisateur supprimé qui n'a pas encore été purgé
+func (r *UserRepository) GetArchivedUserById(id string) (model.User, error) {
+	var user model.User
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).
+		First(&user, "id = ? and is_visible = ? and anonymized_at < ?", id, false, unsetTime)
+	if tx.Error != nil {
+		return model.User{}, tx.Error
+	}
+	return user, nil
+}
+
+// RestoreUser rend de nouveau visible un utilisateur supprimé qui n'a pas encore été purgé
+func (r *UserRepository) RestoreUser(id string) error {
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).
+		Where("id = ? and is_visible = ? and anonymized_at < ?", id, false, unsetTime).
+		Updates(map[string]interface{}{"is_visible": true, "deleted_at": time.Time{}, "updated_at": time.Now()})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Restored user", "user_id", id)
+	return nil
+}
+
+// GetUsersToPurge récupère, toutes organisations confondues, les utilisateurs supprimés
+// avant la date indiquée et pas encore purgés, les plus anciens en premier
+func (r *UserRepository) GetUsersToPurge(deletedBefore time.Time, limit int) ([]model.User, error) {
+	var users []model.User
+	tx := r.db.Model(model.User{}).
+		Where("is_visible = ? and deleted_at > ? and deleted_at < ? and anonymized_at < ?",
+			false, unsetTime, deletedBefore, unsetTime).
+		Order("deleted_at").Limit(limit).Find(&users)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return users, nil
+}
+
 // DeleteUser supprime un utilisateur de la base de données
 func (r *UserRepository) ArchivedUser(user model.User) error {
 	// Suppression dans la base de données
@@ -94,7 +689,7 @@ func (r *UserRepository) ArchivedUser(user model.User) error {
 // GetUserByRole récupère tous les utilisateurs avec un rôle spécifié
 func (r *UserRepository) GetUserByRole(role string) ([]model.User, error) {
 	var users []model.User
-	tx := r.db.Model(model.User{}).Where(
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).Where(
 		"role = ? and is_visible = ?", role, true).Find(&users)
 	if tx.Error != nil {
 		return nil, tx.Error
@@ -105,7 +700,7 @@ func (r *UserRepository) GetUserByRole(role string) ([]model.User, error) {
 // GetUserByUsername récupère un utilisateur par son nom
 func (r *UserRepository) GetUserByUsername(username string) (model.User, error) {
 	var user model.User
-	tx := r.db.Model(model.User{}).
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).
 		First(&user, "username = ? and is_visible = ?", username, true)
 	if tx.Error != nil {
 		return model.User{}, tx.Error
@@ -116,7 +711,7 @@ func (r *UserRepository) GetUserByUsername(username string) (model.User, error)
 // GetUserByEmail récupère un utilisateur par son email
 func (r *UserRepository) GetUserByEmail(email string) (model.User, error) {
 	var user model.User
-	tx := r.db.Model(model.User{}).
+	tx := r.db.Model(model.User{}).Scopes(r.tenantScope).
 		First(&user, "email = ? and is_visible = ?", email, true)
 	if tx.Error != nil {
 		return model.User{}, tx.Error
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"auth/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository crée une nouvelle instance de WebhookRepository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// WithContext retourne une copie du repository dont les requêtes portent le contexte fourni
+func (r *WebhookRepository) WithContext(ctx context.Context) *WebhookRepository {
+	return &WebhookRepository{db: r.db.WithContext(ctx)}
+}
+
+// WebhookDeliveryQuery décrit les filtres et la pagination des envois d'une organisation
+type WebhookDeliveryQuery struct {
+	OrganizationId string
+	SubscriptionId string
+	Status         string
+	Limit          int
+	Offset         int
+}
+
+// WebhookAttempt est le résultat d'une tentative d'envoi
+type WebhookAttempt struct {
+	Status        string
+	StatusCode    int
+	Error         string
+	NextAttemptAt time.Time
+}
+
+// CreateSubscription enregistre un nouvel abonnement
+func (r *WebhookRepository) CreateSubscription(newSubscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	currentTime := time.Now()
+	newSubscription.CreatedAt = currentTime
+	newSubscription.UpdatedAt = currentTime
+
+	if err := r.db.Model(model.WebhookSubscription{}).Create(&newSubscription).Error; err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	logger(r.db).Info("Created webhook", "webhook_id", newSubscription.Id, "organization_id", newSubscription.OrganizationId)
+	return newSubscription, nil
+}
+
+// GetSubscriptionById récupère un abonnement d'une organisation
+func (r *WebhookRepository) GetSubscriptionById(id string, organizationId string) (model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	tx := r.db.Model(model.WebhookSubscription{}).
+		First(&subscription, "id = ? and organization_id = ? and is_visible = ?", id, organizationId, true)
+	if tx.Error != nil {
+		return model.WebhookSubscription{}, tx.Error
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions récupère les abonnements d'une organisation, les plus récents en premier
+func (r *WebhookRepository) GetSubscriptions(organizationId string) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	tx := r.db.Model(model.WebhookSubscription{}).
+		Where("organization_id = ? and is_visible = ?", organizationId, true).
+		Order("created_at desc").Find(&subscriptions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return subscriptions, nil
+}
+
+// GetActiveSubscriptions récupère les abonnements actifs des organisations fournies
+func (r *WebhookRepository) GetActiveSubscriptions(organizationIds []string) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	if len(organizationIds) == 0 {
+		return subscriptions, nil
+	}
+	tx := r.db.Model(model.WebhookSubscription{}).
+		Where("organization_id IN (?) and is_available = ? and is_visible = ?", organizationIds, true, true).
+		Find(&subscriptions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return subscriptions, nil
+}
+
+// UpdateSubscription met à jour l'adresse, la description, les évènements et l'activation d'un abonnement
+func (r *WebhookRepository) UpdateSubscription(subscription model.WebhookSubscription) (model.WebhookSubscription, error) {
+	subscription.UpdatedAt = time.Now()
+	tx := r.db.Model(model.WebhookSubscription{}).
+		Where("id = ? and organization_id = ? and is_visible = ?", subscription.Id, subscription.OrganizationId, true).
+		Updates(map[string]interface{}{
+			"url":          subscription.Url,
+			"description":  subscription.Description,
+			"events":       subscription.Events,
+			"is_available": subscription.IsAvailable,
+			"updated_at":   subscription.UpdatedAt,
+		})
+	if tx.Error != nil {
+		return model.WebhookSubscription{}, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.WebhookSubscription{}, gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Updated webhook", "webhook_id", subscription.Id)
+	return subscription, nil
+}
+
+// DeleteSubscription supprime un abonnement ; ses envois en attente passent en lettre morte
+func (r *WebhookRepository) DeleteSubscription(id string, organizationId string) error {
+	currentTime := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Model(model.WebhookSubscription{}).
+			Where("id = ? and organization_id = ? and is_visible = ?", id, organizationId, true).
+			Updates(map[string]interface{}{"is_visible": false, "deleted_at": currentTime, "updated_at": currentTime})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(model.WebhookDelivery{}).
+			Where("subscription_id = ? and status = ?", id, model.WebhookDeliveryPending).
+			Updates(map[string]interface{}{
+				"status":     model.WebhookDeliveryDead,
+				"last_error": "abonnement supprimé",
+				"updated_at": currentTime,
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	logger(r.db).Info("Deleted webhook", "webhook_id", id)
+	return nil
+}
+
+// GetDueDeliveries récupère les envois en attente dont la prochaine tentative est échue
+func (r *WebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	tx := r.db.Model(model.WebhookDelivery{}).
+		Where("status = ? and next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&deliveries)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return deliveries, nil
+}
+
+// ClaimDelivery réserve un envoi échu jusqu'à la date fournie, afin qu'une autre instance ne l'envoie pas
+// en même temps. Un envoi déjà réservé ou traité est refusé (gorm.ErrRecordNotFound).
+func (r *WebhookRepository) ClaimDelivery(id string, now time.Time, leaseUntil time.Time) error {
+	tx := r.db.Model(model.WebhookDelivery{}).
+		Where("id = ? and status = ? and next_attempt_at <= ?", id, model.WebhookDeliveryPending, now).
+		Updates(map[string]interface{}{"next_attempt_at": leaseUntil, "updated_at": now})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RecordAttempt enregistre le résultat d'une tentative d'envoi
+func (r *WebhookRepository) RecordAttempt(delivery model.WebhookDelivery, attempt WebhookAttempt) error {
+	currentTime := time.Now()
+	values := map[string]interface{}{
+		"status":           attempt.Status,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_attempt_at":  currentTime,
+		"last_status_code": attempt.StatusCode,
+		"last_error":       attempt.Error,
+		"next_attempt_at":  attempt.NextAttemptAt,
+		"updated_at":       currentTime,
+	}
+	if attempt.Status == model.WebhookDeliveryDelivered {
+		values["delivered_at"] = currentTime
+	}
+
+	tx := r.db.Model(model.WebhookDelivery{}).
+		Where("id = ? and status = ?", delivery.Id, model.WebhookDeliveryPending).Updates(values)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	logger(r.db).Info("Webhook delivery attempt", "delivery_id", delivery.Id, "attempt", delivery.Attempts+1, "status", attempt.Status)
+	return nil
+}
+
+// GetDeliveries recherche les envois d'une organisation, les plus récents en premier
+func (r *WebhookRepository) GetDeliveries(query WebhookDeliveryQuery) ([]model.WebhookDelivery, int64, error) {
+	tx := r.db.Model(model.WebhookDelivery{}).Where("organization_id = ?", query.OrganizationId)
+	if query.SubscriptionId != "" {
+		tx = tx.Where("subscription_id = ?", query.SubscriptionId)
+	}
+	if query.Status != "" {
+		tx = tx.Where("status = ?", query.Status)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []model.WebhookDelivery
+	err := tx.Order("created_at desc").Limit(query.Limit).Offset(query.Offset).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// GetDeliveryById récupère un envoi d'une organisation
+func (r *WebhookRepository) GetDeliveryById(id string, organizationId string) (model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	tx := r.db.Model(model.WebhookDelivery{}).First(&delivery, "id = ? and organization_id = ?", id, organizationId)
+	if tx.Error != nil {
+		return model.WebhookDelivery{}, tx.Error
+	}
+	return delivery, nil
+}
+
+// RedeliverDelivery remet un envoi terminé (livré ou en lettre morte) en attente pour un envoi immédiat,
+// avec un nouveau compteur de tentatives
+func (r *WebhookRepository) RedeliverDelivery(id string, organizationId string) error {
+	currentTime := time.Now()
+	tx := r.db.Model(model.WebhookDelivery{}).
+		Where("id = ? and organization_id = ? and status <> ?", id, organizationId, model.WebhookDeliveryPending).
+		Updates(map[string]interface{}{
+			"status":          model.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": currentTime,
+			"updated_at":      currentTime,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger(r.db).Info("Webhook delivery queued for redelivery", "delivery_id", id)
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"auth/model"
+	"auth/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	AccountStateActive            = "active"
+	AccountStateDisabled          = "disabled"
+	AccountStateLocked            = "locked"
+	AccountStatePendingActivation = "pending_activation"
+	AccountStatePasswordExpired   = "password_expired"
+)
+
+const (
+	// MaxFailedLogins est le nombre d'échecs de connexion consécutifs qui verrouille le compte
+	MaxFailedLogins = 5
+	// AccountLockDuration est la durée du verrouillage après trop d'échecs de connexion
+	AccountLockDuration = 15 * time.Minute
+)
+
+// accountTransitions liste, pour chaque état, les états vers lesquels un compte peut passer
+var accountTransitions = map[string][]string{
+	AccountStateActive:            {AccountStateDisabled, AccountStateLocked, AccountStatePasswordExpired},
+	AccountStateLocked:            {AccountStateActive, AccountStateDisabled},
+	AccountStatePendingActivation: {AccountStateActive, AccountStateDisabled},
+	AccountStatePasswordExpired:   {AccountStateActive, AccountStateDisabled},
+	AccountStateDisabled:          {AccountStateActive},
+}
+
+// AccountStateError signale qu'un compte ne peut pas s'authentifier dans son état actuel
+type AccountStateError struct {
+	State       string
+	LockedUntil time.Time
+}
+
+func (e *AccountStateError) Error() string {
+	switch e.State {
+	case AccountStateDisabled:
+		return "ce compte a été désactivé"
+	case AccountStateLocked:
+		return fmt.Sprintf("ce compte est verrouillé jusqu'à %s", e.LockedUntil.Format(time.RFC3339))
+	case AccountStatePendingActivation:
+		return "ce compte n'a pas encore été activé"
+	case AccountStatePasswordExpired:
+		return "le mot de passe de ce compte a expiré, merci de le renouveler"
+	}
+	return "ce compte ne peut pas se connecter"
+}
+
+// ErrorCode retourne le code d'erreur exposé aux clients, par exemple "account_locked"
+func (e *AccountStateError) ErrorCode() string {
+	return "account_" + e.State
+}
+
+// effectiveAccountState retourne l'état du compte ; un verrouillage expiré vaut un compte actif
+func effectiveAccountState(user model.User, now time.Time) string {
+	switch {
+	case user.Status == "":
+		return AccountStateActive
+	case user.Status == AccountStateLocked && !user.LockedUntil.IsZero() && !now.Before(user.LockedUntil):
+		return AccountStateActive
+	}
+	return user.Status
+}
+
+// checkAccountState retourne une AccountStateError si le compte ne peut pas s'authentifier
+func checkAccountState(user model.User, now time.Time) error {
+	state := effectiveAccountState(user, now)
+	if state == AccountStateActive {
+		return nil
+	}
+	return &AccountStateError{State: state, LockedUntil: user.LockedUntil}
+}
+
+// checkSessionAccountState contrôle l'état du compte pour une session déjà ouverte. Le verrouillage
+// protège la connexion par mot de passe : il ne coupe pas les sessions du titulaire du compte.
+func checkSessionAccountState(user model.User, now time.Time) error {
+	if effectiveAccountState(user, now) == AccountStateLocked {
+		return nil
+	}
+	return checkAccountState(user, now)
+}
+
+func canTransition(from string, to string) bool {
+	allowed := accountTransitions[from]
+	for i := 0; i < len(allowed); i++ {
+		if allowed[i] == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionAccountState fait passer le compte dans un nouvel état. Seule la désactivation
+// révoque les sessions : un verrouillage peut être provoqué par n'importe qui connaissant le
+// nom d'utilisateur, il ne doit pas permettre de déconnecter le titulaire du compte.
+func transitionAccountState(userRepo *repository.UserRepository, user model.User, to string, reason string) (model.User, error) {
+	now := time.Now()
+	from := effectiveAccountState(user, now)
+	if from == to {
+		return model.User{}, fmt.Errorf("le compte est déjà dans l'état %s", to)
+	}
+	if !canTransition(from, to) {
+		return model.User{}, fmt.Errorf("le compte ne peut pas passer de l'état %s à l'état %s", from, to)
+	}
+
+	update := repository.AccountStateUpdate{
+		Status:         to,
+		Reason:         reason,
+		RevokeSessions: to == AccountStateDisabled,
+	}
+	if to == AccountStateLocked {
+		update.LockedUntil = now.Add(AccountLockDuration)
+	}
+
+	if err := userRepo.UpdateAccountState(user.Id, update); err != nil {
+		return model.User{}, errors.New("nous avons rencontré un problème durant la mise à jour de l'état du compte")
+	}
+	return userRepo.GetUserById(user.Id)
+}
+
+// checkSessionToken rejette les tokens d'un compte inactif ou émis avant la révocation de ses sessions
+func checkSessionToken(userRepo *repository.UserRepository, userId string, issuedAt int64) error {
+	user, err := userRepo.GetUserById(userId)
+	if err != nil {
+		return errors.New("ce compte n'existe plus")
+	}
+	if err := checkSessionAccountState(user, time.Now()); err != nil {
+		return err
+	}
+	// iat est à la seconde : un token émis dans la seconde de la révocation, comme celui remis
+	// après un changement de mot de passe, reste valide
+	if !user.SessionsRevokedAt.IsZero() && issuedAt < user.SessionsRevokedAt.Unix() {
+		return errors.New("cette session a été révoquée")
+	}
+	return nil
+}
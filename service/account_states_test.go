@@ -0,0 +1,176 @@
+package service
+
+import (
+	"auth/migrations"
+	"auth/model"
+	"auth/repository"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB ouvre une base sqlite temporaire avec le schéma des migrations
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.sqlite")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("ouverture de la base : %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("connexion à la base : %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migrations.NewMigrator(db, "sqlite").Up(); err != nil {
+		t.Fatalf("migrations : %v", err)
+	}
+	return db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, username string, status string) model.User {
+	t.Helper()
+	user := model.User{
+		Name:     "Test",
+		Sername:  "User",
+		Email:    username + "@example.com",
+		Username: username,
+		Password: "hash",
+		Status:   status,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("création de l'utilisateur : %v", err)
+	}
+	return user
+}
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{AccountStateActive, AccountStateDisabled, true},
+		{AccountStateActive, AccountStateLocked, true},
+		{AccountStateActive, AccountStatePasswordExpired, true},
+		{AccountStateLocked, AccountStateActive, true},
+		{AccountStatePendingActivation, AccountStateActive, true},
+		{AccountStatePasswordExpired, AccountStateActive, true},
+		{AccountStateDisabled, AccountStateActive, true},
+		{AccountStatePasswordExpired, AccountStateLocked, false},
+		{AccountStateDisabled, AccountStateLocked, false},
+		{AccountStatePendingActivation, AccountStatePasswordExpired, false},
+		{AccountStateLocked, AccountStatePasswordExpired, false},
+	}
+	for _, c := range cases {
+		if got := canTransition(c.from, c.to); got != c.allowed {
+			t.Errorf("canTransition(%s, %s) = %v, %v attendu", c.from, c.to, got, c.allowed)
+		}
+	}
+}
+
+func TestEffectiveAccountState(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name string
+		user model.User
+		want string
+	}{
+		{"sans état", model.User{}, AccountStateActive},
+		{"verrouillé", model.User{Status: AccountStateLocked, LockedUntil: now.Add(time.Minute)}, AccountStateLocked},
+		{"verrouillage expiré", model.User{Status: AccountStateLocked, LockedUntil: now.Add(-time.Minute)}, AccountStateActive},
+		{"désactivé", model.User{Status: AccountStateDisabled}, AccountStateDisabled},
+	}
+	for _, c := range cases {
+		if got := effectiveAccountState(c.user, now); got != c.want {
+			t.Errorf("%s : effectiveAccountState = %s, %s attendu", c.name, got, c.want)
+		}
+	}
+}
+
+func TestTransitionAccountStateRejectsForbiddenTransitions(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+
+	active := createTestUser(t, db, "active", AccountStateActive)
+	if _, err := transitionAccountState(userRepo, active, AccountStateActive, ""); err == nil {
+		t.Error("la transition vers l'état courant doit être refusée")
+	}
+
+	expired := createTestUser(t, db, "expired", AccountStatePasswordExpired)
+	if _, err := transitionAccountState(userRepo, expired, AccountStateLocked, ""); err == nil {
+		t.Error("un mot de passe expiré ne doit pas pouvoir être verrouillé")
+	}
+	unchanged, _ := userRepo.GetUserById(expired.Id)
+	if unchanged.Status != AccountStatePasswordExpired {
+		t.Errorf("état = %s après une transition refusée, %s attendu", unchanged.Status, AccountStatePasswordExpired)
+	}
+}
+
+func TestTransitionAccountStateRevokesSessionsOnDisableOnly(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+
+	locked, err := transitionAccountState(userRepo, createTestUser(t, db, "locked", AccountStateActive), AccountStateLocked, "too many failed login attempts")
+	if err != nil {
+		t.Fatalf("verrouillage : %v", err)
+	}
+	if locked.Status != AccountStateLocked || !locked.LockedUntil.After(time.Now()) {
+		t.Errorf("compte verrouillé = %s jusqu'à %v", locked.Status, locked.LockedUntil)
+	}
+	if !locked.SessionsRevokedAt.IsZero() {
+		t.Error("le verrouillage ne doit pas révoquer les sessions")
+	}
+
+	expired, err := transitionAccountState(userRepo, createTestUser(t, db, "expired", AccountStateActive), AccountStatePasswordExpired, "")
+	if err != nil {
+		t.Fatalf("expiration : %v", err)
+	}
+	if !expired.SessionsRevokedAt.IsZero() {
+		t.Error("l'expiration du mot de passe ne doit pas révoquer les sessions")
+	}
+
+	disabled, err := transitionAccountState(userRepo, createTestUser(t, db, "disabled", AccountStateActive), AccountStateDisabled, "compromis")
+	if err != nil {
+		t.Fatalf("désactivation : %v", err)
+	}
+	if disabled.SessionsRevokedAt.IsZero() {
+		t.Error("la désactivation doit révoquer les sessions")
+	}
+	if disabled.StatusReason != "compromis" {
+		t.Errorf("raison = %q, %q attendu", disabled.StatusReason, "compromis")
+	}
+}
+
+func TestCheckSessionToken(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+
+	user := createTestUser(t, db, "revoked", AccountStateActive)
+	revokedAt := time.Now().Truncate(time.Second)
+	if err := db.Model(&model.User{}).Where("id = ?", user.Id).Update("sessions_revoked_at", revokedAt).Error; err != nil {
+		t.Fatalf("révocation : %v", err)
+	}
+	if err := checkSessionToken(userRepo, user.Id, revokedAt.Unix()-1); err == nil {
+		t.Error("un token émis avant la révocation doit être refusé")
+	}
+	if err := checkSessionToken(userRepo, user.Id, revokedAt.Unix()); err != nil {
+		t.Errorf("un token émis dans la seconde de la révocation doit être accepté : %v", err)
+	}
+
+	locked := createTestUser(t, db, "locked", AccountStateActive)
+	if _, err := transitionAccountState(userRepo, locked, AccountStateLocked, ""); err != nil {
+		t.Fatalf("verrouillage : %v", err)
+	}
+	if err := checkSessionToken(userRepo, locked.Id, time.Now().Unix()); err != nil {
+		t.Errorf("un compte verrouillé garde ses sessions : %v", err)
+	}
+
+	disabled := createTestUser(t, db, "disabled", AccountStateDisabled)
+	if err := checkSessionToken(userRepo, disabled.Id, time.Now().Unix()); err == nil {
+		t.Error("un compte désactivé ne doit plus être accepté")
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"context"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"time"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+
+	// auditVerifyBatchSize est le nombre d'entrées relues à chaque requête lors de la vérification
+	auditVerifyBatchSize = 500
+)
+
+// AuditListQuery regroupe les filtres et la pagination demandés sur le journal d'audit
+type AuditListQuery struct {
+	Action         string
+	Outcome        string
+	ActorId        string
+	TargetId       string
+	OrganizationId string
+	CorrelationId  string
+	IpAddress      string
+	From           time.Time
+	To             time.Time
+	Limit          int
+	Offset         int
+}
+
+// AuditPage est une page du journal d'audit avec le total des entrées correspondantes
+type AuditPage struct {
+	Events []model.AuditEvent
+	Total  int64
+	Limit  int
+	Offset int
+}
+
+// AuditVerification est le résultat du contrôle de la chaîne d'empreintes du journal
+type AuditVerification struct {
+	Valid    bool
+	Checked  int64
+	BrokenAt int64
+	Reason   string
+}
+
+// AuditService enregistre les évènements de sécurité et permet de les consulter
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+	logger    *slog.Logger
+}
+
+// NewAuditService crée une nouvelle instance de AuditService
+func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    slog.Default(),
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *AuditService) WithContext(ctx context.Context) *AuditService {
+	return &AuditService{
+		auditRepo: s.auditRepo.WithContext(ctx),
+		logger:    logging.FromContext(ctx),
+	}
+}
+
+// Record ajoute un évènement au journal. Un échec d'écriture est journalisé sans interrompre la requête.
+func (s *AuditService) Record(event model.AuditEvent) {
+	// La précision est réduite à la microseconde, conservée par tous les fournisseurs de base de données
+	event.OccurredAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.UserAgent = truncate(event.UserAgent, 512)
+	event.IpAddress = truncate(event.IpAddress, 64)
+
+	if _, err := s.auditRepo.AppendEvent(event); err != nil {
+		s.logger.Error("Failed to record audit event", "action", event.Action, "error", err)
+	}
+}
+
+// FindEvents recherche dans le journal, les entrées les plus récentes en premier
+func (s *AuditService) FindEvents(listQuery AuditListQuery) (AuditPage, error) {
+	limit := listQuery.Limit
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+
+	events, total, err := s.auditRepo.FindEvents(repository.AuditQuery{
+		Action:         listQuery.Action,
+		Outcome:        listQuery.Outcome,
+		ActorId:        listQuery.ActorId,
+		TargetId:       listQuery.TargetId,
+		OrganizationId: listQuery.OrganizationId,
+		CorrelationId:  listQuery.CorrelationId,
+		IpAddress:      listQuery.IpAddress,
+		From:           listQuery.From,
+		To:             listQuery.To,
+		Limit:          limit,
+		Offset:         listQuery.Offset,
+	})
+	if err != nil {
+		return AuditPage{}, err
+	}
+	return AuditPage{Events: events, Total: total, Limit: limit, Offset: listQuery.Offset}, nil
+}
+
+// VerifyChain relit tout le journal et contrôle la continuité des séquences et les empreintes.
+// La première entrée modifiée, supprimée ou insérée est signalée.
+func (s *AuditService) VerifyChain() (AuditVerification, error) {
+	verification := AuditVerification{Valid: true}
+	var previous model.AuditEvent
+
+	for {
+		events, err := s.auditRepo.GetEventsAfter(previous.Sequence, auditVerifyBatchSize)
+		if err != nil {
+			return AuditVerification{}, err
+		}
+
+		for i := 0; i < len(events); i++ {
+			event := events[i]
+			reason := ""
+			switch {
+			case event.Sequence != previous.Sequence+1:
+				reason = fmt.Sprintf("entrée manquante après la séquence %d", previous.Sequence)
+			case event.PreviousHash != previous.Hash:
+				reason = "l'empreinte précédente ne correspond pas"
+			case event.Hash != event.ComputeHash():
+				reason = "le contenu de l'entrée a été modifié"
+			}
+			if reason != "" {
+				verification.Valid = false
+				verification.BrokenAt = event.Sequence
+				verification.Reason = reason
+				return verification, nil
+			}
+
+			verification.Checked++
+			previous = event
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return verification, nil
+		}
+	}
+}
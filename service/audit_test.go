@@ -0,0 +1,108 @@
+package service
+
+import (
+	"auth/model"
+	"auth/repository"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestComputeHashCoversContent(t *testing.T) {
+	event := model.AuditEvent{
+		Sequence:     3,
+		OccurredAt:   time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC),
+		Action:       "user.delete",
+		Outcome:      model.AuditOutcomeSuccess,
+		ActorId:      "admin",
+		TargetId:     "u1",
+		PreviousHash: "abc",
+	}
+	hash := event.ComputeHash()
+	if len(hash) != 64 {
+		t.Fatalf("empreinte = %q, 64 caractères hexadécimaux attendus", hash)
+	}
+
+	local := event
+	local.OccurredAt = event.OccurredAt.In(time.FixedZone("UTC+2", 2*60*60))
+	if local.ComputeHash() != hash {
+		t.Error("l'empreinte ne doit pas dépendre du fuseau horaire")
+	}
+
+	changes := map[string]func(e *model.AuditEvent){
+		"séquence":           func(e *model.AuditEvent) { e.Sequence++ },
+		"date":               func(e *model.AuditEvent) { e.OccurredAt = e.OccurredAt.Add(time.Microsecond) },
+		"action":             func(e *model.AuditEvent) { e.Action = "user.restore" },
+		"résultat":           func(e *model.AuditEvent) { e.Outcome = model.AuditOutcomeFailure },
+		"auteur":             func(e *model.AuditEvent) { e.ActorId = "other" },
+		"cible":              func(e *model.AuditEvent) { e.TargetId = "u2" },
+		"détails":            func(e *model.AuditEvent) { e.Details = "{}" },
+		"empreinte initiale": func(e *model.AuditEvent) { e.PreviousHash = "abd" },
+	}
+	for name, change := range changes {
+		changed := event
+		change(&changed)
+		if changed.ComputeHash() == hash {
+			t.Errorf("modifier %s doit changer l'empreinte", name)
+		}
+	}
+
+	// Les champs sont séparés : déplacer un caractère d'un champ à l'autre change l'empreinte
+	shifted := event
+	shifted.ActorId, shifted.ActorEmail = "admi", "n"
+	if shifted.ComputeHash() == hash {
+		t.Error("les frontières entre les champs doivent être prises en compte")
+	}
+}
+
+// newTestAuditService crée un journal de events entrées dans une base temporaire
+func newTestAuditService(t *testing.T, events int) (*AuditService, *gorm.DB) {
+	t.Helper()
+	db := newTestDB(t)
+	service := NewAuditService(repository.NewAuditRepository(db))
+	for i := 0; i < events; i++ {
+		service.Record(model.AuditEvent{Action: "auth.login", Outcome: model.AuditOutcomeSuccess, ActorId: "u1"})
+	}
+	return service, db
+}
+
+func TestVerifyChainAcceptsIntactLog(t *testing.T) {
+	service, _ := newTestAuditService(t, 5)
+
+	verification, err := service.VerifyChain()
+	if err != nil {
+		t.Fatalf("vérification : %v", err)
+	}
+	if !verification.Valid || verification.Checked != 5 {
+		t.Errorf("vérification = %+v, 5 entrées valides attendues", verification)
+	}
+}
+
+func TestVerifyChainDetectsTampering(t *testing.T) {
+	cases := []struct {
+		name   string
+		tamper string
+		args   []interface{}
+		broken int64
+	}{
+		{"contenu modifié", "UPDATE audit_events SET actor_id = ? WHERE sequence = ?", []interface{}{"intrus", 3}, 3},
+		{"entrée supprimée", "DELETE FROM audit_events WHERE sequence = ?", []interface{}{2}, 3},
+		{"chaînage modifié", "UPDATE audit_events SET previous_hash = ? WHERE sequence = ?", []interface{}{"0000", 4}, 4},
+	}
+
+	for _, c := range cases {
+		service, db := newTestAuditService(t, 5)
+		if err := db.Exec(c.tamper, c.args...).Error; err != nil {
+			t.Fatalf("%s : altération : %v", c.name, err)
+		}
+
+		verification, err := service.VerifyChain()
+		if err != nil {
+			t.Fatalf("%s : vérification : %v", c.name, err)
+		}
+		if verification.Valid || verification.BrokenAt != c.broken || verification.Reason == "" {
+			t.Errorf("%s : vérification = %+v, rupture attendue à la séquence %d", c.name, verification, c.broken)
+		}
+	}
+}
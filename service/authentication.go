@@ -1,15 +1,22 @@
 package service
 
 import (
+	"auth/logging"
+	"auth/metrics"
 	"auth/model"
 	"auth/repository"
+	"auth/tracing"
 	"auth/utils"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
-var jwtKey = []byte("my_secret_key")
+// errRefreshTokenReused signale la présentation d'un refresh token déjà remplacé
+var errRefreshTokenReused = errors.New("refresh token reused")
 
 type TokenResponse struct {
 	Token     string
@@ -18,36 +25,115 @@ type TokenResponse struct {
 
 type OtpResponse struct {
 	SessionId string    `json:"session_id"`
-	Code      string    `json:"code"`
+	Code      string    `json:"code,omitempty"`
+	Method    string    `json:"method"`
 	IsUsed    bool      `json:"is_used"`
 	ExpireHas time.Time `json:"expire_has"`
 }
 
+// TwoFactorEnrollmentTTL est la durée de validité du token permettant d'enrôler un second facteur
+const TwoFactorEnrollmentTTL = 15 * time.Minute
+
+// TwoFactorEnrollmentError signale qu'un rôle de l'utilisateur impose la double authentification
+// alors qu'aucun second facteur n'est actif. Le token ne donne accès qu'à l'enrôlement.
+type TwoFactorEnrollmentError struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (e *TwoFactorEnrollmentError) Error() string {
+	return "votre rôle impose la double authentification : enrôlez un second facteur pour continuer"
+}
+
+// ErrorCode retourne le code d'erreur exploitable par les clients
+func (e *TwoFactorEnrollmentError) ErrorCode() string {
+	return "two_factor_enrollment_required"
+}
+
 type CustomResponse struct {
 	Data interface{}
 }
 
 // AuthenticationService gère la logique métier liée aux utilisateurs
 type AuthenticationService struct {
-	userRepo *repository.UserRepository
-	roleRepo *repository.RoleRepository
-	otpRepo  *repository.OtpRepository
+	userRepo      *repository.UserRepository
+	roleRepo      *repository.RoleRepository
+	orgRepo       *repository.OrganizationRepository
+	groupRepo     *repository.GroupRepository
+	attributeRepo *repository.AttributeRepository
+	sessionRepo   *repository.SessionRepository
+	twoFactor     *TwoFactorService
+	loginHistory  *LoginHistoryService
+	security      utils.Security
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+	ctx           context.Context
 }
 
 // NewAuthenticationService create new AuthenticationService instance
 func NewAuthenticationService(
 	userRepo *repository.UserRepository,
 	roleRepo *repository.RoleRepository,
-	otpRepo *repository.OtpRepository) *AuthenticationService {
+	orgRepo *repository.OrganizationRepository,
+	groupRepo *repository.GroupRepository,
+	attributeRepo *repository.AttributeRepository,
+	sessionRepo *repository.SessionRepository,
+	twoFactor *TwoFactorService,
+	loginHistory *LoginHistoryService,
+	security utils.Security,
+	accessTTL time.Duration,
+	refreshTTL time.Duration) *AuthenticationService {
 	return &AuthenticationService{
-		userRepo: userRepo,
-		roleRepo: roleRepo,
-		otpRepo:  otpRepo,
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		orgRepo:       orgRepo,
+		groupRepo:     groupRepo,
+		attributeRepo: attributeRepo,
+		sessionRepo:   sessionRepo,
+		twoFactor:     twoFactor,
+		loginHistory:  loginHistory,
+		security:      security,
+		accessTTL:     accessTTL,
+		refreshTTL:    refreshTTL,
 	}
 }
 
-// Login permit to authenticated user
-func (a *AuthenticationService) Login(username string, password string) (model.Authentication, error) {
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (a *AuthenticationService) WithContext(ctx context.Context) *AuthenticationService {
+	return &AuthenticationService{
+		userRepo:      a.userRepo.WithContext(ctx),
+		roleRepo:      a.roleRepo.WithContext(ctx),
+		orgRepo:       a.orgRepo.WithContext(ctx),
+		groupRepo:     a.groupRepo.WithContext(ctx),
+		attributeRepo: a.attributeRepo.WithContext(ctx),
+		sessionRepo:   a.sessionRepo.WithContext(ctx),
+		twoFactor:     a.twoFactor.WithContext(ctx),
+		loginHistory:  a.loginHistory.WithContext(ctx),
+		security:      a.security,
+		accessTTL:     a.accessTTL,
+		refreshTTL:    a.refreshTTL,
+		ctx:           ctx,
+	}
+}
+
+// startSpan ouvre le span d'une opération et retourne une copie du service rattachée à ce span,
+// pour que les requêtes de l'opération y soient rattachées
+func (a *AuthenticationService) startSpan(operation string) (*AuthenticationService, trace.Span) {
+	ctx, span := tracing.Start(a.ctx, "AuthenticationService."+operation)
+	return a.WithContext(ctx), span
+}
+
+// Login permit to authenticated user. Une session est ouverte pour l'appareil du client
+// et la connexion est ajoutée à l'historique de l'utilisateur.
+func (a *AuthenticationService) Login(username string, password string, client ClientInfo) (model.Authentication, error) {
+	a, span := a.startSpan("Login")
+	authModel, err := a.passwordLogin(username, password, client)
+	metrics.ObserveLogin(LoginMethodPassword, loginOutcome(err))
+	tracing.End(span, err)
+	return authModel, err
+}
+
+func (a *AuthenticationService) passwordLogin(username string, password string, client ClientInfo) (model.Authentication, error) {
 
 	var existingUser model.User
 	existingUser, err := a.userRepo.GetUserByUsername(username)
@@ -55,36 +141,32 @@ func (a *AuthenticationService) Login(username string, password string) (model.A
 		return model.Authentication{}, fmt.Errorf("username or passwword is invalid")
 	}
 
-	errHash := utils.CompareHashPassword(password, existingUser.Password)
-	if !errHash {
-		return model.Authentication{}, fmt.Errorf("username or passwword is invalid")
+	if err := a.verifyPassword(existingUser, password); err != nil {
+		return model.Authentication{}, err
 	}
 
-	userRole, err := a.roleRepo.GetRoleById(existingUser.RoleId)
+	authModel, err := a.issueAuthentication(existingUser, "", newSession(existingUser.Id, client))
 	if err != nil {
-		return model.Authentication{}, fmt.Errorf(
-			"error system : user role has not found please call admin system to resolve this problem")
+		return model.Authentication{}, err
 	}
 
-	accessToken, _ := a.generateToken("access_token", userRole.Name, existingUser)
-	refreshToken, _ := a.generateToken("refresh_token", userRole.Name, existingUser)
-
-	authModel := model.Authentication{
-		UserId: existingUser.Id,
-		Email:  existingUser.Email,
-		Name:   existingUser.Name,
-		Role:   userRole.Name,
-		UseOTP: existingUser.UseOTP,
-		Token: model.Token{
-			AccessToken:  accessToken.Token,
-			RefreshToken: refreshToken.Token,
-			ExpiresAt:    accessToken.ExpiredAt,
-		},
-	}
+	a.loginHistory.RecordLogin(existingUser, client, LoginMethodPassword)
 	return authModel, nil
 }
 
+// AuthByOtp contrôle le premier facteur et envoie un défi. Seuls ses échecs sont comptés :
+// la connexion aboutie est comptée à la vérification du code.
 func (a *AuthenticationService) AuthByOtp(username string, password string) (OtpResponse, error) {
+	a, span := a.startSpan("AuthByOtp")
+	response, err := a.passwordChallenge(username, password)
+	if err != nil {
+		metrics.ObserveLogin(LoginMethodOtp, loginOutcome(err))
+	}
+	tracing.End(span, err)
+	return response, err
+}
+
+func (a *AuthenticationService) passwordChallenge(username string, password string) (OtpResponse, error) {
 
 	var existingUser model.User
 	existingUser, err := a.userRepo.GetUserByUsername(username)
@@ -92,27 +174,40 @@ func (a *AuthenticationService) AuthByOtp(username string, password string) (Otp
 		return OtpResponse{}, fmt.Errorf("username or passwword is invalid")
 	}
 
-	errHash := utils.CompareHashPassword(password, existingUser.Password)
-	if !errHash {
-		return OtpResponse{}, fmt.Errorf("username or passwword is invalid")
+	if err := a.verifyPassword(existingUser, password); err != nil {
+		return OtpResponse{}, err
 	}
 
-	otpModel := model.Otp{}
-	otpModel.Code = utils.OtpGenerator(6)
-	otpModel.IsUsed = false
-	otpModel.ExpireHas = time.Now().Local().Add(time.Second * time.Duration(60))
-	otpModel.UserId = existingUser.Id
-
-	response, err := a.generateOTP(existingUser.Id)
+	challenge, err := a.twoFactor.Challenge(existingUser.Id, "")
+	if err != nil {
+		return OtpResponse{}, err
+	}
 	return OtpResponse{
-		SessionId: response.SessionId,
-		Code:      response.Code,
-		ExpireHas: response.ExpireHas,
-		IsUsed:    response.IsUsed,
+		SessionId: challenge.SessionId,
+		Method:    challenge.Type,
+		ExpireHas: challenge.ExpiresAt,
 	}, nil
 }
 
-func (a *AuthenticationService) RefreshToken(userId string) (model.Authentication, error) {
+// RefreshToken remplace les tokens de la session. Le refresh token présenté doit être le dernier émis :
+// un ancien token réutilisé révoque la session, car il a pu être dérobé.
+func (a *AuthenticationService) RefreshToken(userId string, orgId string, sessionId string, refreshToken string) (model.Authentication, error) {
+	a, span := a.startSpan("RefreshToken")
+	authModel, err := a.refreshSession(userId, orgId, sessionId, refreshToken)
+	tracing.End(span, err)
+	switch {
+	case err == nil:
+		metrics.ObserveTokenRefresh("success")
+	case errors.Is(err, errRefreshTokenReused):
+		metrics.ObserveTokenRefresh("reused")
+		err = fmt.Errorf("cette session a été révoquée")
+	default:
+		metrics.ObserveTokenRefresh("rejected")
+	}
+	return authModel, err
+}
+
+func (a *AuthenticationService) refreshSession(userId string, orgId string, sessionId string, refreshToken string) (model.Authentication, error) {
 
 	var existingUser model.User
 	existingUser, err := a.userRepo.GetUserById(userId)
@@ -120,45 +215,74 @@ func (a *AuthenticationService) RefreshToken(userId string) (model.Authenticatio
 		return model.Authentication{}, fmt.Errorf("user is not exist")
 	}
 
-	userRole, err := a.roleRepo.GetRoleById(existingUser.RoleId)
+	if err := checkSessionAccountState(existingUser, time.Now()); err != nil {
+		return model.Authentication{}, err
+	}
+
+	session, err := a.activeSession(userId, sessionId)
 	if err != nil {
-		return model.Authentication{}, fmt.Errorf(
-			"error system : user role has not found please call admin system to resolve this problem")
+		return model.Authentication{}, err
+	}
+	if session.RefreshTokenHash != hashLinkToken(refreshToken) {
+		_ = a.sessionRepo.RevokeSession(session.Id, userId)
+		return model.Authentication{}, errRefreshTokenReused
 	}
 
-	accessToken, _ := a.generateToken("access_token", userRole.Name, existingUser)
-	refreshToken, _ := a.generateToken("refresh_token", userRole.Name, existingUser)
+	return a.issueAuthentication(existingUser, orgId, session)
+}
 
-	authModel := model.Authentication{
-		UserId: existingUser.Id,
-		Email:  existingUser.Email,
-		Name:   existingUser.Name,
-		Role:   userRole.Name,
-		UseOTP: existingUser.UseOTP,
-		Token: model.Token{
-			AccessToken:  accessToken.Token,
-			RefreshToken: refreshToken.Token,
-			ExpiresAt:    accessToken.ExpiredAt,
-		},
+// SwitchOrganization génère de nouveaux tokens pour une autre organisation de l'utilisateur, dans la même session
+func (a *AuthenticationService) SwitchOrganization(userId string, orgId string, sessionId string) (model.Authentication, error) {
+	a, span := a.startSpan("SwitchOrganization")
+	defer span.End()
+
+	existingUser, err := a.userRepo.GetUserById(userId)
+	if err != nil {
+		return model.Authentication{}, fmt.Errorf("user is not exist")
 	}
 
-	return authModel, nil
+	if err := checkSessionAccountState(existingUser, time.Now()); err != nil {
+		return model.Authentication{}, err
+	}
+
+	if _, err = a.orgRepo.GetMembership(userId, orgId); err != nil {
+		return model.Authentication{}, fmt.Errorf("vous n'êtes pas membre de cette organisation")
+	}
+
+	session, err := a.activeSession(userId, sessionId)
+	if err != nil {
+		return model.Authentication{}, err
+	}
+
+	return a.issueAuthentication(existingUser, orgId, session)
+}
+
+// UserOrganizations liste les organisations dont l'utilisateur est membre
+func (a *AuthenticationService) UserOrganizations(userId string) ([]model.Organization, error) {
+	a, span := a.startSpan("UserOrganizations")
+	defer span.End()
+
+	return a.orgRepo.GetOrganizationsByUser(userId)
 }
 
 func (a *AuthenticationService) Logout() {
 }
 
 func (a *AuthenticationService) ForgetPassword(email string) {
+	a, span := a.startSpan("ForgetPassword")
+	defer span.End()
+
 	searchResponse, err := a.userRepo.GetUserByEmail(email)
 	if err != nil {
 		return
 	}
 
-	fmt.Println("searchResponse :::> ", searchResponse.Email)
-
+	logging.FromContext(a.ctx).Debug("Password reset requested", "user_id", searchResponse.Id)
 }
 
-func (a *AuthenticationService) ChangePassword(userId string, password string) (model.Authentication, error) {
+func (a *AuthenticationService) ChangePassword(userId string, orgId string, sessionId string, password string) (model.Authentication, error) {
+	a, span := a.startSpan("ChangePassword")
+	defer span.End()
 
 	var existingUser model.User
 
@@ -167,46 +291,76 @@ func (a *AuthenticationService) ChangePassword(userId string, password string) (
 		return model.Authentication{}, fmt.Errorf("user is not exist")
 	}
 
+	session, err := a.activeSession(userId, sessionId)
+	if err != nil {
+		return model.Authentication{}, err
+	}
+
 	isDuplicatePassword := utils.CompareHashPassword(password, existingUser.Password)
 	if isDuplicatePassword {
 		return model.Authentication{}, fmt.Errorf("vous ne pouvez pas utiliser le même mot de passe")
 	}
 
-	newPassword, _ := utils.GenerateHashPassword(password)
+	newPassword, _ := a.security.GenerateHashPassword(password)
 	existingUser.Password = newPassword
 	existingUser.UpdatedAt = time.Now()
 
-	updateUserRespone, err := a.userRepo.UpdateUser(existingUser)
+	updateUserRespone, err := a.userRepo.WithEvent(model.EventUserPasswordReset).UpdateUser(existingUser)
 	if err != nil {
 		return model.Authentication{}, fmt.Errorf(
 			"nous avons rencontré un problème durant la mise à du mot de passe. merci de réessayer")
 	}
 
-	userRole, err := a.roleRepo.GetRoleById(updateUserRespone.RoleId)
+	return a.issueAuthentication(updateUserRespone, orgId, session)
+}
+
+// RenewExpiredPassword remplace un mot de passe expiré. Le compte n'a plus de session valide :
+// l'ancien mot de passe prouve l'identité, puis le compte redevient actif et l'utilisateur se
+// connecte avec son nouveau mot de passe.
+func (a *AuthenticationService) RenewExpiredPassword(username string, oldPassword string, newPassword string) error {
+	a, span := a.startSpan("RenewExpiredPassword")
+	defer span.End()
+
+	existingUser, err := a.userRepo.GetUserByUsername(username)
 	if err != nil {
-		return model.Authentication{}, fmt.Errorf(
-			"error system : user role has not found please call admin system to resolve this problem")
+		return fmt.Errorf("username or passwword is invalid")
 	}
 
-	accessToken, _ := a.generateToken("access_token", userRole.Name, updateUserRespone)
-	refreshToken, _ := a.generateToken("refresh_token", userRole.Name, updateUserRespone)
+	var stateErr *AccountStateError
+	err = a.verifyPassword(existingUser, oldPassword)
+	if err == nil {
+		return errors.New("le mot de passe de ce compte n'a pas expiré")
+	}
+	if !errors.As(err, &stateErr) || stateErr.State != AccountStatePasswordExpired {
+		return err
+	}
 
-	authModel := model.Authentication{
-		UserId: updateUserRespone.Id,
-		Email:  updateUserRespone.Email,
-		Name:   updateUserRespone.Name,
-		Role:   updateUserRespone.Name,
-		UseOTP: existingUser.UseOTP,
-		Token: model.Token{
-			AccessToken:  accessToken.Token,
-			RefreshToken: refreshToken.Token,
-			ExpiresAt:    accessToken.ExpiredAt,
-		},
+	if utils.CompareHashPassword(newPassword, existingUser.Password) {
+		return fmt.Errorf("vous ne pouvez pas utiliser le même mot de passe")
 	}
-	return authModel, nil
+
+	newPasswordHash, err := a.security.GenerateHashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf(
+			"nous avons rencontré un problème durant la mise à du mot de passe. merci de réessayer")
+	}
+	existingUser.Password = newPasswordHash
+	existingUser.UpdatedAt = time.Now()
+
+	updatedUser, err := a.userRepo.WithEvent(model.EventUserPasswordReset).UpdateUser(existingUser)
+	if err != nil {
+		return fmt.Errorf(
+			"nous avons rencontré un problème durant la mise à du mot de passe. merci de réessayer")
+	}
+
+	_, err = a.renewExpiredPassword(updatedUser)
+	return err
 }
 
 func (a *AuthenticationService) UserProfil(userId string) (model.User, error) {
+	a, span := a.startSpan("UserProfil")
+	defer span.End()
+
 	response, err := a.userRepo.GetUserById(userId)
 	if err != nil {
 		return model.User{}, err
@@ -221,6 +375,9 @@ func (a *AuthenticationService) UserProfil(userId string) (model.User, error) {
 }
 
 func (a *AuthenticationService) GetAuthMode(username string) (string, error) {
+	a, span := a.startSpan("GetAuthMode")
+	defer span.End()
+
 	response, err := a.userRepo.GetUserByUsername(username)
 	if err != nil {
 		return "", err
@@ -236,137 +393,369 @@ func (a *AuthenticationService) GetAuthMode(username string) (string, error) {
 	return authMode, nil
 }
 
-func (a *AuthenticationService) InitPassword(userId string, password string) error {
-	currentUser, err := a.userRepo.GetUserById(userId)
+func (a *AuthenticationService) VerifyOtpCode(sessionId string, codeOtp string, client ClientInfo) (model.Authentication, error) {
+	a, span := a.startSpan("VerifyOtpCode")
+	authModel, err := a.otpLogin(sessionId, codeOtp, client)
+	metrics.ObserveLogin(LoginMethodOtp, loginOutcome(err))
+	tracing.End(span, err)
+	return authModel, err
+}
+
+func (a *AuthenticationService) otpLogin(sessionId string, codeOtp string, client ClientInfo) (model.Authentication, error) {
+	otpModel, err := a.twoFactor.VerifyChallenge(sessionId, codeOtp)
 	if err != nil {
-		return fmt.Errorf("utilisateur inexistant")
+		return model.Authentication{}, err
+	}
+
+	var existingUser model.User
+	existingUser, err = a.userRepo.GetUserById(otpModel.UserId)
+	if err != nil {
+		return model.Authentication{}, fmt.Errorf("ce compte utilisateur n'existe pas")
 	}
 
-	newPassword, _ := utils.GenerateHashPassword(password)
-	currentUser.Password = newPassword
-	currentUser.UpdatedAt = time.Now()
+	if err := checkAccountState(existingUser, time.Now()); err != nil {
+		return model.Authentication{}, err
+	}
 
-	_, err = a.userRepo.UpdateUser(currentUser)
+	authModel, err := a.issueAuthentication(existingUser, "", newSession(existingUser.Id, client))
 	if err != nil {
-		return fmt.Errorf("une erreur est survenue durant la mise à des données client")
+		return model.Authentication{}, err
+	}
+
+	a.loginHistory.RecordLogin(existingUser, client, LoginMethodOtp)
+	return authModel, nil
+}
+
+// verifyPassword contrôle le mot de passe puis l'état du compte. Un compte verrouillé est refusé
+// avant toute comparaison ; trop d'échecs consécutifs verrouillent le compte.
+func (a *AuthenticationService) verifyPassword(user model.User, password string) error {
+	a, span := a.startSpan("verifyPassword")
+	defer span.End()
+
+	now := time.Now()
+	state := effectiveAccountState(user, now)
+	if state == AccountStateLocked {
+		return checkAccountState(user, now)
+	}
+	// Un mot de passe expiré ne se verrouille pas temporairement, sinon la fin du verrouillage
+	// rendrait le compte actif sans renouvellement : après trop d'échecs, un administrateur doit intervenir
+	if state == AccountStatePasswordExpired && user.FailedLoginAttempts >= MaxFailedLogins {
+		return errors.New("trop d'échecs de connexion, merci de contacter un administrateur")
+	}
+
+	if !utils.CompareHashPassword(password, user.Password) {
+		attempts, err := a.userRepo.IncrementFailedLogins(user.Id)
+		if err == nil && attempts >= MaxFailedLogins && state == AccountStateActive {
+			lockedUser, err := transitionAccountState(a.userRepo, user, AccountStateLocked, "too many failed login attempts")
+			if err == nil {
+				metrics.ObserveLockout()
+				return checkAccountState(lockedUser, now)
+			}
+		}
+		return fmt.Errorf("username or passwword is invalid")
 	}
 
-	//Send Email or SMS to submit new password
+	if err := checkAccountState(user, now); err != nil {
+		return err
+	}
+
+	// Un verrouillage expiré est levé à la première connexion réussie
+	if user.Status == AccountStateLocked {
+		return a.userRepo.UpdateAccountState(user.Id, repository.AccountStateUpdate{Status: AccountStateActive})
+	}
+	if user.FailedLoginAttempts > 0 {
+		return a.userRepo.ResetFailedLogins(user.Id)
+	}
 	return nil
 }
 
-func (a *AuthenticationService) VerifyOtpCode(sessionId string, codeOtp string) (model.Authentication, error) {
-	otpModel, err := a.otpRepo.GetOtpById(sessionId)
+// renewExpiredPassword réactive un compte dont le mot de passe avait expiré
+func (a *AuthenticationService) renewExpiredPassword(user model.User) (model.User, error) {
+	if effectiveAccountState(user, time.Now()) != AccountStatePasswordExpired {
+		return user, nil
+	}
+	return transitionAccountState(a.userRepo, user, AccountStateActive, "")
+}
+
+// CheckToken rejette les tokens d'un compte inactif ou dont les sessions ont été révoquées.
+// Pour un token d'impersonation, le compte de l'administrateur est également vérifié.
+func (a *AuthenticationService) CheckToken(claims *model.Claims) error {
+	a, span := a.startSpan("CheckToken")
+	defer span.End()
+
+	if err := checkSessionToken(a.userRepo, claims.Id, claims.IssuedAt); err != nil {
+		return err
+	}
+	if claims.SessionId != "" {
+		if err := checkSession(a.sessionRepo, claims.SessionId, claims.Id); err != nil {
+			return err
+		}
+	}
+	if claims.Act != nil {
+		return checkSessionToken(a.userRepo, claims.Act.Subject, claims.IssuedAt)
+	}
+	return nil
+}
+
+// issueAuthentication génère les tokens de l'utilisateur pour l'organisation demandée.
+// Sans organisation, la plus ancienne adhésion de l'utilisateur est utilisée. Une session
+// non enregistrée est créée ; le refresh token émis remplace le précédent de la session.
+func (a *AuthenticationService) issueAuthentication(user model.User, orgId string, session model.Session) (model.Authentication, error) {
+	a, span := a.startSpan("issueAuthentication")
+	defer span.End()
+
+	roleName, membership, orgRoles, err := a.resolveAccess(user, orgId)
 	if err != nil {
-		return model.Authentication{}, fmt.Errorf("votre session a espiré. Merci de généré un nouveau code otp")
+		return model.Authentication{}, err
 	}
 
-	if otpModel.Code != codeOtp {
-		return model.Authentication{}, fmt.Errorf("code otp invalid, merci de réessayer")
+	if !user.UseOTP {
+		required, err := a.twoFactor.IsRequired(append([]string{roleName}, orgRoles...))
+		if err != nil {
+			return model.Authentication{}, fmt.Errorf(
+				"error system : user roles has not found please call admin system to resolve this problem")
+		}
+		if required {
+			enrollmentToken, err := a.generateToken("two_factor_enrollment", roleName, membership.OrganizationId, orgRoles, nil, "", user)
+			if err != nil {
+				return model.Authentication{}, err
+			}
+			return model.Authentication{}, &TwoFactorEnrollmentError{Token: enrollmentToken.Token, ExpiresAt: enrollmentToken.ExpiredAt}
+		}
 	}
 
-	expire_date := otpModel.ExpireHas
-	is_valid_date := expire_date.Before(time.Now())
-	if is_valid_date {
+	attrs, err := claimAttributes(a.attributeRepo, user.Id, membership.OrganizationId)
+	if err != nil {
+		return model.Authentication{}, fmt.Errorf(
+			"error system : user attributes has not found please call admin system to resolve this problem")
+	}
 
-		otpModel.IsUsed = true
-		_, err = a.otpRepo.UpdateOtp(otpModel)
+	if session.Id == "" {
+		session, err = a.sessionRepo.CreateSession(session)
 		if err != nil {
-			return model.Authentication{}, fmt.Errorf("error system : nous avons rencontré un problème pendant la mise à jour du code otp")
+			return model.Authentication{}, fmt.Errorf(
+				"error system : nous avons rencontré un problème durant la création de la session")
 		}
+	}
+
+	accessToken, _ := a.generateToken("access_token", roleName, membership.OrganizationId, orgRoles, attrs, session.Id, user)
+	refreshToken, _ := a.generateToken("refresh_token", roleName, membership.OrganizationId, orgRoles, nil, session.Id, user)
 
-		return model.Authentication{}, fmt.Errorf("code otp expiré")
+	if err := a.sessionRepo.RotateSession(session, hashLinkToken(refreshToken.Token), refreshToken.ExpiredAt); err != nil {
+		return model.Authentication{}, fmt.Errorf("cette session a été révoquée")
 	}
 
-	var existingUser model.User
-	existingUser, err = a.userRepo.GetUserById(otpModel.UserId)
+	authModel := model.Authentication{
+		UserId: user.Id,
+		Email:  user.Email,
+		Name:   user.Name,
+		Role:   roleName,
+		OrgId:  membership.OrganizationId,
+		UseOTP: user.UseOTP,
+		Token: model.Token{
+			AccessToken:  accessToken.Token,
+			RefreshToken: refreshToken.Token,
+			ExpiresAt:    accessToken.ExpiredAt,
+		},
+	}
+	return authModel, nil
+}
+
+// IssueImpersonation génère un access token pour l'utilisateur ciblé, portant le claim "act"
+// de l'administrateur. Aucun refresh token n'est émis : la session s'arrête à son expiration.
+func (a *AuthenticationService) IssueImpersonation(
+	user model.User, orgId string, actor model.Actor, expiresAt time.Time) (model.Authentication, error) {
+	a, span := a.startSpan("IssueImpersonation")
+	defer span.End()
+
+	roleName, membership, orgRoles, err := a.resolveAccess(user, orgId)
 	if err != nil {
-		return model.Authentication{}, fmt.Errorf("ce compte utilisateur n'existe pas")
+		return model.Authentication{}, err
 	}
 
-	userRole, err := a.roleRepo.GetRoleById(existingUser.RoleId)
+	attrs, err := claimAttributes(a.attributeRepo, user.Id, membership.OrganizationId)
 	if err != nil {
 		return model.Authentication{}, fmt.Errorf(
-			"error system : user role has not found please call admin system to resolve this problem")
+			"error system : user attributes has not found please call admin system to resolve this problem")
 	}
 
-	accessToken, _ := a.generateToken("access_token", userRole.Name, existingUser)
-	refreshToken, _ := a.generateToken("refresh_token", userRole.Name, existingUser)
-
-	otpModel.IsUsed = true
-	_, err = a.otpRepo.UpdateOtp(otpModel)
+	accessToken, err := a.signToken(model.Claims{
+		Role:   roleName,
+		Source: "access_token",
+		OrgId:  membership.OrganizationId,
+		Roles:  orgRoles,
+		Act:    &actor,
+		Attrs:  attrs,
+		StandardClaims: jwt.StandardClaims{
+			Id:        user.Id,
+			Subject:   user.Email,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}, expiresAt)
 	if err != nil {
-		return model.Authentication{}, fmt.Errorf("error system : nous avons rencontré un problème pendant la mise à jour du code otp")
+		return model.Authentication{}, err
 	}
 
 	authModel := model.Authentication{
-		UserId: existingUser.Id,
-		Email:  existingUser.Email,
-		Name:   existingUser.Name,
-		Role:   userRole.Name,
-		UseOTP: existingUser.UseOTP,
+		UserId: user.Id,
+		Email:  user.Email,
+		Name:   user.Name,
+		Role:   roleName,
+		OrgId:  membership.OrganizationId,
+		UseOTP: user.UseOTP,
 		Token: model.Token{
-			AccessToken:  accessToken.Token,
-			RefreshToken: refreshToken.Token,
-			ExpiresAt:    accessToken.ExpiredAt,
+			AccessToken: accessToken.Token,
+			ExpiresAt:   accessToken.ExpiredAt,
 		},
 	}
 	return authModel, nil
 }
 
-func (a *AuthenticationService) generateToken(source string, roleName string, user model.User) (TokenResponse, error) {
+// activeSession récupère la session active d'un token
+func (a *AuthenticationService) activeSession(userId string, sessionId string) (model.Session, error) {
+	session, err := a.sessionRepo.GetSessionById(sessionId)
+	if err != nil || session.UserId != userId || !session.IsActive(time.Now()) {
+		return model.Session{}, fmt.Errorf("cette session a été révoquée")
+	}
+	return session, nil
+}
+
+// resolveAccess retourne le rôle système, l'adhésion et les rôles effectifs de l'utilisateur
+func (a *AuthenticationService) resolveAccess(user model.User, orgId string) (string, model.Membership, []string, error) {
+	userRole, err := a.roleRepo.GetRoleById(user.RoleId)
+	if err != nil {
+		return "", model.Membership{}, nil, fmt.Errorf(
+			"error system : user role has not found please call admin system to resolve this problem")
+	}
+
+	membership, err := a.resolveMembership(user.Id, orgId)
+	if err != nil {
+		return "", model.Membership{}, nil, err
+	}
+
+	orgRoles, err := membershipRoleNames(a.roleRepo, a.groupRepo, membership)
+	if err != nil {
+		return "", model.Membership{}, nil, fmt.Errorf(
+			"error system : organization roles has not found please call admin system to resolve this problem")
+	}
+	return userRole.Name, membership, orgRoles, nil
+}
+
+func (a *AuthenticationService) resolveMembership(userId string, orgId string) (model.Membership, error) {
+	if orgId != "" {
+		if _, err := a.orgRepo.GetOrganizationById(orgId); err != nil {
+			return model.Membership{}, fmt.Errorf("cette organisation n'existe pas")
+		}
+
+		membership, err := a.orgRepo.GetMembership(userId, orgId)
+		if err != nil {
+			return model.Membership{}, fmt.Errorf("vous n'êtes pas membre de cette organisation")
+		}
+		return membership, nil
+	}
+
+	memberships, err := a.orgRepo.GetMembershipsByUser(userId)
+	if err != nil || len(memberships) == 0 {
+		return model.Membership{}, fmt.Errorf("ce compte n'est rattaché à aucune organisation")
+	}
+	return memberships[0], nil
+}
+
+// membershipRoleNames retourne les rôles de l'adhésion et ceux hérités
+// des groupes de l'utilisateur dans la même organisation
+func membershipRoleNames(roleRepo *repository.RoleRepository, groupRepo *repository.GroupRepository, membership model.Membership) ([]string, error) {
+	var roleIds []string
+	for i := 0; i < len(membership.MembershipRoles); i++ {
+		roleIds = append(roleIds, membership.MembershipRoles[i].RoleId)
+	}
+
+	groups, err := groupRepo.ForOrganization(membership.OrganizationId).GetGroupsByUser(membership.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(groups); i++ {
+		for j := 0; j < len(groups[i].GroupRoles); j++ {
+			roleIds = append(roleIds, groups[i].GroupRoles[j].RoleId)
+		}
+	}
+
+	roles, err := roleRepo.GetRolesByIds(roleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var roleNames []string
+	for i := 0; i < len(roles); i++ {
+		roleNames = append(roleNames, roles[i].Name)
+	}
+	return roleNames, nil
+}
+
+func (a *AuthenticationService) generateToken(
+	source string, roleName string, orgId string, orgRoles []string, attrs map[string]interface{}, sessionId string, user model.User) (TokenResponse, error) {
 
 	var expirationTime time.Time
 
 	switch source {
 	case "refresh_token":
-		expirationTime = time.Now().Add(6 * time.Hour)
+		expirationTime = time.Now().Add(a.refreshTTL)
 	case "access_token":
-		expirationTime = time.Now().Add(1 * time.Hour)
+		expirationTime = time.Now().Add(a.accessTTL)
+	case "two_factor_enrollment":
+		expirationTime = time.Now().Add(TwoFactorEnrollmentTTL)
 	}
 
 	claimsAccessToken := model.Claims{
-		Role:   roleName,
-		Source: source,
+		Role:      roleName,
+		Source:    source,
+		OrgId:     orgId,
+		Roles:     orgRoles,
+		Attrs:     attrs,
+		SessionId: sessionId,
 		StandardClaims: jwt.StandardClaims{
 			Id:        user.Id,
 			Subject:   user.Email,
+			IssuedAt:  time.Now().Unix(),
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claimsAccessToken)
+	return a.signToken(claimsAccessToken, expirationTime)
+}
 
-	tokenString, err := token.SignedString(jwtKey)
+func (a *AuthenticationService) signToken(claims model.Claims, expirationTime time.Time) (TokenResponse, error) {
+	tokenString, err := a.security.SignToken(claims)
 	if err != nil {
 		return TokenResponse{}, fmt.Errorf(
 			"error system : failled to signed token")
 	}
+
+	tokenType := claims.Source
+	if claims.Act != nil {
+		tokenType = "impersonation"
+	}
+	metrics.ObserveTokenIssued(tokenType)
 	return TokenResponse{
 		Token:     tokenString,
 		ExpiredAt: expirationTime,
 	}, nil
 }
 
-func (a *AuthenticationService) generateOTP(userId string) (OtpResponse, error) {
-	_, err := a.userRepo.GetUserById(userId)
-	if err != nil {
-		return OtpResponse{}, err
+// loginOutcome résume le résultat d'une tentative de connexion pour les métriques :
+// success, failure, le code de l'état du compte refusé ou two_factor_enrollment_required
+func loginOutcome(err error) string {
+	if err == nil {
+		return "success"
 	}
-
-	otp_model := model.Otp{}
-	otp_model.UserId = userId
-	otp_model.ExpireHas = time.Now().Local().Add(time.Second * time.Duration(60))
-	otp_model.Code = utils.OtpGenerator(6)
-
-	response, err := a.otpRepo.CreateOtp(otp_model)
-	if err != nil {
-		return OtpResponse{}, nil
+	var stateErr *AccountStateError
+	if errors.As(err, &stateErr) {
+		return stateErr.ErrorCode()
 	}
-	return OtpResponse{
-		SessionId: response.Id,
-		Code:      response.Code,
-		ExpireHas: response.ExpireHas,
-		IsUsed:    response.IsUsed,
-	}, nil
+	var enrollmentErr *TwoFactorEnrollmentError
+	if errors.As(err, &enrollmentErr) {
+		return "two_factor_enrollment_required"
+	}
+	return "failure"
 }
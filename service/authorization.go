@@ -0,0 +1,327 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	slog "github.com/sagikazarmark/slog-shim"
+	"time"
+)
+
+type Policy struct {
+	Name     string         `json:"name" validate:"required,max=120"`
+	Describe string         `json:"describe" validate:"max=500"`
+	Document PolicyDocument `json:"document" validate:"required"`
+}
+
+type PolicyDetail struct {
+	Policy   model.Policy
+	Document PolicyDocument
+}
+
+// AuthorizationService évalue les politiques d'accès basées sur les attributs
+type AuthorizationService struct {
+	policyRepo *repository.PolicyRepository
+	userRepo   *repository.UserRepository
+	roleRepo   *repository.RoleRepository
+	orgRepo    *repository.OrganizationRepository
+	groupRepo  *repository.GroupRepository
+	logger     *slog.Logger
+}
+
+// NewAuthorizationService crée une nouvelle instance de AuthorizationService
+func NewAuthorizationService(
+	policyRepo *repository.PolicyRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	groupRepo *repository.GroupRepository,
+) *AuthorizationService {
+	return &AuthorizationService{
+		policyRepo: policyRepo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		orgRepo:    orgRepo,
+		groupRepo:  groupRepo,
+		logger:     slog.Default(),
+	}
+}
+
+// ForOrganization retourne une copie du service limitée aux politiques de l'organisation fournie
+func (s *AuthorizationService) ForOrganization(orgId string) *AuthorizationService {
+	return &AuthorizationService{
+		policyRepo: s.policyRepo.ForOrganization(orgId),
+		userRepo:   s.userRepo,
+		roleRepo:   s.roleRepo,
+		orgRepo:    s.orgRepo,
+		groupRepo:  s.groupRepo,
+		logger:     s.logger,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *AuthorizationService) WithContext(ctx context.Context) *AuthorizationService {
+	return &AuthorizationService{
+		policyRepo: s.policyRepo.WithContext(ctx),
+		userRepo:   s.userRepo.WithContext(ctx),
+		roleRepo:   s.roleRepo.WithContext(ctx),
+		orgRepo:    s.orgRepo.WithContext(ctx),
+		groupRepo:  s.groupRepo.WithContext(ctx),
+		logger:     logging.FromContext(ctx),
+	}
+}
+
+// Check évalue la requête et retourne la décision avec ses raisons. En l'absence de politique
+// applicable, l'accès est accordé si les rôles du sujet donnent la permission "<action>_<type>".
+func (s *AuthorizationService) Check(request AuthorizationRequest) (AuthorizationDecision, error) {
+	policies, err := s.loadPolicies()
+	if err != nil {
+		return AuthorizationDecision{}, err
+	}
+
+	request.Subject, err = s.resolveSubject(request.Subject)
+	if err != nil {
+		return AuthorizationDecision{}, err
+	}
+	return decide(policies, normalizeRequest(request, time.Now())), nil
+}
+
+// CheckBatch évalue plusieurs couples (action, ressource) pour un même sujet, avec les mêmes
+// règles que Check. Les politiques ne sont chargées qu'une fois.
+func (s *AuthorizationService) CheckBatch(subject map[string]interface{}, requests []AuthorizationRequest) ([]AuthorizationDecision, error) {
+	policies, err := s.loadPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err = s.resolveSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	decisions := []AuthorizationDecision{}
+	for i := 0; i < len(requests); i++ {
+		request := requests[i]
+		request.Subject = subject
+		request = normalizeRequest(request, now)
+
+		decisions = append(decisions, decide(policies, request))
+	}
+	return decisions, nil
+}
+
+// resolveSubject remplace le rôle, les rôles et les permissions du sujet par ceux enregistrés :
+// ils ne sont jamais lus dans la requête. Le sujet est identifié par subject.id et ses rôles
+// d'organisation par subject.org_id. Un sujet inconnu ou inactif n'a ni rôle ni permission.
+func (s *AuthorizationService) resolveSubject(subject map[string]interface{}) (map[string]interface{}, error) {
+	resolved := map[string]interface{}{}
+	for key, value := range subject {
+		if key != "role" && key != "roles" && key != "permissions" {
+			resolved[key] = value
+		}
+	}
+	resolved["roles"] = []interface{}{}
+	resolved["permissions"] = []interface{}{}
+
+	userId, _ := subject["id"].(string)
+	if userId == "" {
+		return resolved, nil
+	}
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil || checkSessionAccountState(user, time.Now()) != nil {
+		return resolved, nil
+	}
+
+	role, err := s.roleRepo.GetRoleById(user.RoleId)
+	if err != nil {
+		return nil, errors.New("error system : impossible de charger les rôles du sujet")
+	}
+	roleNames := []string{role.Name}
+
+	if orgId, _ := subject["org_id"].(string); orgId != "" {
+		if membership, err := s.orgRepo.GetMembership(user.Id, orgId); err == nil {
+			orgRoles, err := membershipRoleNames(s.roleRepo, s.groupRepo, membership)
+			if err != nil {
+				return nil, errors.New("error system : impossible de charger les rôles du sujet")
+			}
+			roleNames = append(roleNames, orgRoles...)
+		}
+	}
+
+	roles := []interface{}{}
+	for i := 0; i < len(roleNames); i++ {
+		roles = append(roles, roleNames[i])
+	}
+	permissions := []interface{}{}
+	granted := utils.LoadPermissionByRoleNames(roleNames...).Permissions
+	for i := 0; i < len(granted); i++ {
+		permissions = append(permissions, granted[i])
+	}
+
+	resolved["role"] = role.Name
+	resolved["roles"] = roles
+	resolved["permissions"] = permissions
+	return resolved, nil
+}
+
+func (s *AuthorizationService) loadPolicies() ([]evaluatedPolicy, error) {
+	policies, err := s.policyRepo.GetActivePolicies()
+	if err != nil {
+		return nil, errors.New("error system : impossible de charger les politiques d'accès")
+	}
+
+	var evaluated []evaluatedPolicy
+	for i := 0; i < len(policies); i++ {
+		var document PolicyDocument
+		if err := json.Unmarshal([]byte(policies[i].Document), &document); err != nil {
+			s.logger.Warn("Skipped invalid policy", "name", policies[i].Name, "version", policies[i].Version, "error", err)
+			continue
+		}
+
+		evaluated = append(evaluated, evaluatedPolicy{
+			Name:     policies[i].Name,
+			Version:  policies[i].Version,
+			Document: document,
+		})
+	}
+	return evaluated, nil
+}
+
+// GetPolicies récupère la version active de chaque politique
+func (s *AuthorizationService) GetPolicies() ([]PolicyDetail, error) {
+	policies, err := s.policyRepo.GetActivePolicies()
+	if err != nil {
+		return nil, err
+	}
+	return policyDetails(policies), nil
+}
+
+// GetPolicy récupère la version active d'une politique
+func (s *AuthorizationService) GetPolicy(name string) (PolicyDetail, error) {
+	policy, err := s.policyRepo.GetActivePolicyByName(name)
+	if err != nil {
+		return PolicyDetail{}, errors.New("politique non trouvée")
+	}
+	return policyDetail(policy), nil
+}
+
+// GetPolicyVersions récupère l'historique des versions d'une politique
+func (s *AuthorizationService) GetPolicyVersions(name string) ([]PolicyDetail, error) {
+	policies, err := s.policyRepo.GetPolicyVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, errors.New("politique non trouvée")
+	}
+	return policyDetails(policies), nil
+}
+
+// CreatePolicy enregistre la première version d'une politique
+func (s *AuthorizationService) CreatePolicy(newPolicy Policy, createdBy string) (PolicyDetail, error) {
+	versions, err := s.policyRepo.GetPolicyVersions(newPolicy.Name)
+	if err != nil {
+		return PolicyDetail{}, err
+	}
+	if len(versions) > 0 {
+		return PolicyDetail{}, errors.New("une politique porte déjà ce nom")
+	}
+	return s.savePolicy(newPolicy, createdBy)
+}
+
+// UpdatePolicy enregistre une nouvelle version d'une politique existante
+func (s *AuthorizationService) UpdatePolicy(updatedPolicy Policy, createdBy string) (PolicyDetail, error) {
+	versions, err := s.policyRepo.GetPolicyVersions(updatedPolicy.Name)
+	if err != nil {
+		return PolicyDetail{}, err
+	}
+	if len(versions) == 0 {
+		return PolicyDetail{}, errors.New("politique non trouvée")
+	}
+	return s.savePolicy(updatedPolicy, createdBy)
+}
+
+// DeactivatePolicy retire une politique de l'évaluation sans supprimer son historique
+func (s *AuthorizationService) DeactivatePolicy(name string) error {
+	if err := s.policyRepo.DeactivatePolicy(name); err != nil {
+		return errors.New("politique non trouvée")
+	}
+	return nil
+}
+
+func (s *AuthorizationService) savePolicy(policy Policy, createdBy string) (PolicyDetail, error) {
+	if err := validatePolicyDocument(policy.Document); err != nil {
+		return PolicyDetail{}, err
+	}
+
+	document, err := json.Marshal(policy.Document)
+	if err != nil {
+		return PolicyDetail{}, err
+	}
+
+	savedPolicy, err := s.policyRepo.CreatePolicyVersion(model.Policy{
+		Name:      policy.Name,
+		Describe:  policy.Describe,
+		Document:  string(document),
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return PolicyDetail{}, errors.New("nous avons rencontré un problème durant l'enregistrement de la politique")
+	}
+	return PolicyDetail{Policy: savedPolicy, Document: policy.Document}, nil
+}
+
+// normalizeRequest remplace les attributs absents par des valeurs vides et complète l'environnement
+func normalizeRequest(request AuthorizationRequest, now time.Time) AuthorizationRequest {
+	if request.Subject == nil {
+		request.Subject = map[string]interface{}{}
+	}
+	if request.Resource == nil {
+		request.Resource = map[string]interface{}{}
+	}
+
+	environment := map[string]interface{}{}
+	for key, value := range request.Environment {
+		environment[key] = value
+	}
+	request.Environment = withDefaultEnvironment(environment, now)
+	return request
+}
+
+// withDefaultEnvironment complète l'environnement avec l'heure courante
+func withDefaultEnvironment(environment map[string]interface{}, now time.Time) map[string]interface{} {
+	if environment == nil {
+		environment = map[string]interface{}{}
+	}
+
+	defaults := map[string]interface{}{
+		"time":    now.Format(time.RFC3339),
+		"hour":    float64(now.Hour()),
+		"weekday": float64(now.Weekday()),
+	}
+	for key, value := range defaults {
+		if _, ok := environment[key]; !ok {
+			environment[key] = value
+		}
+	}
+	return environment
+}
+
+func policyDetails(policies []model.Policy) []PolicyDetail {
+	details := []PolicyDetail{}
+	for i := 0; i < len(policies); i++ {
+		details = append(details, policyDetail(policies[i]))
+	}
+	return details
+}
+
+func policyDetail(policy model.Policy) PolicyDetail {
+	var document PolicyDocument
+	_ = json.Unmarshal([]byte(policy.Document), &document)
+	return PolicyDetail{Policy: policy, Document: document}
+}
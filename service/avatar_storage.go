@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// MaxAvatarDimension est la largeur et la hauteur maximales d'une photo de profil, en pixels
+const MaxAvatarDimension = 4096
+
+// avatarExtensions associe les types d'image acceptés à l'extension du fichier enregistré
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// AvatarStorage enregistre les photos de profil dans un répertoire local
+type AvatarStorage struct {
+	dir     string
+	maxSize int64
+}
+
+// NewAvatarStorage crée une nouvelle instance de AvatarStorage
+func NewAvatarStorage(dir string, maxSize int64) *AvatarStorage {
+	return &AvatarStorage{
+		dir:     dir,
+		maxSize: maxSize,
+	}
+}
+
+// MaxSize retourne la taille maximale d'une photo de profil, en octets
+func (s *AvatarStorage) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Save vérifie la taille et le type réel de l'image, puis l'enregistre sous le nom de l'utilisateur.
+// Le type est déduit du contenu et non de l'extension ou de l'en-tête envoyés par le client.
+func (s *AvatarStorage) Save(userId string, content []byte) (string, error) {
+	if int64(len(content)) > s.maxSize {
+		return "", fmt.Errorf("l'image ne doit pas dépasser %d octets", s.maxSize)
+	}
+
+	extension, ok := avatarExtensions[http.DetectContentType(content)]
+	if !ok {
+		return "", errors.New("seules les images PNG, JPEG et GIF sont acceptées")
+	}
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return "", errors.New("l'image est illisible")
+	}
+	if imageConfig.Width > MaxAvatarDimension || imageConfig.Height > MaxAvatarDimension {
+		return "", fmt.Errorf("l'image ne doit pas dépasser %dx%d pixels", MaxAvatarDimension, MaxAvatarDimension)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return "", errors.New("error system : impossible d'enregistrer l'image")
+	}
+
+	// Écriture dans un fichier temporaire puis renommage, pour ne jamais servir une image incomplète
+	fileName := userId + extension
+	tmpFile, err := os.CreateTemp(s.dir, userId+"-*.tmp")
+	if err != nil {
+		return "", errors.New("error system : impossible d'enregistrer l'image")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return "", errors.New("error system : impossible d'enregistrer l'image")
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", errors.New("error system : impossible d'enregistrer l'image")
+	}
+	if err := os.Rename(tmpFile.Name(), s.Path(fileName)); err != nil {
+		return "", errors.New("error system : impossible d'enregistrer l'image")
+	}
+	return fileName, nil
+}
+
+// Path retourne le chemin du fichier d'une photo de profil
+func (s *AvatarStorage) Path(fileName string) string {
+	return filepath.Join(s.dir, filepath.Base(fileName))
+}
+
+// Delete supprime une photo de profil ; un fichier déjà absent n'est pas une erreur
+func (s *AvatarStorage) Delete(fileName string) error {
+	if fileName == "" {
+		return nil
+	}
+	if err := os.Remove(s.Path(fileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
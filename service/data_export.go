@@ -0,0 +1,493 @@
+package service
+
+import (
+	"archive/zip"
+	"auth/model"
+	"auth/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// DataExportProfile est le profil de l'utilisateur, sans mot de passe
+type DataExportProfile struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Sername     string    `json:"sername"`
+	Email       string    `json:"email"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	Locale      string    `json:"locale"`
+	UseOTP      bool      `json:"use_otp"`
+	IsAvailable bool      `json:"is_available"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DataExportMembership est l'adhésion de l'utilisateur à une organisation
+type DataExportMembership struct {
+	OrganizationId   string    `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	OrganizationSlug string    `json:"organization_slug"`
+	Roles            []string  `json:"roles"`
+	JoinedAt         time.Time `json:"joined_at"`
+}
+
+// DataExportGroup est un groupe dont l'utilisateur est membre
+type DataExportGroup struct {
+	Id             string   `json:"id"`
+	Name           string   `json:"name"`
+	OrganizationId string   `json:"organization_id"`
+	Roles          []string `json:"roles"`
+}
+
+// DataExportTwoFactor décrit la double authentification, sans les codes
+type DataExportTwoFactor struct {
+	Enabled    bool                      `json:"enabled"`
+	Methods    []DataExportTwoFactorItem `json:"methods"`
+	Challenges []DataExportOtpChallenge  `json:"challenges"`
+}
+
+// DataExportTwoFactorItem est un second facteur enrôlé, sans sa clé
+type DataExportTwoFactorItem struct {
+	Type        string    `json:"type"`
+	Label       string    `json:"label"`
+	IsDefault   bool      `json:"is_default"`
+	CreatedAt   time.Time `json:"created_at"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+}
+
+// DataExportOtpChallenge est un code otp envoyé à l'utilisateur, sans sa valeur
+type DataExportOtpChallenge struct {
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IsUsed    bool      `json:"is_used"`
+}
+
+// DataExportImpersonation est une session durant laquelle un administrateur a agi au nom de l'utilisateur
+type DataExportImpersonation struct {
+	Id        string                      `json:"id"`
+	ActorId   string                      `json:"actor_id"`
+	Reason    string                      `json:"reason"`
+	StartedAt time.Time                   `json:"started_at"`
+	ExpiresAt time.Time                   `json:"expires_at"`
+	EndedAt   time.Time                   `json:"ended_at"`
+	Actions   []model.ImpersonationAction `json:"actions"`
+}
+
+// DataExportAttribute est la valeur d'un attribut personnalisé dans une organisation
+type DataExportAttribute struct {
+	OrganizationId string      `json:"organization_id"`
+	Name           string      `json:"name"`
+	Label          string      `json:"label"`
+	Value          interface{} `json:"value"`
+}
+
+// DataExportSession est une connexion de l'utilisateur sur un appareil
+type DataExportSession struct {
+	Id         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	RevokedAt  time.Time `json:"revoked_at"`
+}
+
+// DataExportLogin est une connexion réussie de l'historique
+type DataExportLogin struct {
+	Method      string    `json:"method"`
+	Device      string    `json:"device"`
+	UserAgent   string    `json:"user_agent"`
+	IpAddress   string    `json:"ip_address"`
+	Country     string    `json:"country"`
+	City        string    `json:"city"`
+	NewDevice   bool      `json:"new_device"`
+	NewLocation bool      `json:"new_location"`
+	ReportedAt  time.Time `json:"reported_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+const dataExportReadme = `Export des données personnelles
+
+profile.json         profil du compte (le mot de passe n'est jamais exporté)
+organizations.json   organisations dont vous êtes membre et rôles associés
+groups.json          groupes dont vous êtes membre et rôles hérités
+two_factor.json      état de la double authentification, seconds facteurs et codes envoyés (sans leur valeur)
+impersonations.json  sessions durant lesquelles un administrateur a agi en votre nom
+attributes.json      attributs de profil personnalisés, par organisation
+sessions.json        connexions par appareil (navigateur, adresse IP, dernière activité)
+logins.json          historique des connexions (appareil, adresse IP, localisation approximative, alertes)
+`
+
+// DataExportService rassemble les données personnelles d'un utilisateur (droit d'accès RGPD)
+type DataExportService struct {
+	userRepo          *repository.UserRepository
+	roleRepo          *repository.RoleRepository
+	orgRepo           *repository.OrganizationRepository
+	groupRepo         *repository.GroupRepository
+	otpRepo           *repository.OtpRepository
+	impersonationRepo *repository.ImpersonationRepository
+	attributeRepo     *repository.AttributeRepository
+	methodRepo        *repository.TwoFactorMethodRepository
+	sessionRepo       *repository.SessionRepository
+	loginRepo         *repository.LoginEventRepository
+}
+
+// NewDataExportService crée une nouvelle instance de DataExportService
+func NewDataExportService(
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	groupRepo *repository.GroupRepository,
+	otpRepo *repository.OtpRepository,
+	impersonationRepo *repository.ImpersonationRepository,
+	attributeRepo *repository.AttributeRepository,
+	methodRepo *repository.TwoFactorMethodRepository,
+	sessionRepo *repository.SessionRepository,
+	loginRepo *repository.LoginEventRepository) *DataExportService {
+	return &DataExportService{
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		orgRepo:           orgRepo,
+		groupRepo:         groupRepo,
+		otpRepo:           otpRepo,
+		impersonationRepo: impersonationRepo,
+		attributeRepo:     attributeRepo,
+		methodRepo:        methodRepo,
+		sessionRepo:       sessionRepo,
+		loginRepo:         loginRepo,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *DataExportService) WithContext(ctx context.Context) *DataExportService {
+	return &DataExportService{
+		userRepo:          s.userRepo.WithContext(ctx),
+		roleRepo:          s.roleRepo.WithContext(ctx),
+		orgRepo:           s.orgRepo.WithContext(ctx),
+		groupRepo:         s.groupRepo.WithContext(ctx),
+		otpRepo:           s.otpRepo.WithContext(ctx),
+		impersonationRepo: s.impersonationRepo.WithContext(ctx),
+		attributeRepo:     s.attributeRepo.WithContext(ctx),
+		methodRepo:        s.methodRepo.WithContext(ctx),
+		sessionRepo:       s.sessionRepo.WithContext(ctx),
+		loginRepo:         s.loginRepo.WithContext(ctx),
+	}
+}
+
+// WriteUserArchive écrit une archive zip contenant les données de l'utilisateur
+func (s *DataExportService) WriteUserArchive(userId string, writer io.Writer) error {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return errors.New("utilisateur non trouvé")
+	}
+
+	profile := s.profile(user)
+	memberships, err := s.memberships(user.Id)
+	if err != nil {
+		return err
+	}
+	groups, err := s.groups(user.Id)
+	if err != nil {
+		return err
+	}
+	twoFactor, err := s.twoFactor(user)
+	if err != nil {
+		return err
+	}
+	impersonations, err := s.impersonations(user.Id)
+	if err != nil {
+		return err
+	}
+	attributes, err := s.attributes(user.Id)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.sessions(user.Id)
+	if err != nil {
+		return err
+	}
+	logins, err := s.logins(user.Id)
+	if err != nil {
+		return err
+	}
+
+	sections := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", profile},
+		{"organizations.json", memberships},
+		{"groups.json", groups},
+		{"two_factor.json", twoFactor},
+		{"impersonations.json", impersonations},
+		{"attributes.json", attributes},
+		{"sessions.json", sessions},
+		{"logins.json", logins},
+	}
+
+	exportedAt := time.Now()
+	archive := zip.NewWriter(writer)
+	readme, err := archive.CreateHeader(&zip.FileHeader{Name: "README.txt", Method: zip.Deflate, Modified: exportedAt})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(readme, dataExportReadme); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(sections); i++ {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: sections[i].name, Method: zip.Deflate, Modified: exportedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(sections[i].content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (s *DataExportService) profile(user model.User) DataExportProfile {
+	role, _ := s.roleRepo.GetRoleById(user.RoleId)
+	return DataExportProfile{
+		Id:          user.Id,
+		Name:        user.Name,
+		Sername:     user.Sername,
+		Email:       user.Email,
+		Username:    user.Username,
+		Role:        role.Name,
+		Locale:      user.Locale,
+		UseOTP:      user.UseOTP,
+		IsAvailable: user.IsAvailable,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
+
+func (s *DataExportService) memberships(userId string) ([]DataExportMembership, error) {
+	memberships, err := s.orgRepo.GetMembershipsByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []DataExportMembership{}
+	for i := 0; i < len(memberships); i++ {
+		organization, err := s.orgRepo.GetOrganizationById(memberships[i].OrganizationId)
+		if err != nil {
+			continue
+		}
+
+		var roleIds []string
+		for j := 0; j < len(memberships[i].MembershipRoles); j++ {
+			roleIds = append(roleIds, memberships[i].MembershipRoles[j].RoleId)
+		}
+		roles, err := s.roleNames(roleIds)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, DataExportMembership{
+			OrganizationId:   organization.Id,
+			OrganizationName: organization.Name,
+			OrganizationSlug: organization.Slug,
+			Roles:            roles,
+			JoinedAt:         memberships[i].CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) groups(userId string) ([]DataExportGroup, error) {
+	groups, err := s.groupRepo.GetGroupsByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []DataExportGroup{}
+	for i := 0; i < len(groups); i++ {
+		var roleIds []string
+		for j := 0; j < len(groups[i].GroupRoles); j++ {
+			roleIds = append(roleIds, groups[i].GroupRoles[j].RoleId)
+		}
+		roles, err := s.roleNames(roleIds)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, DataExportGroup{
+			Id:             groups[i].Id,
+			Name:           groups[i].Name,
+			OrganizationId: groups[i].OrganizationId,
+			Roles:          roles,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) twoFactor(user model.User) (DataExportTwoFactor, error) {
+	otps, err := s.otpRepo.GetOtpsByUser(user.Id)
+	if err != nil {
+		return DataExportTwoFactor{}, err
+	}
+
+	methods, err := s.methodRepo.GetMethodsByUser(user.Id)
+	if err != nil {
+		return DataExportTwoFactor{}, err
+	}
+
+	result := DataExportTwoFactor{Enabled: user.UseOTP, Methods: []DataExportTwoFactorItem{}, Challenges: []DataExportOtpChallenge{}}
+	for i := 0; i < len(methods); i++ {
+		result.Methods = append(result.Methods, DataExportTwoFactorItem{
+			Type:        methods[i].Type,
+			Label:       methods[i].Label,
+			IsDefault:   methods[i].IsDefault,
+			CreatedAt:   methods[i].CreatedAt,
+			ConfirmedAt: methods[i].ConfirmedAt,
+			LastUsedAt:  methods[i].LastUsedAt,
+		})
+	}
+	for i := 0; i < len(otps); i++ {
+		result.Challenges = append(result.Challenges, DataExportOtpChallenge{
+			CreatedAt: otps[i].CreatedAt,
+			ExpiresAt: otps[i].ExpireHas,
+			IsUsed:    otps[i].IsUsed,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) impersonations(userId string) ([]DataExportImpersonation, error) {
+	impersonations, err := s.impersonationRepo.GetImpersonationsByTarget(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []DataExportImpersonation{}
+	for i := 0; i < len(impersonations); i++ {
+		actions := impersonations[i].Actions
+		if actions == nil {
+			actions = []model.ImpersonationAction{}
+		}
+		result = append(result, DataExportImpersonation{
+			Id:        impersonations[i].Id,
+			ActorId:   impersonations[i].ActorId,
+			Reason:    impersonations[i].Reason,
+			StartedAt: impersonations[i].CreatedAt,
+			ExpiresAt: impersonations[i].ExpiresAt,
+			EndedAt:   impersonations[i].EndedAt,
+			Actions:   actions,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) attributes(userId string) ([]DataExportAttribute, error) {
+	attributes, err := s.attributeRepo.GetAllUserAttributes(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []DataExportAttribute{}
+	if len(attributes) == 0 {
+		return result, nil
+	}
+
+	var definitionIds []string
+	for i := 0; i < len(attributes); i++ {
+		definitionIds = append(definitionIds, attributes[i].DefinitionId)
+	}
+	definitions, err := s.attributeRepo.GetDefinitionsByIds(definitionIds)
+	if err != nil {
+		return nil, err
+	}
+	byId := map[string]model.AttributeDefinition{}
+	for i := 0; i < len(definitions); i++ {
+		byId[definitions[i].Id] = definitions[i]
+	}
+
+	for i := 0; i < len(attributes); i++ {
+		definition, ok := byId[attributes[i].DefinitionId]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		_ = json.Unmarshal([]byte(attributes[i].Value), &value)
+		result = append(result, DataExportAttribute{
+			OrganizationId: attributes[i].OrganizationId,
+			Name:           definition.Name,
+			Label:          definition.Label,
+			Value:          value,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) sessions(userId string) ([]DataExportSession, error) {
+	sessions, err := s.sessionRepo.GetSessionsByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []DataExportSession{}
+	for i := 0; i < len(sessions); i++ {
+		result = append(result, DataExportSession{
+			Id:         sessions[i].Id,
+			Device:     sessions[i].Device,
+			UserAgent:  sessions[i].UserAgent,
+			IpAddress:  sessions[i].IpAddress,
+			CreatedAt:  sessions[i].CreatedAt,
+			LastSeenAt: sessions[i].LastSeenAt,
+			ExpiresAt:  sessions[i].ExpiresAt,
+			RevokedAt:  sessions[i].RevokedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) logins(userId string) ([]DataExportLogin, error) {
+	events, err := s.loginRepo.GetAllLoginEventsByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []DataExportLogin{}
+	for i := 0; i < len(events); i++ {
+		result = append(result, DataExportLogin{
+			Method:      events[i].Method,
+			Device:      events[i].Device,
+			UserAgent:   events[i].UserAgent,
+			IpAddress:   events[i].IpAddress,
+			Country:     events[i].Country,
+			City:        events[i].City,
+			NewDevice:   events[i].NewDevice,
+			NewLocation: events[i].NewLocation,
+			ReportedAt:  events[i].ReportedAt,
+			CreatedAt:   events[i].CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *DataExportService) roleNames(roleIds []string) ([]string, error) {
+	names := []string{}
+	if len(roleIds) == 0 {
+		return names, nil
+	}
+
+	roles, err := s.roleRepo.GetRolesByIds(roleIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(roles); i++ {
+		names = append(names, roles[i].Name)
+	}
+	return names, nil
+}
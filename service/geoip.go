@@ -0,0 +1,152 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	slog "github.com/sagikazarmark/slog-shim"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IpLocation est la localisation approximative d'une adresse IP
+type IpLocation struct {
+	Country string `json:"country"`
+	City    string `json:"city"`
+}
+
+type ipRange struct {
+	start    netip.Addr
+	end      netip.Addr
+	location IpLocation
+}
+
+// IpLocator géolocalise les adresses IP à partir d'un fichier local, sans appel à un service externe
+type IpLocator struct {
+	ranges []ipRange
+}
+
+var (
+	ipLocatorsLock sync.Mutex
+	ipLocators     = map[string]*IpLocator{}
+)
+
+// OpenIpLocator charge une seule fois le fichier de géolocalisation et le partage entre les services.
+// Un fichier absent ou illisible est tracé : les connexions sont alors enregistrées sans localisation.
+func OpenIpLocator(path string) *IpLocator {
+	ipLocatorsLock.Lock()
+	defer ipLocatorsLock.Unlock()
+
+	if locator, ok := ipLocators[path]; ok {
+		return locator
+	}
+
+	locator, err := LoadIpLocator(path)
+	if err != nil {
+		slog.Default().Warn("IP location database unavailable", "path", path, "error", err)
+		locator = &IpLocator{}
+	}
+	ipLocators[path] = locator
+	return locator
+}
+
+// LoadIpLocator lit un fichier CSV de plages d'adresses au format DB-IP lite :
+// "ip_start,ip_end,country" ou "ip_start,ip_end,continent,country,stateprov,city,latitude,longitude".
+// Le format simplifié "ip_start,ip_end,country,city" est également accepté.
+func LoadIpLocator(path string) (*IpLocator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	locator := &IpLocator{}
+	skipped := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry, ok := parseIpRange(record)
+		if !ok {
+			skipped++
+			continue
+		}
+		locator.ranges = append(locator.ranges, entry)
+	}
+	if len(locator.ranges) == 0 {
+		return nil, errors.New("aucune plage d'adresses valide")
+	}
+
+	sort.Slice(locator.ranges, func(i, j int) bool {
+		return locator.ranges[i].start.Less(locator.ranges[j].start)
+	})
+
+	slog.Default().Info("Loaded IP ranges", "ranges", len(locator.ranges), "path", path, "skipped", skipped)
+	return locator, nil
+}
+
+func parseIpRange(record []string) (ipRange, bool) {
+	if len(record) < 3 {
+		return ipRange{}, false
+	}
+	start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+	if err != nil {
+		return ipRange{}, false
+	}
+	end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+	if err != nil {
+		return ipRange{}, false
+	}
+	start, end = start.Unmap(), end.Unmap()
+	if start.Is4() != end.Is4() || end.Less(start) {
+		return ipRange{}, false
+	}
+
+	var location IpLocation
+	switch {
+	case len(record) >= 6:
+		location = IpLocation{Country: record[3], City: record[5]}
+	case len(record) == 4:
+		location = IpLocation{Country: record[2], City: record[3]}
+	default:
+		location = IpLocation{Country: record[2]}
+	}
+	location.Country = truncate(strings.TrimSpace(location.Country), 120)
+	location.City = truncate(strings.TrimSpace(location.City), 120)
+	return ipRange{start: start, end: end, location: location}, true
+}
+
+// Lookup retourne la localisation d'une adresse ; une adresse inconnue ou invalide donne une localisation vide
+func (l *IpLocator) Lookup(ipAddress string) IpLocation {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil || len(l.ranges) == 0 {
+		return IpLocation{}
+	}
+	addr = addr.Unmap()
+
+	// Dernière plage commençant avant l'adresse
+	index := sort.Search(len(l.ranges), func(i int) bool {
+		return addr.Less(l.ranges[i].start)
+	}) - 1
+	if index < 0 {
+		return IpLocation{}
+	}
+
+	entry := l.ranges[index]
+	if entry.start.Is4() != addr.Is4() || entry.end.Less(addr) {
+		return IpLocation{}
+	}
+	return entry.location
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"auth/model"
+	"auth/repository"
+	"context"
+	"errors"
+)
+
+type Group struct {
+	Name     string `json:"name" validate:"required"`
+	Describe string `json:"describe"`
+}
+
+type GroupDetail struct {
+	Group model.Group
+	Roles []string
+}
+
+// GroupService gère la logique métier liée aux groupes d'utilisateurs
+type GroupService struct {
+	groupRepo *repository.GroupRepository
+	userRepo  *repository.UserRepository
+	roleRepo  *repository.RoleRepository
+}
+
+// NewGroupService crée une nouvelle instance de GroupService
+func NewGroupService(
+	groupRepo *repository.GroupRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository) *GroupService {
+	return &GroupService{
+		groupRepo: groupRepo,
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+	}
+}
+
+// ForOrganization retourne un service limité aux groupes et aux membres de l'organisation fournie
+func (s *GroupService) ForOrganization(orgId string) *GroupService {
+	return &GroupService{
+		groupRepo: s.groupRepo.ForOrganization(orgId),
+		userRepo:  s.userRepo.ForOrganization(orgId),
+		roleRepo:  s.roleRepo,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *GroupService) WithContext(ctx context.Context) *GroupService {
+	return &GroupService{
+		groupRepo: s.groupRepo.WithContext(ctx),
+		userRepo:  s.userRepo.WithContext(ctx),
+		roleRepo:  s.roleRepo.WithContext(ctx),
+	}
+}
+
+// GetAllGroups récupère tous les groupes avec leurs rôles
+func (s *GroupService) GetAllGroups() ([]GroupDetail, error) {
+	groups, err := s.groupRepo.GetAllGroups()
+	if err != nil {
+		return nil, err
+	}
+	return s.details(groups)
+}
+
+// GetGroupById récupère un groupe par son ID
+func (s *GroupService) GetGroupById(id string) (GroupDetail, error) {
+	group, err := s.groupRepo.GetGroupById(id)
+	if err != nil {
+		return GroupDetail{}, errors.New("groupe non trouvé")
+	}
+	return s.detail(group)
+}
+
+// CreateGroup crée un nouveau groupe
+func (s *GroupService) CreateGroup(newGroup Group) (GroupDetail, error) {
+	if _, err := s.groupRepo.GetGroupByName(newGroup.Name); err == nil {
+		return GroupDetail{}, errors.New("ce nom de groupe a déjà été utilisé")
+	}
+
+	group, err := s.groupRepo.CreateGroup(model.Group{
+		Name:     newGroup.Name,
+		Describe: newGroup.Describe,
+	})
+	if err != nil {
+		return GroupDetail{}, err
+	}
+	return GroupDetail{Group: group, Roles: []string{}}, nil
+}
+
+// UpdateGroup met à jour un groupe
+func (s *GroupService) UpdateGroup(id string, updatedGroup Group) (GroupDetail, error) {
+	group, err := s.groupRepo.GetGroupById(id)
+	if err != nil {
+		return GroupDetail{}, errors.New("groupe non trouvé")
+	}
+
+	if updatedGroup.Name != group.Name {
+		if _, err = s.groupRepo.GetGroupByName(updatedGroup.Name); err == nil {
+			return GroupDetail{}, errors.New("ce nom de groupe a déjà été utilisé")
+		}
+	}
+
+	group.Name = updatedGroup.Name
+	group.Describe = updatedGroup.Describe
+
+	group, err = s.groupRepo.UpdateGroup(group)
+	if err != nil {
+		return GroupDetail{}, errors.New("nous avons rencontré un problème durant la mise à jour")
+	}
+	return s.detail(group)
+}
+
+// DeleteGroup supprime un groupe
+func (s *GroupService) DeleteGroup(id string) error {
+	if err := s.groupRepo.DeleteGroup(id); err != nil {
+		return errors.New("groupe non trouvé")
+	}
+	return nil
+}
+
+// GetMembers récupère les utilisateurs membres d'un groupe
+func (s *GroupService) GetMembers(groupId string) ([]model.User, error) {
+	group, err := s.groupRepo.GetGroupById(groupId)
+	if err != nil {
+		return nil, errors.New("groupe non trouvé")
+	}
+
+	users := []model.User{}
+	for i := 0; i < len(group.GroupMembers); i++ {
+		user, err := s.userRepo.GetUserById(group.GroupMembers[i].UserId)
+		if err != nil {
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// AddMember ajoute un utilisateur de l'organisation à un groupe
+func (s *GroupService) AddMember(groupId string, userId string) error {
+	group, err := s.groupRepo.GetGroupById(groupId)
+	if err != nil {
+		return errors.New("groupe non trouvé")
+	}
+
+	if _, err = s.userRepo.GetUserById(userId); err != nil {
+		return errors.New("utilisateur non trouvé")
+	}
+
+	for i := 0; i < len(group.GroupMembers); i++ {
+		if group.GroupMembers[i].UserId == userId {
+			return errors.New("l'utilisateur est déjà membre de ce groupe")
+		}
+	}
+
+	_, err = s.groupRepo.AddMember(group.Id, userId)
+	return err
+}
+
+// RemoveMember retire un utilisateur d'un groupe
+func (s *GroupService) RemoveMember(groupId string, userId string) error {
+	group, err := s.groupRepo.GetGroupById(groupId)
+	if err != nil {
+		return errors.New("groupe non trouvé")
+	}
+
+	if err = s.groupRepo.RemoveMember(group.Id, userId); err != nil {
+		return errors.New("l'utilisateur n'est pas membre de ce groupe")
+	}
+	return nil
+}
+
+// AddRole attache un rôle à un groupe
+func (s *GroupService) AddRole(groupId string, roleName string) (GroupDetail, error) {
+	group, err := s.groupRepo.GetGroupById(groupId)
+	if err != nil {
+		return GroupDetail{}, errors.New("groupe non trouvé")
+	}
+
+	role, err := s.roleRepo.GetRoleByName(roleName)
+	if err != nil {
+		return GroupDetail{}, errors.New("aucun rôle ne correspond à cette valeur")
+	}
+
+	for i := 0; i < len(group.GroupRoles); i++ {
+		if group.GroupRoles[i].RoleId == role.Id {
+			return GroupDetail{}, errors.New("ce rôle est déjà attribué au groupe")
+		}
+	}
+
+	groupRole, err := s.groupRepo.AddRole(group.Id, role.Id)
+	if err != nil {
+		return GroupDetail{}, err
+	}
+
+	group.GroupRoles = append(group.GroupRoles, groupRole)
+	return s.detail(group)
+}
+
+// RemoveRole détache un rôle d'un groupe
+func (s *GroupService) RemoveRole(groupId string, roleName string) (GroupDetail, error) {
+	group, err := s.groupRepo.GetGroupById(groupId)
+	if err != nil {
+		return GroupDetail{}, errors.New("groupe non trouvé")
+	}
+
+	role, err := s.roleRepo.GetRoleByName(roleName)
+	if err != nil {
+		return GroupDetail{}, errors.New("aucun rôle ne correspond à cette valeur")
+	}
+
+	if err = s.groupRepo.RemoveRole(group.Id, role.Id); err != nil {
+		return GroupDetail{}, errors.New("ce rôle n'est pas attribué au groupe")
+	}
+
+	group, err = s.groupRepo.GetGroupById(group.Id)
+	if err != nil {
+		return GroupDetail{}, err
+	}
+	return s.detail(group)
+}
+
+// GetUserGroups récupère les groupes d'un utilisateur de l'organisation
+func (s *GroupService) GetUserGroups(userId string) ([]GroupDetail, error) {
+	if _, err := s.userRepo.GetUserById(userId); err != nil {
+		return nil, errors.New("utilisateur non trouvé")
+	}
+
+	groups, err := s.groupRepo.GetGroupsByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.details(groups)
+}
+
+func (s *GroupService) details(groups []model.Group) ([]GroupDetail, error) {
+	groupDetails := []GroupDetail{}
+	for i := 0; i < len(groups); i++ {
+		groupDetail, err := s.detail(groups[i])
+		if err != nil {
+			return nil, err
+		}
+		groupDetails = append(groupDetails, groupDetail)
+	}
+	return groupDetails, nil
+}
+
+func (s *GroupService) detail(group model.Group) (GroupDetail, error) {
+	var roleIds []string
+	for i := 0; i < len(group.GroupRoles); i++ {
+		roleIds = append(roleIds, group.GroupRoles[i].RoleId)
+	}
+
+	roles, err := s.roleRepo.GetRolesByIds(roleIds)
+	if err != nil {
+		return GroupDetail{}, err
+	}
+
+	roleNames := []string{}
+	for i := 0; i < len(roles); i++ {
+		roleNames = append(roleNames, roles[i].Name)
+	}
+	return GroupDetail{Group: group, Roles: roleNames}, nil
+}
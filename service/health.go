@@ -0,0 +1,119 @@
+package service
+
+import (
+	"auth/migrations"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	HealthStatusOk    = "ok"
+	HealthStatusError = "error"
+)
+
+// HealthCheck est le résultat d'une vérification de disponibilité
+type HealthCheck struct {
+	Name     string
+	Status   string
+	Error    string
+	Duration time.Duration
+}
+
+// HealthReport regroupe les vérifications ; le service est prêt si toutes ont réussi
+type HealthReport struct {
+	Ready  bool
+	Checks []HealthCheck
+}
+
+type HealthService struct {
+	healthRepo *repository.HealthRepository
+	migrator   *migrations.Migrator
+	security   utils.Security
+}
+
+// NewHealthService crée une nouvelle instance de HealthService. Le service n'est prêt
+// que si aucune migration du schéma n'est en attente.
+func NewHealthService(
+	healthRepo *repository.HealthRepository,
+	migrator *migrations.Migrator,
+	security utils.Security) *HealthService {
+	return &HealthService{
+		healthRepo: healthRepo,
+		migrator:   migrator,
+		security:   security,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *HealthService) WithContext(ctx context.Context) *HealthService {
+	return &HealthService{
+		healthRepo: s.healthRepo.WithContext(ctx),
+		migrator:   s.migrator.WithContext(ctx),
+		security:   s.security,
+	}
+}
+
+// Readiness vérifie la connexion à la base, l'application des migrations du schéma et la clé de signature des tokens
+func (s *HealthService) Readiness() HealthReport {
+	report := HealthReport{Ready: true}
+	checks := []struct {
+		name  string
+		check func() error
+	}{
+		{"database", s.healthRepo.Ping},
+		{"migrations", s.checkSchema},
+		{"signing_key", s.checkSigningKey},
+	}
+
+	for i := 0; i < len(checks); i++ {
+		start := time.Now()
+		result := HealthCheck{Name: checks[i].name, Status: HealthStatusOk}
+		if err := checks[i].check(); err != nil {
+			result.Status = HealthStatusError
+			result.Error = err.Error()
+			report.Ready = false
+		}
+		result.Duration = time.Since(start)
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func (s *HealthService) checkSchema() error {
+	pending, err := s.migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		versions := make([]string, 0, len(pending))
+		for _, migration := range pending {
+			versions = append(versions, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+		return fmt.Errorf("migrations en attente : %s", strings.Join(versions, ", "))
+	}
+	return nil
+}
+
+// checkSigningKey signe un token de test puis le vérifie comme le font les middlewares d'authentification
+func (s *HealthService) checkSigningKey() error {
+	token, err := s.security.SignToken(model.Claims{
+		Source: "health_check",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("signature impossible : %v", err)
+	}
+	if _, err := s.security.ParseToken(token); err != nil {
+		return fmt.Errorf("le token signé n'est pas reconnu : %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"context"
+	"errors"
+	slog "github.com/sagikazarmark/slog-shim"
+	"time"
+)
+
+// ImpersonationTTL est la durée de vie d'un token d'impersonation
+const ImpersonationTTL = 15 * time.Minute
+
+// ImpersonationActor décrit l'administrateur qui démarre une impersonation
+type ImpersonationActor struct {
+	UserId string
+	Email  string
+	OrgId  string
+}
+
+// ImpersonationService gère les sessions durant lesquelles un administrateur agit au nom d'un utilisateur
+type ImpersonationService struct {
+	authService       *AuthenticationService
+	impersonationRepo *repository.ImpersonationRepository
+	userRepo          *repository.UserRepository
+	roleRepo          *repository.RoleRepository
+	logger            *slog.Logger
+}
+
+// NewImpersonationService crée une nouvelle instance de ImpersonationService
+func NewImpersonationService(
+	authService *AuthenticationService,
+	impersonationRepo *repository.ImpersonationRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository) *ImpersonationService {
+	return &ImpersonationService{
+		authService:       authService,
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		logger:            slog.Default(),
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *ImpersonationService) WithContext(ctx context.Context) *ImpersonationService {
+	return &ImpersonationService{
+		authService:       s.authService.WithContext(ctx),
+		impersonationRepo: s.impersonationRepo.WithContext(ctx),
+		userRepo:          s.userRepo.WithContext(ctx),
+		roleRepo:          s.roleRepo.WithContext(ctx),
+		logger:            logging.FromContext(ctx),
+	}
+}
+
+// StartImpersonation ouvre une session et génère un token de courte durée pour l'utilisateur ciblé
+func (s *ImpersonationService) StartImpersonation(
+	actor ImpersonationActor, targetUserId string, reason string) (model.Authentication, model.Impersonation, error) {
+
+	if actor.UserId == targetUserId {
+		return model.Authentication{}, model.Impersonation{}, errors.New("vous ne pouvez pas vous impersonner vous-même")
+	}
+
+	target, err := s.userRepo.ForOrganization(actor.OrgId).GetUserById(targetUserId)
+	if err != nil {
+		return model.Authentication{}, model.Impersonation{}, errors.New("utilisateur inexistant")
+	}
+
+	targetRole, err := s.roleRepo.GetRoleById(target.RoleId)
+	if err != nil {
+		return model.Authentication{}, model.Impersonation{}, errors.New("error system : user role has not found")
+	}
+	if targetRole.Name == "admin" {
+		return model.Authentication{}, model.Impersonation{}, errors.New("un administrateur ne peut pas être impersonné")
+	}
+
+	impersonation, err := s.impersonationRepo.CreateImpersonation(model.Impersonation{
+		ActorId:        actor.UserId,
+		TargetUserId:   target.Id,
+		OrganizationId: actor.OrgId,
+		Reason:         reason,
+		ExpiresAt:      time.Now().Add(ImpersonationTTL),
+	})
+	if err != nil {
+		return model.Authentication{}, model.Impersonation{}, errors.New("nous avons rencontré un problème durant l'ouverture de la session")
+	}
+
+	authModel, err := s.authService.IssueImpersonation(target, actor.OrgId, model.Actor{
+		Subject:   actor.UserId,
+		Email:     actor.Email,
+		SessionId: impersonation.Id,
+	}, impersonation.ExpiresAt)
+	if err != nil {
+		_ = s.impersonationRepo.EndImpersonation(impersonation.Id, actor.UserId)
+		return model.Authentication{}, model.Impersonation{}, err
+	}
+
+	return authModel, impersonation, nil
+}
+
+// EndImpersonation termine une session ; le token associé est refusé dès la requête suivante
+func (s *ImpersonationService) EndImpersonation(id string, orgId string, endedBy string) error {
+	impersonation, err := s.GetImpersonation(id, orgId)
+	if err != nil {
+		return err
+	}
+	if !impersonation.EndedAt.IsZero() {
+		return errors.New("cette session d'impersonation est déjà terminée")
+	}
+
+	if err := s.impersonationRepo.EndImpersonation(impersonation.Id, endedBy); err != nil {
+		return errors.New("nous avons rencontré un problème durant la fermeture de la session")
+	}
+	return nil
+}
+
+// GetImpersonations liste les sessions d'impersonation de l'organisation
+func (s *ImpersonationService) GetImpersonations(orgId string) ([]model.Impersonation, error) {
+	return s.impersonationRepo.GetImpersonationsByOrganization(orgId)
+}
+
+// GetImpersonation récupère une session de l'organisation avec les actions effectuées
+func (s *ImpersonationService) GetImpersonation(id string, orgId string) (model.Impersonation, error) {
+	impersonation, err := s.impersonationRepo.GetImpersonationById(id)
+	if err != nil || impersonation.OrganizationId != orgId {
+		return model.Impersonation{}, errors.New("session d'impersonation introuvable")
+	}
+
+	actions, err := s.impersonationRepo.GetActions(impersonation.Id)
+	if err != nil {
+		return model.Impersonation{}, err
+	}
+	impersonation.Actions = actions
+	return impersonation, nil
+}
+
+// CheckToken refuse les tokens d'impersonation dont la session est terminée ou expirée
+func (s *ImpersonationService) CheckToken(claims *model.Claims) error {
+	if claims.Act == nil {
+		return nil
+	}
+
+	impersonation, err := s.impersonationRepo.GetImpersonationById(claims.Act.SessionId)
+	if err != nil ||
+		impersonation.ActorId != claims.Act.Subject ||
+		impersonation.TargetUserId != claims.Id ||
+		!impersonation.IsActive(time.Now()) {
+		return errors.New("la session d'impersonation est terminée")
+	}
+	return nil
+}
+
+// RecordAction attribue une requête effectuée sous un token d'impersonation aux deux identités
+func (s *ImpersonationService) RecordAction(claims *model.Claims, method string, path string, status int, ip string) {
+	if claims.Act == nil {
+		return
+	}
+
+	err := s.impersonationRepo.CreateAction(model.ImpersonationAction{
+		ImpersonationId: claims.Act.SessionId,
+		ActorId:         claims.Act.Subject,
+		TargetUserId:    claims.Id,
+		Method:          method,
+		Path:            path,
+		Status:          status,
+		Ip:              ip,
+	})
+	if err != nil {
+		s.logger.Error("Failed to record impersonation action", "method", method, "path", path, "error", err)
+	}
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"net/url"
+	"time"
+)
+
+// ErrInvitationNotSent signale une invitation enregistrée dont l'email n'a pas pu être envoyé
+var ErrInvitationNotSent = errors.New("l'invitation a été enregistrée mais l'email n'a pas pu être envoyé, merci de la renvoyer")
+
+// NewUserInvitation décrit le compte créé pour une personne invitée
+type NewUserInvitation struct {
+	Name     string
+	Sername  string
+	Email    string
+	Username string
+	Role     string
+}
+
+// InvitationService invite les utilisateurs à définir eux-mêmes leur mot de passe
+type InvitationService struct {
+	invitationRepo *repository.InvitationRepository
+	userRepo       *repository.UserRepository
+	roleRepo       *repository.RoleRepository
+	orgRepo        *repository.OrganizationRepository
+	notifier       Notifier
+	acceptUrl      string
+	ttl            time.Duration
+	security       utils.Security
+	orgId          string
+	logger         *slog.Logger
+}
+
+// NewInvitationService crée une nouvelle instance de InvitationService
+func NewInvitationService(
+	invitationRepo *repository.InvitationRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	notifier Notifier,
+	acceptUrl string,
+	ttl time.Duration,
+	security utils.Security) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		orgRepo:        orgRepo,
+		notifier:       notifier,
+		acceptUrl:      acceptUrl,
+		ttl:            ttl,
+		security:       security,
+		logger:         slog.Default(),
+	}
+}
+
+// ForOrganization retourne un service limité aux invitations de l'organisation fournie
+func (s *InvitationService) ForOrganization(orgId string) *InvitationService {
+	return &InvitationService{
+		invitationRepo: s.invitationRepo,
+		userRepo:       s.userRepo,
+		roleRepo:       s.roleRepo,
+		orgRepo:        s.orgRepo,
+		notifier:       s.notifier,
+		acceptUrl:      s.acceptUrl,
+		ttl:            s.ttl,
+		security:       s.security,
+		orgId:          orgId,
+		logger:         s.logger,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *InvitationService) WithContext(ctx context.Context) *InvitationService {
+	return &InvitationService{
+		invitationRepo: s.invitationRepo.WithContext(ctx),
+		userRepo:       s.userRepo.WithContext(ctx),
+		roleRepo:       s.roleRepo.WithContext(ctx),
+		orgRepo:        s.orgRepo.WithContext(ctx),
+		notifier:       s.notifier,
+		acceptUrl:      s.acceptUrl,
+		ttl:            s.ttl,
+		security:       s.security,
+		orgId:          s.orgId,
+		logger:         logging.FromContext(ctx),
+	}
+}
+
+// InviteNewUser crée un compte en attente d'activation, sans mot de passe, et envoie le lien d'invitation
+func (s *InvitationService) InviteNewUser(newUser NewUserInvitation, invitedBy string) (model.Invitation, error) {
+	if s.orgId == "" {
+		return model.Invitation{}, errors.New("aucune organisation active pour l'invitation")
+	}
+
+	roleName := newUser.Role
+	if roleName == "" {
+		roleName = "client"
+	}
+	role, err := s.roleRepo.GetRoleByName(roleName)
+	if err != nil {
+		return model.Invitation{}, errors.New("rôle inconnu : " + roleName)
+	}
+
+	takenEmails, takenUsernames, err := s.userRepo.GetTakenIdentities([]string{newUser.Email}, []string{newUser.Username})
+	if err != nil {
+		return model.Invitation{}, err
+	}
+	if takenEmails[newUser.Email] {
+		return model.Invitation{}, errors.New("cette adresse email est déjà utilisée")
+	}
+	if takenUsernames[newUser.Username] {
+		return model.Invitation{}, errors.New("ce nom d'utilisateur est déjà utilisé")
+	}
+
+	tenantUsers := s.userRepo.ForOrganization(s.orgId)
+	createdUser, err := tenantUsers.CreateUser(model.User{
+		Name:     newUser.Name,
+		Sername:  newUser.Sername,
+		Email:    newUser.Email,
+		Username: newUser.Username,
+		RoleId:   role.Id,
+		Status:   AccountStatePendingActivation,
+	})
+	if err != nil {
+		return model.Invitation{}, errors.New("nous avons rencontré un problème durant la création de l'utilisateur")
+	}
+
+	err = tenantUsers.UpdateAccountState(createdUser.Id, repository.AccountStateUpdate{
+		Status: AccountStatePendingActivation,
+		Reason: "invited",
+	})
+	if err != nil {
+		return model.Invitation{}, errors.New("nous avons rencontré un problème durant la création de l'utilisateur")
+	}
+
+	return s.invite(createdUser, invitedBy)
+}
+
+// InviteExistingUser envoie à un membre de l'organisation un lien pour définir un nouveau mot de passe
+func (s *InvitationService) InviteExistingUser(userId string, invitedBy string) (model.Invitation, error) {
+	user, err := s.userRepo.ForOrganization(s.orgId).GetUserById(userId)
+	if err != nil {
+		return model.Invitation{}, errors.New("utilisateur inexistant")
+	}
+	switch effectiveAccountState(user, time.Now()) {
+	case AccountStateDisabled:
+		return model.Invitation{}, errors.New("ce compte est désactivé : il doit être réactivé avant d'être invité")
+	case AccountStateLocked:
+		return model.Invitation{}, errors.New("ce compte est verrouillé : il doit être déverrouillé avant d'être invité")
+	}
+
+	return s.invite(user, invitedBy)
+}
+
+// GetPendingInvitations liste les invitations ni acceptées ni révoquées de l'organisation
+func (s *InvitationService) GetPendingInvitations() ([]model.Invitation, error) {
+	return s.invitationRepo.GetPendingInvitations(s.orgId)
+}
+
+// ResendInvitation génère un nouveau lien, avec une nouvelle date d'expiration, et le renvoie
+func (s *InvitationService) ResendInvitation(id string) (model.Invitation, error) {
+	invitation, err := s.invitationRepo.GetInvitationById(id, s.orgId)
+	if err != nil {
+		return model.Invitation{}, errors.New("invitation non trouvée")
+	}
+
+	token, tokenHash, err := newLinkToken()
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	if err := s.invitationRepo.RenewInvitation(invitation.Id, tokenHash, expiresAt); err != nil {
+		return model.Invitation{}, errors.New("cette invitation a déjà été acceptée ou révoquée")
+	}
+
+	invitation, err = s.invitationRepo.GetInvitationById(id, s.orgId)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+	return invitation, s.sendInvitation(invitation, token)
+}
+
+// RevokeInvitation invalide le lien d'une invitation en attente
+func (s *InvitationService) RevokeInvitation(id string) error {
+	invitation, err := s.invitationRepo.GetInvitationById(id, s.orgId)
+	if err != nil {
+		return errors.New("invitation non trouvée")
+	}
+	if err := s.invitationRepo.RevokeInvitation(invitation.Id); err != nil {
+		return errors.New("cette invitation a déjà été acceptée ou révoquée")
+	}
+
+	// Un compte jamais activé n'a pas de mot de passe : sans invitation, il ne pourrait plus
+	// l'être. Il est supprimé, ce qui libère son email et son nom d'utilisateur.
+	user, err := s.userRepo.ForOrganization(s.orgId).GetUserById(invitation.UserId)
+	if err != nil || user.Status != AccountStatePendingActivation {
+		return nil
+	}
+	if err := s.userRepo.ForOrganization(s.orgId).DeleteUser(user.Id); err != nil {
+		s.logger.Error("Failed to delete pending user of revoked invitation", "invitation_id", invitation.Id, "user_id", user.Id, "error", err)
+		return errors.New("l'invitation a été révoquée mais le compte en attente d'activation n'a pas pu être supprimé")
+	}
+	return nil
+}
+
+// GetInvitationByToken retourne l'invitation associée à un lien encore valide
+func (s *InvitationService) GetInvitationByToken(token string) (model.Invitation, error) {
+	invitation, err := s.invitationRepo.GetInvitationByTokenHash(hashLinkToken(token))
+	if err != nil || !invitation.IsPending(time.Now()) {
+		return model.Invitation{}, errors.New("ce lien d'invitation est invalide ou a expiré")
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation enregistre le mot de passe choisi par l'utilisateur et active son compte
+func (s *InvitationService) AcceptInvitation(token string, password string) (model.Invitation, error) {
+	invitation, err := s.GetInvitationByToken(token)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	user, err := s.userRepo.ForOrganization(s.orgId).GetUserById(invitation.UserId)
+	if err != nil {
+		return model.Invitation{}, errors.New("ce compte n'existe plus")
+	}
+	// Le lien ne lève ni une désactivation ni un verrouillage : seul un administrateur le peut
+	state := effectiveAccountState(user, time.Now())
+	if state == AccountStateDisabled || state == AccountStateLocked {
+		return model.Invitation{}, &AccountStateError{State: state, LockedUntil: user.LockedUntil}
+	}
+
+	passwordHash, err := s.security.GenerateHashPassword(password)
+	if err != nil {
+		return model.Invitation{}, errors.New("erreur de cryptage du mot de passe utilisateur")
+	}
+
+	if err := s.invitationRepo.AcceptInvitation(invitation, user.Status, passwordHash); err != nil {
+		return model.Invitation{}, errors.New("ce lien d'invitation est invalide ou a expiré")
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) invite(user model.User, invitedBy string) (model.Invitation, error) {
+	token, tokenHash, err := newLinkToken()
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	invitation, err := s.invitationRepo.CreateInvitation(model.Invitation{
+		Email:          user.Email,
+		UserId:         user.Id,
+		OrganizationId: s.orgId,
+		InvitedBy:      invitedBy,
+		TokenHash:      tokenHash,
+		ExpiresAt:      time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		return model.Invitation{}, errors.New("nous avons rencontré un problème durant la création de l'invitation")
+	}
+
+	return invitation, s.sendInvitation(invitation, token)
+}
+
+// sendInvitation envoie le lien ; en cas d'échec, l'invitation reste valide et peut être renvoyée
+func (s *InvitationService) sendInvitation(invitation model.Invitation, token string) error {
+	organizationName := invitation.OrganizationId
+	if organization, err := s.orgRepo.GetOrganizationById(invitation.OrganizationId); err == nil {
+		organizationName = organization.Name
+	}
+
+	link := s.acceptUrl + "?token=" + url.QueryEscape(token)
+	err := s.notifier.Send(Notification{
+		Recipient: invitation.Email,
+		Subject:   "Invitation à rejoindre " + organizationName,
+		Body: fmt.Sprintf("Bonjour, définissez votre mot de passe en suivant ce lien avant le %s : %s",
+			invitation.ExpiresAt.Format("02/01/2006 15:04"), link),
+	})
+	if err != nil {
+		s.logger.Error("Failed to send invitation", "invitation_id", invitation.Id, "email", invitation.Email, "error", err)
+		return ErrInvitationNotSent
+	}
+	return nil
+}
+
+// newLinkToken génère le jeton aléatoire d'un lien envoyé par email ; seule son empreinte est conservée
+func newLinkToken() (string, string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", "", errors.New("error system : impossible de générer le lien")
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buffer)
+	return token, hashLinkToken(token), nil
+}
+
+func hashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
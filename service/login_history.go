@@ -0,0 +1,260 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	LoginMethodPassword = "password"
+	LoginMethodOtp      = "otp"
+
+	defaultLoginHistoryPageSize = 20
+	maxLoginHistoryPageSize     = 100
+)
+
+// LoginHistoryPage est une page de l'historique des connexions avec le total des connexions
+type LoginHistoryPage struct {
+	Events []model.LoginEvent
+	Total  int64
+	Limit  int
+	Offset int
+}
+
+// LoginHistoryService enregistre les connexions réussies et alerte l'utilisateur d'une connexion
+// depuis un appareil ou une plage d'adresses IP inconnus
+type LoginHistoryService struct {
+	loginRepo *repository.LoginEventRepository
+	userRepo  *repository.UserRepository
+	notifier  Notifier
+	locator   *IpLocator
+	reportUrl string
+	reportTTL time.Duration
+	security  utils.Security
+	logger    *slog.Logger
+}
+
+// NewLoginHistoryService crée une nouvelle instance de LoginHistoryService
+func NewLoginHistoryService(
+	loginRepo *repository.LoginEventRepository,
+	userRepo *repository.UserRepository,
+	notifier Notifier,
+	locator *IpLocator,
+	reportUrl string,
+	reportTTL time.Duration,
+	security utils.Security) *LoginHistoryService {
+	return &LoginHistoryService{
+		loginRepo: loginRepo,
+		userRepo:  userRepo,
+		notifier:  notifier,
+		locator:   locator,
+		reportUrl: reportUrl,
+		reportTTL: reportTTL,
+		security:  security,
+		logger:    slog.Default(),
+	}
+}
+
+// ForOrganization retourne un service limité aux utilisateurs membres de l'organisation
+func (s *LoginHistoryService) ForOrganization(orgId string) *LoginHistoryService {
+	return &LoginHistoryService{
+		loginRepo: s.loginRepo,
+		userRepo:  s.userRepo.ForOrganization(orgId),
+		notifier:  s.notifier,
+		locator:   s.locator,
+		reportUrl: s.reportUrl,
+		reportTTL: s.reportTTL,
+		security:  s.security,
+		logger:    s.logger,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *LoginHistoryService) WithContext(ctx context.Context) *LoginHistoryService {
+	return &LoginHistoryService{
+		loginRepo: s.loginRepo.WithContext(ctx),
+		userRepo:  s.userRepo.WithContext(ctx),
+		notifier:  s.notifier,
+		locator:   s.locator,
+		reportUrl: s.reportUrl,
+		reportTTL: s.reportTTL,
+		security:  s.security,
+		logger:    logging.FromContext(ctx),
+	}
+}
+
+// RecordLogin enregistre une connexion réussie. La première connexion d'un utilisateur sert de référence ;
+// ensuite, un appareil ou une plage d'adresses IP absents de l'historique déclenchent une alerte.
+// Un échec est journalisé sans interrompre la connexion.
+func (s *LoginHistoryService) RecordLogin(user model.User, client ClientInfo, method string) {
+	location := s.locator.Lookup(client.IpAddress)
+	event := model.LoginEvent{
+		UserId:            user.Id,
+		Method:            method,
+		Device:            describeDevice(client.UserAgent),
+		DeviceFingerprint: deviceFingerprint(client.UserAgent),
+		UserAgent:         truncate(client.UserAgent, 512),
+		IpAddress:         truncate(client.IpAddress, 64),
+		IpRange:           networkRange(client.IpAddress),
+		Country:           location.Country,
+		City:              location.City,
+	}
+
+	footprint, err := s.loginRepo.GetLoginFootprint(user.Id, event.DeviceFingerprint, event.IpRange)
+	if err != nil {
+		s.logger.Error("Failed to read login history", "user_id", user.Id, "error", err)
+		return
+	}
+	event.NewDevice = footprint.HasHistory && !footprint.KnownDevice
+	event.NewLocation = footprint.HasHistory && !footprint.KnownIpRange
+
+	token := ""
+	if event.NewDevice || event.NewLocation {
+		var tokenHash string
+		if token, tokenHash, err = newLinkToken(); err == nil {
+			event.ReportTokenHash = tokenHash
+			event.ReportExpiresAt = time.Now().Add(s.reportTTL)
+		}
+	}
+
+	event, err = s.loginRepo.CreateLoginEvent(event)
+	if err != nil {
+		s.logger.Error("Failed to record login", "user_id", user.Id, "error", err)
+		return
+	}
+
+	if event.ReportTokenHash != "" {
+		s.sendAlert(user, event, token)
+	}
+}
+
+// GetHistory liste les connexions d'un utilisateur, les plus récentes en premier
+func (s *LoginHistoryService) GetHistory(userId string, limit int, offset int) (LoginHistoryPage, error) {
+	if _, err := s.userRepo.GetUserById(userId); err != nil {
+		return LoginHistoryPage{}, errors.New("utilisateur non trouvé")
+	}
+
+	if limit <= 0 {
+		limit = defaultLoginHistoryPageSize
+	}
+	if limit > maxLoginHistoryPageSize {
+		limit = maxLoginHistoryPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	events, total, err := s.loginRepo.GetLoginEventsByUser(userId, limit, offset)
+	if err != nil {
+		return LoginHistoryPage{}, errors.New("nous avons rencontré un problème durant la lecture de l'historique des connexions")
+	}
+	return LoginHistoryPage{Events: events, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// GetReportableLogin retourne la connexion associée à un lien "ce n'était pas moi" encore valide
+func (s *LoginHistoryService) GetReportableLogin(token string) (model.LoginEvent, error) {
+	event, err := s.loginRepo.GetLoginEventByReportHash(hashLinkToken(token))
+	if err != nil || !event.IsReportable(time.Now()) {
+		return model.LoginEvent{}, errors.New("ce lien est invalide ou a expiré")
+	}
+	return event, nil
+}
+
+// ReportLogin traite un lien "ce n'était pas moi" : toutes les sessions de l'utilisateur sont révoquées
+// et le mot de passe est remplacé par celui qu'il vient de choisir
+func (s *LoginHistoryService) ReportLogin(token string, password string) (model.LoginEvent, error) {
+	event, err := s.GetReportableLogin(token)
+	if err != nil {
+		return model.LoginEvent{}, err
+	}
+
+	user, err := s.userRepo.GetUserById(event.UserId)
+	if err != nil {
+		return model.LoginEvent{}, errors.New("ce compte n'existe plus")
+	}
+	if effectiveAccountState(user, time.Now()) == AccountStateDisabled {
+		return model.LoginEvent{}, &AccountStateError{State: AccountStateDisabled}
+	}
+	if utils.CompareHashPassword(password, user.Password) {
+		return model.LoginEvent{}, errors.New("vous ne pouvez pas utiliser le même mot de passe")
+	}
+
+	passwordHash, err := s.security.GenerateHashPassword(password)
+	if err != nil {
+		return model.LoginEvent{}, errors.New("erreur de cryptage du mot de passe utilisateur")
+	}
+
+	if err := s.loginRepo.ReportLoginEvent(event, passwordHash); err != nil {
+		return model.LoginEvent{}, errors.New("ce lien est invalide ou a expiré")
+	}
+	return event, nil
+}
+
+// sendAlert prévient l'utilisateur d'une connexion inhabituelle ; un échec d'envoi est tracé
+func (s *LoginHistoryService) sendAlert(user model.User, event model.LoginEvent, token string) {
+	if user.Email == "" {
+		return
+	}
+
+	subject := "Nouvelle connexion à votre compte"
+	if event.NewDevice {
+		subject = "Connexion depuis un nouvel appareil"
+	}
+
+	place := event.IpAddress
+	if location := strings.Trim(event.City+", "+event.Country, ", "); location != "" {
+		place = fmt.Sprintf("%s (%s)", location, event.IpAddress)
+	}
+
+	link := s.reportUrl + "?token=" + url.QueryEscape(token)
+	err := s.notifier.Send(Notification{
+		Recipient: user.Email,
+		Subject:   subject,
+		Body: fmt.Sprintf("Bonjour, une connexion à votre compte a eu lieu le %s depuis %s, %s. "+
+			"Si ce n'était pas vous, suivez ce lien avant le %s pour déconnecter tous vos appareils "+
+			"et choisir un nouveau mot de passe : %s",
+			event.CreatedAt.Format("02/01/2006 15:04"), event.Device, place,
+			event.ReportExpiresAt.Format("02/01/2006 15:04"), link),
+	})
+	if err != nil {
+		s.logger.Error("Failed to send login alert", "login_id", event.Id, "email", user.Email, "error", err)
+	}
+}
+
+// deviceFingerprint identifie un appareil par son navigateur et son système, sans leurs versions,
+// afin qu'une mise à jour du navigateur ne soit pas prise pour un nouvel appareil
+func deviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(describeDevice(userAgent))))
+	return hex.EncodeToString(sum[:])
+}
+
+// networkRange retourne la plage d'une adresse IP : /24 en IPv4, /48 en IPv6
+func networkRange(ipAddress string) string {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return truncate(ipAddress, 64)
+	}
+	addr = addr.Unmap()
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return addr.String()
+	}
+	return prefix.String()
+}
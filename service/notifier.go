@@ -0,0 +1,153 @@
+package service
+
+import (
+	"auth/config"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	slog "github.com/sagikazarmark/slog-shim"
+)
+
+// Notification est un message adressé à un utilisateur
+type Notification struct {
+	Recipient string
+	Subject   string
+	Body      string
+}
+
+// Notifier envoie les notifications aux utilisateurs (email, SMS, ...)
+type Notifier interface {
+	Send(notification Notification) error
+}
+
+// NewNotifier retourne le notifier configuré : l'envoi par SMTP, ou à défaut l'écriture dans les logs
+func NewNotifier(smtpConfig config.SmtpConfig) Notifier {
+	if !smtpConfig.Enabled() {
+		return NewLogNotifier()
+	}
+	return NewSmtpNotifier(smtpConfig)
+}
+
+// LogNotifier écrit les notifications dans les logs sans les envoyer. Réservé au développement :
+// le contenu, qui peut porter un code ou un lien, n'est écrit qu'au niveau debug.
+type LogNotifier struct{}
+
+// NewLogNotifier crée une nouvelle instance de LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Send écrit la notification dans les logs
+func (n *LogNotifier) Send(notification Notification) error {
+	logger := slog.Default()
+	logger.Warn("Notification not delivered, no SMTP server configured", "recipient", notification.Recipient, "subject", notification.Subject)
+	logger.Debug("Notification content", "recipient", notification.Recipient, "body", notification.Body)
+	return nil
+}
+
+// SmtpNotifier envoie les notifications par email
+type SmtpNotifier struct {
+	config config.SmtpConfig
+}
+
+// NewSmtpNotifier crée une nouvelle instance de SmtpNotifier
+func NewSmtpNotifier(smtpConfig config.SmtpConfig) *SmtpNotifier {
+	return &SmtpNotifier{
+		config: smtpConfig,
+	}
+}
+
+// Send envoie la notification en texte brut. STARTTLS est utilisé dès que le serveur le propose ;
+// l'authentification n'est jamais envoyée en clair, sauf vers localhost.
+func (n *SmtpNotifier) Send(notification Notification) error {
+	from, err := mail.ParseAddress(n.config.From)
+	if err != nil {
+		return fmt.Errorf("adresse d'expédition invalide : %w", err)
+	}
+	to, err := mail.ParseAddress(notification.Recipient)
+	if err != nil {
+		return fmt.Errorf("adresse du destinataire invalide : %w", err)
+	}
+
+	address := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	conn, err := net.DialTimeout("tcp", address, n.config.Timeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(n.config.Timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return err
+		}
+	}
+	if n.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("le serveur SMTP ne propose pas d'authentification")
+		}
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(buildMessage(from, to, notification)); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage construit le message au format RFC 5322, en UTF-8. Le sujet, qui peut contenir
+// le nom d'une organisation, est ramené à une ligne.
+func buildMessage(from *mail.Address, to *mail.Address, notification Notification) []byte {
+	var message strings.Builder
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(notification.Subject), " "))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "8bit"},
+	}
+	for _, header := range headers {
+		message.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	message.WriteString("\r\n")
+
+	body := strings.ReplaceAll(notification.Body, "\r\n", "\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	message.WriteString("\r\n")
+	return []byte(message.String())
+}
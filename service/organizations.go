@@ -0,0 +1,212 @@
+package service
+
+import (
+	"auth/model"
+	"auth/repository"
+	"context"
+	"errors"
+)
+
+type Organization struct {
+	Name     string `json:"name" validate:"required"`
+	Slug     string `json:"slug" validate:"required"`
+	Describe string `json:"describe"`
+}
+
+type OrganizationMember struct {
+	MembershipId string
+	User         model.User
+	Roles        []string
+}
+
+// OrganizationService gère la logique métier liée aux organisations et à leurs membres
+type OrganizationService struct {
+	orgRepo  *repository.OrganizationRepository
+	userRepo *repository.UserRepository
+	roleRepo *repository.RoleRepository
+}
+
+// NewOrganizationService crée une nouvelle instance de OrganizationService
+func NewOrganizationService(
+	orgRepo *repository.OrganizationRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *OrganizationService) WithContext(ctx context.Context) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:  s.orgRepo.WithContext(ctx),
+		userRepo: s.userRepo.WithContext(ctx),
+		roleRepo: s.roleRepo.WithContext(ctx),
+	}
+}
+
+// CreateOrganization crée une nouvelle organisation
+func (s *OrganizationService) CreateOrganization(newOrganization Organization) (model.Organization, error) {
+	_, err := s.orgRepo.GetOrganizationBySlug(newOrganization.Slug)
+	if err == nil {
+		return model.Organization{}, errors.New("ce slug d'organisation a déjà été utilisé")
+	}
+
+	return s.orgRepo.CreateOrganization(model.Organization{
+		Name:     newOrganization.Name,
+		Slug:     newOrganization.Slug,
+		Describe: newOrganization.Describe,
+	})
+}
+
+// GetAllOrganizations récupère toutes les organisations
+func (s *OrganizationService) GetAllOrganizations() ([]model.Organization, error) {
+	return s.orgRepo.GetAllOrganizations()
+}
+
+// GetOrganizationById récupère une organisation par son ID
+func (s *OrganizationService) GetOrganizationById(id string) (model.Organization, error) {
+	return s.orgRepo.GetOrganizationById(id)
+}
+
+// GetMembers récupère les membres d'une organisation avec leurs rôles
+func (s *OrganizationService) GetMembers(orgId string) ([]OrganizationMember, error) {
+	if _, err := s.orgRepo.GetOrganizationById(orgId); err != nil {
+		return nil, errors.New("organisation non trouvée")
+	}
+
+	memberships, err := s.orgRepo.GetMembershipsByOrganization(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantUserRepo := s.userRepo.ForOrganization(orgId)
+	members := []OrganizationMember{}
+
+	for i := 0; i < len(memberships); i++ {
+		user, err := tenantUserRepo.GetUserById(memberships[i].UserId)
+		if err != nil {
+			continue
+		}
+
+		roleNames, err := s.roleNames(memberships[i].MembershipRoles)
+		if err != nil {
+			return nil, err
+		}
+
+		members = append(members, OrganizationMember{
+			MembershipId: memberships[i].Id,
+			User:         user,
+			Roles:        roleNames,
+		})
+	}
+
+	return members, nil
+}
+
+// AddMember ajoute un utilisateur existant à une organisation
+func (s *OrganizationService) AddMember(orgId string, userId string, roleNames []string) (OrganizationMember, error) {
+	if _, err := s.orgRepo.GetOrganizationById(orgId); err != nil {
+		return OrganizationMember{}, errors.New("organisation non trouvée")
+	}
+
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return OrganizationMember{}, errors.New("utilisateur non trouvé")
+	}
+
+	if _, err = s.orgRepo.GetMembership(userId, orgId); err == nil {
+		return OrganizationMember{}, errors.New("l'utilisateur est déjà membre de cette organisation")
+	}
+
+	roleIds, err := s.roleIds(roleNames)
+	if err != nil {
+		return OrganizationMember{}, err
+	}
+
+	membership, err := s.orgRepo.CreateMembership(userId, orgId, roleIds)
+	if err != nil {
+		return OrganizationMember{}, err
+	}
+
+	return OrganizationMember{
+		MembershipId: membership.Id,
+		User:         user,
+		Roles:        roleNames,
+	}, nil
+}
+
+// UpdateMemberRoles remplace les rôles d'un membre dans une organisation
+func (s *OrganizationService) UpdateMemberRoles(orgId string, userId string, roleNames []string) (OrganizationMember, error) {
+	membership, err := s.orgRepo.GetMembership(userId, orgId)
+	if err != nil {
+		return OrganizationMember{}, errors.New("l'utilisateur n'est pas membre de cette organisation")
+	}
+
+	user, err := s.userRepo.ForOrganization(orgId).GetUserById(userId)
+	if err != nil {
+		return OrganizationMember{}, errors.New("utilisateur non trouvé")
+	}
+
+	roleIds, err := s.roleIds(roleNames)
+	if err != nil {
+		return OrganizationMember{}, err
+	}
+
+	if _, err = s.orgRepo.SetMembershipRoles(membership.Id, roleIds); err != nil {
+		return OrganizationMember{}, err
+	}
+
+	return OrganizationMember{
+		MembershipId: membership.Id,
+		User:         user,
+		Roles:        roleNames,
+	}, nil
+}
+
+// RemoveMember retire un utilisateur d'une organisation
+func (s *OrganizationService) RemoveMember(orgId string, userId string) error {
+	membership, err := s.orgRepo.GetMembership(userId, orgId)
+	if err != nil {
+		return errors.New("l'utilisateur n'est pas membre de cette organisation")
+	}
+
+	return s.orgRepo.DeleteMembership(membership.Id)
+}
+
+func (s *OrganizationService) roleIds(roleNames []string) ([]string, error) {
+	roles, err := s.roleRepo.GetRolesByNames(roleNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roles) != len(roleNames) {
+		return nil, errors.New("aucun rôle ne correspond à cette valeur")
+	}
+
+	var roleIds []string
+	for i := 0; i < len(roles); i++ {
+		roleIds = append(roleIds, roles[i].Id)
+	}
+	return roleIds, nil
+}
+
+func (s *OrganizationService) roleNames(membershipRoles []model.MembershipRole) ([]string, error) {
+	var roleIds []string
+	for i := 0; i < len(membershipRoles); i++ {
+		roleIds = append(roleIds, membershipRoles[i].RoleId)
+	}
+
+	roles, err := s.roleRepo.GetRolesByIds(roleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames := []string{}
+	for i := 0; i < len(roles); i++ {
+		roleNames = append(roleNames, roles[i].Name)
+	}
+	return roleNames, nil
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	PolicyEffectAllow = "allow"
+	PolicyEffectDeny  = "deny"
+)
+
+const noApplicablePolicyReason = "no applicable policy allows this action"
+
+// PolicyDocument décrit une règle évaluée sur les attributs du sujet,
+// de la ressource, de l'action et de l'environnement.
+//
+// Exemple : un manager ne peut modifier que les utilisateurs de sa propre
+// organisation, pendant les heures ouvrées.
+//
+//	{
+//	  "effect": "allow",
+//	  "actions": ["update"],
+//	  "resources": ["user"],
+//	  "all": [
+//	    {"attribute": "subject.roles", "operator": "contains", "value": "manager"},
+//	    {"attribute": "subject.org_id", "operator": "eq", "attribute_ref": "resource.org_id"},
+//	    {"attribute": "environment.hour", "operator": "between", "value": [9, 18]}
+//	  ]
+//	}
+//
+// L'intervalle de between est semi-ouvert : [9, 18] couvre 9 <= hour < 18, si bien que
+// deux plages consécutives comme [9, 12] et [12, 18] ne se chevauchent pas.
+type PolicyDocument struct {
+	Effect    string            `json:"effect" validate:"required,oneof=allow deny"`
+	Actions   []string          `json:"actions" validate:"required,min=1"`
+	Resources []string          `json:"resources" validate:"required,min=1"`
+	All       []PolicyCondition `json:"all" validate:"dive"`
+	Any       []PolicyCondition `json:"any" validate:"dive"`
+}
+
+// PolicyCondition compare un attribut à une valeur littérale ou à un autre attribut
+type PolicyCondition struct {
+	Attribute    string      `json:"attribute" validate:"required"`
+	Operator     string      `json:"operator" validate:"required,oneof=eq ne in not_in contains gt gte lt lte between exists starts_with ends_with"`
+	Value        interface{} `json:"value,omitempty"`
+	AttributeRef string      `json:"attribute_ref,omitempty"`
+}
+
+// AuthorizationRequest regroupe les attributs soumis à une décision
+type AuthorizationRequest struct {
+	Subject     map[string]interface{} `json:"subject"`
+	Resource    map[string]interface{} `json:"resource"`
+	Action      string                 `json:"action"`
+	Environment map[string]interface{} `json:"environment"`
+}
+
+// AuthorizationDecision est le résultat de l'évaluation des politiques
+type AuthorizationDecision struct {
+	Allowed         bool     `json:"allowed"`
+	Decision        string   `json:"decision"`
+	Reasons         []string `json:"reasons"`
+	MatchedPolicies []string `json:"matched_policies"`
+
+	// explicitDeny indique qu'une politique "deny" s'est appliquée
+	explicitDeny bool
+}
+
+// evaluatedPolicy associe un document à la politique stockée dont il provient
+type evaluatedPolicy struct {
+	Name     string
+	Version  int
+	Document PolicyDocument
+}
+
+// evaluatePolicies applique les politiques à la requête.
+// Un refus explicite l'emporte toujours ; sans règle applicable, l'accès est refusé.
+func evaluatePolicies(policies []evaluatedPolicy, request AuthorizationRequest) AuthorizationDecision {
+	attributes := map[string]interface{}{
+		"subject":     request.Subject,
+		"resource":    request.Resource,
+		"action":      request.Action,
+		"environment": request.Environment,
+	}
+	resourceType := fmt.Sprintf("%v", request.Resource["type"])
+
+	decision := AuthorizationDecision{
+		Reasons:         []string{},
+		MatchedPolicies: []string{},
+	}
+	var allowed, denied bool
+
+	for i := 0; i < len(policies); i++ {
+		policy := policies[i]
+		label := fmt.Sprintf("%s@v%d", policy.Name, policy.Version)
+
+		if !matchesPattern(policy.Document.Actions, request.Action) ||
+			!matchesPattern(policy.Document.Resources, resourceType) {
+			continue
+		}
+
+		matched, reason := evaluateConditions(policy.Document, attributes)
+		if !matched {
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("%s not matched: %s", label, reason))
+			continue
+		}
+
+		decision.MatchedPolicies = append(decision.MatchedPolicies, label)
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("%s matched with effect %s", label, policy.Document.Effect))
+
+		if policy.Document.Effect == PolicyEffectDeny {
+			denied = true
+		} else {
+			allowed = true
+		}
+	}
+
+	switch {
+	case denied:
+		decision.Allowed = false
+		decision.explicitDeny = true
+	case allowed:
+		decision.Allowed = true
+	default:
+		decision.Reasons = append(decision.Reasons, noApplicablePolicyReason)
+	}
+
+	return withDecisionLabel(decision)
+}
+
+// decide calcule la décision d'une requête : les politiques actives d'abord, puis
+// à défaut la permission "<action>_<type>" portée par l'attribut subject.permissions,
+// que le service renseigne à partir des rôles enregistrés du sujet.
+func decide(policies []evaluatedPolicy, request AuthorizationRequest) AuthorizationDecision {
+	return grantByPermission(evaluatePolicies(policies, request), request)
+}
+
+// grantByPermission autorise la requête lorsque le sujet détient la permission
+// correspondante, sauf si une politique l'a explicitement refusée.
+func grantByPermission(decision AuthorizationDecision, request AuthorizationRequest) AuthorizationDecision {
+	if decision.Allowed || decision.explicitDeny {
+		return decision
+	}
+
+	permission := permissionFor(request)
+	permissions := subjectPermissions(request.Subject)
+	for i := 0; i < len(permissions); i++ {
+		if permissions[i] == permission {
+			reasons := []string{}
+			for j := 0; j < len(decision.Reasons); j++ {
+				if decision.Reasons[j] != noApplicablePolicyReason {
+					reasons = append(reasons, decision.Reasons[j])
+				}
+			}
+
+			decision.Allowed = true
+			decision.Reasons = append(reasons, fmt.Sprintf("granted by permission %s", permission))
+			return withDecisionLabel(decision)
+		}
+	}
+
+	decision.Reasons = append(decision.Reasons, fmt.Sprintf("permission %s is not granted", permission))
+	return decision
+}
+
+// subjectPermissions retourne les permissions listées dans l'attribut "permissions" du sujet
+func subjectPermissions(subject map[string]interface{}) []string {
+	switch permissions := subject["permissions"].(type) {
+	case []string:
+		return permissions
+	case []interface{}:
+		result := []string{}
+		for i := 0; i < len(permissions); i++ {
+			if permission, ok := permissions[i].(string); ok {
+				result = append(result, permission)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// permissionFor retourne le nom de permission associé à une action sur un type
+// de ressource, par exemple "delete" sur "user" donne "delete_user".
+func permissionFor(request AuthorizationRequest) string {
+	resourceType, _ := request.Resource["type"].(string)
+	if resourceType == "" {
+		return request.Action
+	}
+	return request.Action + "_" + resourceType
+}
+
+func withDecisionLabel(decision AuthorizationDecision) AuthorizationDecision {
+	decision.Decision = PolicyEffectDeny
+	if decision.Allowed {
+		decision.Decision = PolicyEffectAllow
+	}
+	return decision
+}
+
+// validatePolicyDocument vérifie les parties du document que le validateur ne couvre pas
+func validatePolicyDocument(document PolicyDocument) error {
+	conditions := append(append([]PolicyCondition{}, document.All...), document.Any...)
+	for i := 0; i < len(conditions); i++ {
+		condition := conditions[i]
+		if condition.Operator == "exists" {
+			continue
+		}
+		if condition.Value == nil && condition.AttributeRef == "" {
+			return fmt.Errorf("la condition sur %s doit fournir value ou attribute_ref", condition.Attribute)
+		}
+		if condition.Operator == "between" {
+			bounds, ok := condition.Value.([]interface{})
+			if !ok || len(bounds) != 2 {
+				return fmt.Errorf("la condition between sur %s attend deux bornes", condition.Attribute)
+			}
+		}
+	}
+	return nil
+}
+
+func matchesPattern(patterns []string, value string) bool {
+	for i := 0; i < len(patterns); i++ {
+		if patterns[i] == "*" || patterns[i] == value {
+			return true
+		}
+	}
+	return false
+}
+
+func evaluateConditions(document PolicyDocument, attributes map[string]interface{}) (bool, string) {
+	for i := 0; i < len(document.All); i++ {
+		ok, err := evaluateCondition(document.All[i], attributes)
+		if !ok {
+			return false, describeFailure(document.All[i], err)
+		}
+	}
+
+	if len(document.Any) == 0 {
+		return true, ""
+	}
+
+	var failures []string
+	for i := 0; i < len(document.Any); i++ {
+		ok, err := evaluateCondition(document.Any[i], attributes)
+		if ok {
+			return true, ""
+		}
+		failures = append(failures, describeFailure(document.Any[i], err))
+	}
+	return false, "none of [" + strings.Join(failures, "; ") + "]"
+}
+
+func describeFailure(condition PolicyCondition, err error) string {
+	right := fmt.Sprintf("%v", condition.Value)
+	if condition.AttributeRef != "" {
+		right = condition.AttributeRef
+	}
+
+	failure := fmt.Sprintf("%s %s %s", condition.Attribute, condition.Operator, right)
+	if err != nil {
+		failure += " (" + err.Error() + ")"
+	}
+	return failure
+}
+
+func evaluateCondition(condition PolicyCondition, attributes map[string]interface{}) (bool, error) {
+	left, found := lookupAttribute(attributes, condition.Attribute)
+	if condition.Operator == "exists" {
+		return found, nil
+	}
+	if !found {
+		return false, errors.New("attribute is missing")
+	}
+
+	right := condition.Value
+	if condition.AttributeRef != "" {
+		var refFound bool
+		right, refFound = lookupAttribute(attributes, condition.AttributeRef)
+		if !refFound {
+			return false, errors.New("referenced attribute is missing")
+		}
+	}
+
+	switch condition.Operator {
+	case "eq":
+		return equalValues(left, right), nil
+	case "ne":
+		return !equalValues(left, right), nil
+	case "in":
+		return containsValue(right, left), nil
+	case "not_in":
+		return !containsValue(right, left), nil
+	case "contains":
+		if text, ok := left.(string); ok {
+			return strings.Contains(text, fmt.Sprintf("%v", right)), nil
+		}
+		return containsValue(left, right), nil
+	case "starts_with":
+		return strings.HasPrefix(fmt.Sprintf("%v", left), fmt.Sprintf("%v", right)), nil
+	case "ends_with":
+		return strings.HasSuffix(fmt.Sprintf("%v", left), fmt.Sprintf("%v", right)), nil
+	case "gt", "gte", "lt", "lte":
+		comparison, err := compareValues(left, right)
+		if err != nil {
+			return false, err
+		}
+		switch condition.Operator {
+		case "gt":
+			return comparison > 0, nil
+		case "gte":
+			return comparison >= 0, nil
+		case "lt":
+			return comparison < 0, nil
+		default:
+			return comparison <= 0, nil
+		}
+	case "between":
+		bounds, ok := right.([]interface{})
+		if !ok || len(bounds) != 2 {
+			return false, errors.New("between expects two bounds")
+		}
+		lower, err := compareValues(left, bounds[0])
+		if err != nil {
+			return false, err
+		}
+		upper, err := compareValues(left, bounds[1])
+		if err != nil {
+			return false, err
+		}
+		return lower >= 0 && upper < 0, nil
+	}
+
+	return false, fmt.Errorf("unknown operator %s", condition.Operator)
+}
+
+// lookupAttribute résout un chemin comme "subject.org_id" dans les attributs
+func lookupAttribute(attributes map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = attributes
+	parts := strings.Split(path, ".")
+
+	for i := 0; i < len(parts); i++ {
+		values, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = values[parts[i]]
+		if !ok || current == nil {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func equalValues(left interface{}, right interface{}) bool {
+	leftNumber, leftIsNumber := toNumber(left)
+	rightNumber, rightIsNumber := toNumber(right)
+	if leftIsNumber && rightIsNumber {
+		return leftNumber == rightNumber
+	}
+	return fmt.Sprintf("%v", left) == fmt.Sprintf("%v", right)
+}
+
+func containsValue(collection interface{}, value interface{}) bool {
+	switch items := collection.(type) {
+	case []interface{}:
+		for i := 0; i < len(items); i++ {
+			if equalValues(items[i], value) {
+				return true
+			}
+		}
+	case []string:
+		for i := 0; i < len(items); i++ {
+			if equalValues(items[i], value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareValues compare deux nombres ou deux dates RFC3339
+func compareValues(left interface{}, right interface{}) (int, error) {
+	leftNumber, leftIsNumber := toNumber(left)
+	rightNumber, rightIsNumber := toNumber(right)
+	if leftIsNumber && rightIsNumber {
+		switch {
+		case leftNumber < rightNumber:
+			return -1, nil
+		case leftNumber > rightNumber:
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	leftTime, leftErr := time.Parse(time.RFC3339, fmt.Sprintf("%v", left))
+	rightTime, rightErr := time.Parse(time.RFC3339, fmt.Sprintf("%v", right))
+	if leftErr == nil && rightErr == nil {
+		switch {
+		case leftTime.Before(rightTime):
+			return -1, nil
+		case leftTime.After(rightTime):
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return 0, errors.New("values are not comparable")
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case float32:
+		return float64(number), true
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	}
+	return 0, false
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// testAttributes reproduit les attributs d'une requête après décodage JSON
+func testAttributes(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var attributes map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"subject": {"id": "u1", "org_id": "o1", "roles": ["manager", "client"], "email": "jane@example.com", "level": 3},
+		"resource": {"type": "user", "org_id": "o1", "created_at": "2026-01-15T10:00:00Z"},
+		"action": "update",
+		"environment": {"hour": 18}
+	}`), &attributes)
+	if err != nil {
+		t.Fatalf("attributs de test : %v", err)
+	}
+	return attributes
+}
+
+func TestEvaluateConditionOperators(t *testing.T) {
+	attributes := testAttributes(t)
+	cases := []struct {
+		name      string
+		condition PolicyCondition
+		want      bool
+	}{
+		{"eq", PolicyCondition{Attribute: "subject.org_id", Operator: "eq", Value: "o1"}, true},
+		{"eq nombre", PolicyCondition{Attribute: "subject.level", Operator: "eq", Value: 3}, true},
+		{"eq attribut", PolicyCondition{Attribute: "subject.org_id", Operator: "eq", AttributeRef: "resource.org_id"}, true},
+		{"ne", PolicyCondition{Attribute: "subject.org_id", Operator: "ne", Value: "o2"}, true},
+		{"in", PolicyCondition{Attribute: "action", Operator: "in", Value: []interface{}{"read", "update"}}, true},
+		{"not_in", PolicyCondition{Attribute: "action", Operator: "not_in", Value: []interface{}{"delete"}}, true},
+		{"contains liste", PolicyCondition{Attribute: "subject.roles", Operator: "contains", Value: "manager"}, true},
+		{"contains absent", PolicyCondition{Attribute: "subject.roles", Operator: "contains", Value: "admin"}, false},
+		{"contains texte", PolicyCondition{Attribute: "subject.email", Operator: "contains", Value: "@example"}, true},
+		{"starts_with", PolicyCondition{Attribute: "subject.email", Operator: "starts_with", Value: "jane"}, true},
+		{"ends_with", PolicyCondition{Attribute: "subject.email", Operator: "ends_with", Value: ".com"}, true},
+		{"gt", PolicyCondition{Attribute: "subject.level", Operator: "gt", Value: 2}, true},
+		{"gte", PolicyCondition{Attribute: "subject.level", Operator: "gte", Value: 3}, true},
+		{"lt", PolicyCondition{Attribute: "subject.level", Operator: "lt", Value: 3}, false},
+		{"lte", PolicyCondition{Attribute: "subject.level", Operator: "lte", Value: 3}, true},
+		{"lt date", PolicyCondition{Attribute: "resource.created_at", Operator: "lt", Value: "2026-02-01T00:00:00Z"}, true},
+		{"exists", PolicyCondition{Attribute: "subject.email", Operator: "exists"}, true},
+		{"exists absent", PolicyCondition{Attribute: "subject.phone", Operator: "exists"}, false},
+		{"attribut absent", PolicyCondition{Attribute: "subject.phone", Operator: "eq", Value: "x"}, false},
+		{"between borne basse incluse", PolicyCondition{Attribute: "environment.hour", Operator: "between", Value: []interface{}{18.0, 20.0}}, true},
+		{"between borne haute exclue", PolicyCondition{Attribute: "environment.hour", Operator: "between", Value: []interface{}{9.0, 18.0}}, false},
+		{"between", PolicyCondition{Attribute: "subject.level", Operator: "between", Value: []interface{}{1.0, 5.0}}, true},
+		{"valeurs non comparables", PolicyCondition{Attribute: "subject.email", Operator: "gt", Value: 1}, false},
+	}
+
+	for _, c := range cases {
+		got, _ := evaluateCondition(c.condition, attributes)
+		if got != c.want {
+			t.Errorf("%s : evaluateCondition = %v, %v attendu", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBetweenRangesDoNotOverlap(t *testing.T) {
+	morning := PolicyCondition{Attribute: "environment.hour", Operator: "between", Value: []interface{}{9.0, 12.0}}
+	afternoon := PolicyCondition{Attribute: "environment.hour", Operator: "between", Value: []interface{}{12.0, 18.0}}
+
+	for hour := 9; hour < 18; hour++ {
+		attributes := map[string]interface{}{"environment": map[string]interface{}{"hour": float64(hour)}}
+		inMorning, _ := evaluateCondition(morning, attributes)
+		inAfternoon, _ := evaluateCondition(afternoon, attributes)
+		if inMorning == inAfternoon {
+			t.Errorf("%dh : matin = %v, après-midi = %v ; une seule plage attendue", hour, inMorning, inAfternoon)
+		}
+	}
+}
+
+func TestDecideDeniesByDefault(t *testing.T) {
+	request := normalizeRequest(AuthorizationRequest{
+		Subject:  map[string]interface{}{"id": "u1"},
+		Resource: map[string]interface{}{"type": "user"},
+		Action:   "delete",
+	}, time.Now())
+
+	decision := decide(nil, request)
+	if decision.Allowed || decision.Decision != PolicyEffectDeny {
+		t.Fatalf("décision = %+v, refus attendu sans politique ni permission", decision)
+	}
+}
+
+func TestDecideExplicitDenyWins(t *testing.T) {
+	policies := []evaluatedPolicy{
+		{Name: "allow-all", Version: 1, Document: PolicyDocument{Effect: PolicyEffectAllow, Actions: []string{"*"}, Resources: []string{"*"}}},
+		{Name: "deny-delete", Version: 2, Document: PolicyDocument{Effect: PolicyEffectDeny, Actions: []string{"delete"}, Resources: []string{"user"}}},
+	}
+	request := normalizeRequest(AuthorizationRequest{
+		Subject:  map[string]interface{}{"permissions": []interface{}{"delete_user"}},
+		Resource: map[string]interface{}{"type": "user"},
+		Action:   "delete",
+	}, time.Now())
+
+	decision := decide(policies, request)
+	if decision.Allowed {
+		t.Fatalf("décision = %+v, un refus explicite doit l'emporter sur une politique et une permission", decision)
+	}
+	if len(decision.MatchedPolicies) != 2 {
+		t.Errorf("politiques appliquées = %v, les deux attendues", decision.MatchedPolicies)
+	}
+
+	request.Action = "update"
+	if decision := decide(policies, request); !decision.Allowed {
+		t.Errorf("décision = %+v, la politique allow-all doit accorder update", decision)
+	}
+}
+
+func TestDecideGrantsByPermission(t *testing.T) {
+	request := normalizeRequest(AuthorizationRequest{
+		Subject:  map[string]interface{}{"permissions": []interface{}{"delete_user"}},
+		Resource: map[string]interface{}{"type": "user"},
+		Action:   "delete",
+	}, time.Now())
+
+	decision := decide(nil, request)
+	if !decision.Allowed {
+		t.Fatalf("décision = %+v, la permission delete_user doit accorder l'action", decision)
+	}
+	for _, reason := range decision.Reasons {
+		if reason == noApplicablePolicyReason {
+			t.Errorf("raisons = %v, l'absence de politique ne doit plus être signalée", decision.Reasons)
+		}
+	}
+}
+
+func TestDecideIgnoresPoliciesForOtherActions(t *testing.T) {
+	policies := []evaluatedPolicy{
+		{Name: "office-hours", Version: 1, Document: PolicyDocument{
+			Effect:    PolicyEffectAllow,
+			Actions:   []string{"update"},
+			Resources: []string{"user"},
+			All:       []PolicyCondition{{Attribute: "environment.hour", Operator: "between", Value: []interface{}{9.0, 18.0}}},
+		}},
+	}
+	request := normalizeRequest(AuthorizationRequest{
+		Resource:    map[string]interface{}{"type": "user"},
+		Action:      "update",
+		Environment: map[string]interface{}{"hour": 18.0},
+	}, time.Now())
+
+	if decision := decide(policies, request); decision.Allowed {
+		t.Errorf("décision = %+v, 18h est hors de la plage [9, 18)", decision)
+	}
+	request.Environment["hour"] = 17.0
+	if decision := decide(policies, request); !decision.Allowed {
+		t.Errorf("décision = %+v, 17h est dans la plage [9, 18)", decision)
+	}
+	request.Action = "delete"
+	if decision := decide(policies, request); decision.Allowed {
+		t.Errorf("décision = %+v, la politique ne couvre pas delete", decision)
+	}
+}
+
+func TestValidatePolicyDocument(t *testing.T) {
+	valid := PolicyDocument{Effect: PolicyEffectAllow, Actions: []string{"read"}, Resources: []string{"user"},
+		All: []PolicyCondition{{Attribute: "environment.hour", Operator: "between", Value: []interface{}{9.0, 18.0}}}}
+	if err := validatePolicyDocument(valid); err != nil {
+		t.Errorf("document valide refusé : %v", err)
+	}
+
+	invalid := []PolicyDocument{
+		{All: []PolicyCondition{{Attribute: "subject.org_id", Operator: "eq"}}},
+		{All: []PolicyCondition{{Attribute: "environment.hour", Operator: "between", Value: []interface{}{9.0}}}},
+		{Any: []PolicyCondition{{Attribute: "environment.hour", Operator: "between", Value: 9.0}}},
+	}
+	for i, document := range invalid {
+		if err := validatePolicyDocument(document); err == nil {
+			t.Errorf("document invalide %d accepté", i)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"errors"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"net/url"
+	"time"
+)
+
+const (
+	IdentityFieldEmail    = "email"
+	IdentityFieldUsername = "username"
+)
+
+// ProfileUpdate regroupe les informations du profil modifiables sans vérification
+type ProfileUpdate struct {
+	Name    string
+	Sername string
+	Locale  string
+}
+
+// ProfileService gère le profil de l'utilisateur connecté : informations personnelles,
+// changements d'email et de nom d'utilisateur, photo de profil et langue
+type ProfileService struct {
+	userRepo   *repository.UserRepository
+	changeRepo *repository.IdentityChangeRepository
+	notifier   Notifier
+	avatars    *AvatarStorage
+	confirmUrl string
+	ttl        time.Duration
+	locales    []string
+	logger     *slog.Logger
+}
+
+// NewProfileService crée une nouvelle instance de ProfileService
+func NewProfileService(
+	userRepo *repository.UserRepository,
+	changeRepo *repository.IdentityChangeRepository,
+	notifier Notifier,
+	avatars *AvatarStorage,
+	confirmUrl string,
+	ttl time.Duration,
+	locales []string) *ProfileService {
+	return &ProfileService{
+		userRepo:   userRepo,
+		changeRepo: changeRepo,
+		notifier:   notifier,
+		avatars:    avatars,
+		confirmUrl: confirmUrl,
+		ttl:        ttl,
+		locales:    locales,
+		logger:     slog.Default(),
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *ProfileService) WithContext(ctx context.Context) *ProfileService {
+	return &ProfileService{
+		userRepo:   s.userRepo.WithContext(ctx),
+		changeRepo: s.changeRepo.WithContext(ctx),
+		notifier:   s.notifier,
+		avatars:    s.avatars,
+		confirmUrl: s.confirmUrl,
+		ttl:        s.ttl,
+		locales:    s.locales,
+		logger:     logging.FromContext(ctx),
+	}
+}
+
+// GetProfile récupère le profil d'un utilisateur
+func (s *ProfileService) GetProfile(userId string) (model.User, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return model.User{}, errors.New("ce compte n'existe dans le système")
+	}
+	return user, nil
+}
+
+// GetPendingChanges liste les changements d'email ou de nom d'utilisateur en attente de confirmation
+func (s *ProfileService) GetPendingChanges(userId string) ([]model.IdentityChange, error) {
+	return s.changeRepo.GetPendingIdentityChanges(userId)
+}
+
+// UpdateProfile modifie le nom, le prénom et la langue de l'utilisateur
+func (s *ProfileService) UpdateProfile(userId string, update ProfileUpdate) (model.User, error) {
+	if update.Locale != "" && !s.isSupportedLocale(update.Locale) {
+		return model.User{}, fmt.Errorf("langue non prise en charge : %s", update.Locale)
+	}
+
+	user, err := s.GetProfile(userId)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user.Name = update.Name
+	user.Sername = update.Sername
+	user.Locale = update.Locale
+	updatedUser, err := s.userRepo.WithEvent(model.EventUserUpdated).UpdateUser(user)
+	if err != nil {
+		return model.User{}, errors.New("nous avons rencontré un problème durant la mise à jour")
+	}
+	return updatedUser, nil
+}
+
+// RequestEmailChange vérifie le mot de passe actuel puis envoie un lien de confirmation à la
+// nouvelle adresse ; l'ancienne adresse est prévenue. L'email ne change qu'après confirmation.
+func (s *ProfileService) RequestEmailChange(userId string, currentPassword string, newEmail string) (model.IdentityChange, error) {
+	user, err := s.checkCurrentPassword(userId, currentPassword)
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+	if newEmail == user.Email {
+		return model.IdentityChange{}, errors.New("cette adresse email est déjà la vôtre")
+	}
+	if err := s.checkIdentityAvailable(IdentityFieldEmail, newEmail); err != nil {
+		return model.IdentityChange{}, err
+	}
+
+	change, token, err := s.createChange(user, IdentityFieldEmail, newEmail)
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+
+	err = s.sendLink(change, Notification{
+		Recipient: newEmail,
+		Subject:   "Confirmez votre nouvelle adresse email",
+		Body: fmt.Sprintf("Bonjour, confirmez votre nouvelle adresse email en suivant ce lien avant le %s : %s",
+			change.ExpiresAt.Format("02/01/2006 15:04"), s.confirmLink(token)),
+	})
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+	s.send(Notification{
+		Recipient: user.Email,
+		Subject:   "Demande de changement d'adresse email",
+		Body: fmt.Sprintf("Bonjour, un changement de votre adresse email vers %s a été demandé. "+
+			"Si vous n'en êtes pas à l'origine, changez votre mot de passe.", newEmail),
+	})
+	return change, nil
+}
+
+// RequestUsernameChange vérifie le mot de passe actuel puis envoie un lien de confirmation
+// à l'adresse email du compte. Le nom d'utilisateur ne change qu'après confirmation.
+func (s *ProfileService) RequestUsernameChange(userId string, currentPassword string, newUsername string) (model.IdentityChange, error) {
+	user, err := s.checkCurrentPassword(userId, currentPassword)
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+	if newUsername == user.Username {
+		return model.IdentityChange{}, errors.New("ce nom d'utilisateur est déjà le vôtre")
+	}
+	if err := s.checkIdentityAvailable(IdentityFieldUsername, newUsername); err != nil {
+		return model.IdentityChange{}, err
+	}
+
+	change, token, err := s.createChange(user, IdentityFieldUsername, newUsername)
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+
+	err = s.sendLink(change, Notification{
+		Recipient: user.Email,
+		Subject:   "Confirmez votre nouveau nom d'utilisateur",
+		Body: fmt.Sprintf("Bonjour, confirmez votre nouveau nom d'utilisateur %s en suivant ce lien avant le %s : %s",
+			newUsername, change.ExpiresAt.Format("02/01/2006 15:04"), s.confirmLink(token)),
+	})
+	if err != nil {
+		return model.IdentityChange{}, err
+	}
+	return change, nil
+}
+
+// ConfirmIdentityChange applique le changement associé au lien. La disponibilité de l'identifiant
+// est vérifiée de nouveau, et toutes les sessions de l'utilisateur sont révoquées.
+func (s *ProfileService) ConfirmIdentityChange(token string) (model.IdentityChange, error) {
+	change, err := s.changeRepo.GetIdentityChangeByTokenHash(hashLinkToken(token))
+	if err != nil || !change.IsPending(time.Now()) {
+		return model.IdentityChange{}, errors.New("ce lien de confirmation est invalide ou a expiré")
+	}
+	if change.Field != IdentityFieldEmail && change.Field != IdentityFieldUsername {
+		return model.IdentityChange{}, errors.New("ce lien de confirmation est invalide ou a expiré")
+	}
+
+	user, err := s.userRepo.GetUserById(change.UserId)
+	if err != nil {
+		return model.IdentityChange{}, errors.New("ce compte n'existe plus")
+	}
+	if err := s.checkIdentityAvailable(change.Field, change.NewValue); err != nil {
+		return model.IdentityChange{}, err
+	}
+
+	if err := s.changeRepo.ConfirmIdentityChange(change); err != nil {
+		return model.IdentityChange{}, errors.New("ce lien de confirmation est invalide ou a expiré")
+	}
+
+	if change.Field == IdentityFieldEmail {
+		s.send(Notification{
+			Recipient: user.Email,
+			Subject:   "Votre adresse email a été modifiée",
+			Body:      fmt.Sprintf("Bonjour, l'adresse email de votre compte est désormais %s.", change.NewValue),
+		})
+	}
+	return change, nil
+}
+
+// SetAvatar enregistre la photo de profil de l'utilisateur et supprime la précédente
+func (s *ProfileService) SetAvatar(userId string, content []byte) (model.User, error) {
+	user, err := s.GetProfile(userId)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	fileName, err := s.avatars.Save(user.Id, content)
+	if err != nil {
+		return model.User{}, err
+	}
+	if err := s.userRepo.UpdateAvatar(user.Id, fileName); err != nil {
+		return model.User{}, errors.New("nous avons rencontré un problème durant l'enregistrement de l'image")
+	}
+
+	// Une image d'un autre type porte une autre extension : l'ancien fichier n'a pas été écrasé
+	if user.AvatarFile != "" && user.AvatarFile != fileName {
+		if err := s.avatars.Delete(user.AvatarFile); err != nil {
+			s.logger.Warn("Failed to delete avatar", "avatar_file", user.AvatarFile, "user_id", user.Id, "error", err)
+		}
+	}
+	user.AvatarFile = fileName
+	return user, nil
+}
+
+// GetAvatarPath retourne le chemin de la photo de profil de l'utilisateur
+func (s *ProfileService) GetAvatarPath(userId string) (string, error) {
+	user, err := s.GetProfile(userId)
+	if err != nil {
+		return "", err
+	}
+	if user.AvatarFile == "" {
+		return "", errors.New("aucune photo de profil")
+	}
+	return s.avatars.Path(user.AvatarFile), nil
+}
+
+// DeleteAvatar supprime la photo de profil de l'utilisateur
+func (s *ProfileService) DeleteAvatar(userId string) error {
+	user, err := s.GetProfile(userId)
+	if err != nil {
+		return err
+	}
+	if user.AvatarFile == "" {
+		return errors.New("aucune photo de profil")
+	}
+
+	if err := s.userRepo.UpdateAvatar(user.Id, ""); err != nil {
+		return errors.New("nous avons rencontré un problème durant la suppression de l'image")
+	}
+	return s.avatars.Delete(user.AvatarFile)
+}
+
+// AvatarMaxSize retourne la taille maximale d'une photo de profil, en octets
+func (s *ProfileService) AvatarMaxSize() int64 {
+	return s.avatars.MaxSize()
+}
+
+// checkCurrentPassword exige le mot de passe actuel avant un changement d'identifiant
+func (s *ProfileService) checkCurrentPassword(userId string, currentPassword string) (model.User, error) {
+	user, err := s.GetProfile(userId)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.Password == "" || !utils.CompareHashPassword(currentPassword, user.Password) {
+		return model.User{}, errors.New("le mot de passe actuel est incorrect")
+	}
+	return user, nil
+}
+
+func (s *ProfileService) checkIdentityAvailable(field string, value string) error {
+	var emails, usernames []string
+	if field == IdentityFieldEmail {
+		emails = []string{value}
+	} else {
+		usernames = []string{value}
+	}
+
+	takenEmails, takenUsernames, err := s.userRepo.GetTakenIdentities(emails, usernames)
+	if err != nil {
+		return err
+	}
+	if field == IdentityFieldEmail && takenEmails[value] {
+		return errors.New("cette adresse email est déjà utilisée")
+	}
+	if field == IdentityFieldUsername && takenUsernames[value] {
+		return errors.New("ce nom d'utilisateur est déjà utilisé")
+	}
+	return nil
+}
+
+func (s *ProfileService) createChange(user model.User, field string, newValue string) (model.IdentityChange, string, error) {
+	token, tokenHash, err := newLinkToken()
+	if err != nil {
+		return model.IdentityChange{}, "", err
+	}
+
+	change, err := s.changeRepo.CreateIdentityChange(model.IdentityChange{
+		UserId:    user.Id,
+		Field:     field,
+		NewValue:  newValue,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		return model.IdentityChange{}, "", errors.New("nous avons rencontré un problème durant l'enregistrement de la demande")
+	}
+	return change, token, nil
+}
+
+func (s *ProfileService) confirmLink(token string) string {
+	return s.confirmUrl + "?token=" + url.QueryEscape(token)
+}
+
+// sendLink envoie le lien de confirmation d'une demande. Sans lien, la demande ne peut pas
+// aboutir : elle est annulée et l'utilisateur est invité à la renouveler.
+func (s *ProfileService) sendLink(change model.IdentityChange, notification Notification) error {
+	if err := s.notifier.Send(notification); err != nil {
+		s.logger.Error("Failed to send identity change link", "change_id", change.Id, "recipient", notification.Recipient, "error", err)
+		if err := s.changeRepo.CancelIdentityChange(change.Id); err != nil {
+			s.logger.Error("Failed to cancel identity change", "change_id", change.Id, "error", err)
+		}
+		return errors.New("le lien de confirmation n'a pas pu être envoyé, merci de réessayer")
+	}
+	return nil
+}
+
+// send envoie une notification d'information ; un échec d'envoi est seulement tracé
+func (s *ProfileService) send(notification Notification) {
+	if err := s.notifier.Send(notification); err != nil {
+		s.logger.Error("Failed to send notification", "recipient", notification.Recipient, "error", err)
+	}
+}
+
+func (s *ProfileService) isSupportedLocale(locale string) bool {
+	for i := 0; i < len(s.locales); i++ {
+		if s.locales[i] == locale {
+			return true
+		}
+	}
+	return false
+}
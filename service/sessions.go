@@ -0,0 +1,144 @@
+package service
+
+import (
+	"auth/model"
+	"auth/repository"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// SessionTouchInterval limite la mise à jour de la date de dernière activité d'une session
+const SessionTouchInterval = time.Minute
+
+// ClientInfo décrit l'appareil à l'origine d'une connexion
+type ClientInfo struct {
+	IpAddress string
+	UserAgent string
+}
+
+// SessionService gère les sessions actives des utilisateurs
+type SessionService struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+}
+
+// NewSessionService crée une nouvelle instance de SessionService
+func NewSessionService(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// ForOrganization retourne un service limité aux utilisateurs membres de l'organisation
+func (s *SessionService) ForOrganization(orgId string) *SessionService {
+	return &SessionService{
+		sessionRepo: s.sessionRepo,
+		userRepo:    s.userRepo.ForOrganization(orgId),
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *SessionService) WithContext(ctx context.Context) *SessionService {
+	return &SessionService{
+		sessionRepo: s.sessionRepo.WithContext(ctx),
+		userRepo:    s.userRepo.WithContext(ctx),
+	}
+}
+
+// GetSessions liste les sessions actives d'un utilisateur
+func (s *SessionService) GetSessions(userId string) ([]model.Session, error) {
+	if _, err := s.userRepo.GetUserById(userId); err != nil {
+		return nil, errors.New("utilisateur non trouvé")
+	}
+	return s.sessionRepo.GetActiveSessionsByUser(userId)
+}
+
+// RevokeSession révoque une session de l'utilisateur : son refresh token et ses access tokens sont refusés
+func (s *SessionService) RevokeSession(userId string, sessionId string) error {
+	if _, err := s.userRepo.GetUserById(userId); err != nil {
+		return errors.New("utilisateur non trouvé")
+	}
+	if err := s.sessionRepo.RevokeSession(sessionId, userId); err != nil {
+		return errors.New("session non trouvée ou déjà révoquée")
+	}
+	return nil
+}
+
+// newSession prépare la session d'une nouvelle connexion
+func newSession(userId string, client ClientInfo) model.Session {
+	return model.Session{
+		UserId:    userId,
+		Device:    describeDevice(client.UserAgent),
+		UserAgent: truncate(client.UserAgent, 512),
+		IpAddress: truncate(client.IpAddress, 64),
+	}
+}
+
+// checkSession rejette un token dont la session a été révoquée ou a expiré
+func checkSession(sessionRepo *repository.SessionRepository, sessionId string, userId string) error {
+	session, err := sessionRepo.GetSessionById(sessionId)
+	if err != nil || session.UserId != userId || !session.IsActive(time.Now()) {
+		return errors.New("cette session a été révoquée")
+	}
+	return sessionRepo.TouchSession(sessionId, SessionTouchInterval)
+}
+
+// describeDevice résume le navigateur et le système d'un user agent, par exemple "Firefox sur Linux"
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Appareil inconnu"
+	}
+
+	browsers := []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	systems := []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+
+	browser := ""
+	for i := 0; i < len(browsers); i++ {
+		if strings.Contains(userAgent, browsers[i].token) {
+			browser = browsers[i].name
+			break
+		}
+	}
+	system := ""
+	for i := 0; i < len(systems); i++ {
+		if strings.Contains(userAgent, systems[i].token) {
+			system = systems[i].name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " sur " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	return truncate(userAgent, 120)
+}
+
+func truncate(value string, size int) string {
+	runes := []rune(value)
+	if len(runes) <= size {
+		return value
+	}
+	return string(runes[:size])
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/metrics"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"time"
+)
+
+const (
+	TwoFactorTypeTotp  = "totp"
+	TwoFactorTypeEmail = "email"
+)
+
+// MaxTwoFactorAttempts est le nombre de codes erronés après lequel le défi est invalidé
+const MaxTwoFactorAttempts = 5
+
+// TwoFactorReauth prouve l'identité de l'utilisateur avant une modification de ses seconds facteurs :
+// le mot de passe actuel, ou un code récent obtenu par un défi sur un facteur confirmé
+type TwoFactorReauth struct {
+	CurrentPassword string
+	SessionId       string
+	Code            string
+}
+
+// TwoFactorStatus décrit la double authentification d'un utilisateur
+type TwoFactorStatus struct {
+	Enabled  bool
+	Required bool
+	Methods  []model.TwoFactorMethod
+}
+
+// TwoFactorChallenge est un code attendu pour un second facteur
+type TwoFactorChallenge struct {
+	SessionId string
+	MethodId  string
+	Type      string
+	ExpiresAt time.Time
+}
+
+// TwoFactorEnrollment est un second facteur en attente de confirmation. Pour un facteur totp,
+// la clé et l'adresse otpauth ne sont communiquées qu'une fois, à l'enrôlement.
+type TwoFactorEnrollment struct {
+	Method    model.TwoFactorMethod
+	Secret    string
+	Url       string
+	Challenge TwoFactorChallenge
+}
+
+// TwoFactorService gère les seconds facteurs des utilisateurs et les défis de double authentification
+type TwoFactorService struct {
+	userRepo   *repository.UserRepository
+	roleRepo   *repository.RoleRepository
+	methodRepo *repository.TwoFactorMethodRepository
+	otpRepo    *repository.OtpRepository
+	notifier   Notifier
+	codeLength int
+	codeTTL    time.Duration
+	logger     *slog.Logger
+}
+
+// NewTwoFactorService crée une nouvelle instance de TwoFactorService. codeLength est le nombre de chiffres
+// des codes envoyés par email et codeTTL la durée de validité d'un défi.
+func NewTwoFactorService(
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	methodRepo *repository.TwoFactorMethodRepository,
+	otpRepo *repository.OtpRepository,
+	notifier Notifier,
+	codeLength int,
+	codeTTL time.Duration) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		methodRepo: methodRepo,
+		otpRepo:    otpRepo,
+		notifier:   notifier,
+		codeLength: codeLength,
+		codeTTL:    codeTTL,
+		logger:     slog.Default(),
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *TwoFactorService) WithContext(ctx context.Context) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:   s.userRepo.WithContext(ctx),
+		roleRepo:   s.roleRepo.WithContext(ctx),
+		methodRepo: s.methodRepo.WithContext(ctx),
+		otpRepo:    s.otpRepo.WithContext(ctx),
+		notifier:   s.notifier,
+		codeLength: s.codeLength,
+		codeTTL:    s.codeTTL,
+		logger:     logging.FromContext(ctx),
+	}
+}
+
+// IsRequired indique si l'un des rôles impose la double authentification
+func (s *TwoFactorService) IsRequired(roleNames []string) (bool, error) {
+	roles, err := s.roleRepo.GetRolesByNames(roleNames)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < len(roles); i++ {
+		if roles[i].RequireTwoFactor {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetStatus retourne l'état de la double authentification et les seconds facteurs de l'utilisateur
+func (s *TwoFactorService) GetStatus(userId string, roleNames []string) (TwoFactorStatus, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return TwoFactorStatus{}, errors.New("ce compte n'existe dans le système")
+	}
+
+	methods, err := s.methodRepo.GetMethodsByUser(userId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+	required, err := s.IsRequired(roleNames)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+	return TwoFactorStatus{Enabled: user.UseOTP, Required: required, Methods: methods}, nil
+}
+
+// StartEnrollment ajoute un second facteur, actif seulement après confirmation d'un premier code
+func (s *TwoFactorService) StartEnrollment(userId string, methodType string, label string, reauth TwoFactorReauth) (TwoFactorEnrollment, error) {
+	user, err := s.reauthenticate(userId, reauth)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	newMethod := model.TwoFactorMethod{UserId: user.Id, Type: methodType, Label: label}
+	switch methodType {
+	case TwoFactorTypeTotp:
+		secret, err := newTotpSecret()
+		if err != nil {
+			return TwoFactorEnrollment{}, err
+		}
+		newMethod.Secret = secret
+	case TwoFactorTypeEmail:
+		if label == "" {
+			newMethod.Label = user.Email
+		}
+	default:
+		return TwoFactorEnrollment{}, fmt.Errorf("type de second facteur inconnu : %s", methodType)
+	}
+
+	method, err := s.methodRepo.CreateMethod(newMethod)
+	if err != nil {
+		return TwoFactorEnrollment{}, errors.New("nous avons rencontré un problème durant l'enregistrement du second facteur")
+	}
+
+	enrollment := TwoFactorEnrollment{Method: method}
+	if method.Type == TwoFactorTypeTotp {
+		enrollment.Secret = method.Secret
+		enrollment.Url = totpUrl(method.Secret, user.Email)
+		return enrollment, nil
+	}
+
+	enrollment.Challenge, err = s.createChallenge(user, method)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+	return enrollment, nil
+}
+
+// ConfirmEnrollment vérifie un premier code du facteur et active la double authentification.
+// Un facteur email attend le code envoyé lors de l'enrôlement, un facteur totp le code de l'application.
+func (s *TwoFactorService) ConfirmEnrollment(userId string, methodId string, sessionId string, code string) (model.TwoFactorMethod, error) {
+	method, err := s.methodRepo.GetMethodById(methodId, userId)
+	if err != nil {
+		return model.TwoFactorMethod{}, errors.New("second facteur non trouvé")
+	}
+	if method.IsConfirmed() {
+		return model.TwoFactorMethod{}, errors.New("ce second facteur est déjà confirmé")
+	}
+
+	if method.Type == TwoFactorTypeTotp {
+		step, ok := verifyTotp(method.Secret, code, time.Now())
+		if !ok {
+			return model.TwoFactorMethod{}, errors.New("code invalide, merci de réessayer")
+		}
+		if err := s.methodRepo.MarkMethodUsed(method, step); err != nil {
+			return model.TwoFactorMethod{}, errors.New("ce code a déjà été utilisé")
+		}
+		method.LastUsedAt = time.Now()
+	} else {
+		otp, err := s.VerifyChallenge(sessionId, code)
+		if err != nil {
+			return model.TwoFactorMethod{}, err
+		}
+		if otp.MethodId != method.Id {
+			return model.TwoFactorMethod{}, errors.New("ce code ne correspond pas à ce second facteur")
+		}
+	}
+
+	confirmedMethod, err := s.methodRepo.ConfirmMethod(method)
+	if err != nil {
+		return model.TwoFactorMethod{}, errors.New("ce second facteur est déjà confirmé")
+	}
+	return confirmedMethod, nil
+}
+
+// SetDefault choisit le second facteur demandé à la connexion
+func (s *TwoFactorService) SetDefault(userId string, methodId string, reauth TwoFactorReauth) (model.TwoFactorMethod, error) {
+	if _, err := s.reauthenticate(userId, reauth); err != nil {
+		return model.TwoFactorMethod{}, err
+	}
+
+	method, err := s.methodRepo.GetMethodById(methodId, userId)
+	if err != nil {
+		return model.TwoFactorMethod{}, errors.New("second facteur non trouvé")
+	}
+	if !method.IsConfirmed() {
+		return model.TwoFactorMethod{}, errors.New("ce second facteur n'a pas encore été confirmé")
+	}
+
+	if err := s.methodRepo.SetDefaultMethod(method); err != nil {
+		return model.TwoFactorMethod{}, errors.New("nous avons rencontré un problème durant la mise à jour du second facteur")
+	}
+	method.IsDefault = true
+	return method, nil
+}
+
+// RemoveMethod supprime un second facteur. Le dernier facteur confirmé ne peut pas être
+// supprimé lorsqu'un rôle de l'utilisateur impose la double authentification.
+func (s *TwoFactorService) RemoveMethod(userId string, methodId string, reauth TwoFactorReauth, roleNames []string) error {
+	if _, err := s.reauthenticate(userId, reauth); err != nil {
+		return err
+	}
+
+	method, err := s.methodRepo.GetMethodById(methodId, userId)
+	if err != nil {
+		return errors.New("second facteur non trouvé")
+	}
+
+	if method.IsConfirmed() {
+		methods, err := s.methodRepo.GetMethodsByUser(userId)
+		if err != nil {
+			return err
+		}
+		confirmed := 0
+		for i := 0; i < len(methods); i++ {
+			if methods[i].IsConfirmed() {
+				confirmed++
+			}
+		}
+
+		required, err := s.IsRequired(roleNames)
+		if err != nil {
+			return err
+		}
+		if confirmed == 1 && required {
+			return errors.New("votre rôle impose la double authentification : ajoutez un autre second facteur avant de supprimer celui-ci")
+		}
+	}
+
+	if err := s.methodRepo.DeleteMethod(method); err != nil {
+		return errors.New("nous avons rencontré un problème durant la suppression du second facteur")
+	}
+	return nil
+}
+
+// Challenge demande un code sur un second facteur confirmé, le facteur par défaut si aucun n'est indiqué.
+// Sans facteur enrôlé, le code est envoyé à l'adresse email du compte.
+func (s *TwoFactorService) Challenge(userId string, methodId string) (TwoFactorChallenge, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return TwoFactorChallenge{}, errors.New("ce compte n'existe dans le système")
+	}
+
+	methods, err := s.methodRepo.GetMethodsByUser(userId)
+	if err != nil {
+		return TwoFactorChallenge{}, err
+	}
+
+	var selected *model.TwoFactorMethod
+	confirmed := 0
+	for i := 0; i < len(methods); i++ {
+		if !methods[i].IsConfirmed() {
+			continue
+		}
+		confirmed++
+		if methods[i].Id == methodId || (methodId == "" && methods[i].IsDefault) {
+			selected = &methods[i]
+		}
+	}
+
+	if selected == nil {
+		if methodId != "" || confirmed > 0 {
+			return TwoFactorChallenge{}, errors.New("second facteur non trouvé")
+		}
+		return s.createChallenge(user, model.TwoFactorMethod{Type: TwoFactorTypeEmail})
+	}
+	return s.createChallenge(user, *selected)
+}
+
+// VerifyChallenge contrôle le code d'un défi. Le défi n'est utilisable qu'une fois et
+// il est invalidé après MaxTwoFactorAttempts codes erronés.
+func (s *TwoFactorService) VerifyChallenge(sessionId string, code string) (model.Otp, error) {
+	otp, err := s.otpRepo.GetOtpById(sessionId)
+	if err != nil {
+		metrics.ObserveOtpVerification("unknown_challenge")
+		return model.Otp{}, fmt.Errorf("votre session a espiré. Merci de généré un nouveau code otp")
+	}
+	if otp.ExpireHas.Before(time.Now()) || otp.Attempts >= MaxTwoFactorAttempts {
+		metrics.ObserveOtpVerification("expired")
+		s.consumeChallenge(otp)
+		return model.Otp{}, fmt.Errorf("code otp expiré")
+	}
+
+	var method model.TwoFactorMethod
+	if otp.MethodId != "" {
+		method, err = s.methodRepo.GetMethodById(otp.MethodId, otp.UserId)
+		if err != nil {
+			metrics.ObserveOtpVerification("unknown_method")
+			s.consumeChallenge(otp)
+			return model.Otp{}, errors.New("second facteur non trouvé")
+		}
+	}
+
+	var step int64
+	valid := false
+	if method.Type == TwoFactorTypeTotp {
+		step, valid = verifyTotp(method.Secret, code, time.Now())
+	} else {
+		valid = otp.Code != "" && subtle.ConstantTimeCompare([]byte(otp.Code), []byte(code)) == 1
+	}
+
+	if !valid {
+		metrics.ObserveOtpVerification("invalid")
+		attempts, err := s.otpRepo.IncrementOtpAttempts(otp.Id)
+		if err == nil && attempts >= MaxTwoFactorAttempts {
+			s.consumeChallenge(otp)
+		}
+		return model.Otp{}, fmt.Errorf("code otp invalid, merci de réessayer")
+	}
+
+	if err := s.consumeChallenge(otp); err != nil {
+		metrics.ObserveOtpVerification("replayed")
+		return model.Otp{}, fmt.Errorf("votre session a espiré. Merci de généré un nouveau code otp")
+	}
+	if method.Id != "" {
+		if err := s.methodRepo.MarkMethodUsed(method, step); err != nil {
+			metrics.ObserveOtpVerification("replayed")
+			return model.Otp{}, errors.New("ce code a déjà été utilisé")
+		}
+	}
+	metrics.ObserveOtpVerification("success")
+	return otp, nil
+}
+
+// GetRoles liste les rôles et l'obligation de double authentification de chacun
+func (s *TwoFactorService) GetRoles() ([]model.Role, error) {
+	return s.roleRepo.GetAllRoles()
+}
+
+// SetRoleRequirement impose ou non la double authentification aux utilisateurs d'un rôle.
+// Les utilisateurs concernés sans second facteur devront en enrôler un à leur prochaine connexion.
+func (s *TwoFactorService) SetRoleRequirement(roleName string, required bool) (model.Role, error) {
+	role, err := s.roleRepo.GetRoleByName(roleName)
+	if err != nil {
+		return model.Role{}, errors.New("rôle inconnu : " + roleName)
+	}
+	if err := s.roleRepo.UpdateRoleTwoFactor(role.Id, required); err != nil {
+		return model.Role{}, errors.New("nous avons rencontré un problème durant la mise à jour du rôle")
+	}
+	role.RequireTwoFactor = required
+	return role, nil
+}
+
+// reauthenticate exige le mot de passe actuel ou un code récent sur un facteur confirmé de l'utilisateur
+func (s *TwoFactorService) reauthenticate(userId string, reauth TwoFactorReauth) (model.User, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return model.User{}, errors.New("ce compte n'existe dans le système")
+	}
+
+	if reauth.CurrentPassword != "" {
+		if user.Password == "" || !utils.CompareHashPassword(reauth.CurrentPassword, user.Password) {
+			return model.User{}, errors.New("le mot de passe actuel est incorrect")
+		}
+		return user, nil
+	}
+
+	if reauth.SessionId == "" || reauth.Code == "" {
+		return model.User{}, errors.New("le mot de passe actuel ou un code de double authentification est requis")
+	}
+	otp, err := s.VerifyChallenge(reauth.SessionId, reauth.Code)
+	if err != nil {
+		return model.User{}, err
+	}
+	if otp.UserId != user.Id || otp.MethodId == "" {
+		return model.User{}, errors.New("ce code ne permet pas de confirmer votre identité")
+	}
+
+	method, err := s.methodRepo.GetMethodById(otp.MethodId, user.Id)
+	if err != nil || !method.IsConfirmed() {
+		return model.User{}, errors.New("ce code ne permet pas de confirmer votre identité")
+	}
+	return user, nil
+}
+
+// createChallenge enregistre un défi ; pour un facteur email, le code est envoyé à l'utilisateur
+func (s *TwoFactorService) createChallenge(user model.User, method model.TwoFactorMethod) (TwoFactorChallenge, error) {
+	otpModel := model.Otp{
+		UserId:    user.Id,
+		MethodId:  method.Id,
+		ExpireHas: time.Now().Add(s.codeTTL),
+	}
+	if method.Type == TwoFactorTypeEmail {
+		otpModel.Code = utils.OtpGenerator(s.codeLength)
+	}
+
+	otp, err := s.otpRepo.CreateOtp(otpModel)
+	if err != nil {
+		return TwoFactorChallenge{}, errors.New("error system : nous avons rencontré un problème durant la création du code otp")
+	}
+
+	if method.Type == TwoFactorTypeEmail {
+		err := s.notifier.Send(Notification{
+			Recipient: user.Email,
+			Subject:   "Votre code de vérification",
+			Body: fmt.Sprintf("Bonjour, votre code de vérification est %s. Il expire le %s.",
+				otp.Code, otp.ExpireHas.Format("02/01/2006 15:04")),
+		})
+		if err != nil {
+			s.logger.Error("Failed to send verification code", "email", user.Email, "error", err)
+			return TwoFactorChallenge{}, errors.New("le code de vérification n'a pas pu être envoyé, merci de réessayer")
+		}
+	}
+
+	return TwoFactorChallenge{
+		SessionId: otp.Id,
+		MethodId:  method.Id,
+		Type:      method.Type,
+		ExpiresAt: otp.ExpireHas,
+	}, nil
+}
+
+func (s *TwoFactorService) consumeChallenge(otp model.Otp) error {
+	return s.otpRepo.MarkOtpUsed(otp.Id)
+}
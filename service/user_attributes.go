@@ -0,0 +1,426 @@
+package service
+
+import (
+	"auth/model"
+	"auth/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeDate    = "date"
+	AttributeTypeEnum    = "enum"
+)
+
+// attributeNamePattern limite les noms d'attributs et de claims à des identifiants simples
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// reservedClaimNames ne peuvent pas être utilisés pour exposer un attribut dans les tokens
+var reservedClaimNames = map[string]bool{
+	"role": true, "source": true, "org_id": true, "roles": true, "act": true,
+	"aud": true, "exp": true, "jti": true, "iat": true, "iss": true, "nbf": true, "sub": true,
+}
+
+// AttributeDefinitionInput décrit un attribut personnalisé à créer ou modifier
+type AttributeDefinitionInput struct {
+	Name         string
+	Label        string
+	Type         string
+	Required     bool
+	UserEditable bool
+	Options      []string
+	Pattern      string
+	MaxLength    int
+	ClaimName    string
+}
+
+// AttributeDefinitionDetail associe une définition à ses options décodées
+type AttributeDefinitionDetail struct {
+	Definition model.AttributeDefinition
+	Options    []string
+}
+
+// UserAttributeService gère les attributs de profil personnalisés d'une organisation
+type UserAttributeService struct {
+	attributeRepo *repository.AttributeRepository
+	userRepo      *repository.UserRepository
+	orgId         string
+}
+
+// NewUserAttributeService crée une nouvelle instance de UserAttributeService
+func NewUserAttributeService(
+	attributeRepo *repository.AttributeRepository,
+	userRepo *repository.UserRepository) *UserAttributeService {
+	return &UserAttributeService{
+		attributeRepo: attributeRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// ForOrganization retourne un service limité aux attributs de l'organisation fournie
+func (s *UserAttributeService) ForOrganization(orgId string) *UserAttributeService {
+	return &UserAttributeService{
+		attributeRepo: s.attributeRepo,
+		userRepo:      s.userRepo.ForOrganization(orgId),
+		orgId:         orgId,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *UserAttributeService) WithContext(ctx context.Context) *UserAttributeService {
+	return &UserAttributeService{
+		attributeRepo: s.attributeRepo.WithContext(ctx),
+		userRepo:      s.userRepo.WithContext(ctx),
+		orgId:         s.orgId,
+	}
+}
+
+// GetDefinitions liste les attributs personnalisés de l'organisation
+func (s *UserAttributeService) GetDefinitions() ([]AttributeDefinitionDetail, error) {
+	definitions, err := s.attributeRepo.GetDefinitions(s.orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	details := []AttributeDefinitionDetail{}
+	for i := 0; i < len(definitions); i++ {
+		details = append(details, attributeDefinitionDetail(definitions[i]))
+	}
+	return details, nil
+}
+
+// GetDefinition récupère un attribut personnalisé de l'organisation
+func (s *UserAttributeService) GetDefinition(name string) (AttributeDefinitionDetail, error) {
+	definition, err := s.attributeRepo.GetDefinitionByName(s.orgId, name)
+	if err != nil {
+		return AttributeDefinitionDetail{}, errors.New("attribut non trouvé")
+	}
+	return attributeDefinitionDetail(definition), nil
+}
+
+// CreateDefinition ajoute un attribut personnalisé à l'organisation
+func (s *UserAttributeService) CreateDefinition(input AttributeDefinitionInput) (AttributeDefinitionDetail, error) {
+	if s.orgId == "" {
+		return AttributeDefinitionDetail{}, errors.New("aucune organisation active")
+	}
+	if !attributeNamePattern.MatchString(input.Name) {
+		return AttributeDefinitionDetail{}, errors.New("le nom doit commencer par une lettre minuscule et ne contenir que des minuscules, chiffres et _")
+	}
+	if _, err := s.attributeRepo.GetDefinitionByName(s.orgId, input.Name); err == nil {
+		return AttributeDefinitionDetail{}, errors.New("un attribut porte déjà ce nom")
+	}
+
+	definition := model.AttributeDefinition{OrganizationId: s.orgId, Name: input.Name}
+	if err := applyDefinitionInput(&definition, input); err != nil {
+		return AttributeDefinitionDetail{}, err
+	}
+	if err := s.checkClaimName(definition); err != nil {
+		return AttributeDefinitionDetail{}, err
+	}
+
+	createdDefinition, err := s.attributeRepo.CreateDefinition(definition)
+	if err != nil {
+		return AttributeDefinitionDetail{}, errors.New("nous avons rencontré un problème durant l'enregistrement de l'attribut")
+	}
+	return attributeDefinitionDetail(createdDefinition), nil
+}
+
+// UpdateDefinition modifie un attribut personnalisé. Son nom et son type ne changent pas,
+// afin que les valeurs déjà enregistrées restent valides.
+func (s *UserAttributeService) UpdateDefinition(name string, input AttributeDefinitionInput) (AttributeDefinitionDetail, error) {
+	definition, err := s.attributeRepo.GetDefinitionByName(s.orgId, name)
+	if err != nil {
+		return AttributeDefinitionDetail{}, errors.New("attribut non trouvé")
+	}
+	if input.Type != definition.Type {
+		return AttributeDefinitionDetail{}, errors.New("le type d'un attribut ne peut pas être modifié")
+	}
+
+	if err := applyDefinitionInput(&definition, input); err != nil {
+		return AttributeDefinitionDetail{}, err
+	}
+	if err := s.checkClaimName(definition); err != nil {
+		return AttributeDefinitionDetail{}, err
+	}
+
+	updatedDefinition, err := s.attributeRepo.UpdateDefinition(definition)
+	if err != nil {
+		return AttributeDefinitionDetail{}, errors.New("nous avons rencontré un problème durant la mise à jour de l'attribut")
+	}
+	return attributeDefinitionDetail(updatedDefinition), nil
+}
+
+// DeleteDefinition supprime un attribut personnalisé et ses valeurs pour tous les utilisateurs
+func (s *UserAttributeService) DeleteDefinition(name string) error {
+	definition, err := s.attributeRepo.GetDefinitionByName(s.orgId, name)
+	if err != nil {
+		return errors.New("attribut non trouvé")
+	}
+	return s.attributeRepo.DeleteDefinition(definition)
+}
+
+// GetUserAttributes retourne les attributs personnalisés d'un utilisateur de l'organisation
+func (s *UserAttributeService) GetUserAttributes(userId string) (map[string]interface{}, error) {
+	if _, err := s.userRepo.GetUserById(userId); err != nil {
+		return nil, errors.New("utilisateur non trouvé")
+	}
+
+	definitions, err := s.attributeRepo.GetDefinitions(s.orgId)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := s.attributeRepo.GetUserAttributes(userId, s.orgId)
+	if err != nil {
+		return nil, err
+	}
+	return decodeUserAttributes(definitions, attributes), nil
+}
+
+// UpdateUserAttributes modifie les attributs fournis ; une valeur null supprime l'attribut.
+// En libre-service, seuls les attributs modifiables par l'utilisateur sont acceptés.
+// Toutes les valeurs sont validées avant qu'aucune ne soit enregistrée.
+func (s *UserAttributeService) UpdateUserAttributes(userId string, values map[string]interface{}, selfService bool) (map[string]interface{}, error) {
+	if _, err := s.userRepo.GetUserById(userId); err != nil {
+		return nil, errors.New("utilisateur non trouvé")
+	}
+
+	definitions, err := s.attributeRepo.GetDefinitions(s.orgId)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]model.AttributeDefinition{}
+	for i := 0; i < len(definitions); i++ {
+		byName[definitions[i].Name] = definitions[i]
+	}
+
+	var saved []model.UserAttribute
+	var removed []string
+	var validationErrors []string
+	for name, value := range values {
+		definition, ok := byName[name]
+		if !ok {
+			validationErrors = append(validationErrors, name+" : attribut inconnu")
+			continue
+		}
+		if selfService && !definition.UserEditable {
+			validationErrors = append(validationErrors, name+" : attribut non modifiable par l'utilisateur")
+			continue
+		}
+
+		if value == nil {
+			if definition.Required {
+				validationErrors = append(validationErrors, name+" : attribut obligatoire")
+				continue
+			}
+			removed = append(removed, definition.Id)
+			continue
+		}
+
+		normalized, err := validateAttributeValue(definition, value)
+		if err != nil {
+			validationErrors = append(validationErrors, name+" : "+err.Error())
+			continue
+		}
+		encoded, _ := json.Marshal(normalized)
+		saved = append(saved, model.UserAttribute{DefinitionId: definition.Id, Value: string(encoded)})
+	}
+	if len(validationErrors) > 0 {
+		sort.Strings(validationErrors)
+		return nil, &AttributeValidationError{Errors: validationErrors}
+	}
+
+	if err := s.attributeRepo.SaveUserAttributes(userId, s.orgId, saved, removed); err != nil {
+		return nil, errors.New("nous avons rencontré un problème durant l'enregistrement des attributs")
+	}
+	return s.GetUserAttributes(userId)
+}
+
+// AttributeValidationError regroupe les valeurs d'attributs refusées
+type AttributeValidationError struct {
+	Errors []string
+}
+
+func (e *AttributeValidationError) Error() string {
+	return "attributs invalides"
+}
+
+// checkClaimName refuse les claims réservés ou déjà utilisés par un autre attribut
+func (s *UserAttributeService) checkClaimName(definition model.AttributeDefinition) error {
+	if definition.ClaimName == "" {
+		return nil
+	}
+	if !attributeNamePattern.MatchString(definition.ClaimName) || reservedClaimNames[definition.ClaimName] {
+		return fmt.Errorf("le claim %s n'est pas autorisé", definition.ClaimName)
+	}
+
+	definitions, err := s.attributeRepo.GetDefinitions(s.orgId)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(definitions); i++ {
+		if definitions[i].ClaimName == definition.ClaimName && definitions[i].Name != definition.Name {
+			return fmt.Errorf("le claim %s est déjà utilisé par l'attribut %s", definition.ClaimName, definitions[i].Name)
+		}
+	}
+	return nil
+}
+
+// applyDefinitionInput vérifie la cohérence de la définition avant de l'appliquer
+func applyDefinitionInput(definition *model.AttributeDefinition, input AttributeDefinitionInput) error {
+	if input.Type == AttributeTypeEnum && len(input.Options) == 0 {
+		return errors.New("un attribut enum doit lister ses options")
+	}
+	if input.Type != AttributeTypeEnum && len(input.Options) > 0 {
+		return errors.New("seul un attribut enum accepte des options")
+	}
+	if input.Type != AttributeTypeString && (input.Pattern != "" || input.MaxLength > 0) {
+		return errors.New("pattern et max_length ne s'appliquent qu'aux attributs string")
+	}
+	if input.Pattern != "" {
+		if _, err := regexp.Compile(input.Pattern); err != nil {
+			return errors.New("pattern n'est pas une expression régulière valide")
+		}
+	}
+
+	options := ""
+	if len(input.Options) > 0 {
+		encoded, _ := json.Marshal(input.Options)
+		options = string(encoded)
+	}
+
+	definition.Label = input.Label
+	definition.Type = input.Type
+	definition.Required = input.Required
+	definition.UserEditable = input.UserEditable
+	definition.Options = options
+	definition.Pattern = input.Pattern
+	definition.MaxLength = input.MaxLength
+	definition.ClaimName = input.ClaimName
+	return nil
+}
+
+// validateAttributeValue contrôle une valeur selon le type de l'attribut et la normalise
+func validateAttributeValue(definition model.AttributeDefinition, value interface{}) (interface{}, error) {
+	switch definition.Type {
+	case AttributeTypeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, errors.New("une chaîne de caractères est attendue")
+		}
+		if definition.MaxLength > 0 && utf8.RuneCountInString(text) > definition.MaxLength {
+			return nil, fmt.Errorf("%d caractères au maximum", definition.MaxLength)
+		}
+		if definition.Pattern != "" {
+			pattern, err := regexp.Compile(definition.Pattern)
+			if err != nil || !pattern.MatchString(text) {
+				return nil, errors.New("la valeur ne respecte pas le format attendu")
+			}
+		}
+		return text, nil
+	case AttributeTypeNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, errors.New("un nombre est attendu")
+		}
+		return number, nil
+	case AttributeTypeBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("un booléen est attendu")
+		}
+		return flag, nil
+	case AttributeTypeDate:
+		text, ok := value.(string)
+		if !ok {
+			return nil, errors.New("une date AAAA-MM-JJ est attendue")
+		}
+		if _, err := time.Parse("2006-01-02", text); err != nil {
+			return nil, errors.New("une date AAAA-MM-JJ est attendue")
+		}
+		return text, nil
+	case AttributeTypeEnum:
+		text, ok := value.(string)
+		options := attributeDefinitionDetail(definition).Options
+		for i := 0; ok && i < len(options); i++ {
+			if options[i] == text {
+				return text, nil
+			}
+		}
+		return nil, fmt.Errorf("la valeur doit être l'une de %v", options)
+	}
+	return nil, fmt.Errorf("type d'attribut inconnu : %s", definition.Type)
+}
+
+// decodeUserAttributes associe chaque valeur enregistrée au nom de son attribut
+func decodeUserAttributes(definitions []model.AttributeDefinition, attributes []model.UserAttribute) map[string]interface{} {
+	names := map[string]string{}
+	for i := 0; i < len(definitions); i++ {
+		names[definitions[i].Id] = definitions[i].Name
+	}
+
+	values := map[string]interface{}{}
+	for i := 0; i < len(attributes); i++ {
+		name, ok := names[attributes[i].DefinitionId]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(attributes[i].Value), &value); err == nil {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// claimAttributes retourne les attributs de l'utilisateur exposés dans les tokens de l'organisation
+func claimAttributes(attributeRepo *repository.AttributeRepository, userId string, orgId string) (map[string]interface{}, error) {
+	definitions, err := attributeRepo.GetDefinitions(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []model.AttributeDefinition
+	for i := 0; i < len(definitions); i++ {
+		if definitions[i].ClaimName != "" {
+			claimed = append(claimed, definitions[i])
+		}
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	attributes, err := attributeRepo.GetUserAttributes(userId, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	values := decodeUserAttributes(claimed, attributes)
+	claims := map[string]interface{}{}
+	for i := 0; i < len(claimed); i++ {
+		if value, ok := values[claimed[i].Name]; ok {
+			claims[claimed[i].ClaimName] = value
+		}
+	}
+	if len(claims) == 0 {
+		return nil, nil
+	}
+	return claims, nil
+}
+
+func attributeDefinitionDetail(definition model.AttributeDefinition) AttributeDefinitionDetail {
+	options := []string{}
+	if definition.Options != "" {
+		_ = json.Unmarshal([]byte(definition.Options), &options)
+	}
+	return AttributeDefinitionDetail{Definition: definition, Options: options}
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"auth/utils"
+	"context"
+	"errors"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"runtime"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ImportStatusValid   = "valid"
+	ImportStatusInvalid = "invalid"
+	ImportStatusCreated = "created"
+)
+
+// ErrImportRejected indique qu'au moins une ligne est invalide : rien n'a été écrit
+var ErrImportRejected = errors.New("import annulé : certaines lignes sont invalides")
+
+// ImportRow est une ligne du fichier d'import, avec les erreurs de validation déjà détectées
+type ImportRow struct {
+	Line   int
+	User   User
+	Role   string
+	Errors []string
+}
+
+// ImportOptions contrôle l'exécution d'un import
+type ImportOptions struct {
+	DryRun          bool
+	SendInvitations bool
+}
+
+// ImportRowResult est le résultat d'une ligne du fichier
+type ImportRowResult struct {
+	Line     int      `json:"line"`
+	Email    string   `json:"email"`
+	Username string   `json:"username"`
+	Role     string   `json:"role"`
+	Status   string   `json:"status"`
+	UserId   string   `json:"user_id,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// ImportReport résume un import ligne par ligne
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Invalid int               `json:"invalid"`
+	Created int               `json:"created"`
+	Invited int               `json:"invited"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// UserImportService crée des comptes utilisateurs en masse
+type UserImportService struct {
+	userRepo *repository.UserRepository
+	roleRepo *repository.RoleRepository
+	orgRepo  *repository.OrganizationRepository
+	notifier Notifier
+	security utils.Security
+	orgId    string
+	logger   *slog.Logger
+}
+
+// NewUserImportService crée une nouvelle instance de UserImportService
+func NewUserImportService(
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	notifier Notifier,
+	security utils.Security) *UserImportService {
+	return &UserImportService{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		orgRepo:  orgRepo,
+		notifier: notifier,
+		security: security,
+		logger:   slog.Default(),
+	}
+}
+
+// ForOrganization retourne un service qui importe les utilisateurs dans l'organisation fournie
+func (s *UserImportService) ForOrganization(orgId string) *UserImportService {
+	return &UserImportService{
+		userRepo: s.userRepo.ForOrganization(orgId),
+		roleRepo: s.roleRepo,
+		orgRepo:  s.orgRepo,
+		notifier: s.notifier,
+		security: s.security,
+		orgId:    orgId,
+		logger:   s.logger,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *UserImportService) WithContext(ctx context.Context) *UserImportService {
+	return &UserImportService{
+		userRepo: s.userRepo.WithContext(ctx),
+		roleRepo: s.roleRepo.WithContext(ctx),
+		orgRepo:  s.orgRepo.WithContext(ctx),
+		notifier: s.notifier,
+		security: s.security,
+		orgId:    s.orgId,
+		logger:   logging.FromContext(ctx),
+	}
+}
+
+// Import valide toutes les lignes puis, hors simulation, crée les utilisateurs dans une
+// seule transaction. Si une ligne est invalide, aucun utilisateur n'est créé et
+// ErrImportRejected est retournée avec le rapport.
+func (s *UserImportService) Import(rows []ImportRow, options ImportOptions) (ImportReport, error) {
+	if s.orgId == "" {
+		return ImportReport{}, errors.New("aucune organisation active pour l'import")
+	}
+
+	report := ImportReport{
+		DryRun: options.DryRun,
+		Total:  len(rows),
+		Rows:   []ImportRowResult{},
+	}
+
+	roleIds, err := s.resolveRoles(rows)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	if err := s.checkDuplicates(rows); err != nil {
+		return ImportReport{}, err
+	}
+
+	for i := 0; i < len(rows); i++ {
+		row := &rows[i]
+		if row.Role == "" {
+			row.Role = "client"
+		}
+		if _, ok := roleIds[row.Role]; !ok {
+			row.Errors = append(row.Errors, "rôle inconnu : "+row.Role)
+		}
+
+		result := ImportRowResult{
+			Line:     row.Line,
+			Email:    row.User.Email,
+			Username: row.User.Username,
+			Role:     row.Role,
+			Status:   ImportStatusValid,
+			Errors:   row.Errors,
+		}
+		if len(row.Errors) > 0 {
+			result.Status = ImportStatusInvalid
+			report.Invalid++
+		} else {
+			report.Valid++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if report.Invalid > 0 && !options.DryRun {
+		return report, ErrImportRejected
+	}
+	if options.DryRun {
+		return report, nil
+	}
+
+	users, err := s.buildUsers(rows, roleIds)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	createdUsers, err := s.userRepo.WithEvent(model.EventUserCreated).CreateUsers(users)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ImportReport{}, errors.New("adresse email ou nom d'utilisateur déjà utilisé : aucun utilisateur n'a été créé")
+	}
+	if err != nil {
+		return ImportReport{}, errors.New("nous avons rencontré un problème durant l'import : aucun utilisateur n'a été créé")
+	}
+
+	for i := 0; i < len(createdUsers); i++ {
+		report.Rows[i].Status = ImportStatusCreated
+		report.Rows[i].UserId = createdUsers[i].Id
+		report.Created++
+	}
+
+	if options.SendInvitations {
+		report.Invited = s.sendInvitations(createdUsers)
+	}
+
+	return report, nil
+}
+
+// resolveRoles associe chaque nom de rôle utilisé dans le fichier à son identifiant
+func (s *UserImportService) resolveRoles(rows []ImportRow) (map[string]string, error) {
+	var names []string
+	seen := map[string]bool{"client": true}
+	names = append(names, "client")
+	for i := 0; i < len(rows); i++ {
+		if rows[i].Role != "" && !seen[rows[i].Role] {
+			seen[rows[i].Role] = true
+			names = append(names, rows[i].Role)
+		}
+	}
+
+	roles, err := s.roleRepo.GetRolesByNames(names)
+	if err != nil {
+		return nil, errors.New("error system : impossible de charger les rôles")
+	}
+
+	roleIds := map[string]string{}
+	for i := 0; i < len(roles); i++ {
+		roleIds[roles[i].Name] = roles[i].Id
+	}
+	return roleIds, nil
+}
+
+// checkDuplicates signale les emails et noms d'utilisateur répétés dans le fichier
+// ou déjà utilisés par un compte existant
+func (s *UserImportService) checkDuplicates(rows []ImportRow) error {
+	var emails, usernames []string
+	emailLines := map[string]int{}
+	usernameLines := map[string]int{}
+
+	for i := 0; i < len(rows); i++ {
+		row := &rows[i]
+		email := strings.ToLower(row.User.Email)
+		username := strings.ToLower(row.User.Username)
+
+		if line, ok := emailLines[email]; ok && email != "" {
+			row.Errors = append(row.Errors, fmt.Sprintf("email déjà présent à la ligne %d", line))
+		} else {
+			emailLines[email] = row.Line
+		}
+		if line, ok := usernameLines[username]; ok && username != "" {
+			row.Errors = append(row.Errors, fmt.Sprintf("nom d'utilisateur déjà présent à la ligne %d", line))
+		} else {
+			usernameLines[username] = row.Line
+		}
+
+		emails = append(emails, row.User.Email)
+		usernames = append(usernames, row.User.Username)
+	}
+
+	takenEmails, takenUsernames, err := s.userRepo.GetTakenIdentities(emails, usernames)
+	if err != nil {
+		return errors.New("error system : impossible de vérifier les comptes existants")
+	}
+
+	for i := 0; i < len(rows); i++ {
+		if takenEmails[rows[i].User.Email] {
+			rows[i].Errors = append(rows[i].Errors, "adresse email a déjà été utilisé")
+		}
+		if takenUsernames[rows[i].User.Username] {
+			rows[i].Errors = append(rows[i].Errors, "le nom d'utilisateur a déjà été utilisé")
+		}
+	}
+	return nil
+}
+
+// buildUsers prépare les utilisateurs à créer ; les mots de passe sont hachés en parallèle
+func (s *UserImportService) buildUsers(rows []ImportRow, roleIds map[string]string) ([]model.User, error) {
+	users := make([]model.User, len(rows))
+	hashErrors := make([]error, len(rows))
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				hashPassword, err := s.security.GenerateHashPassword(rows[i].User.Password)
+				hashErrors[i] = err
+				users[i] = model.User{
+					Name:     rows[i].User.Name,
+					Email:    rows[i].User.Email,
+					RoleId:   roleIds[rows[i].Role],
+					Sername:  rows[i].User.Sername,
+					Username: rows[i].User.Username,
+					Password: hashPassword,
+					UseOTP:   false,
+				}
+			}
+		}()
+	}
+	for i := 0; i < len(rows); i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := 0; i < len(hashErrors); i++ {
+		if hashErrors[i] != nil {
+			return nil, errors.New("erreur de cryptage du mot de passe utilisateur")
+		}
+	}
+	return users, nil
+}
+
+// sendInvitations prévient les utilisateurs créés et retourne le nombre d'invitations envoyées
+func (s *UserImportService) sendInvitations(users []model.User) int {
+	organizationName := s.orgId
+	if organization, err := s.orgRepo.GetOrganizationById(s.orgId); err == nil {
+		organizationName = organization.Name
+	}
+
+	invited := 0
+	for i := 0; i < len(users); i++ {
+		err := s.notifier.Send(Notification{
+			Recipient: users[i].Email,
+			Subject:   "Invitation à rejoindre " + organizationName,
+			Body: fmt.Sprintf("Bonjour %s, un compte a été créé pour vous avec le nom d'utilisateur %s.",
+				users[i].Name, users[i].Username),
+		})
+		if err != nil {
+			s.logger.Error("Failed to send invitation", "email", users[i].Email, "error", err)
+			continue
+		}
+		invited++
+	}
+	return invited
+}
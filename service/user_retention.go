@@ -0,0 +1,124 @@
+package service
+
+import (
+	"auth/repository"
+	slog "github.com/sagikazarmark/slog-shim"
+	"time"
+)
+
+const (
+	PurgeModeAnonymize = "anonymize"
+	PurgeModeDelete    = "delete"
+)
+
+const (
+	// purgeBatchSize limite le nombre d'utilisateurs traités à chaque passage
+	purgeBatchSize = 200
+
+	// userPurgeLockName réserve chaque passage de la purge à une seule instance
+	userPurgeLockName = "auth_user_purge"
+)
+
+// UserRetentionService purge les utilisateurs supprimés depuis plus longtemps que la durée de rétention
+type UserRetentionService struct {
+	userRepo  *repository.UserRepository
+	lockRepo  *repository.JobLockRepository
+	avatars   *AvatarStorage
+	retention time.Duration
+	mode      string
+	logger    *slog.Logger
+}
+
+// NewUserRetentionService crée une nouvelle instance de UserRetentionService
+func NewUserRetentionService(
+	userRepo *repository.UserRepository,
+	lockRepo *repository.JobLockRepository,
+	avatars *AvatarStorage,
+	retention time.Duration,
+	mode string) *UserRetentionService {
+	if mode != PurgeModeDelete {
+		mode = PurgeModeAnonymize
+	}
+	return &UserRetentionService{
+		userRepo:  userRepo,
+		lockRepo:  lockRepo,
+		avatars:   avatars,
+		retention: retention,
+		mode:      mode,
+		logger:    slog.Default(),
+	}
+}
+
+// Start lance la purge immédiatement puis à intervalle régulier
+func (s *UserRetentionService) Start(interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Info("User purge disabled : no purge interval configured")
+		return
+	}
+
+	s.logger.Info("User purge scheduled", "interval", interval, "retention", s.retention, "mode", s.mode)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.purgeLocked(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+// purgeLocked lance un passage de la purge sauf si une autre instance en effectue déjà un
+func (s *UserRetentionService) purgeLocked(now time.Time) {
+	acquired, err := s.lockRepo.RunLocked(userPurgeLockName, func() error {
+		_, err := s.PurgeExpiredUsers(now)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("User purge failed", "error", err)
+		return
+	}
+	if !acquired {
+		s.logger.Debug("User purge skipped : another instance is running it")
+	}
+}
+
+// PurgeExpiredUsers anonymise ou supprime définitivement les utilisateurs dont la suppression
+// est plus ancienne que la durée de rétention. Retourne le nombre d'utilisateurs purgés.
+func (s *UserRetentionService) PurgeExpiredUsers(now time.Time) (int, error) {
+	deletedBefore := now.Add(-s.retention)
+	users, err := s.userRepo.GetUsersToPurge(deletedBefore, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, nil
+	}
+
+	s.logger.Info("User purge started", "users", len(users), "deleted_before", deletedBefore.Format(time.RFC3339))
+
+	purged := 0
+	for i := 0; i < len(users); i++ {
+		var err error
+		if s.mode == PurgeModeDelete {
+			err = s.userRepo.DeleteUser(users[i].Id)
+		} else {
+			err = s.userRepo.AnonymizeUser(users[i].Id)
+		}
+		if err != nil {
+			s.logger.Warn("User purge skipped user", "user_id", users[i].Id, "error", err)
+			continue
+		}
+		if err := s.avatars.Delete(users[i].AvatarFile); err != nil {
+			s.logger.Warn("User purge kept avatar of user", "user_id", users[i].Id, "error", err)
+		}
+		purged++
+	}
+
+	s.logger.Info("User purge finished", "purged", purged, "users", len(users), "mode", s.mode)
+	if len(users) == purgeBatchSize {
+		s.logger.Info("User purge : remaining users will be processed on the next run")
+	}
+	return purged, nil
+}
@@ -6,8 +6,14 @@ import (
 	"auth/model"
 	"auth/repository"
 	"auth/utils"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
@@ -22,33 +28,257 @@ type User struct {
 type UserService struct {
 	userRepo *repository.UserRepository
 	roleRepo *repository.RoleRepository
+	orgRepo  *repository.OrganizationRepository
+	security utils.Security
+	orgId    string
 }
 
 // NewUserService crée une nouvelle instance de UserService
 func NewUserService(
-	userRepo *repository.UserRepository, roleRepo *repository.RoleRepository) *UserService {
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	orgRepo *repository.OrganizationRepository,
+	security utils.Security) *UserService {
 	return &UserService{
 		userRepo: userRepo,
 		roleRepo: roleRepo,
+		orgRepo:  orgRepo,
+		security: security,
+	}
+}
+
+// ForOrganization retourne un service dont les opérations sont limitées à l'organisation fournie
+func (s *UserService) ForOrganization(orgId string) *UserService {
+	return &UserService{
+		userRepo: s.userRepo.ForOrganization(orgId),
+		roleRepo: s.roleRepo,
+		orgRepo:  s.orgRepo,
+		security: s.security,
+		orgId:    orgId,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	return &UserService{
+		userRepo: s.userRepo.WithContext(ctx),
+		roleRepo: s.roleRepo.WithContext(ctx),
+		orgRepo:  s.orgRepo.WithContext(ctx),
+		security: s.security,
+		orgId:    s.orgId,
 	}
 }
 
-// GetAllUsers récupère tous les utilisateurs
-func (s *UserService) GetAllUsers() ([]model.User, error) {
-	users, err := s.userRepo.GetAllUsers()
+// UserListQuery regroupe les filtres, le tri et la pagination demandés sur la liste des utilisateurs
+type UserListQuery struct {
+	Role        string
+	EmailDomain string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	IsAvailable *bool
+	UseOTP      *bool
+	Sort        string
+	Limit       int
+	Offset      int
+	Cursor      string
+}
+
+// UserPage est une page d'utilisateurs avec le total et le curseur de la page suivante
+type UserPage struct {
+	Users      []model.User
+	Total      int64
+	Limit      int
+	Offset     int
+	NextCursor string
+}
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// userCursor est le contenu encodé d'un curseur de pagination
+type userCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Id    string `json:"id"`
+}
+
+// toUserQuery valide le tri et la taille de page demandés et les traduit en critères du repository
+func toUserQuery(listQuery UserListQuery) (repository.UserQuery, error) {
+	query := repository.UserQuery{
+		Role:        listQuery.Role,
+		EmailDomain: listQuery.EmailDomain,
+		CreatedFrom: listQuery.CreatedFrom,
+		CreatedTo:   listQuery.CreatedTo,
+		IsAvailable: listQuery.IsAvailable,
+		UseOTP:      listQuery.UseOTP,
+		Limit:       listQuery.Limit,
+		Offset:      listQuery.Offset,
+	}
+
+	sort := listQuery.Sort
+	if sort == "" {
+		sort = "created_at"
+	}
+	query.SortBy = strings.TrimPrefix(sort, "-")
+	query.SortDesc = strings.HasPrefix(sort, "-")
+	if _, ok := repository.UserSortColumns[query.SortBy]; !ok {
+		return repository.UserQuery{}, errors.New("champ de tri inconnu : " + query.SortBy)
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultUserPageSize
+	}
+	if query.Limit > maxUserPageSize {
+		query.Limit = maxUserPageSize
+	}
+	return query, nil
+}
+
+// StreamUsers parcourt tous les utilisateurs correspondant aux filtres sans pagination ;
+// le rôle de chaque utilisateur est remplacé par son nom
+func (s *UserService) StreamUsers(listQuery UserListQuery, handle func(user model.User) error) error {
+	query, err := toUserQuery(listQuery)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.StreamUsers(query, func(user repository.UserWithRole) error {
+		userItem := user.User
+		userItem.RoleId = user.RoleName
+		return handle(userItem)
+	})
+}
+
+// GetAllUsers récupère une page d'utilisateurs filtrée et triée.
+// Le tri s'exprime par un nom de champ, préfixé par "-" pour un ordre décroissant.
+func (s *UserService) GetAllUsers(listQuery UserListQuery) (UserPage, error) {
+	query, err := toUserQuery(listQuery)
 	if err != nil {
-		return []model.User{}, err
+		return UserPage{}, err
+	}
+	sort := listQuery.Sort
+	if sort == "" {
+		sort = "created_at"
 	}
 
+	if listQuery.Cursor != "" {
+		cursor, err := decodeUserCursor(listQuery.Cursor, sort)
+		if err != nil {
+			return UserPage{}, err
+		}
+		query.Cursor = &cursor
+		query.Offset = 0
+	}
+
+	// Un élément supplémentaire indique l'existence d'une page suivante
+	limit := query.Limit
+	query.Limit = limit + 1
+	users, total, err := s.userRepo.GetAllUsers(query)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	page := UserPage{
+		Users:  []model.User{},
+		Total:  total,
+		Limit:  limit,
+		Offset: query.Offset,
+	}
+	for i := 0; i < len(users) && i < limit; i++ {
+		userItem := users[i].User
+		userItem.RoleId = users[i].RoleName
+		page.Users = append(page.Users, userItem)
+	}
+	if len(users) > limit {
+		page.NextCursor = encodeUserCursor(users[limit-1].User, sort)
+	}
+
+	return page, nil
+}
+
+// maxSearchTerms limite le nombre de mots pris en compte par la recherche
+const maxSearchTerms = 5
+
+// SearchUsers recherche les utilisateurs par nom, prénom, nom d'utilisateur ou email,
+// classés par pertinence
+func (s *UserService) SearchUsers(q string, limit int, offset int) (UserPage, error) {
+	terms := strings.Fields(q)
+	if len(terms) == 0 {
+		return UserPage{}, errors.New("le texte recherché est vide")
+	}
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+
+	if limit <= 0 {
+		limit = defaultUserPageSize
+	}
+	if limit > maxUserPageSize {
+		limit = maxUserPageSize
+	}
+
+	users, total, err := s.userRepo.SearchUsers(terms, limit, offset)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	page := UserPage{
+		Users:  []model.User{},
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
 	for i := 0; i < len(users); i++ {
-		userItem := users[i]
-		roleResponse, _ := s.roleRepo.GetRoleById(userItem.RoleId)
+		userItem := users[i].User
+		userItem.RoleId = users[i].RoleName
+		page.Users = append(page.Users, userItem)
+	}
+	return page, nil
+}
+
+func encodeUserCursor(user model.User, sort string) string {
+	cursor := userCursor{Sort: sort, Id: user.Id}
+	switch strings.TrimPrefix(sort, "-") {
+	case "name":
+		cursor.Value = user.Name
+	case "email":
+		cursor.Value = user.Email
+	case "username":
+		cursor.Value = user.Username
+	case "updated_at":
+		cursor.Value = user.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = user.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func decodeUserCursor(encoded string, sort string) (repository.UserCursor, error) {
+	invalid := errors.New("curseur de pagination invalide")
 
-		userItem.RoleId = roleResponse.Name
-		users[i] = userItem
+	content, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return repository.UserCursor{}, invalid
+	}
+
+	var cursor userCursor
+	if err := json.Unmarshal(content, &cursor); err != nil || cursor.Sort != sort || cursor.Id == "" {
+		return repository.UserCursor{}, invalid
 	}
 
-	return users, nil
+	switch strings.TrimPrefix(sort, "-") {
+	case "created_at", "updated_at":
+		value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return repository.UserCursor{}, invalid
+		}
+		return repository.UserCursor{Value: value, Id: cursor.Id}, nil
+	}
+	return repository.UserCursor{Value: cursor.Value, Id: cursor.Id}, nil
 }
 
 // GetUserById récupère un utilisateur par son ID
@@ -66,16 +296,27 @@ func (s *UserService) GetUserById(id string) (model.User, error) {
 	return userResponse, nil
 }
 
-// CreateUser crée un nouvel utilisateur avec un rôle spécifié
+// CreateUser crée un nouvel utilisateur avec un rôle spécifié.
+// Hors organisation, l'utilisateur rejoint l'organisation par défaut.
 func (s *UserService) CreateUser(newUser User) (model.User, error) {
 
-	_, err := s.userRepo.GetUserByEmail(newUser.Email)
-	if err == nil {
-		return model.User{}, errors.New("adresse email a déjà été utilisé")
+	if s.orgId == "" {
+		defaultOrganization, err := s.orgRepo.GetOrganizationBySlug(model.DefaultOrganizationSlug)
+		if err != nil {
+			return model.User{}, errors.New("aucune organisation par défaut n'est configurée")
+		}
+		return s.ForOrganization(defaultOrganization.Id).CreateUser(newUser)
 	}
 
-	_, err = s.userRepo.GetUserByUsername(newUser.Username)
-	if err == nil {
+	// Les emails et noms d'utilisateur sont uniques sur toutes les organisations
+	takenEmails, takenUsernames, err := s.userRepo.GetTakenIdentities([]string{newUser.Email}, []string{newUser.Username})
+	if err != nil {
+		return model.User{}, err
+	}
+	if takenEmails[newUser.Email] {
+		return model.User{}, errors.New("adresse email a déjà été utilisé")
+	}
+	if takenUsernames[newUser.Username] {
 		return model.User{}, errors.New("le nom d'utilisateur a déjà été utilisé")
 	}
 
@@ -84,7 +325,7 @@ func (s *UserService) CreateUser(newUser User) (model.User, error) {
 		return model.User{}, errors.New("aucun rôle ne correspond à cette valeur")
 	}
 
-	hashPassword, err := utils.GenerateHashPassword(newUser.Password)
+	hashPassword, err := s.security.GenerateHashPassword(newUser.Password)
 	if err != nil {
 		return model.User{}, errors.New("erreur de cryptage du mot de passe utilisateur")
 	}
@@ -99,7 +340,10 @@ func (s *UserService) CreateUser(newUser User) (model.User, error) {
 		UseOTP:   false,
 	}
 
-	userResponse, err := s.userRepo.CreateUser(userModel)
+	userResponse, err := s.userRepo.WithEvent(model.EventUserCreated).CreateUser(userModel)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return model.User{}, errors.New("adresse email ou nom d'utilisateur déjà utilisé")
+	}
 	if err != nil {
 		return model.User{}, err
 	}
@@ -110,9 +354,9 @@ func (s *UserService) CreateUser(newUser User) (model.User, error) {
 }
 
 // UpdateUser met à jour un utilisateur
-func (s *UserService) UpdateUser(updatedUser User) (model.User, error) {
+func (s *UserService) UpdateUser(id string, updatedUser User) (model.User, error) {
 
-	existingUser, err := s.userRepo.GetUserByUsername(updatedUser.Username)
+	existingUser, err := s.userRepo.GetUserById(id)
 	if err != nil {
 		return model.User{}, errors.New("utilisateur inconnu du système")
 	}
@@ -122,7 +366,7 @@ func (s *UserService) UpdateUser(updatedUser User) (model.User, error) {
 	existingUser.Name = updatedUser.Name
 	existingUser.UpdatedAt = time.Now()
 
-	updateUser, err := s.userRepo.UpdateUser(existingUser)
+	updateUser, err := s.userRepo.WithEvent(model.EventUserUpdated).UpdateUser(existingUser)
 	if err != nil {
 		return model.User{}, errors.New("nous avons rencontré un problème durant la mise à jour")
 	}
@@ -143,7 +387,64 @@ func (s *UserService) DeleteUser(id string) error {
 
 	existingUser.IsVisible = false
 	existingUser.DeletedAt = time.Now()
-	return s.userRepo.ArchivedUser(existingUser)
+	return s.userRepo.WithEvent(model.EventUserDeleted).ArchivedUser(existingUser)
+}
+
+// DisableUser désactive un compte et révoque immédiatement ses sessions
+func (s *UserService) DisableUser(id string, actorId string, reason string) (model.User, error) {
+	if id == actorId {
+		return model.User{}, errors.New("vous ne pouvez pas désactiver votre propre compte")
+	}
+
+	existingUser, err := s.userRepo.GetUserById(id)
+	if err != nil {
+		return model.User{}, errors.New("utilisateur non trouvé")
+	}
+	return transitionAccountState(s.userRepo, existingUser, AccountStateDisabled, reason)
+}
+
+// EnableUser rend actif un compte désactivé, verrouillé ou en attente d'activation. Pour un mot
+// de passe expiré, l'administrateur lève l'expiration sans que l'utilisateur ait à le renouveler.
+func (s *UserService) EnableUser(id string) (model.User, error) {
+	existingUser, err := s.userRepo.GetUserById(id)
+	if err != nil {
+		return model.User{}, errors.New("utilisateur non trouvé")
+	}
+	return transitionAccountState(s.userRepo, existingUser, AccountStateActive, "")
+}
+
+// ExpirePassword oblige l'utilisateur à renouveler son mot de passe avant de se reconnecter.
+// Ses tokens sont refusés dès maintenant ; le renouvellement se fait avec l'ancien mot de passe.
+func (s *UserService) ExpirePassword(id string, actorId string) (model.User, error) {
+	if id == actorId {
+		return model.User{}, errors.New("vous ne pouvez pas faire expirer votre propre mot de passe")
+	}
+
+	existingUser, err := s.userRepo.GetUserById(id)
+	if err != nil {
+		return model.User{}, errors.New("utilisateur non trouvé")
+	}
+	return transitionAccountState(s.userRepo, existingUser, AccountStatePasswordExpired, "password expired by an administrator")
+}
+
+// RestoreUser annule la suppression d'un utilisateur tant que la purge ne l'a pas traité
+func (s *UserService) RestoreUser(id string) (model.User, error) {
+	if _, err := s.userRepo.GetArchivedUserById(id); err != nil {
+		return model.User{}, errors.New("aucun utilisateur supprimé ne correspond à cet identifiant")
+	}
+
+	if err := s.userRepo.RestoreUser(id); err != nil {
+		return model.User{}, errors.New("nous avons rencontré un problème durant la restauration de l'utilisateur")
+	}
+
+	restoredUser, err := s.userRepo.GetUserById(id)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	roleResponse, _ := s.roleRepo.GetRoleById(restoredUser.RoleId)
+	restoredUser.RoleId = roleResponse.Name
+	return restoredUser, nil
 }
 
 // GetUserByRole récupère tous les utilisateurs avec un rôle spécifié
@@ -151,40 +452,107 @@ func (s *UserService) GetUserByRole(role string) ([]model.User, error) {
 	return s.userRepo.GetUserByRole(role)
 }
 
-// RemoveRole retire un rôle à un utilisateur
-func (s *UserService) RemoveRole(userId string) (model.User, error) {
+// MemberRoles décrit les rôles d'un utilisateur dans une organisation
+type MemberRoles struct {
+	UserId string
+	OrgId  string
+	Roles  []string
+}
+
+// AssignRole ajoute un rôle à l'utilisateur dans l'organisation active, sans toucher aux autres
+// organisations dont il est membre
+func (s *UserService) AssignRole(userId string, roleName string) (MemberRoles, error) {
+	return s.updateMemberRoles(userId, func(roleNames []string) ([]string, error) {
+		for i := 0; i < len(roleNames); i++ {
+			if roleNames[i] == roleName {
+				return nil, errors.New("l'utilisateur a déjà ce rôle dans l'organisation")
+			}
+		}
+		return append(roleNames, roleName), nil
+	})
+}
+
+// RemoveRole retire un rôle de l'utilisateur dans l'organisation active
+func (s *UserService) RemoveRole(userId string, roleName string) (MemberRoles, error) {
+	return s.updateMemberRoles(userId, func(roleNames []string) ([]string, error) {
+		remaining := []string{}
+		for i := 0; i < len(roleNames); i++ {
+			if roleNames[i] != roleName {
+				remaining = append(remaining, roleNames[i])
+			}
+		}
+		if len(remaining) == len(roleNames) {
+			return nil, errors.New("l'utilisateur n'a pas ce rôle dans l'organisation")
+		}
+		return remaining, nil
+	})
+}
+
+// AssignSystemRole remplace le rôle système de l'utilisateur, valable dans toutes ses
+// organisations. Seul un administrateur système peut le changer.
+func (s *UserService) AssignSystemRole(userId string, roleName string, actorRole string) (model.User, error) {
+	if actorRole != "admin" {
+		return model.User{}, errors.New("seul un administrateur système peut changer le rôle système d'un utilisateur")
+	}
+
 	user, err := s.userRepo.GetUserById(userId)
 	if err != nil {
-		return model.User{}, err
+		return model.User{}, errors.New("utilisateur non trouvé")
 	}
-
-	if user.RoleId == "" {
-		return model.User{}, errors.New("l'utilisateur n'a pas de rôle à retirer")
+	role, err := s.roleRepo.GetRoleByName(roleName)
+	if err != nil {
+		return model.User{}, errors.New("aucun rôle ne correspond à cette valeur")
 	}
 
-	user.RoleId = ""
-	updatedUser, err := s.userRepo.UpdateUser(user)
+	user.RoleId = role.Id
+	updatedUser, err := s.userRepo.WithEvent(model.EventUserRoleChanged).UpdateUser(user)
 	if err != nil {
-		return model.User{}, err
+		return model.User{}, errors.New("nous avons rencontré un problème durant la mise à jour du rôle")
 	}
-
+	updatedUser.RoleId = role.Name
 	return updatedUser, nil
 }
 
-// AssignRole assigne un rôle à un utilisateur
-func (s *UserService) AssignRole(userId string, role string) (model.User, error) {
-	user, err := s.userRepo.GetUserById(userId)
+// updateMemberRoles applique change aux rôles de l'adhésion de l'utilisateur dans l'organisation active
+func (s *UserService) updateMemberRoles(userId string, change func(roleNames []string) ([]string, error)) (MemberRoles, error) {
+	if s.orgId == "" {
+		return MemberRoles{}, errors.New("aucune organisation active")
+	}
+	membership, err := s.orgRepo.GetMembership(userId, s.orgId)
 	if err != nil {
-		return model.User{}, err
+		return MemberRoles{}, errors.New("l'utilisateur n'est pas membre de cette organisation")
 	}
 
-	user.RoleId = role
-	updatedUser, err := s.userRepo.UpdateUser(user)
+	var roleIds []string
+	for i := 0; i < len(membership.MembershipRoles); i++ {
+		roleIds = append(roleIds, membership.MembershipRoles[i].RoleId)
+	}
+	roles, err := s.roleRepo.GetRolesByIds(roleIds)
 	if err != nil {
-		return model.User{}, err
+		return MemberRoles{}, err
+	}
+	var roleNames []string
+	for i := 0; i < len(roles); i++ {
+		roleNames = append(roleNames, roles[i].Name)
 	}
 
-	return updatedUser, nil
+	roleNames, err = change(roleNames)
+	if err != nil {
+		return MemberRoles{}, err
+	}
+	newRoles, err := s.roleRepo.GetRolesByNames(roleNames)
+	if err != nil || len(newRoles) != len(roleNames) {
+		return MemberRoles{}, errors.New("aucun rôle ne correspond à cette valeur")
+	}
+
+	roleIds = []string{}
+	for i := 0; i < len(newRoles); i++ {
+		roleIds = append(roleIds, newRoles[i].Id)
+	}
+	if _, err := s.orgRepo.SetMembershipRoles(membership.Id, roleIds); err != nil {
+		return MemberRoles{}, errors.New("nous avons rencontré un problème durant la mise à jour des rôles")
+	}
+	return MemberRoles{UserId: userId, OrgId: s.orgId, Roles: roleNames}, nil
 }
 
 func (s *UserService) GetUserByEmail(email string) (model.User, error) {
@@ -0,0 +1,555 @@
+package service
+
+import (
+	"auth/logging"
+	"auth/model"
+	"auth/repository"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	slog "github.com/sagikazarmark/slog-shim"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Lots traités à chaque passage du distributeur
+	webhookDispatchBatchSize = 100
+	webhookDeliveryBatchSize = 50
+
+	defaultWebhookPageSize = 50
+	maxWebhookPageSize     = 200
+)
+
+// WebhookInput décrit un abonnement créé ou modifié par un administrateur
+type WebhookInput struct {
+	Url         string
+	Description string
+	Events      []string
+	IsActive    bool
+}
+
+// WebhookDeliveryListQuery regroupe les filtres et la pagination demandés sur les envois
+type WebhookDeliveryListQuery struct {
+	SubscriptionId string
+	Status         string
+	Limit          int
+	Offset         int
+}
+
+// WebhookDeliveryPage est une page d'envois avec le total des envois correspondants
+type WebhookDeliveryPage struct {
+	Deliveries []model.WebhookDelivery
+	Total      int64
+	Limit      int
+	Offset     int
+}
+
+// WebhookEnvelope est le corps JSON envoyé aux abonnés
+type WebhookEnvelope struct {
+	Id             string          `json:"id"`
+	Type           string          `json:"type"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	OrganizationId string          `json:"organization_id"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// WebhookService gère les abonnements aux évènements et distribue les évènements de l'outbox
+type WebhookService struct {
+	webhookRepo    *repository.WebhookRepository
+	outboxRepo     *repository.OutboxRepository
+	orgRepo        *repository.OrganizationRepository
+	orgId          string
+	client         *http.Client
+	maxAttempts    int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	allowPrivate   bool
+	logger         *slog.Logger
+}
+
+// NewWebhookService crée une nouvelle instance de WebhookService
+func NewWebhookService(
+	webhookRepo *repository.WebhookRepository,
+	outboxRepo *repository.OutboxRepository,
+	orgRepo *repository.OrganizationRepository,
+	timeout time.Duration,
+	maxAttempts int,
+	retryBaseDelay time.Duration,
+	retryMaxDelay time.Duration,
+	allowPrivate bool) *WebhookService {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &WebhookService{
+		webhookRepo:    webhookRepo,
+		outboxRepo:     outboxRepo,
+		orgRepo:        orgRepo,
+		client:         newWebhookClient(timeout, allowPrivate),
+		maxAttempts:    maxAttempts,
+		retryBaseDelay: retryBaseDelay,
+		retryMaxDelay:  retryMaxDelay,
+		allowPrivate:   allowPrivate,
+		logger:         slog.Default(),
+	}
+}
+
+// ForOrganization retourne un service limité aux abonnements de l'organisation
+func (s *WebhookService) ForOrganization(orgId string) *WebhookService {
+	return &WebhookService{
+		webhookRepo:    s.webhookRepo,
+		outboxRepo:     s.outboxRepo,
+		orgRepo:        s.orgRepo,
+		orgId:          orgId,
+		client:         s.client,
+		maxAttempts:    s.maxAttempts,
+		retryBaseDelay: s.retryBaseDelay,
+		retryMaxDelay:  s.retryMaxDelay,
+		allowPrivate:   s.allowPrivate,
+		logger:         s.logger,
+	}
+}
+
+// WithContext retourne une copie du service dont les opérations portent le contexte fourni
+func (s *WebhookService) WithContext(ctx context.Context) *WebhookService {
+	return &WebhookService{
+		webhookRepo:    s.webhookRepo.WithContext(ctx),
+		outboxRepo:     s.outboxRepo.WithContext(ctx),
+		orgRepo:        s.orgRepo.WithContext(ctx),
+		orgId:          s.orgId,
+		client:         s.client,
+		maxAttempts:    s.maxAttempts,
+		retryBaseDelay: s.retryBaseDelay,
+		retryMaxDelay:  s.retryMaxDelay,
+		allowPrivate:   s.allowPrivate,
+		logger:         logging.FromContext(ctx),
+	}
+}
+
+// CreateSubscription enregistre un abonnement et retourne son secret de signature, affiché une seule fois
+func (s *WebhookService) CreateSubscription(input WebhookInput, createdBy string) (model.WebhookSubscription, string, error) {
+	if s.orgId == "" {
+		return model.WebhookSubscription{}, "", errors.New("aucune organisation active")
+	}
+	events, err := s.validateWebhookInput(input)
+	if err != nil {
+		return model.WebhookSubscription{}, "", err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return model.WebhookSubscription{}, "", err
+	}
+
+	subscription, err := s.webhookRepo.CreateSubscription(model.WebhookSubscription{
+		AbstractModel:  model.AbstractModel{IsAvailable: input.IsActive, IsVisible: true},
+		OrganizationId: s.orgId,
+		Url:            input.Url,
+		Description:    input.Description,
+		Events:         strings.Join(events, ","),
+		Secret:         secret,
+		CreatedBy:      createdBy,
+	})
+	if err != nil {
+		return model.WebhookSubscription{}, "", errors.New("nous avons rencontré un problème durant la création du webhook")
+	}
+
+	// La valeur par défaut de is_available ignore un abonnement créé inactif
+	if !input.IsActive {
+		if subscription, err = s.webhookRepo.UpdateSubscription(subscription); err != nil {
+			return model.WebhookSubscription{}, "", errors.New("nous avons rencontré un problème durant la création du webhook")
+		}
+	}
+	return subscription, secret, nil
+}
+
+// GetSubscriptions liste les abonnements de l'organisation
+func (s *WebhookService) GetSubscriptions() ([]model.WebhookSubscription, error) {
+	return s.webhookRepo.GetSubscriptions(s.orgId)
+}
+
+// GetSubscription récupère un abonnement de l'organisation
+func (s *WebhookService) GetSubscription(id string) (model.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetSubscriptionById(id, s.orgId)
+	if err != nil {
+		return model.WebhookSubscription{}, errors.New("webhook non trouvé")
+	}
+	return subscription, nil
+}
+
+// UpdateSubscription modifie l'adresse, la description, les évènements ou l'activation d'un abonnement
+func (s *WebhookService) UpdateSubscription(id string, input WebhookInput) (model.WebhookSubscription, error) {
+	subscription, err := s.GetSubscription(id)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	events, err := s.validateWebhookInput(input)
+	if err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	subscription.Url = input.Url
+	subscription.Description = input.Description
+	subscription.Events = strings.Join(events, ",")
+	subscription.IsAvailable = input.IsActive
+	return s.webhookRepo.UpdateSubscription(subscription)
+}
+
+// DeleteSubscription supprime un abonnement ; ses envois en attente ne sont pas effectués
+func (s *WebhookService) DeleteSubscription(id string) error {
+	if err := s.webhookRepo.DeleteSubscription(id, s.orgId); err != nil {
+		return errors.New("webhook non trouvé")
+	}
+	return nil
+}
+
+// GetDeliveries liste les envois de l'organisation, les plus récents en premier.
+// Le filtre sur le statut "dead" donne les lettres mortes.
+func (s *WebhookService) GetDeliveries(listQuery WebhookDeliveryListQuery) (WebhookDeliveryPage, error) {
+	limit := listQuery.Limit
+	if limit <= 0 {
+		limit = defaultWebhookPageSize
+	}
+	if limit > maxWebhookPageSize {
+		limit = maxWebhookPageSize
+	}
+	offset := listQuery.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, total, err := s.webhookRepo.GetDeliveries(repository.WebhookDeliveryQuery{
+		OrganizationId: s.orgId,
+		SubscriptionId: listQuery.SubscriptionId,
+		Status:         listQuery.Status,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return WebhookDeliveryPage{}, errors.New("nous avons rencontré un problème durant la lecture des envois")
+	}
+	return WebhookDeliveryPage{Deliveries: deliveries, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// Redeliver renvoie un évènement déjà livré ou passé en lettre morte, avec le même identifiant
+func (s *WebhookService) Redeliver(deliveryId string) (model.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.GetDeliveryById(deliveryId, s.orgId)
+	if err != nil {
+		return model.WebhookDelivery{}, errors.New("envoi non trouvé")
+	}
+	if _, err := s.GetSubscription(delivery.SubscriptionId); err != nil {
+		return model.WebhookDelivery{}, errors.New("le webhook de cet envoi a été supprimé")
+	}
+	if err := s.webhookRepo.RedeliverDelivery(delivery.Id, s.orgId); err != nil {
+		return model.WebhookDelivery{}, errors.New("cet envoi est déjà en attente")
+	}
+	return s.webhookRepo.GetDeliveryById(delivery.Id, s.orgId)
+}
+
+// Start distribue les évènements de l'outbox et effectue les envois échus à intervalle régulier
+func (s *WebhookService) Start(interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Info("Webhook dispatcher disabled : no dispatch interval configured")
+		return
+	}
+
+	s.logger.Info("Webhook dispatcher scheduled", "interval", interval, "max_attempts", s.maxAttempts)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.DispatchPendingEvents(); err != nil {
+				s.logger.Error("Webhook dispatch failed", "error", err)
+			}
+			if err := s.DeliverDue(time.Now()); err != nil {
+				s.logger.Error("Webhook delivery failed", "error", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// DispatchPendingEvents crée un envoi par abonnement concerné pour chaque évènement validé de l'outbox.
+// Un évènement sans organisation concerne les organisations dont l'utilisateur est membre.
+func (s *WebhookService) DispatchPendingEvents() error {
+	events, err := s.outboxRepo.GetPendingEvents(webhookDispatchBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(events); i++ {
+		event := events[i]
+		organizationIds, err := s.eventOrganizations(event)
+		if err != nil {
+			return err
+		}
+		subscriptions, err := s.webhookRepo.GetActiveSubscriptions(organizationIds)
+		if err != nil {
+			return err
+		}
+
+		deliveries := []model.WebhookDelivery{}
+		for j := 0; j < len(subscriptions); j++ {
+			if !subscriptions[j].Accepts(event.EventType) {
+				continue
+			}
+
+			body, err := json.Marshal(WebhookEnvelope{
+				Id:             event.Id,
+				Type:           event.EventType,
+				OccurredAt:     event.OccurredAt.UTC(),
+				OrganizationId: subscriptions[j].OrganizationId,
+				Data:           json.RawMessage(event.Payload),
+			})
+			if err != nil {
+				return err
+			}
+
+			deliveries = append(deliveries, model.WebhookDelivery{
+				SubscriptionId: subscriptions[j].Id,
+				OrganizationId: subscriptions[j].OrganizationId,
+				EventId:        event.Id,
+				EventType:      event.EventType,
+				Payload:        string(body),
+				Status:         model.WebhookDeliveryPending,
+				NextAttemptAt:  time.Now(),
+			})
+		}
+
+		// Un évènement déjà distribué par une autre instance est simplement ignoré
+		if err := s.outboxRepo.DispatchEvent(event, deliveries); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue effectue les envois en attente dont la prochaine tentative est échue
+func (s *WebhookService) DeliverDue(now time.Time) error {
+	deliveries, err := s.webhookRepo.GetDueDeliveries(now, webhookDeliveryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	// La réservation couvre la durée d'un envoi, au-delà l'envoi pourra être repris
+	leaseUntil := now.Add(2*s.client.Timeout + time.Minute)
+	for i := 0; i < len(deliveries); i++ {
+		if err := s.webhookRepo.ClaimDelivery(deliveries[i].Id, now, leaseUntil); err != nil {
+			continue
+		}
+
+		attempt := s.deliver(deliveries[i])
+		if err := s.webhookRepo.RecordAttempt(deliveries[i], attempt); err != nil {
+			s.logger.Error("Failed to record webhook delivery", "delivery_id", deliveries[i].Id, "error", err)
+		}
+	}
+	return nil
+}
+
+// deliver envoie l'évènement signé à l'abonné et calcule la suite : livré, nouvelle tentative ou lettre morte
+func (s *WebhookService) deliver(delivery model.WebhookDelivery) repository.WebhookAttempt {
+	subscription, err := s.webhookRepo.GetSubscriptionById(delivery.SubscriptionId, delivery.OrganizationId)
+	if err != nil {
+		return repository.WebhookAttempt{Status: model.WebhookDeliveryDead, Error: "abonnement supprimé"}
+	}
+	if !subscription.IsAvailable {
+		return repository.WebhookAttempt{Status: model.WebhookDeliveryDead, Error: "abonnement désactivé"}
+	}
+
+	statusCode, err := s.post(subscription, delivery)
+	if err == nil {
+		return repository.WebhookAttempt{Status: model.WebhookDeliveryDelivered, StatusCode: statusCode}
+	}
+
+	attempt := repository.WebhookAttempt{
+		Status:     model.WebhookDeliveryPending,
+		StatusCode: statusCode,
+		Error:      truncate(err.Error(), 500),
+	}
+	if delivery.Attempts+1 >= s.maxAttempts {
+		attempt.Status = model.WebhookDeliveryDead
+		return attempt
+	}
+	attempt.NextAttemptAt = time.Now().Add(s.retryDelay(delivery.Attempts))
+	return attempt
+}
+
+// post envoie le corps de l'évènement. La signature X-Webhook-Signature vaut "sha256=" suivi du
+// HMAC-SHA256 hexadécimal de "<X-Webhook-Timestamp>.<corps>" calculé avec le secret de l'abonnement.
+func (s *WebhookService) post(subscription model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request, err := http.NewRequest(http.MethodPost, subscription.Url, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "CMagic-Auth-Webhooks/1.0")
+	request.Header.Set("X-Webhook-Id", delivery.EventId)
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(subscription.Secret, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("réponse HTTP %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// retryDelay double le délai à chaque échec, dans la limite du délai maximal
+func (s *WebhookService) retryDelay(previousAttempts int) time.Duration {
+	delay := s.retryBaseDelay
+	for i := 0; i < previousAttempts && delay < s.retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.retryMaxDelay {
+		delay = s.retryMaxDelay
+	}
+	return delay
+}
+
+func (s *WebhookService) eventOrganizations(event model.OutboxEvent) ([]string, error) {
+	if event.OrganizationId != "" {
+		return []string{event.OrganizationId}, nil
+	}
+
+	memberships, err := s.orgRepo.GetMembershipsByUser(event.SubjectId)
+	if err != nil {
+		return nil, err
+	}
+	organizationIds := []string{}
+	for i := 0; i < len(memberships); i++ {
+		organizationIds = append(organizationIds, memberships[i].OrganizationId)
+	}
+	return organizationIds, nil
+}
+
+func (s *WebhookService) validateWebhookInput(input WebhookInput) ([]string, error) {
+	target, err := url.Parse(input.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return nil, errors.New("l'adresse du webhook doit être une URL http ou https")
+	}
+	if !s.allowPrivate {
+		if err := checkWebhookHost(target.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	events := []string{}
+	seen := map[string]bool{}
+	for i := 0; i < len(input.Events); i++ {
+		event := strings.TrimSpace(input.Events[i])
+		if !isUserEvent(event) {
+			return nil, fmt.Errorf("évènement inconnu : %s", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return nil, errors.New("le webhook doit s'abonner à au moins un évènement")
+	}
+	return events, nil
+}
+
+// checkWebhookHost refuse un hôte qui désigne, ou se résout en, une adresse interne. La
+// vérification est refaite à chaque connexion, l'enregistrement DNS pouvant changer entre-temps.
+func checkWebhookHost(host string) error {
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		ips, err = net.LookupIP(host)
+		if err != nil || len(ips) == 0 {
+			return errors.New("l'hôte du webhook est introuvable")
+		}
+	}
+	for i := 0; i < len(ips); i++ {
+		if isInternalIP(ips[i]) {
+			return errors.New("l'adresse du webhook ne doit pas désigner un réseau privé ou local")
+		}
+	}
+	return nil
+}
+
+// isInternalIP indique si l'adresse est de bouclage, privée, lien-local, non spécifiée ou multicast
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// newWebhookClient crée le client des envois. Sauf autorisation explicite, l'adresse réellement
+// contactée est contrôlée au moment de la connexion et les redirections ne sont pas suivies, afin
+// qu'un abonné ne puisse pas rediriger les envois vers le réseau interne.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+				return fmt.Errorf("connexion refusée vers l'adresse interne %s", host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isUserEvent(event string) bool {
+	for i := 0; i < len(model.UserEvents); i++ {
+		if model.UserEvents[i] == event {
+			return true
+		}
+	}
+	return false
+}
+
+func signWebhook(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", errors.New("error system : impossible de générer le secret du webhook")
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(buffer), nil
+}
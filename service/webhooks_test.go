@@ -0,0 +1,139 @@
+package service
+
+import (
+	"auth/model"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// HMAC-SHA256 de "1700000000.{}" avec le secret "whsec_test", calculé indépendamment
+	want := "35495024f4ef3f94e5a93e22221544c4b75e9a42300cd965ab81cb85cd994e91"
+	got := signWebhook("whsec_test", "1700000000", "{}")
+	if got != want {
+		t.Fatalf("signature = %s, %s attendu", got, want)
+	}
+	if got == signWebhook("whsec_other", "1700000000", "{}") {
+		t.Error("la signature doit dépendre du secret")
+	}
+	if got == signWebhook("whsec_test", "1700000001", "{}") {
+		t.Error("la signature doit couvrir l'horodatage")
+	}
+	if got == signWebhook("whsec_test", "1700000000", `{"a":1}`) {
+		t.Error("la signature doit couvrir le corps")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	s := &WebhookService{retryBaseDelay: 30 * time.Second, retryMaxDelay: 5 * time.Minute}
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := s.retryDelay(c.attempts); got != c.want {
+			t.Errorf("retryDelay(%d) = %v, %v attendu", c.attempts, got, c.want)
+		}
+	}
+}
+
+func TestValidateWebhookInputRejectsInternalAddresses(t *testing.T) {
+	s := &WebhookService{}
+	events := []string{model.EventUserCreated}
+
+	internal := []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8000/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+	}
+	for _, target := range internal {
+		if _, err := s.validateWebhookInput(WebhookInput{Url: target, Events: events}); err == nil {
+			t.Errorf("%s accepté, adresse interne", target)
+		}
+	}
+
+	if _, err := s.validateWebhookInput(WebhookInput{Url: "https://93.184.216.34/hook", Events: events}); err != nil {
+		t.Errorf("adresse publique refusée : %v", err)
+	}
+	if _, err := s.validateWebhookInput(WebhookInput{Url: "ftp://93.184.216.34/hook", Events: events}); err == nil {
+		t.Error("schéma ftp accepté")
+	}
+
+	allowed := &WebhookService{allowPrivate: true}
+	if _, err := allowed.validateWebhookInput(WebhookInput{Url: "http://127.0.0.1/hook", Events: events}); err != nil {
+		t.Errorf("adresse locale refusée malgré l'autorisation : %v", err)
+	}
+}
+
+func TestIsInternalIP(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.0.1":     true,
+		"169.254.169.254": true,
+		"::1":             true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"0.0.0.0":         true,
+		"8.8.8.8":         false,
+		"2606:4700::1111": false,
+	}
+	for address, want := range cases {
+		if got := isInternalIP(net.ParseIP(address)); got != want {
+			t.Errorf("isInternalIP(%s) = %v, %v attendu", address, got, want)
+		}
+	}
+}
+
+func TestWebhookClientRefusesInternalConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if _, err := newWebhookClient(time.Second, false).Get(server.URL); err == nil {
+		t.Error("connexion vers le serveur local acceptée")
+	}
+
+	response, err := newWebhookClient(time.Second, true).Get(server.URL)
+	if err != nil {
+		t.Fatalf("connexion refusée malgré l'autorisation : %v", err)
+	}
+	response.Body.Close()
+}
+
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+
+	response, err := newWebhookClient(time.Second, true).Post(redirect.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("envoi : %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("statut = %d, la redirection ne doit pas être suivie", response.StatusCode)
+	}
+}
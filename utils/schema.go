@@ -15,3 +15,31 @@ func (h HttpResponse[T]) ToJson() interface{} {
 		"data":       h.Data,
 	}
 }
+
+// LoadPermissionByRoleNames fusionne les permissions de plusieurs rôles
+func LoadPermissionByRoleNames(roleNames ...string) JsonRoleItem {
+	merged := JsonRoleItem{Permissions: []string{}}
+	seen := map[string]bool{}
+
+	for i := 0; i < len(roleNames); i++ {
+		role := LoadPermissionByRoleName(roleNames[i])
+		if role.Name == "" {
+			continue
+		}
+
+		if merged.Name == "" {
+			merged.Name = role.Name
+			merged.Describe = role.Describe
+		}
+
+		for j := 0; j < len(role.Permissions); j++ {
+			permission := role.Permissions[j]
+			if !seen[permission] {
+				seen[permission] = true
+				merged.Permissions = append(merged.Permissions, permission)
+			}
+		}
+	}
+
+	return merged
+}
@@ -1,21 +1,46 @@
 package utils
 
 import (
+	"auth/metrics"
 	"auth/model"
+	"crypto/rand"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/crypto/bcrypt"
-	"math/rand"
+	"math/big"
 	"strings"
+	"time"
 )
 
-func GenerateHashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+// Security porte les paramètres cryptographiques du service : la clé de signature des tokens
+// et le coût bcrypt des mots de passe
+type Security struct {
+	tokenKey   []byte
+	bcryptCost int
+}
+
+// NewSecurity crée une nouvelle instance de Security
+func NewSecurity(tokenKey []byte, bcryptCost int) Security {
+	return Security{
+		tokenKey:   tokenKey,
+		bcryptCost: bcryptCost,
+	}
+}
+
+func (s Security) GenerateHashPassword(password string) (string, error) {
+	defer metrics.ObservePasswordHashing("hash", time.Now())
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
 	return string(bytes), err
 }
 
+// SignToken signe les claims avec la clé du service (HS256)
+func (s Security) SignToken(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.tokenKey)
+}
+
 func CompareHashPassword(password, hash string) bool {
+	defer metrics.ObservePasswordHashing("compare", time.Now())
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
@@ -28,9 +53,12 @@ func ExtractToken(token string) string {
 	return ""
 }
 
-func ParseToken(tokenString string) (claims *model.Claims, err error) {
+func (s Security) ParseToken(tokenString string) (claims *model.Claims, err error) {
 	token, err := jwt.ParseWithClaims(tokenString, &model.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte("my_secret_key"), nil
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return s.tokenKey, nil
 	})
 
 	if err != nil {
@@ -71,11 +99,39 @@ func VerifyPermission(ctx echo.Context, value string) error {
 	return nil
 }
 
+// GetOrganizationId retourne l'organisation active portée par le token de la requête
+func GetOrganizationId(ctx echo.Context) string {
+	orgId, ok := ctx.Get("orgId").(string)
+	if !ok {
+		return ""
+	}
+	return orgId
+}
+
+// GetSessionId retourne la session portée par le token de la requête
+func GetSessionId(ctx echo.Context) string {
+	claims, ok := ctx.Get("claims").(*model.Claims)
+	if !ok {
+		return ""
+	}
+	return claims.SessionId
+}
+
+// SetAuditTarget désigne la cible de l'évènement tracé par le middleware d'audit de la route
+func SetAuditTarget(ctx echo.Context, targetId string) {
+	ctx.Set("auditTarget", targetId)
+}
+
+// OtpGenerator génère un code numérique aléatoire ; crypto/rand rend le code imprévisible
 func OtpGenerator(n int) string {
 	var number = []byte("0123456789")
 	b := make([]byte, n)
 	for i := range b {
-		b[i] = number[rand.Intn(len(number))]
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(number))))
+		if err != nil {
+			panic("error system : random generator unavailable")
+		}
+		b[i] = number[index.Int64()]
 	}
 
 	return string(b)
@@ -0,0 +1,58 @@
+// Package version expose les informations de build du service. Version, Commit et BuildDate
+// sont renseignés à la compilation :
+//
+//	go build -ldflags "-X auth/version.Version=1.2.0 -X auth/version.Commit=$(git rev-parse HEAD) -X auth/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// À défaut, le commit et sa date sont lus dans les informations VCS ajoutées par go build.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info décrit le build en cours d'exécution
+type Info struct {
+	Version   string
+	Commit    string
+	BuildDate string
+	Modified  bool
+	GoVersion string
+}
+
+// Get retourne les informations de build
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for i := 0; i < len(buildInfo.Settings); i++ {
+		setting := buildInfo.Settings[i]
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}